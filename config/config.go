@@ -1,15 +1,37 @@
 package config
 
 import (
+	"bytes"
+	"encoding/json"
 	"errors"
 	"fmt"
-	"log"
+	"os"
+	"reflect"
 	"strings"
+	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_echo"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_grpc"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/cache"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/degradation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/featureflags"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/health"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/lifecycle"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/migration"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/notify"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/reload"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/scheduler"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/transporter"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/webhooks"
+	"github.com/invopop/validation"
 	"github.com/spf13/viper"
 )
 
@@ -17,11 +39,33 @@ type (
 	Config struct {
 		App            AppConfig
 		Server         app_echo.EchoConfig
+		GRPC           app_grpc.GRPCConfig
+		GraphQL        app_graphql.GraphQLConfig
 		Database       datasources.DatabaseConfig
+		Migration      migration.Config
+		Health         health.Config
+		Lifecycle      lifecycle.Config
 		Authentication AuthenticationConfig
+		Admin          AdminConfig
 		Observability  ObservabilityConfig
 		JWT            jwt.JWTConfig
 		Redis          redis.RedisConfig
+		PasswordPolicy password.PolicyConfig
+		Cache          cache.Config
+		Outbox         outbox.Config
+		Kafka          kafka.Config
+		Reload         reload.Config
+		FeatureFlags   featureflags.Config
+		Degradation    degradation.Config
+		Jobs           jobs.Config
+		Scheduler      scheduler.Config
+		Retention      RetentionConfig
+		Notify         notify.Config
+		Webhooks       webhooks.Config
+		HTTPClient     transporter.Config
+
+		path  string   // The path the config was loaded from.
+		files []string // The config files read, the base file first.
 	}
 
 	// AppConfig holds the configuration related to the application settings.
@@ -30,96 +74,324 @@ type (
 		Version     string
 		Scheme      string
 		Host        string
-		Environment string
+		Environment string // One of the Environment constants, selects the config-<Environment> layer.
 	}
 
-	// ServerConfig holds the configuration for the server settings.
-	ServerConfig struct {
-		Port     string // The port on which the server will listen.
-		Debug    bool   // Indicates if debug mode is enabled.
-		TimeZone string // The time zone setting for the server.
+	AuthenticationConfig struct {
+		Key string `secret:"true"`
 	}
 
-	// DatabaseConfig holds the configuration for the database connection.
-	DatabaseConfig struct {
-		Host     string // The host address of the database.
-		Port     string // The port number of the database.
-		DBName   string // The name of the database.
-		UserName string // The username for connecting to the database.
-		Password string // The password for connecting to the database.
-		Debug    bool   // The Debug for debugging when query executed.
+	// AdminConfig lists the admins of the service.
+	AdminConfig struct {
+		// UserIDs are the users allowed to use the admin APIs under
+		// /api/v1/admin and the GraphQL queries restricted to admins.
+		UserIDs []int64
 	}
 
-	RedisConfig struct {
-		Addr     string // The address of the database.
-		Password string // The password for connecting to the database.
-		DB       int    // The name of the database.
+	// ObservabilityConfig holds the configuration for observability settings.
+	ObservabilityConfig struct {
+		Enable bool         // Indicates if observability is enabled.
+		Mode   string       // Specifies the observability mode.
+		Logger LoggerConfig // Reloadable without restart.
 	}
 
-	AuthenticationConfig struct {
-		Key string
+	LoggerConfig struct {
+		Level string // The minimum level logged: trace, debug, info, warn or error. Every level when empty.
 	}
 
-	// ObservabilityConfig holds the configuration for observability settings.
-	ObservabilityConfig struct {
-		Enable bool   // Indicates if observability is enabled.
-		Mode   string // Specifies the observability mode.
+	// RetentionConfig bounds how long the scheduled tasks keep the users that
+	// cannot log in.
+	RetentionConfig struct {
+		DeletedUsers time.Duration // Deleted users are purged once deleted for this long. Defaults to 30 days.
+		PendingUsers time.Duration // Users still PENDING this long after signing up are rejected. Defaults to 7 days.
+	}
+
+	// RuntimeConfig is the subset of the config reloaded while the service
+	// runs, see Config.Runtime.
+	RuntimeConfig struct {
+		Logger    LoggerConfig
+		CORS      app_echo.CORSConfig
+		RateLimit app_echo.RateLimitConfig
 	}
 )
 
-// LoadConfig loads the configuration from the specified filename.
+// Environments the service runs in.
+const (
+	EnvironmentLocal       = "local"
+	EnvironmentTest        = "test"
+	EnvironmentDevelopment = "development"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
+// secretFileSuffix marks the environment variables naming a file that holds the
+// value of a setting, e.g. JWT_KEY_FILE=/run/secrets/jwt-key.
+const secretFileSuffix = "_FILE"
+
+// LoadConfig loads the configuration from the config directory, see LoadConfigPath.
 func LoadConfig(filename string) (Config, error) {
-	// Create a new Viper instance.
-	v := viper.New()
+	return LoadConfigPath(fmt.Sprintf("config/%s", filename))
+}
 
-	// Set the configuration file name, path, and environment variable settings.
-	v.SetConfigName(fmt.Sprintf("config/%s", filename))
+// LoadConfigPath loads the configuration from the layers of path, each
+// overriding the previous ones:
+//
+//  1. the base file path, e.g. config/config.yaml,
+//  2. the file of the environment, path-<App.Environment>, e.g. config/config-local.yaml, when it exists,
+//  3. environment variables named after the keys, e.g. DATABASE_PASSWORD for Database.Password,
+//  4. files named by environment variables with the _FILE suffix, e.g. DATABASE_PASSWORD_FILE.
+//
+// The result is validated and every invalid setting is reported at once.
+func LoadConfigPath(path string) (Config, error) {
+	v := viper.New()
 	v.AddConfigPath(".")
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
 
-	// Read the configuration file.
-	if err := v.ReadInConfig(); err != nil {
-		fmt.Printf("Error reading config file: %v\n", err)
-		return Config{}, err
+	// Every key can be set from the environment, also the ones the files omit.
+	envKeys := make(map[string]string)
+	for _, key := range keys(reflect.TypeFor[Config](), "") {
+		envKeys[key] = strings.ToUpper(strings.ReplaceAll(key, ".", "_"))
+		if err := v.BindEnv(key, envKeys[key]); err != nil {
+			return Config{}, err
+		}
 	}
 
-	// Unmarshal the configuration into the Config struct.
-	var config Config
-	if err := v.Unmarshal(&config); err != nil {
-		fmt.Printf("Error unmarshaling config: %v\n", err)
+	v.SetConfigName(path)
+	if err := v.ReadInConfig(); err != nil {
+		if errors.As(err, new(viper.ConfigFileNotFoundError)) {
+			return Config{}, fmt.Errorf("config file %s not found", path)
+		}
+
 		return Config{}, err
 	}
 
-	return config, nil
-}
+	files := []string{v.ConfigFileUsed()}
 
-// LoadConfigPath loads the configuration from the specified path.
-func LoadConfigPath(path string) (Config, error) {
-	// Create a new Viper instance.
-	v := viper.New()
+	if environment := v.GetString("App.Environment"); environment != "" {
+		v.SetConfigName(fmt.Sprintf("%s-%s", path, environment))
+		switch err := v.MergeInConfig(); {
+		case err == nil:
+			files = append(files, v.ConfigFileUsed())
+		case !errors.As(err, new(viper.ConfigFileNotFoundError)):
+			return Config{}, err
+		}
+	}
 
-	// Set the configuration file name, path, and environment variable settings.
-	v.SetConfigName(path)
-	v.AddConfigPath(".")
-	v.AutomaticEnv()
-	v.SetEnvKeyReplacer(strings.NewReplacer(".", "_"))
+	for key, env := range envKeys {
+		file, ok := os.LookupEnv(env + secretFileSuffix)
+		if !ok {
+			continue
+		}
 
-	// Read the configuration file.
-	if err := v.ReadInConfig(); err != nil {
-		// Handle the case where the configuration file is not found.
-		if _, ok := err.(viper.ConfigFileNotFoundError); ok {
-			return Config{}, errors.New("config file not found")
+		secret, err := os.ReadFile(file)
+		if err != nil {
+			return Config{}, fmt.Errorf("%s%s: %w", env, secretFileSuffix, err)
 		}
-		return Config{}, err
+
+		v.Set(key, strings.TrimRight(string(secret), "\r\n"))
 	}
 
-	// Parse the configuration into the Config struct.
-	var c Config
+	c := Config{path: path, files: files}
 	if err := v.Unmarshal(&c); err != nil {
-		log.Printf("unable to decode into struct, %v", err)
 		return Config{}, err
 	}
 
+	if err := c.Validate(); err != nil {
+		return Config{}, fmt.Errorf("invalid config: %w", err)
+	}
+
 	return c, nil
 }
+
+// Reloaded loads the config again from the layers it was loaded from, with the
+// current files and environment.
+func (c Config) Reloaded() (Config, error) {
+	return LoadConfigPath(c.path)
+}
+
+// Files returns the config files the config was read from, the base file
+// first. An environment file created after loading is not among them.
+func (c Config) Files() []string {
+	return c.files
+}
+
+// Runtime returns the settings that are reloaded while the service runs.
+func (c Config) Runtime() RuntimeConfig {
+	return RuntimeConfig{
+		Logger:    c.Observability.Logger,
+		CORS:      c.Server.CORS,
+		RateLimit: c.Server.RateLimit,
+	}
+}
+
+// Override returns the settings with the ones set in data, a JSON document of
+// the shape of RuntimeConfig such as {"Logger": {"Level": "warn"}}. Unknown
+// settings are rejected.
+func (c RuntimeConfig) Override(data []byte) (RuntimeConfig, error) {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+
+	if err := decoder.Decode(&c); err != nil {
+		return RuntimeConfig{}, fmt.Errorf("runtime overrides: %w", err)
+	}
+
+	return c, nil
+}
+
+func (c RuntimeConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Logger),
+		validation.Field(&c.CORS),
+		validation.Field(&c.RateLimit),
+	)
+}
+
+// keys returns the dotted keys of the settings of the struct t.
+func keys(t reflect.Type, prefix string) []string {
+	var settings []string
+	for i := range t.NumField() {
+		field := t.Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := prefix + field.Name
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			settings = append(settings, keys(field.Type, key+".")...)
+			continue
+		}
+
+		settings = append(settings, key)
+	}
+
+	return settings
+}
+
+// Validate reports every invalid setting of the config.
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.App),
+		validation.Field(&c.Server),
+		validation.Field(&c.GRPC),
+		validation.Field(&c.GraphQL),
+		validation.Field(&c.Database),
+		validation.Field(&c.Migration),
+		validation.Field(&c.Health),
+		validation.Field(&c.Lifecycle),
+		validation.Field(&c.Authentication),
+		validation.Field(&c.Admin),
+		validation.Field(&c.Observability),
+		validation.Field(&c.JWT),
+		validation.Field(&c.Redis),
+		validation.Field(&c.PasswordPolicy),
+		validation.Field(&c.Cache),
+		validation.Field(&c.Outbox),
+		validation.Field(&c.Kafka),
+		validation.Field(&c.Reload),
+		validation.Field(&c.FeatureFlags),
+		validation.Field(&c.Degradation),
+		validation.Field(&c.Jobs),
+		validation.Field(&c.Scheduler),
+		validation.Field(&c.Retention),
+		validation.Field(&c.Notify, c.requiresJobs(c.Notify.Enable)),
+		validation.Field(&c.Webhooks, c.requiresJobs(c.Webhooks.Enable)),
+		validation.Field(&c.HTTPClient),
+	)
+}
+
+// requiresJobs rejects a section enabled without Jobs.Enable, whose workers
+// deliver its messages.
+func (c Config) requiresJobs(enabled bool) validation.Rule {
+	return validation.By(func(any) error {
+		if enabled && !c.Jobs.Enable {
+			return validation.NewError("validation_requires_jobs", "requires Jobs.Enable")
+		}
+
+		return nil
+	})
+}
+
+func (c AppConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required),
+		validation.Field(&c.Environment, validation.Required, validation.In(
+			EnvironmentLocal, EnvironmentTest, EnvironmentDevelopment, EnvironmentStaging, EnvironmentProduction,
+		)),
+	)
+}
+
+func (c AuthenticationConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Key, validation.Required),
+	)
+}
+
+func (c AdminConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.UserIDs, validation.Each(validation.Min(int64(1)))),
+	)
+}
+
+func (c ObservabilityConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		// The modes of pkg/observability, which depends on this package.
+		validation.Field(&c.Mode, validation.When(c.Enable, validation.Required, validation.In("otlp/http", "otlp/grpc", "console"))),
+		validation.Field(&c.Logger),
+	)
+}
+
+func (c RetentionConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.DeletedUsers, validation.Min(time.Duration(0))),
+		validation.Field(&c.PendingUsers, validation.Min(time.Duration(0))),
+	)
+}
+
+// WithDefaults returns the config with the defaults of the unset settings.
+func (c RetentionConfig) WithDefaults() RetentionConfig {
+	if c.DeletedUsers <= 0 {
+		c.DeletedUsers = 30 * 24 * time.Hour
+	}
+
+	if c.PendingUsers <= 0 {
+		c.PendingUsers = 7 * 24 * time.Hour
+	}
+
+	return c
+}
+
+func (c LoggerConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Level, validation.In("trace", "debug", "info", "warn", "error")),
+	)
+}
+
+// redactedValue replaces the secrets of a redacted config.
+const redactedValue = "******"
+
+// Redacted returns a copy of the config with its secrets masked, safe to print
+// or log. The secrets are the settings tagged secret:"true".
+func (c Config) Redacted() Config {
+	redact(reflect.ValueOf(&c).Elem())
+
+	return c
+}
+
+// redact masks the secrets set in the struct v, walking it like keys.
+func redact(v reflect.Value) {
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		value := v.Field(i)
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			redact(value)
+			continue
+		}
+
+		if field.Tag.Get("secret") == "true" && value.Kind() == reflect.String && value.String() != "" {
+			value.SetString(redactedValue)
+		}
+	}
+}
@@ -0,0 +1,177 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const baseConfig = `
+App:
+  Name: "users"
+  Environment: staging
+Server:
+  Port: "9090"
+Database:
+  Host: "db"
+  Port: "3306"
+  DBName: "users"
+  UserName: "root"
+  Password: "base"
+Authentication:
+  Key: "auth"
+JWT:
+  Key: "base-jwt"
+Redis:
+  Addr: "redis:6379"
+`
+
+func writeFile(t *testing.T, name, content string) string {
+	t.Helper()
+
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+
+	return path
+}
+
+func TestLoadConfigPath_Layers(t *testing.T) {
+	dir := t.TempDir()
+	t.Chdir(dir)
+
+	require.NoError(t, os.WriteFile("config.yaml", []byte(baseConfig), 0o600))
+	require.NoError(t, os.WriteFile("config-staging.yaml", []byte("Database:\n  Host: staging-db\n  Port: \"3307\"\n"), 0o600))
+
+	t.Setenv("DATABASE_PORT", "3308")
+	t.Setenv("KAFKA_BROKERS", "kafka-1:9092,kafka-2:9092")
+	t.Setenv("JWT_KEY", "env-jwt")
+	t.Setenv("JWT_KEY_FILE", writeFile(t, "jwt-key", "file-jwt\n"))
+
+	cfg, err := LoadConfigPath("config")
+	require.NoError(t, err)
+
+	assert.Equal(t, "users", cfg.App.Name, "from the base file")
+	assert.Equal(t, "base", cfg.Database.Password, "from the base file")
+	assert.Equal(t, "staging-db", cfg.Database.Host, "from the environment file")
+	assert.Equal(t, "3308", cfg.Database.Port, "environment variables override the files")
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers, "keys missing from the files are read from the environment")
+	assert.Equal(t, "file-jwt", cfg.JWT.Key, "secret files override environment variables")
+}
+
+func TestLoadConfigPath_EnvironmentSelectsLayer(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, os.WriteFile("config.yaml", []byte(baseConfig), 0o600))
+	require.NoError(t, os.WriteFile("config-production.yaml", []byte("Redis:\n  Addr: production-redis:6379\n"), 0o600))
+
+	t.Setenv("APP_ENVIRONMENT", "production")
+
+	cfg, err := LoadConfigPath("config")
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentProduction, cfg.App.Environment)
+	assert.Equal(t, "production-redis:6379", cfg.Redis.Addr)
+}
+
+func TestLoadConfigPath_ReportsEveryInvalidSetting(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, os.WriteFile("config.yaml", []byte(baseConfig+"GRPC:\n  Enable: true\nWebhooks:\n  Enable: true\nNotify:\n  Enable: true\nCache:\n  Jitter: 2\nOutbox:\n  BatchSize: -1\n"), 0o600))
+
+	t.Setenv("JWT_KEY", "")
+	t.Setenv("JWT_KEY_FILE", writeFile(t, "empty", ""))
+	t.Setenv("REDIS_ADDR_FILE", writeFile(t, "empty", ""))
+	t.Setenv("SERVER_TIMEZONE", "Mars/Olympus_Mons")
+
+	_, err := LoadConfigPath("config")
+	require.Error(t, err)
+
+	for _, want := range []string{"JWT: (Key: cannot be blank.)", "Redis: (Addr: cannot be blank.)", "GRPC: (Port: cannot be blank.)", "TimeZone: must be a valid IANA time zone", "Webhooks: requires Jobs.Enable", "Notify: requires Jobs.Enable", "Jitter: must be no greater than 1", "BatchSize: must be no less than 0"} {
+		assert.ErrorContains(t, err, want)
+	}
+}
+
+func TestLoadConfigPath_Errors(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	_, err := LoadConfigPath("config")
+	assert.EqualError(t, err, "config file config not found")
+
+	require.NoError(t, os.WriteFile("config.yaml", []byte(baseConfig), 0o600))
+	t.Setenv("JWT_KEY_FILE", filepath.Join(t.TempDir(), "missing"))
+
+	_, err = LoadConfigPath("config")
+	assert.ErrorContains(t, err, "JWT_KEY_FILE")
+}
+
+// The config files of the repository must load and be valid.
+func TestLoadConfig_RepositoryFiles(t *testing.T) {
+	t.Chdir("..")
+
+	cfg, err := LoadConfig("config")
+	require.NoError(t, err)
+	assert.Equal(t, EnvironmentLocal, cfg.App.Environment)
+	assert.True(t, cfg.Migration.AutoMigrate, "the local layer is applied")
+}
+
+func TestConfig_Redacted(t *testing.T) {
+	var cfg Config
+	cfg.Database.Password = "pwd"
+	cfg.Database.UserName = "root"
+	cfg.Authentication.Key = "key"
+	cfg.JWT.Key = "jwt-key"
+	cfg.Notify.Email.SMTP.Password = "smtp-password"
+	cfg.Redis.SentinelPassword = "sentinel-password"
+
+	redacted := cfg.Redacted()
+
+	assert.Equal(t, redactedValue, redacted.Database.Password)
+	assert.Equal(t, redactedValue, redacted.Authentication.Key)
+	assert.Equal(t, redactedValue, redacted.JWT.Key)
+	assert.Equal(t, redactedValue, redacted.Notify.Email.SMTP.Password)
+	assert.Equal(t, redactedValue, redacted.Redis.SentinelPassword, "the secrets of nested sections are masked")
+	assert.Empty(t, redacted.Redis.Password, "unset secrets stay empty")
+	assert.Equal(t, "root", redacted.Database.UserName)
+	assert.Equal(t, "pwd", cfg.Database.Password, "the config itself is left untouched")
+}
+
+func TestLoadConfigPath_KeepsFilesForReload(t *testing.T) {
+	t.Chdir(t.TempDir())
+
+	require.NoError(t, os.WriteFile("config.yaml", []byte(baseConfig), 0o600))
+	require.NoError(t, os.WriteFile("config-staging.yaml", []byte("Observability:\n  Logger:\n    Level: warn\n"), 0o600))
+
+	cfg, err := LoadConfigPath("config")
+	require.NoError(t, err)
+	require.Len(t, cfg.Files(), 2)
+	assert.Equal(t, "config.yaml", filepath.Base(cfg.Files()[0]))
+	assert.Equal(t, "config-staging.yaml", filepath.Base(cfg.Files()[1]))
+	assert.Equal(t, "warn", cfg.Runtime().Logger.Level)
+
+	require.NoError(t, os.WriteFile("config-staging.yaml", []byte("Observability:\n  Logger:\n    Level: error\n"), 0o600))
+
+	reloaded, err := cfg.Reloaded()
+	require.NoError(t, err)
+	assert.Equal(t, "error", reloaded.Runtime().Logger.Level)
+}
+
+func TestRuntimeConfig_Override(t *testing.T) {
+	runtime := RuntimeConfig{Logger: LoggerConfig{Level: "info"}}
+	runtime.CORS.AllowOrigins = []string{"*"}
+
+	overridden, err := runtime.Override([]byte(`{"Logger": {"Level": "warn"}, "RateLimit": {"Enable": true, "RequestsPerSecond": 5}}`))
+	require.NoError(t, err)
+	assert.Equal(t, "warn", overridden.Logger.Level)
+	assert.Equal(t, []string{"*"}, overridden.CORS.AllowOrigins, "settings missing from the overrides are kept")
+	assert.True(t, overridden.RateLimit.Enable)
+	assert.NoError(t, overridden.Validate())
+
+	_, err = runtime.Override([]byte(`{"Logger": {"Levl": "warn"}}`))
+	assert.ErrorContains(t, err, "unknown field")
+
+	overridden, err = runtime.Override([]byte(`{"Logger": {"Level": "loud"}}`))
+	require.NoError(t, err)
+	assert.Error(t, overridden.Validate())
+}
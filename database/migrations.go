@@ -0,0 +1,35 @@
+// Package database embeds the SQL migrations of every supported driver and the
+// seed fixtures, so the binary can migrate and seed its own database.
+package database
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/samber/lo"
+)
+
+//go:embed mysql/migration/*.sql postgres/migration/*.sql
+var migrations embed.FS
+
+//go:embed seeds/*.yaml seeds/*.json
+var seeds embed.FS
+
+// Migrations returns the migrations of driver.
+func Migrations(driver string) (fs.FS, error) {
+	switch driver {
+	case "", datasources.DriverMySQL:
+		return fs.Sub(migrations, "mysql/migration")
+	case datasources.DriverPostgres:
+		return fs.Sub(migrations, "postgres/migration")
+	default:
+		return nil, fmt.Errorf("%w: %q", datasources.ErrUnsupportedDriver, driver)
+	}
+}
+
+// Seeds returns the seed fixtures, by file name.
+func Seeds() fs.FS {
+	return lo.Must(fs.Sub(seeds, "seeds"))
+}
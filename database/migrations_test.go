@@ -0,0 +1,55 @@
+package database_test
+
+import (
+	"io/fs"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/database"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/seeds"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/stretchr/testify/require"
+)
+
+func migrationNames(t *testing.T, driver string) []string {
+	t.Helper()
+
+	fsys, err := database.Migrations(driver)
+	require.NoError(t, err)
+
+	names, err := fs.Glob(fsys, "*.sql")
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	return names
+}
+
+// Every migration must exist for every driver, so the schema version means the
+// same whichever database is used.
+func TestMigrations_DriverParity(t *testing.T) {
+	require.Equal(t, migrationNames(t, datasources.DriverMySQL), migrationNames(t, datasources.DriverPostgres))
+}
+
+func TestMigrations_UnsupportedDriver(t *testing.T) {
+	_, err := database.Migrations("sqlite")
+	require.ErrorIs(t, err, datasources.ErrUnsupportedDriver)
+}
+
+// The seed fixtures must decode into valid users, so a seed run does not fail
+// halfway on a typo.
+func TestSeeds_Fixtures(t *testing.T) {
+	names, err := fs.Glob(database.Seeds(), "*")
+	require.NoError(t, err)
+	require.NotEmpty(t, names)
+
+	for _, name := range names {
+		t.Run(name, func(t *testing.T) {
+			users, err := seeds.FromFixture(database.Seeds(), name)
+			require.NoError(t, err)
+			require.NotEmpty(t, users)
+
+			for _, user := range users {
+				require.NoError(t, user.Validate(), user.ContactValue)
+			}
+		})
+	}
+}
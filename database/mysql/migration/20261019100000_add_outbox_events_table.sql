@@ -0,0 +1,26 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `outbox_events` (
+    `id` BIGINT UNSIGNED NOT NULL AUTO_INCREMENT,
+    `aggregate_type` VARCHAR(64) NOT NULL,
+    `aggregate_id` VARCHAR(64) NOT NULL,
+    `event_type` VARCHAR(128) NOT NULL,
+    `payload` JSON NOT NULL,
+    `status` ENUM('PENDING', 'PUBLISHED', 'DEAD') NOT NULL DEFAULT 'PENDING',
+    `attempts` INT UNSIGNED NOT NULL DEFAULT 0,
+    `last_error` TEXT NULL,
+    `available_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `published_at` TIMESTAMP NULL DEFAULT NULL,
+
+    PRIMARY KEY (`id`),
+    INDEX `idx_status_id` (`status`, `id`),
+    INDEX `idx_aggregate` (`aggregate_type`, `aggregate_id`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `outbox_events`;
+-- +goose StatementEnd
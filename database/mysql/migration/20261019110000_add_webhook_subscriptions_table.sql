@@ -0,0 +1,22 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TABLE `webhook_subscriptions` (
+    `id` CHAR(36) NOT NULL,
+    `url` VARCHAR(2048) NOT NULL,
+    `events` JSON NOT NULL,
+    `secret` VARCHAR(255) NOT NULL,
+    `enabled` BOOLEAN NOT NULL DEFAULT TRUE,
+    `disabled_reason` VARCHAR(255) NOT NULL DEFAULT '',
+    `created_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    `updated_at` TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP,
+
+    PRIMARY KEY (`id`),
+    INDEX `idx_created_at` (`created_at`, `id`)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4 COLLATE=utf8mb4_unicode_ci;
+
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS `webhook_subscriptions`;
+-- +goose StatementEnd
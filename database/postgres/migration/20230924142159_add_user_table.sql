@@ -0,0 +1,52 @@
+-- +goose Up
+-- +goose StatementBegin
+-- citext keeps contact values case insensitive, like the utf8mb4_unicode_ci collation on MySQL.
+CREATE EXTENSION IF NOT EXISTS citext;
+
+CREATE TYPE user_contact_type AS ENUM ('EMAIL', 'PHONE');
+CREATE TYPE user_language AS ENUM ('EN', 'ID');
+CREATE TYPE user_status AS ENUM ('PENDING', 'ACTIVE', 'REJECT', 'CLOSED');
+
+-- Postgres has no ON UPDATE CURRENT_TIMESTAMP, the trigger below keeps updated_at current instead.
+CREATE FUNCTION set_updated_at() RETURNS TRIGGER AS $$
+BEGIN
+    NEW.updated_at = CURRENT_TIMESTAMP;
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TABLE users (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+    name VARCHAR(255) NOT NULL,
+    contact_type user_contact_type NOT NULL,
+    contact_value CITEXT NOT NULL,
+    birth_date DATE NULL,
+    language user_language NOT NULL DEFAULT 'ID',
+    password VARCHAR(255) NOT NULL,
+    status user_status NOT NULL DEFAULT 'PENDING',
+    -- Second precision, as MySQL TIMESTAMP.
+    created_at TIMESTAMPTZ(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    deleted_at TIMESTAMPTZ(0) NULL DEFAULT NULL,
+
+    PRIMARY KEY (id),
+    CONSTRAINT idx_contact UNIQUE (contact_type, contact_value),
+    CONSTRAINT chk_contact_value_length CHECK (char_length(contact_value) <= 320)
+);
+
+CREATE INDEX idx_status ON users (status);
+CREATE INDEX idx_created_at ON users (created_at);
+CREATE INDEX idx_deleted_at ON users (deleted_at);
+
+CREATE TRIGGER trg_users_updated_at BEFORE UPDATE ON users
+    FOR EACH ROW EXECUTE FUNCTION set_updated_at();
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS users;
+DROP FUNCTION IF EXISTS set_updated_at();
+DROP TYPE IF EXISTS user_status;
+DROP TYPE IF EXISTS user_language;
+DROP TYPE IF EXISTS user_contact_type;
+-- +goose StatementEnd
@@ -0,0 +1,29 @@
+-- +goose Up
+-- +goose StatementBegin
+CREATE TYPE outbox_status AS ENUM ('PENDING', 'PUBLISHED', 'DEAD');
+
+CREATE TABLE outbox_events (
+    id BIGINT GENERATED BY DEFAULT AS IDENTITY,
+    aggregate_type VARCHAR(64) NOT NULL,
+    aggregate_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(128) NOT NULL,
+    payload JSONB NOT NULL,
+    status outbox_status NOT NULL DEFAULT 'PENDING',
+    attempts INTEGER NOT NULL DEFAULT 0 CHECK (attempts >= 0),
+    last_error TEXT NULL,
+    available_at TIMESTAMPTZ(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    created_at TIMESTAMPTZ(0) NOT NULL DEFAULT CURRENT_TIMESTAMP,
+    published_at TIMESTAMPTZ(0) NULL DEFAULT NULL,
+
+    PRIMARY KEY (id)
+);
+
+CREATE INDEX idx_status_id ON outbox_events (status, id);
+CREATE INDEX idx_aggregate ON outbox_events (aggregate_type, aggregate_id, id);
+-- +goose StatementEnd
+
+-- +goose Down
+-- +goose StatementBegin
+DROP TABLE IF EXISTS outbox_events;
+DROP TYPE IF EXISTS outbox_status;
+-- +goose StatementEnd
@@ -0,0 +1,43 @@
+package graphql
+
+import (
+	"context"
+	_ "embed"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
+)
+
+//go:embed schema.graphql
+var schema string
+
+type handlers struct {
+	uc      users.Usecase
+	graphql *app_graphql.Handler
+}
+
+// NewHandlers creates the GraphQL handlers, the queries restricted to admins
+// are open to the users of admins.
+func NewHandlers(uc users.Usecase, cfg app_graphql.GraphQLConfig, admins []int64, opts ...app_graphql.GraphQLOptionFn) *handlers {
+	return &handlers{
+		uc:      uc,
+		graphql: lo.Must(cfg.New(schema, &resolver{uc: uc, admins: admins}, opts...)),
+	}
+}
+
+// GraphQLHandler serves the users GraphQL schema. The JWT claims set by the JWT
+// middleware and the per-request dataloaders are passed to the resolvers through
+// the request context.
+func (h *handlers) GraphQLHandler(c echo.Context) error {
+	ctx := withLoaders(c.Request().Context(), h.uc)
+	if claims := c.Get(types.CredentialDataContextKey.String()); claims != nil {
+		ctx = context.WithValue(ctx, types.CredentialDataContextKey, claims)
+	}
+
+	c.SetRequest(c.Request().WithContext(ctx))
+
+	return h.graphql.Handle(c)
+}
@@ -0,0 +1,134 @@
+package graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	mocks "github.com/DoWithLogic/golang-clean-architecture/mocks/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Path       []any          `json:"path"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func execute(t *testing.T, uc *mocks.MockUsecase, query string) graphQLResponse {
+	t.Helper()
+
+	return executeAs(t, uc, 1, query)
+}
+
+// executeAs runs query for the user userID, the user 1 being an admin.
+func executeAs(t *testing.T, uc *mocks.MockUsecase, userID int64, query string) graphQLResponse {
+	t.Helper()
+
+	handlers := graphql.NewHandlers(uc, app_graphql.GraphQLConfig{MaxDepth: 8, MaxComplexity: 1000, DefaultListSize: 20}, []int64{1})
+
+	body, err := json.Marshal(app_graphql.Request{Query: query})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/api/graphql", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	c := echo.New().NewContext(req, rec)
+	c.Set(types.CredentialDataContextKey.String(), &jwt.JWTClaims{Data: &jwt.Data{ID: userID}})
+
+	require.NoError(t, handlers.GraphQLHandler(c))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res graphQLResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+	return res
+}
+
+func TestGraphQL_BatchesUserLookups(t *testing.T) {
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+
+	uc.EXPECT().UsersByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, ids []int64) ([]dtos.User, error) {
+			assert.ElementsMatch(t, []int64{1, 7, 9}, ids)
+			return []dtos.User{
+				{ID: 1, Name: "Me", Status: types.ACTIVE},
+				{ID: 7, Name: "John", Status: types.ACTIVE},
+			}, nil
+		}).
+		Times(1)
+
+	res := execute(t, uc, `{
+		me { name }
+		john: user(id: "7") { id name status }
+		missing: user(id: "9") { id }
+	}`)
+
+	require.Empty(t, res.Errors)
+	require.Equal(t, map[string]any{"name": "Me"}, res.Data["me"])
+	require.Equal(t, map[string]any{"id": "7", "name": "John", "status": "ACTIVE"}, res.Data["john"])
+	require.Nil(t, res.Data["missing"])
+}
+
+func TestGraphQL_ValidationErrorExtensions(t *testing.T) {
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+
+	res := execute(t, uc, `{ users(page: 0) { total } }`)
+
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "BAD_REQUEST", res.Errors[0].Extensions["code"])
+	require.EqualValues(t, http.StatusBadRequest, res.Errors[0].Extensions["status"])
+	require.Contains(t, res.Errors[0].Extensions["errors"], "page")
+}
+
+func TestGraphQL_UsersRequiresAdmin(t *testing.T) {
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+
+	res := executeAs(t, uc, 2, `{ users { total } }`)
+
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "FORBIDDEN", res.Errors[0].Extensions["code"])
+	require.EqualValues(t, http.StatusForbidden, res.Errors[0].Extensions["status"])
+
+	uc.EXPECT().ListUsers(gomock.Any(), gomock.Any()).Return(dtos.UserList{Total: 3}, nil)
+
+	res = execute(t, uc, `{ users { total } }`)
+
+	require.Empty(t, res.Errors)
+	require.Equal(t, map[string]any{"total": float64(3)}, res.Data["users"])
+}
+
+func TestGraphQL_UserByContactWithPhoneNumber(t *testing.T) {
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+
+	uc.EXPECT().UserDetail(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, request dtos.UserDetailRequest) (dtos.User, error) {
+			var detail entities.UserDetailRequest
+			request.ToUserDetailOption().Apply(&detail)
+			require.NotNil(t, detail.ContactValue)
+			assert.Equal(t, "+628123456789", *detail.ContactValue, "the + of the phone number is kept")
+
+			return dtos.User{ID: 8, Name: "Budi", ContactValue: "+628123456789", Status: types.ACTIVE}, nil
+		})
+
+	res := execute(t, uc, `{ userByContact(contactValue: "+628123456789") { id contactValue } }`)
+
+	require.Empty(t, res.Errors)
+	require.Equal(t, map[string]any{"id": "8", "contactValue": "+628123456789"}, res.Data["userByContact"])
+}
@@ -0,0 +1,77 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/graph-gophers/dataloader/v7"
+)
+
+type loadersContextKey struct{}
+
+// loaders batch and cache the lookups made while resolving a single request.
+type loaders struct {
+	users *dataloader.Loader[int64, dtos.User]
+}
+
+func withLoaders(ctx context.Context, uc users.Usecase) context.Context {
+	return context.WithValue(ctx, loadersContextKey{}, &loaders{
+		users: dataloader.NewBatchedLoader(batchUsers(uc)),
+	})
+}
+
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersContextKey{}).(*loaders)
+}
+
+// batchUsers loads all users requested during a tick with a single usecase call.
+func batchUsers(uc users.Usecase) dataloader.BatchFunc[int64, dtos.User] {
+	return func(ctx context.Context, ids []int64) []*dataloader.Result[dtos.User] {
+		results := make([]*dataloader.Result[dtos.User], len(ids))
+
+		usersData, err := uc.UsersByIDs(ctx, ids)
+		if err != nil {
+			for i := range results {
+				results[i] = &dataloader.Result[dtos.User]{Error: err}
+			}
+
+			return results
+		}
+
+		byID := make(map[int64]dtos.User, len(usersData))
+		for _, user := range usersData {
+			byID[user.ID] = user
+		}
+
+		for i, id := range ids {
+			user, ok := byID[id]
+			if !ok {
+				results[i] = &dataloader.Result[dtos.User]{Error: response.NotFound(app_error.ErrUserNotFound)}
+				continue
+			}
+
+			results[i] = &dataloader.Result[dtos.User]{Data: user}
+		}
+
+		return results
+	}
+}
+
+func (l *loaders) user(ctx context.Context, id int64) (dtos.User, error) {
+	return l.users.Load(ctx, id)()
+}
+
+// primeUsers caches users that were already loaded, e.g. by a list query.
+func (l *loaders) primeUsers(ctx context.Context, usersData ...dtos.User) {
+	for _, user := range usersData {
+		l.users.Prime(ctx, user.ID, user)
+	}
+}
+
+// forgetUser drops a cached user after it has been changed.
+func (l *loaders) forgetUser(ctx context.Context, id int64) {
+	l.users.Clear(ctx, id)
+}
@@ -0,0 +1,212 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/graph-gophers/graphql-go"
+)
+
+var ErrInvalidID = errors.New("invalid user id")
+
+// resolver resolves the Query and Mutation types.
+type resolver struct {
+	uc     users.Usecase
+	admins []int64
+}
+
+type userFilterInput struct {
+	Status      *string
+	ContactType *string
+	Search      *string
+}
+
+type updateUserInput struct {
+	Name         *string
+	ContactType  *string
+	ContactValue *string
+	BirthDate    *string
+	Language     *string
+	Password     *string
+}
+
+func (r *resolver) Me(ctx context.Context) (*userResolver, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "MeGraphQLResolver")
+	defer span.End()
+
+	claims, ok := ctx.Value(types.CredentialDataContextKey).(*jwt.JWTClaims)
+	if !ok || claims.Data == nil {
+		return nil, app_graphql.ToError(response.Unauthorized(app_error.ErrInvalidToken))
+	}
+
+	user, err := loadersFromContext(ctx).user(ctx, claims.Data.ID)
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) User(ctx context.Context, args struct{ ID graphql.ID }) (*userResolver, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UserGraphQLResolver")
+	defer span.End()
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	user, err := loadersFromContext(ctx).user(ctx, id)
+	if errors.Is(err, app_error.ErrUserNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) UserByContact(ctx context.Context, args struct{ ContactValue string }) (*userResolver, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UserByContactGraphQLResolver")
+	defer span.End()
+
+	user, err := r.uc.UserDetail(ctx, dtos.UserDetailByContactValueRequest{ContactValue: args.ContactValue})
+	if errors.Is(err, app_error.ErrUserNotFound) {
+		return nil, nil
+	}
+
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	loadersFromContext(ctx).primeUsers(ctx, user)
+
+	return &userResolver{user: user}, nil
+}
+
+func (r *resolver) Users(ctx context.Context, args struct {
+	Filter  *userFilterInput
+	Page    int32
+	PerPage int32
+}) (*userPageResolver, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UsersGraphQLResolver")
+	defer span.End()
+
+	// Listing exposes the contact data of every user.
+	if !middleware.IsAdmin(ctx, r.admins) {
+		return nil, app_graphql.ToError(response.Forbidden(middleware.ErrNotAdmin))
+	}
+
+	request := dtos.ListUsersRequest{Page: int(args.Page), PerPage: int(args.PerPage)}
+	if args.Filter != nil {
+		request.Status = (*types.USER_STATUS)(args.Filter.Status)
+		request.ContactType = (*types.CONTACT_TYPE)(args.Filter.ContactType)
+		request.Search = args.Filter.Search
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, app_graphql.ToError(response.BadRequest(err))
+	}
+
+	list, err := r.uc.ListUsers(ctx, request)
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	loadersFromContext(ctx).primeUsers(ctx, list.Users...)
+
+	return &userPageResolver{list: list}, nil
+}
+
+func (r *resolver) UpdateUser(ctx context.Context, args struct {
+	ID    graphql.ID
+	Input updateUserInput
+}) (*userResolver, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UpdateUserGraphQLResolver")
+	defer span.End()
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	request := dtos.UserUpdateRequest{
+		ID: id,
+		UserUpdate: dtos.UserUpdate{
+			Name:         args.Input.Name,
+			ContactType:  (*types.CONTACT_TYPE)(args.Input.ContactType),
+			ContactValue: args.Input.ContactValue,
+			BirthDate:    args.Input.BirthDate,
+			Language:     (*types.LANGUAGE)(args.Input.Language),
+			Password:     args.Input.Password,
+		},
+	}
+
+	if err := r.uc.UserUpdate(ctx, request); err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	return r.reloadUser(ctx, id)
+}
+
+func (r *resolver) TransitionUserStatus(ctx context.Context, args struct {
+	ID     graphql.ID
+	Status string
+}) (*userResolver, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "TransitionUserStatusGraphQLResolver")
+	defer span.End()
+
+	id, err := parseID(args.ID)
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	request := dtos.TransitionUserStatusRequest{
+		ID:                   id,
+		TransitionUserStatus: dtos.TransitionUserStatus{Status: types.USER_STATUS(args.Status)},
+	}
+
+	if err := request.Validate(); err != nil {
+		return nil, app_graphql.ToError(response.BadRequest(err))
+	}
+
+	if err := r.uc.TransitionUserStatus(ctx, request); err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	return r.reloadUser(ctx, id)
+}
+
+// reloadUser returns the user after a mutation, bypassing the cached lookup.
+func (r *resolver) reloadUser(ctx context.Context, id int64) (*userResolver, error) {
+	loaders := loadersFromContext(ctx)
+	loaders.forgetUser(ctx, id)
+
+	user, err := loaders.user(ctx, id)
+	if err != nil {
+		return nil, app_graphql.ToError(err)
+	}
+
+	return &userResolver{user: user}, nil
+}
+
+func parseID(id graphql.ID) (int64, error) {
+	parsed, err := strconv.ParseInt(string(id), 10, 64)
+	if err != nil || parsed <= 0 {
+		return 0, response.BadRequest(ErrInvalidID)
+	}
+
+	return parsed, nil
+}
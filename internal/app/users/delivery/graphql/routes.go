@@ -0,0 +1,10 @@
+package graphql
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/labstack/echo/v4"
+)
+
+func (h *handlers) MapRoutes(echo *echo.Group, mw *middleware.Middleware) {
+	echo.POST("/graphql", h.GraphQLHandler, mw.JWTMiddleware())
+}
@@ -0,0 +1,55 @@
+package graphql
+
+import (
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/graph-gophers/graphql-go"
+)
+
+// userResolver resolves the User type. The password hash is not part of the schema.
+type userResolver struct {
+	user dtos.User
+}
+
+func (r *userResolver) ID() graphql.ID          { return graphql.ID(strconv.FormatInt(r.user.ID, 10)) }
+func (r *userResolver) Name() string            { return r.user.Name }
+func (r *userResolver) ContactType() string     { return string(r.user.ContactType) }
+func (r *userResolver) ContactValue() string    { return r.user.ContactValue }
+func (r *userResolver) BirthDate() *string      { return r.user.BirthDate }
+func (r *userResolver) Status() string          { return string(r.user.Status) }
+func (r *userResolver) CreatedAt() graphql.Time { return graphql.Time{Time: r.user.CreatedAt} }
+
+func (r *userResolver) Language() *string {
+	if r.user.Language == nil {
+		return nil
+	}
+
+	language := string(*r.user.Language)
+	return &language
+}
+
+func (r *userResolver) UpdatedAt() *graphql.Time {
+	if r.user.UpdatedAt == nil {
+		return nil
+	}
+
+	return &graphql.Time{Time: *r.user.UpdatedAt}
+}
+
+type userPageResolver struct {
+	list dtos.UserList
+}
+
+func (r *userPageResolver) Items() []*userResolver {
+	items := make([]*userResolver, 0, len(r.list.Users))
+	for _, user := range r.list.Users {
+		items = append(items, &userResolver{user: user})
+	}
+
+	return items
+}
+
+func (r *userPageResolver) Total() int32   { return int32(r.list.Total) }
+func (r *userPageResolver) Page() int32    { return int32(r.list.Page) }
+func (r *userPageResolver) PerPage() int32 { return int32(r.list.PerPage) }
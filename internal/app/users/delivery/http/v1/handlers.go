@@ -1,6 +1,8 @@
 package v1
 
 import (
+	"net/url"
+
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
@@ -125,6 +127,13 @@ func (h *handlers) UserDetailByContactValueHandler(c echo.Context) error {
 		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
 	}
 
+	contactValue, err := url.QueryUnescape(request.ContactValue)
+	if err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	request.ContactValue = contactValue
+
 	userData, err := h.uc.UserDetail(ctx, *request)
 	if err != nil {
 		return response.ErrorBuilder(err).Send(c)
@@ -0,0 +1,55 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+)
+
+const (
+	TopicTransitionUserStatus = "users.commands.transition-status" // Commands to transition the status of a user.
+	TopicUserEvents           = "users.events"                     // User domain events published from the outbox.
+)
+
+type handlers struct {
+	uc users.Usecase
+}
+
+func NewHandlers(uc users.Usecase) *handlers {
+	return &handlers{uc}
+}
+
+func (h *handlers) TransitionUserStatusHandler(ctx context.Context, msg kafka.Message) error {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "TransitionUserStatusKafkaHandler")
+	defer span.End()
+
+	var command dtos.TransitionUserStatusCommand
+	if err := json.Unmarshal(msg.Value, &command); err != nil {
+		return kafka.Permanent(response.BadRequest(err))
+	}
+
+	request := command.ToRequest()
+	if err := request.Validate(); err != nil {
+		return kafka.Permanent(response.BadRequest(err))
+	}
+
+	return permanentIfClientError(h.uc.TransitionUserStatus(ctx, request))
+}
+
+// permanentIfClientError marks errors caused by the message itself, such as an
+// unknown user, as permanent so they are not retried.
+func permanentIfClientError(err error) error {
+	var appErr *response.AppError
+	if errors.As(err, &appErr) && appErr.Code >= http.StatusBadRequest && appErr.Code < http.StatusInternalServerError {
+		return kafka.Permanent(err)
+	}
+
+	return err
+}
@@ -0,0 +1,122 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	userKafka "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	mocks "github.com/DoWithLogic/golang-clean-architecture/mocks/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka/kafkatest"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+const topic = userKafka.TopicTransitionUserStatus
+
+// newRouter runs the users topics on broker and returns a function stopping it.
+func newRouter(t *testing.T, broker *kafkatest.Broker) (*mocks.MockUsecase, func()) {
+	t.Helper()
+
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+
+	router := kafka.NewRouter(kafka.Config{
+		MaxAttempts:     2,
+		RetryBackoff:    10 * time.Millisecond,
+		MaxRetryBackoff: 20 * time.Millisecond,
+	}, broker, nil)
+	userKafka.NewHandlers(uc).MapTopics(router)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- router.Run(ctx) }()
+
+	return uc, func() {
+		cancel()
+		require.NoError(t, <-done)
+	}
+}
+
+func produce(t *testing.T, broker *kafkatest.Broker, value string) {
+	t.Helper()
+	require.NoError(t, kafka.NewProducer(broker).Produce(context.Background(), kafka.Message{Topic: topic, Key: []byte("42"), Value: []byte(value)}))
+}
+
+func TestTransitionUserStatusHandler(t *testing.T) {
+	broker := kafkatest.NewBroker()
+	uc, stop := newRouter(t, broker)
+
+	uc.EXPECT().TransitionUserStatus(gomock.Any(), dtos.TransitionUserStatusRequest{
+		ID:                   42,
+		TransitionUserStatus: dtos.TransitionUserStatus{Status: types.BANNED},
+	}).Return(nil)
+
+	produce(t, broker, `{"id":42,"status":"CLOSED"}`)
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, func(b *kafkatest.Broker) bool { return b.Committed(topic) == 1 }))
+	stop()
+
+	require.Empty(t, broker.Messages(kafka.RetryTopic(topic)))
+	require.Empty(t, broker.Messages(kafka.DLQTopic(topic)))
+}
+
+func TestTransitionUserStatusHandler_InvalidCommandsAreDeadLettered(t *testing.T) {
+	for name, value := range map[string]string{
+		"malformed JSON": `{"id":`,
+		"missing ID":     `{"status":"ACTIVE"}`,
+		"unknown status": `{"id":42,"status":"DELETED"}`,
+		"missing status": `{"id":42}`,
+	} {
+		t.Run(name, func(t *testing.T) {
+			broker := kafkatest.NewBroker()
+			_, stop := newRouter(t, broker)
+
+			produce(t, broker, value)
+
+			require.True(t, broker.WaitFor(context.Background(), time.Second, func(b *kafkatest.Broker) bool { return len(b.Messages(kafka.DLQTopic(topic))) == 1 }))
+			stop()
+
+			require.Empty(t, broker.Messages(kafka.RetryTopic(topic)), "invalid commands are not retried")
+		})
+	}
+}
+
+func TestTransitionUserStatusHandler_ClientErrorsAreNotRetried(t *testing.T) {
+	broker := kafkatest.NewBroker()
+	uc, stop := newRouter(t, broker)
+
+	uc.EXPECT().TransitionUserStatus(gomock.Any(), gomock.Any()).Return(response.NotFound(app_error.ErrUserNotFound)).Times(1)
+
+	produce(t, broker, `{"id":42,"status":"ACTIVE"}`)
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, func(b *kafkatest.Broker) bool { return len(b.Messages(kafka.DLQTopic(topic))) == 1 }))
+	stop()
+
+	require.Empty(t, broker.Messages(kafka.RetryTopic(topic)))
+
+	reason, _ := kafka.GetHeader(broker.Messages(kafka.DLQTopic(topic))[0], kafka.HeaderError)
+	require.Contains(t, reason, app_error.ErrUserNotFound.Error())
+}
+
+func TestTransitionUserStatusHandler_ServerErrorsAreRetried(t *testing.T) {
+	broker := kafkatest.NewBroker()
+	uc, stop := newRouter(t, broker)
+
+	gomock.InOrder(
+		uc.EXPECT().TransitionUserStatus(gomock.Any(), gomock.Any()).Return(response.InternalServerError(errors.New("database unavailable"))),
+		uc.EXPECT().TransitionUserStatus(gomock.Any(), gomock.Any()).Return(errors.New("connection reset")),
+	)
+
+	produce(t, broker, `{"id":42,"status":"ACTIVE"}`)
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, func(b *kafkatest.Broker) bool { return len(b.Messages(kafka.DLQTopic(topic))) == 1 }))
+	stop()
+
+	require.Len(t, broker.Messages(kafka.RetryTopic(topic)), 1, "the message is retried before it is dead-lettered")
+}
@@ -0,0 +1,45 @@
+package kafka
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+)
+
+const (
+	headerEventID       = "x-event-id"
+	headerEventType     = "x-event-type"
+	headerAggregateType = "x-aggregate-type"
+)
+
+type eventPublisher struct {
+	producer *kafka.Producer
+}
+
+// NewEventPublisher creates an outbox.Publisher that writes user events to
+// TopicUserEvents. Events are keyed by user ID so the events of a user keep their
+// order within a partition.
+func NewEventPublisher(producer *kafka.Producer) outbox.Publisher {
+	return &eventPublisher{producer: producer}
+}
+
+func (p *eventPublisher) Publish(ctx context.Context, message outbox.Message) error {
+	if message.AggregateType != entities.UserAggregateType {
+		return fmt.Errorf("kafka: no topic for aggregate type %q", message.AggregateType)
+	}
+
+	return p.producer.Produce(ctx, kafka.Message{
+		Topic: TopicUserEvents,
+		Key:   []byte(message.AggregateID),
+		Value: message.Payload,
+		Headers: []kafka.Header{
+			{Key: headerEventID, Value: []byte(strconv.FormatInt(message.ID, 10))},
+			{Key: headerEventType, Value: []byte(message.EventType)},
+			{Key: headerAggregateType, Value: []byte(message.AggregateType)},
+		},
+	})
+}
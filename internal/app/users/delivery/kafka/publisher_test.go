@@ -0,0 +1,47 @@
+package kafka_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	userKafka "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka/kafkatest"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventPublisher(t *testing.T) {
+	broker := kafkatest.NewBroker()
+	publisher := userKafka.NewEventPublisher(kafka.NewProducer(broker))
+
+	require.NoError(t, publisher.Publish(context.Background(), outbox.Message{
+		ID:            7,
+		AggregateType: entities.UserAggregateType,
+		AggregateID:   "42",
+		EventType:     entities.UserSignedUpEventType,
+		Payload:       json.RawMessage(`{"user_id":42}`),
+	}))
+
+	messages := broker.Messages(userKafka.TopicUserEvents)
+	require.Len(t, messages, 1)
+	require.Equal(t, "42", string(messages[0].Key), "events are keyed by user to keep their order")
+	require.JSONEq(t, `{"user_id":42}`, string(messages[0].Value))
+
+	for key, want := range map[string]string{"x-event-id": "7", "x-event-type": entities.UserSignedUpEventType, "x-aggregate-type": entities.UserAggregateType} {
+		value, ok := kafka.GetHeader(messages[0], key)
+		require.True(t, ok, key)
+		require.Equal(t, want, value)
+	}
+}
+
+func TestEventPublisher_RejectsOtherAggregates(t *testing.T) {
+	broker := kafkatest.NewBroker()
+	publisher := userKafka.NewEventPublisher(kafka.NewProducer(broker))
+
+	err := publisher.Publish(context.Background(), outbox.Message{ID: 1, AggregateType: "order", AggregateID: "1"})
+	require.ErrorContains(t, err, `no topic for aggregate type "order"`)
+	require.Empty(t, broker.Messages(userKafka.TopicUserEvents))
+}
@@ -0,0 +1,7 @@
+package kafka
+
+import "github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+
+func (h *handlers) MapTopics(router *kafka.Router) {
+	router.Handle(TopicTransitionUserStatus, h.TransitionUserStatusHandler)
+}
@@ -0,0 +1,67 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"slices"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/notify"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+)
+
+// EventPasswordChanged is the Event of the security alert sent when a user
+// changes their password.
+const EventPasswordChanged = "password_changed"
+
+type eventNotifier struct {
+	uc       users.Usecase
+	notifier *notify.Notifier
+}
+
+// NewEventNotifier creates an outbox.Publisher that notifies the users of the
+// events of their account: a security alert when they change their password.
+// The other events are ignored. Like every publisher it may run twice for an
+// event, so an alert may be sent twice.
+func NewEventNotifier(uc users.Usecase, notifier *notify.Notifier) outbox.Publisher {
+	return &eventNotifier{uc: uc, notifier: notifier}
+}
+
+func (p *eventNotifier) Publish(ctx context.Context, message outbox.Message) error {
+	if message.EventType != entities.UserUpdatedEventType {
+		return nil
+	}
+
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UserUpdatedNotifier")
+	defer span.End()
+
+	var event entities.UserUpdated
+	if err := json.Unmarshal(message.Payload, &event); err != nil {
+		return err
+	}
+
+	if !slices.Contains(event.Fields, "password") {
+		return nil
+	}
+
+	user, err := p.uc.UserDetail(ctx, dtos.UserDetailByIDRequest{ID: event.UserID})
+	if errors.Is(err, app_error.ErrUserNotFound) {
+		return nil
+	}
+
+	if err != nil {
+		return err
+	}
+
+	return p.notifier.Send(ctx, notify.Notification{
+		Recipient: notify.Recipient{UserID: user.ID, ContactType: user.ContactType, ContactValue: user.ContactValue, Language: user.Language},
+		Template:  notify.TemplateSecurityAlert,
+		Data:      map[string]string{"Name": user.Name, "Time": event.OccurredAt.UTC().Format(time.RFC1123), "Event": EventPasswordChanged},
+	})
+}
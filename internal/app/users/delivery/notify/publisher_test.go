@@ -0,0 +1,71 @@
+package notify_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	userNotify "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/notify"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	mocks "github.com/DoWithLogic/golang-clean-architecture/mocks/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/notify"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+func userUpdated(t *testing.T, fields ...string) outbox.Message {
+	t.Helper()
+
+	payload, err := json.Marshal(entities.UserUpdated{UserID: 42, Fields: fields, OccurredAt: time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)})
+	require.NoError(t, err)
+
+	return outbox.Message{ID: 7, AggregateType: entities.UserAggregateType, AggregateID: "42", EventType: entities.UserUpdatedEventType, Payload: payload}
+}
+
+func TestEventNotifier(t *testing.T) {
+	ctx := context.Background()
+
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+	backend := jobs.NewMemoryBackend()
+	notifier := notify.NewNotifier(notify.Config{}, notify.DefaultTemplates(), notify.Senders{}, nil, jobs.NewClient(backend), nil)
+	publisher := userNotify.NewEventNotifier(uc, notifier)
+
+	queued := func() int64 {
+		stats, err := backend.Stats(ctx, notify.DefaultQueue)
+		require.NoError(t, err)
+		return stats.Ready
+	}
+
+	require.NoError(t, publisher.Publish(ctx, userUpdated(t, "name")), "only password changes are notified")
+	require.NoError(t, publisher.Publish(ctx, outbox.Message{EventType: entities.UserSignedUpEventType, Payload: json.RawMessage(`{}`)}))
+	assert.Zero(t, queued())
+
+	language := types.LANGUAGE_ID
+	uc.EXPECT().UserDetail(gomock.Any(), dtos.UserDetailByIDRequest{ID: 42}).
+		Return(dtos.User{ID: 42, Name: "Budi", ContactType: types.CONTACT_TYPE_PHONE, ContactValue: "+628123456789", Language: &language}, nil)
+
+	require.NoError(t, publisher.Publish(ctx, userUpdated(t, "name", "password")))
+	require.Equal(t, int64(1), queued())
+
+	job, err := backend.Reserve(ctx, notify.DefaultQueue, time.Minute, 1)
+	require.NoError(t, err)
+
+	var notification notify.Notification
+	require.NoError(t, json.Unmarshal(job.Payload, &notification))
+	assert.Equal(t, notify.Notification{
+		Recipient: notify.Recipient{UserID: 42, ContactType: types.CONTACT_TYPE_PHONE, ContactValue: "+628123456789", Language: &language},
+		Template:  notify.TemplateSecurityAlert,
+		Data:      map[string]string{"Name": "Budi", "Time": "Fri, 02 Jan 2026 03:04:05 UTC", "Event": userNotify.EventPasswordChanged},
+	}, notification)
+
+	uc.EXPECT().UserDetail(gomock.Any(), dtos.UserDetailByIDRequest{ID: 42}).Return(dtos.User{}, response.NotFound(app_error.ErrUserNotFound))
+	require.NoError(t, publisher.Publish(ctx, userUpdated(t, "password")), "the alerts of the deleted users are dropped")
+}
@@ -0,0 +1,106 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	usersv1 "github.com/DoWithLogic/golang-clean-architecture/pkg/pb/users/v1"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+)
+
+var ErrMissingLookup = errors.New("either id or contact_value is required")
+
+type handlers struct {
+	usersv1.UnimplementedUserServiceServer
+
+	uc users.Usecase
+}
+
+func NewHandlers(uc users.Usecase) *handlers {
+	return &handlers{uc: uc}
+}
+
+func (h *handlers) Login(ctx context.Context, req *usersv1.LoginRequest) (*usersv1.LoginResponse, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "LoginRPCHandler")
+	defer span.End()
+
+	request := toUserLoginRequest(req)
+	if err := request.Validate(); err != nil {
+		return nil, response.BadRequest(err)
+	}
+
+	authData, err := h.uc.Login(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usersv1.LoginResponse{AccessToken: authData.AccessToken, ExpiredAt: authData.ExpiredAt}, nil
+}
+
+func (h *handlers) SignUp(ctx context.Context, req *usersv1.SignUpRequest) (*usersv1.SignUpResponse, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "SignUpRPCHandler")
+	defer span.End()
+
+	request := toSignUpRequest(req)
+	if err := request.Validate(); err != nil {
+		return nil, response.BadRequest(err)
+	}
+
+	if err := h.uc.SignUp(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return &usersv1.SignUpResponse{}, nil
+}
+
+func (h *handlers) GetUser(ctx context.Context, req *usersv1.GetUserRequest) (*usersv1.GetUserResponse, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "GetUserRPCHandler")
+	defer span.End()
+
+	var request dtos.UserDetailRequest
+	switch lookup := req.GetLookup().(type) {
+	case *usersv1.GetUserRequest_Id:
+		request = dtos.UserDetailByIDRequest{ID: lookup.Id}
+	case *usersv1.GetUserRequest_ContactValue:
+		request = dtos.UserDetailByContactValueRequest{ContactValue: lookup.ContactValue}
+	default:
+		return nil, response.BadRequest(ErrMissingLookup)
+	}
+
+	userData, err := h.uc.UserDetail(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	return &usersv1.GetUserResponse{User: toUserMessage(userData)}, nil
+}
+
+func (h *handlers) UpdateUser(ctx context.Context, req *usersv1.UpdateUserRequest) (*usersv1.UpdateUserResponse, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UpdateUserRPCHandler")
+	defer span.End()
+
+	if err := h.uc.UserUpdate(ctx, toUserUpdateRequest(req)); err != nil {
+		return nil, err
+	}
+
+	return &usersv1.UpdateUserResponse{}, nil
+}
+
+func (h *handlers) TransitionUserStatus(ctx context.Context, req *usersv1.TransitionUserStatusRequest) (*usersv1.TransitionUserStatusResponse, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "TransitionUserStatusRPCHandler")
+	defer span.End()
+
+	request := toTransitionUserStatusRequest(req)
+	if err := request.Validate(); err != nil {
+		return nil, response.BadRequest(err)
+	}
+
+	if err := h.uc.TransitionUserStatus(ctx, request); err != nil {
+		return nil, err
+	}
+
+	return &usersv1.TransitionUserStatusResponse{}, nil
+}
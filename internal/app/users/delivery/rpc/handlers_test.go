@@ -0,0 +1,170 @@
+package rpc_test
+
+import (
+	"context"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/rpc"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	mocks "github.com/DoWithLogic/golang-clean-architecture/mocks/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_grpc"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	usersv1 "github.com/DoWithLogic/golang-clean-architecture/pkg/pb/users/v1"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+func newClient(t *testing.T) (usersv1.UserServiceClient, *mocks.MockUsecase, context.Context) {
+	t.Helper()
+
+	uc := mocks.NewMockUsecase(gomock.NewController(t))
+
+	mr := miniredis.RunT(t)
+	redisClient, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+
+	jwtFactory := jwt.NewJWTFactory(
+		jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600},
+		redis.NewRedisManager(redisClient),
+	)
+
+	server := app_grpc.GRPCConfig{}.New(app_grpc.WithAuth(jwtFactory))
+	rpc.NewHandlers(uc).MapServices(server)
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: 1}})
+	require.NoError(t, err)
+
+	authCtx := metadata.AppendToOutgoingContext(t.Context(), "authorization", "Bearer "+token)
+
+	return usersv1.NewUserServiceClient(conn), uc, authCtx
+}
+
+func TestLogin(t *testing.T) {
+	client, uc, _ := newClient(t)
+
+	uc.EXPECT().Login(gomock.Any(), dtos.UserLoginRequest{
+		ContactType:  types.CONTACT_TYPE_EMAIL,
+		ContactValue: "john@example.com",
+		Password:     "Str0ng#Pass",
+	}).Return(dtos.UserLoginResponse{AccessToken: "token", ExpiredAt: 100}, nil)
+
+	res, err := client.Login(t.Context(), &usersv1.LoginRequest{
+		ContactType:  "EMAIL",
+		ContactValue: "john@example.com",
+		Password:     "Str0ng#Pass",
+	})
+	require.NoError(t, err)
+	require.Equal(t, "token", res.GetAccessToken())
+	require.EqualValues(t, 100, res.GetExpiredAt())
+}
+
+func TestSignUp_InvalidRequest(t *testing.T) {
+	client, _, _ := newClient(t)
+
+	_, err := client.SignUp(t.Context(), &usersv1.SignUpRequest{Name: "John"})
+	require.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGetUser(t *testing.T) {
+	client, uc, authCtx := newClient(t)
+
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	uc.EXPECT().UserDetail(gomock.Any(), dtos.UserDetailByIDRequest{ID: 7}).Return(dtos.User{
+		ID:           7,
+		Name:         "John",
+		ContactType:  types.CONTACT_TYPE_EMAIL,
+		ContactValue: "john@example.com",
+		Password:     "hashed",
+		Status:       types.ACTIVE,
+		CreatedAt:    createdAt,
+	}, nil)
+
+	res, err := client.GetUser(authCtx, &usersv1.GetUserRequest{Lookup: &usersv1.GetUserRequest_Id{Id: 7}})
+	require.NoError(t, err)
+	require.EqualValues(t, 7, res.GetUser().GetId())
+	require.Equal(t, "ACTIVE", res.GetUser().GetStatus())
+	require.Equal(t, createdAt, res.GetUser().GetCreatedAt().AsTime())
+}
+
+// byContactValue matches the requests looking up contactValue.
+func byContactValue(contactValue string) gomock.Matcher {
+	return gomock.Cond(func(x any) bool {
+		request, ok := x.(dtos.UserDetailRequest)
+		if !ok {
+			return false
+		}
+
+		var detail entities.UserDetailRequest
+		request.ToUserDetailOption().Apply(&detail)
+
+		return detail.ContactValue != nil && *detail.ContactValue == contactValue
+	})
+}
+
+func TestGetUser_ByPhoneNumber(t *testing.T) {
+	client, uc, authCtx := newClient(t)
+
+	uc.EXPECT().UserDetail(gomock.Any(), byContactValue("+628123456789")).Return(dtos.User{
+		ID:           8,
+		ContactType:  types.CONTACT_TYPE_PHONE,
+		ContactValue: "+628123456789",
+	}, nil)
+
+	res, err := client.GetUser(authCtx, &usersv1.GetUserRequest{Lookup: &usersv1.GetUserRequest_ContactValue{ContactValue: "+628123456789"}})
+	require.NoError(t, err)
+	require.EqualValues(t, 8, res.GetUser().GetId())
+}
+
+func TestGetUser_RequiresToken(t *testing.T) {
+	client, _, _ := newClient(t)
+
+	_, err := client.GetUser(t.Context(), &usersv1.GetUserRequest{Lookup: &usersv1.GetUserRequest_Id{Id: 7}})
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+}
+
+func TestGetUser_NotFound(t *testing.T) {
+	client, uc, authCtx := newClient(t)
+
+	uc.EXPECT().UserDetail(gomock.Any(), byContactValue("jane@example.com")).
+		Return(dtos.User{}, response.NotFound(app_error.ErrUserNotFound))
+
+	_, err := client.GetUser(authCtx, &usersv1.GetUserRequest{Lookup: &usersv1.GetUserRequest_ContactValue{ContactValue: "jane@example.com"}})
+	require.Equal(t, codes.NotFound, status.Code(err))
+}
+
+func TestTransitionUserStatus(t *testing.T) {
+	client, uc, authCtx := newClient(t)
+
+	uc.EXPECT().TransitionUserStatus(gomock.Any(), dtos.TransitionUserStatusRequest{
+		ID:                   7,
+		TransitionUserStatus: dtos.TransitionUserStatus{Status: types.ACTIVE},
+	}).Return(nil)
+
+	_, err := client.TransitionUserStatus(authCtx, &usersv1.TransitionUserStatusRequest{Id: 7, Status: "ACTIVE"})
+	require.NoError(t, err)
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	usersv1 "github.com/DoWithLogic/golang-clean-architecture/pkg/pb/users/v1"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func toUserLoginRequest(req *usersv1.LoginRequest) dtos.UserLoginRequest {
+	return dtos.UserLoginRequest{
+		ContactType:  types.CONTACT_TYPE(req.GetContactType()),
+		ContactValue: req.GetContactValue(),
+		Password:     req.GetPassword(),
+	}
+}
+
+func toSignUpRequest(req *usersv1.SignUpRequest) dtos.SignUpRequest {
+	return dtos.SignUpRequest{
+		Name:         req.GetName(),
+		ContactType:  types.CONTACT_TYPE(req.GetContactType()),
+		ContactValue: req.GetContactValue(),
+		Password:     req.GetPassword(),
+	}
+}
+
+func toUserUpdateRequest(req *usersv1.UpdateUserRequest) dtos.UserUpdateRequest {
+	request := dtos.UserUpdateRequest{
+		ID: req.GetId(),
+		UserUpdate: dtos.UserUpdate{
+			Name:         req.Name,
+			ContactValue: req.ContactValue,
+			BirthDate:    req.BirthDate,
+			Password:     req.Password,
+		},
+	}
+
+	if req.ContactType != nil {
+		contactType := types.CONTACT_TYPE(*req.ContactType)
+		request.ContactType = &contactType
+	}
+
+	if req.Language != nil {
+		language := types.LANGUAGE(*req.Language)
+		request.Language = &language
+	}
+
+	return request
+}
+
+func toTransitionUserStatusRequest(req *usersv1.TransitionUserStatusRequest) dtos.TransitionUserStatusRequest {
+	return dtos.TransitionUserStatusRequest{
+		ID:                   req.GetId(),
+		TransitionUserStatus: dtos.TransitionUserStatus{Status: types.USER_STATUS(req.GetStatus())},
+	}
+}
+
+// toUserMessage converts a user to its protobuf message. The password hash is
+// never exposed over gRPC.
+func toUserMessage(user dtos.User) *usersv1.User {
+	message := &usersv1.User{
+		Id:           user.ID,
+		Name:         user.Name,
+		ContactType:  string(user.ContactType),
+		ContactValue: user.ContactValue,
+		BirthDate:    user.BirthDate,
+		Status:       string(user.Status),
+		CreatedAt:    timestamppb.New(user.CreatedAt),
+	}
+
+	if user.Language != nil {
+		language := string(*user.Language)
+		message.Language = &language
+	}
+
+	if user.UpdatedAt != nil {
+		message.UpdatedAt = timestamppb.New(*user.UpdatedAt)
+	}
+
+	return message
+}
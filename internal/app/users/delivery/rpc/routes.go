@@ -0,0 +1,15 @@
+package rpc
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_grpc"
+	usersv1 "github.com/DoWithLogic/golang-clean-architecture/pkg/pb/users/v1"
+)
+
+func (h *handlers) MapServices(server *app_grpc.Server) {
+	usersv1.RegisterUserServiceServer(server, h)
+
+	server.AllowUnauthenticated(
+		usersv1.UserService_Login_FullMethodName,
+		usersv1.UserService_SignUp_FullMethodName,
+	)
+}
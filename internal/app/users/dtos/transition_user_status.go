@@ -17,6 +17,17 @@ type TransitionUserStatusRequest struct {
 	TransitionUserStatus
 }
 
+// TransitionUserStatusCommand is the message other services send to transition
+// the status of a user.
+type TransitionUserStatusCommand struct {
+	ID int64 `json:"id"`
+	TransitionUserStatus
+}
+
+func (c TransitionUserStatusCommand) ToRequest() TransitionUserStatusRequest {
+	return TransitionUserStatusRequest{ID: c.ID, TransitionUserStatus: c.TransitionUserStatus}
+}
+
 func (u TransitionUserStatusRequest) Validate() error {
 	return validation.ValidateStruct(&u,
 		validation.Field(&u.ID, validation.Required),
@@ -31,3 +42,12 @@ func (t TransitionUserStatusRequest) ToUpdateStatusEntity() *entities.UpdateUser
 		UpdatedAt: time.Now(),
 	}
 }
+
+func (t TransitionUserStatusRequest) ToUserStatusChangedEvent(from types.USER_STATUS) entities.UserStatusChanged {
+	return entities.UserStatusChanged{
+		UserID:     t.ID,
+		From:       from,
+		To:         t.Status,
+		OccurredAt: time.Now(),
+	}
+}
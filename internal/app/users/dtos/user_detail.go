@@ -1,8 +1,6 @@
 package dtos
 
 import (
-	"net/url"
-
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
 )
 
@@ -19,8 +17,7 @@ type UserDetailByIDRequest struct {
 }
 
 func (u UserDetailByContactValueRequest) ToUserDetailOption() entities.UserDetailOption {
-	decodedEmail, _ := url.QueryUnescape(u.ContactValue)
-	return entities.WithContactValue(decodedEmail)
+	return entities.WithContactValue(u.ContactValue)
 }
 
 func (u UserDetailByIDRequest) ToUserDetailOption() entities.UserDetailOption {
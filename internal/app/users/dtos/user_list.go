@@ -0,0 +1,58 @@
+package dtos
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/invopop/validation"
+)
+
+const MaxUsersPerPage = 100
+
+type ListUsersRequest struct {
+	Status      *types.USER_STATUS  `json:"status" query:"status"`
+	ContactType *types.CONTACT_TYPE `json:"contact_type" query:"contact_type"`
+	Search      *string             `json:"search" query:"search"`
+	Page        int                 `json:"page" query:"page"`
+	PerPage     int                 `json:"per_page" query:"per_page"`
+}
+
+type UserList struct {
+	Users   []User `json:"users"`
+	Total   int64  `json:"total"`
+	Page    int    `json:"page"`
+	PerPage int    `json:"per_page"`
+}
+
+func (l ListUsersRequest) Validate() error {
+	return validation.ValidateStruct(&l,
+		validation.Field(&l.Status, validation.NilOrNotEmpty, validation.In(types.ACTIVE, types.PENDING, types.REJECT, types.BANNED)),
+		validation.Field(&l.ContactType, validation.NilOrNotEmpty, validation.In(types.CONTACT_TYPE_EMAIL, types.CONTACT_TYPE_PHONE)),
+		validation.Field(&l.Page, validation.Required, validation.Min(1)),
+		validation.Field(&l.PerPage, validation.Required, validation.Min(1), validation.Max(MaxUsersPerPage)),
+	)
+}
+
+func (l ListUsersRequest) ToUserFilter() entities.UserFilter {
+	return entities.UserFilter{
+		Status:      l.Status,
+		ContactType: l.ContactType,
+		Search:      l.Search,
+		Limit:       l.PerPage,
+		Offset:      (l.Page - 1) * l.PerPage,
+	}
+}
+
+func ToUserListDTO(users []entities.User, total int64, request ListUsersRequest) UserList {
+	list := UserList{
+		Users:   make([]User, 0, len(users)),
+		Total:   total,
+		Page:    request.Page,
+		PerPage: request.PerPage,
+	}
+
+	for _, user := range users {
+		list.Users = append(list.Users, ToUserDTO(user))
+	}
+
+	return list
+}
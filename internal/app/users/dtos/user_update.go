@@ -1,6 +1,7 @@
 package dtos
 
 import (
+	"sort"
 	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
@@ -33,3 +34,44 @@ func (u UserUpdateRequest) ToUpdateUserEntity(encryptedPassword *string) *entiti
 		UpdatedAt:    time.Now(),
 	}
 }
+
+// ApplyTo returns a copy of the user with the requested profile changes applied.
+func (u UserUpdate) ApplyTo(user entities.User) entities.User {
+	if u.Name != nil {
+		user.Name = *u.Name
+	}
+
+	if u.ContactType != nil {
+		user.ContactType = *u.ContactType
+	}
+
+	if u.ContactValue != nil {
+		user.ContactValue = *u.ContactValue
+	}
+
+	return user
+}
+
+func (u UserUpdateRequest) ToUserUpdatedEvent() entities.UserUpdated {
+	fields := make([]string, 0, 6)
+	for field, isSet := range map[string]bool{
+		"name":          u.Name != nil,
+		"contact_type":  u.ContactType != nil,
+		"contact_value": u.ContactValue != nil,
+		"birth_date":    u.BirthDate != nil,
+		"language":      u.Language != nil,
+		"password":      u.Password != nil,
+	} {
+		if isSet {
+			fields = append(fields, field)
+		}
+	}
+
+	sort.Strings(fields)
+
+	return entities.UserUpdated{
+		UserID:     u.ID,
+		Fields:     fields,
+		OccurredAt: time.Now(),
+	}
+}
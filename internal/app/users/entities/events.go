@@ -0,0 +1,65 @@
+package entities
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+)
+
+const UserAggregateType = "user"
+
+const (
+	UserSignedUpEventType      = "user.signed_up"
+	UserUpdatedEventType       = "user.updated"
+	UserStatusChangedEventType = "user.status_changed"
+)
+
+// UserSignedUp is recorded when a new user registers.
+type UserSignedUp struct {
+	UserID       int64              `json:"user_id"`
+	Name         string             `json:"name"`
+	ContactType  types.CONTACT_TYPE `json:"contact_type"`
+	ContactValue string             `json:"contact_value"`
+	Status       types.USER_STATUS  `json:"status"`
+	OccurredAt   time.Time          `json:"occurred_at"`
+}
+
+func (e UserSignedUp) AggregateType() string { return UserAggregateType }
+func (e UserSignedUp) AggregateID() string   { return strconv.FormatInt(e.UserID, 10) }
+func (UserSignedUp) EventType() string       { return UserSignedUpEventType }
+
+// UserUpdated is recorded when a user changes their profile. Fields lists the
+// names of the changed fields, values are never included.
+type UserUpdated struct {
+	UserID     int64     `json:"user_id"`
+	Fields     []string  `json:"fields"`
+	OccurredAt time.Time `json:"occurred_at"`
+}
+
+func (e UserUpdated) AggregateType() string { return UserAggregateType }
+func (e UserUpdated) AggregateID() string   { return strconv.FormatInt(e.UserID, 10) }
+func (UserUpdated) EventType() string       { return UserUpdatedEventType }
+
+// UserStatusChanged is recorded when a user transitions to another status.
+type UserStatusChanged struct {
+	UserID     int64             `json:"user_id"`
+	From       types.USER_STATUS `json:"from"`
+	To         types.USER_STATUS `json:"to"`
+	OccurredAt time.Time         `json:"occurred_at"`
+}
+
+func (e UserStatusChanged) AggregateType() string { return UserAggregateType }
+func (e UserStatusChanged) AggregateID() string   { return strconv.FormatInt(e.UserID, 10) }
+func (UserStatusChanged) EventType() string       { return UserStatusChangedEventType }
+
+func (u User) ToUserSignedUp() UserSignedUp {
+	return UserSignedUp{
+		UserID:       u.ID,
+		Name:         u.Name,
+		ContactType:  u.ContactType,
+		ContactValue: u.ContactValue,
+		Status:       u.Status,
+		OccurredAt:   time.Now(),
+	}
+}
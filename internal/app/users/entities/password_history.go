@@ -0,0 +1,20 @@
+package entities
+
+import "time"
+
+type PasswordHistory struct {
+	ID        int64     `gorm:"column:id;primaryKey;autoIncrement"`
+	UserID    int64     `gorm:"column:user_id"`
+	Password  string    `gorm:"column:password"`
+	CreatedAt time.Time `gorm:"column:created_at"`
+}
+
+func (PasswordHistory) TableName() string { return "user_password_histories" }
+
+func NewPasswordHistory(userID int64, encryptedPassword string) *PasswordHistory {
+	return &PasswordHistory{
+		UserID:    userID,
+		Password:  encryptedPassword,
+		CreatedAt: time.Now(),
+	}
+}
@@ -3,6 +3,9 @@ package entities
 type UserDetailRequest struct {
 	ID           *int64
 	ContactValue *string
+	// Credentials is set by the lookups that check the password. They bypass
+	// the cache, which does not keep the password.
+	Credentials bool
 }
 
 type UserDetailOption interface {
@@ -20,3 +23,8 @@ func WithID(id int64) UserDetailOption {
 func WithContactValue(contactValue string) UserDetailOption {
 	return userDetailOptionFn(func(r *UserDetailRequest) { r.ContactValue = &contactValue })
 }
+
+// WithCredentials loads the password of the user along with it.
+func WithCredentials() UserDetailOption {
+	return userDetailOptionFn(func(r *UserDetailRequest) { r.Credentials = true })
+}
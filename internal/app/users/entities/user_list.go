@@ -0,0 +1,17 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+)
+
+// UserFilter narrows down and paginates a list of users.
+type UserFilter struct {
+	Status        *types.USER_STATUS
+	ContactType   *types.CONTACT_TYPE
+	Search        *string    // Matches a part of the name or the contact value.
+	CreatedBefore *time.Time // Keeps the users created before it.
+	Limit         int
+	Offset        int
+}
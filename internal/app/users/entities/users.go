@@ -15,7 +15,7 @@ type User struct {
 	ContactType  types.CONTACT_TYPE `gorm:"column:contact_type"`
 	ContactValue string             `gorm:"column:contact_value"`
 	BirthDate    *string            `gorm:"column:birth_date"`
-	Language     *types.LANGUAGE    `gorm:"column:language"`
+	Language     *types.LANGUAGE    `gorm:"column:language;default:ID"`
 	Password     string             `gorm:"column:password"`
 	Status       types.USER_STATUS  `gorm:"column:status"`
 	CreatedAt    time.Time          `gorm:"column:created_at"`
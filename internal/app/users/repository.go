@@ -2,16 +2,25 @@ package users
 
 import (
 	"context"
-	"database/sql"
+	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
 )
 
+// Repository takes part in the transaction of a datasources.UnitOfWork carried
+// by the context.
 type Repository interface {
-	WithTx(ctx context.Context, opt *sql.TxOptions, cb func(tx Repository) error) error
-
 	AddUser(ctx context.Context, user *entities.User) error
 	IsUserExists(ctx context.Context, contactValue string) bool
 	UserDetail(ctx context.Context, opts ...entities.UserDetailOption) (user entities.User, err error)
 	UpdateUser(ctx context.Context, user *entities.UpdateUser) error
+	Users(ctx context.Context, filter entities.UserFilter) (users []entities.User, total int64, err error)
+	UsersByIDs(ctx context.Context, ids []int64) (users []entities.User, err error)
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (purged int64, err error)
+
+	AddPasswordHistory(ctx context.Context, history *entities.PasswordHistory) error
+	PasswordHistories(ctx context.Context, userID int64, limit int) (histories []entities.PasswordHistory, err error)
+
+	AddEvents(ctx context.Context, events ...outbox.Event) error
 }
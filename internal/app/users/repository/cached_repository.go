@@ -0,0 +1,110 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/cache"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+)
+
+// cachedRepository decorates a users.Repository with a read-through cache for
+// UserDetail. Writes that change a user invalidate its cached entries, once the
+// surrounding transaction commits. The password is never cached, lookups with
+// entities.WithCredentials read the database.
+type cachedRepository struct {
+	users.Repository
+
+	cache *cache.Cache[entities.User]
+}
+
+func NewCachedRepository(repo users.Repository, c *cache.Cache[entities.User]) users.Repository {
+	return &cachedRepository{Repository: repo, cache: c}
+}
+
+func userIDCacheKey(id int64) string { return fmt.Sprintf("id:%d", id) }
+
+func userContactCacheKey(contactValue string) string { return fmt.Sprintf("contact:%s", contactValue) }
+
+func (r *cachedRepository) AddUser(ctx context.Context, user *entities.User) error {
+	if err := r.Repository.AddUser(ctx, user); err != nil {
+		return err
+	}
+
+	// Drop a cached "not found" for the new contact value.
+	return r.invalidate(ctx, userContactCacheKey(user.ContactValue))
+}
+
+func (r *cachedRepository) UserDetail(ctx context.Context, opts ...entities.UserDetailOption) (entities.User, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UserDetailCachedRepo")
+	defer span.End()
+
+	request := new(entities.UserDetailRequest)
+	for _, opt := range opts {
+		opt.Apply(request)
+	}
+
+	// Reads inside a transaction may see uncommitted data and must not be cached.
+	key, ok := r.cacheKey(request)
+	if datasources.InTx(ctx) || !ok || request.Credentials {
+		return r.Repository.UserDetail(ctx, opts...)
+	}
+
+	user, err := r.cache.GetOrLoad(ctx, key, func(ctx context.Context) (entities.User, error) {
+		user, err := r.Repository.UserDetail(ctx, opts...)
+		if errors.Is(err, app_error.ErrUserNotFound) {
+			return user, errors.Join(cache.ErrNotFound, err)
+		}
+
+		user.Password = ""
+
+		return user, err
+	})
+	if errors.Is(err, cache.ErrNotFound) {
+		return user, response.NotFound(app_error.ErrUserNotFound)
+	}
+
+	return user, err
+}
+
+func (r *cachedRepository) UpdateUser(ctx context.Context, user *entities.UpdateUser) error {
+	keys := []string{userIDCacheKey(user.ID)}
+
+	if current, err := r.Repository.UserDetail(ctx, entities.WithID(user.ID)); err == nil {
+		keys = append(keys, userContactCacheKey(current.ContactValue))
+	}
+
+	if user.ContactValue != nil {
+		keys = append(keys, userContactCacheKey(*user.ContactValue))
+	}
+
+	if err := r.Repository.UpdateUser(ctx, user); err != nil {
+		return err
+	}
+
+	return r.invalidate(ctx, keys...)
+}
+
+// cacheKey returns the cache key for lookups by exactly one of ID or contact value.
+func (r *cachedRepository) cacheKey(request *entities.UserDetailRequest) (string, bool) {
+	switch {
+	case request.ID != nil && request.ContactValue == nil:
+		return userIDCacheKey(*request.ID), true
+	case request.ContactValue != nil && request.ID == nil:
+		return userContactCacheKey(*request.ContactValue), true
+	default:
+		return "", false
+	}
+}
+
+func (r *cachedRepository) invalidate(ctx context.Context, keys ...string) error {
+	return datasources.AfterCommit(ctx, func(ctx context.Context) error {
+		return r.cache.Delete(ctx, keys...)
+	})
+}
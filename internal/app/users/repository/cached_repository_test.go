@@ -0,0 +1,57 @@
+package repository
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/cache"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubRepository serves a single user and counts the lookups.
+type stubRepository struct {
+	users.Repository
+
+	user    entities.User
+	lookups int
+}
+
+func (r *stubRepository) UserDetail(ctx context.Context, opts ...entities.UserDetailOption) (entities.User, error) {
+	r.lookups++
+	return r.user, nil
+}
+
+func TestCachedRepository_DoesNotCachePasswords(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	stub := &stubRepository{user: entities.User{ID: 1, ContactValue: "john@example.com", Password: "hashed-password"}}
+	repo := NewCachedRepository(stub, cache.New[entities.User]("users", client, cache.Config{TTL: time.Minute}))
+	ctx := context.Background()
+
+	user, err := repo.UserDetail(ctx, entities.WithID(1))
+	require.NoError(t, err)
+	assert.Empty(t, user.Password)
+
+	for _, key := range mr.Keys() {
+		value, _ := mr.Get(key)
+		assert.False(t, strings.Contains(value, "hashed-password"), "the password is written to Redis under %s", key)
+	}
+
+	_, err = repo.UserDetail(ctx, entities.WithID(1))
+	require.NoError(t, err)
+	assert.Equal(t, 1, stub.lookups, "the second lookup is cached")
+
+	user, err = repo.UserDetail(ctx, entities.WithID(1), entities.WithCredentials())
+	require.NoError(t, err)
+	assert.Equal(t, "hashed-password", user.Password, "credential lookups read the repository")
+	assert.Equal(t, 2, stub.lookups)
+}
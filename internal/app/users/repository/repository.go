@@ -2,12 +2,14 @@ package repository
 
 import (
 	"context"
-	"database/sql"
 	"errors"
+	"fmt"
+	"time"
 
-	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
 	"gorm.io/gorm"
@@ -21,23 +23,11 @@ func NewRepository(db *gorm.DB) *repository {
 	return &repository{db: db}
 }
 
-func (r *repository) WithTx(ctx context.Context, opt *sql.TxOptions, cb func(tx users.Repository) error) error {
-	tx := r.db.Begin(opt)
-
-	if err := cb(&repository{db: tx}); err != nil {
-		if errRollback := tx.Rollback(); errRollback != nil {
-			return err
-		}
-	}
-
-	return tx.Commit().Error
-}
-
 func (r *repository) AddUser(ctx context.Context, user *entities.User) error {
 	ctx, span := instrumentation.NewTraceSpan(ctx, "AddUserRepo")
 	defer span.End()
 
-	return r.db.WithContext(ctx).Create(user).Error
+	return translateError(datasources.Conn(ctx, r.db).Create(user).Error)
 }
 
 func (r *repository) IsUserExists(ctx context.Context, contactValue string) bool {
@@ -45,7 +35,7 @@ func (r *repository) IsUserExists(ctx context.Context, contactValue string) bool
 	defer span.End()
 
 	var count int64
-	if err := r.db.WithContext(ctx).Model(&entities.User{}).Where("contact_value = ?", contactValue).Count(&count).Error; err != nil {
+	if err := datasources.Conn(ctx, r.db).Model(&entities.User{}).Where("contact_value = ?", contactValue).Count(&count).Error; err != nil {
 		return false
 	}
 
@@ -61,7 +51,7 @@ func (r *repository) UserDetail(ctx context.Context, opts ...entities.UserDetail
 		opt.Apply(request)
 	}
 
-	baseQuery := r.db.WithContext(ctx).Model(&entities.User{})
+	baseQuery := datasources.Conn(ctx, r.db).Model(&entities.User{})
 	if request.ID != nil {
 		baseQuery = baseQuery.Where("id = ?", request.ID)
 	}
@@ -85,5 +75,109 @@ func (r *repository) UpdateUser(ctx context.Context, user *entities.UpdateUser)
 	ctx, span := instrumentation.NewTraceSpan(ctx, "UpdateUserRepo")
 	defer span.End()
 
-	return r.db.WithContext(ctx).Model(&entities.User{}).Where("id = ?", user.ID).Updates(user).Error
+	return translateError(datasources.Conn(ctx, r.db).Model(&entities.User{}).Where("id = ?", user.ID).Updates(user).Error)
+}
+
+func (r *repository) Users(ctx context.Context, filter entities.UserFilter) (users []entities.User, total int64, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UsersRepo")
+	defer span.End()
+
+	baseQuery := datasources.Conn(ctx, r.db).Model(&entities.User{})
+	if filter.Status != nil {
+		baseQuery = baseQuery.Where("status = ?", filter.Status)
+	}
+
+	if filter.ContactType != nil {
+		baseQuery = baseQuery.Where("contact_type = ?", filter.ContactType)
+	}
+
+	if filter.CreatedBefore != nil {
+		baseQuery = baseQuery.Where("created_at < ?", filter.CreatedBefore)
+	}
+
+	if filter.Search != nil && *filter.Search != "" {
+		pattern := "%" + *filter.Search + "%"
+		baseQuery = baseQuery.Where(fmt.Sprintf("name %[1]s ? OR contact_value %[1]s ?", r.likeOperator()), pattern, pattern)
+	}
+
+	if err := baseQuery.Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	err = baseQuery.Order("id ASC").Limit(filter.Limit).Offset(filter.Offset).Find(&users).Error
+
+	return users, total, err
+}
+
+func (r *repository) UsersByIDs(ctx context.Context, ids []int64) (users []entities.User, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UsersByIDsRepo")
+	defer span.End()
+
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	err = datasources.Conn(ctx, r.db).Where("id IN ?", ids).Find(&users).Error
+
+	return users, err
+}
+
+// PurgeDeletedUsers permanently deletes the users soft deleted before
+// deletedBefore. Their password histories are deleted by the foreign key.
+func (r *repository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "PurgeDeletedUsersRepo")
+	defer span.End()
+
+	result := datasources.Conn(ctx, r.db).Unscoped().Where("deleted_at < ?", deletedBefore).Delete(&entities.User{})
+
+	return result.RowsAffected, result.Error
+}
+
+func (r *repository) AddPasswordHistory(ctx context.Context, history *entities.PasswordHistory) error {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "AddPasswordHistoryRepo")
+	defer span.End()
+
+	return datasources.Conn(ctx, r.db).Create(history).Error
+}
+
+func (r *repository) PasswordHistories(ctx context.Context, userID int64, limit int) (histories []entities.PasswordHistory, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "PasswordHistoriesRepo")
+	defer span.End()
+
+	err = datasources.Conn(ctx, r.db).
+		Where("user_id = ?", userID).
+		Order("created_at DESC").
+		Order("id DESC").
+		Limit(limit).
+		Find(&histories).Error
+
+	return histories, err
+}
+
+func (r *repository) AddEvents(ctx context.Context, events ...outbox.Event) error {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "AddEventsRepo")
+	defer span.End()
+
+	return outbox.Add(ctx, datasources.Conn(ctx, r.db), events...)
+}
+
+// likeOperator returns a case insensitive LIKE. MySQL compares with the case
+// insensitive collation of the table, Postgres needs ILIKE.
+func (r *repository) likeOperator() string {
+	if r.db.Dialector.Name() == datasources.DriverPostgres {
+		return "ILIKE"
+	}
+
+	return "LIKE"
+}
+
+// translateError maps a unique violation on the contact to a conflict. Both
+// drivers are opened with TranslateError, so the violation is reported as
+// gorm.ErrDuplicatedKey on MySQL and Postgres alike.
+func translateError(err error) error {
+	if errors.Is(err, gorm.ErrDuplicatedKey) {
+		return response.Conflict(app_error.ErrUserAlreadyExists)
+	}
+
+	return err
 }
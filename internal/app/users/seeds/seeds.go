@@ -0,0 +1,155 @@
+// Package seeds seeds users through the users repository.
+package seeds
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/invopop/validation"
+)
+
+// DefaultPassword is the password of the generated users.
+const DefaultPassword = "Seed!Passw0rd"
+
+// User is a user to seed, as written in the fixtures.
+type User struct {
+	Name         string             `json:"name" yaml:"name"`
+	ContactType  types.CONTACT_TYPE `json:"contact_type" yaml:"contact_type"`
+	ContactValue string             `json:"contact_value" yaml:"contact_value"`
+	Password     string             `json:"password" yaml:"password"`
+	Status       types.USER_STATUS  `json:"status" yaml:"status"`
+	Language     *types.LANGUAGE    `json:"language" yaml:"language"`
+	BirthDate    *string            `json:"birth_date" yaml:"birth_date"`
+}
+
+func (u User) Validate() error {
+	return validation.ValidateStruct(&u,
+		validation.Field(&u.Name, validation.Required),
+		validation.Field(&u.ContactType, validation.Required, validation.In(types.CONTACT_TYPE_EMAIL, types.CONTACT_TYPE_PHONE)),
+		validation.Field(&u.ContactValue, validation.Required),
+		validation.Field(&u.Password, validation.Required),
+		validation.Field(&u.Status, validation.Required, validation.In(types.ACTIVE, types.PENDING, types.REJECT, types.BANNED)),
+		validation.Field(&u.Language, validation.NilOrNotEmpty, validation.In(types.LANGUAGE_EN, types.LANGUAGE_ID)),
+	)
+}
+
+// ToUserEntity returns the user with the password hashed like on sign up.
+func (u User) ToUserEntity(crypto *encryptions.Crypto) *entities.User {
+	return &entities.User{
+		Name:         u.Name,
+		ContactType:  u.ContactType,
+		ContactValue: u.ContactValue,
+		BirthDate:    u.BirthDate,
+		Language:     u.Language,
+		Password:     crypto.EncodeSHA256(u.Password),
+		Status:       u.Status,
+	}
+}
+
+// Generated returns a user in every status for every contact type, all with
+// DefaultPassword.
+func Generated() []User {
+	statuses := []types.USER_STATUS{types.ACTIVE, types.PENDING, types.REJECT, types.BANNED}
+
+	var seeded []User
+	for _, status := range statuses {
+		seeded = append(seeded,
+			User{
+				Name:         fmt.Sprintf("%s Email User", status),
+				ContactType:  types.CONTACT_TYPE_EMAIL,
+				ContactValue: fmt.Sprintf("seed.%s@example.com", status),
+				Password:     DefaultPassword,
+				Status:       status,
+			},
+			User{
+				Name:         fmt.Sprintf("%s Phone User", status),
+				ContactType:  types.CONTACT_TYPE_PHONE,
+				ContactValue: fmt.Sprintf("+6281%08d", len(seeded)),
+				Password:     DefaultPassword,
+				Status:       status,
+			},
+		)
+	}
+
+	return seeded
+}
+
+// FromFixture reads users from a JSON or YAML fixture file of fsys.
+func FromFixture(fsys fs.FS, name string) ([]User, error) {
+	var fixture struct {
+		Users []User `json:"users" yaml:"users"`
+	}
+
+	if err := seed.Decode(fsys, name, &fixture); err != nil {
+		return nil, err
+	}
+
+	return fixture.Users, nil
+}
+
+// Dependencies of the users seeder.
+type Dependencies struct {
+	Repo       users.Repository
+	UnitOfWork datasources.UnitOfWork
+	Crypto     *encryptions.Crypto
+}
+
+type usersSeeder struct {
+	name  string
+	deps  Dependencies
+	users []User
+}
+
+// NewUsersSeeder creates a seeder named name that adds users with their
+// password history. Users whose contact value is taken are skipped. Seeding
+// does not publish sign up events.
+func NewUsersSeeder(name string, deps Dependencies, users []User) seed.Seeder {
+	return &usersSeeder{name: name, deps: deps, users: users}
+}
+
+func (s *usersSeeder) Name() string { return s.name }
+
+func (s *usersSeeder) Seed(ctx context.Context) (result seed.Result, err error) {
+	for _, user := range s.users {
+		if err := user.Validate(); err != nil {
+			return result, fmt.Errorf("user %q: %w", user.ContactValue, err)
+		}
+	}
+
+	for _, user := range s.users {
+		if s.deps.Repo.IsUserExists(ctx, user.ContactValue) {
+			result.Skipped++
+			continue
+		}
+
+		err := s.deps.UnitOfWork.Do(ctx, func(ctx context.Context) error {
+			newUser := user.ToUserEntity(s.deps.Crypto)
+			if err := s.deps.Repo.AddUser(ctx, newUser); err != nil {
+				return err
+			}
+
+			return s.deps.Repo.AddPasswordHistory(ctx, entities.NewPasswordHistory(newUser.ID, newUser.Password))
+		})
+
+		switch {
+		case errors.Is(err, app_error.ErrUserAlreadyExists):
+			// Taken by a deleted user, or by a concurrent run.
+			result.Skipped++
+		case err != nil:
+			return result, fmt.Errorf("user %q: %w", user.ContactValue, err)
+		default:
+			result.Created++
+		}
+	}
+
+	return result, nil
+}
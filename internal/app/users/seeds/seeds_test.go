@@ -0,0 +1,110 @@
+package seeds_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/seeds"
+	mocks "github.com/DoWithLogic/golang-clean-architecture/mocks/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// unitOfWork runs fn without a transaction.
+type unitOfWork struct{}
+
+func (unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }
+
+func TestUsersSeeder_Seed(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	repo := mocks.NewMockRepository(ctrl)
+	crypto := encryptions.NewCrypto("DoWithLogic!@#")
+
+	users := []seeds.User{
+		{Name: "Existing", ContactType: types.CONTACT_TYPE_EMAIL, ContactValue: "existing@example.com", Password: "Passw0rd!", Status: types.ACTIVE},
+		{Name: "New", ContactType: types.CONTACT_TYPE_PHONE, ContactValue: "+6281200000001", Password: "Passw0rd!", Status: types.BANNED},
+		{Name: "Deleted", ContactType: types.CONTACT_TYPE_EMAIL, ContactValue: "deleted@example.com", Password: "Passw0rd!", Status: types.PENDING},
+	}
+
+	repo.EXPECT().IsUserExists(gomock.Any(), "existing@example.com").Return(true)
+
+	repo.EXPECT().IsUserExists(gomock.Any(), "+6281200000001").Return(false)
+	repo.EXPECT().AddUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user *entities.User) error {
+		assert.Equal(t, crypto.EncodeSHA256("Passw0rd!"), user.Password, "passwords are hashed like on sign up")
+		assert.Equal(t, types.BANNED, user.Status)
+		user.ID = 7
+		return nil
+	})
+	repo.EXPECT().AddPasswordHistory(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, history *entities.PasswordHistory) error {
+		assert.EqualValues(t, 7, history.UserID)
+		assert.Equal(t, crypto.EncodeSHA256("Passw0rd!"), history.Password)
+		return nil
+	})
+
+	repo.EXPECT().IsUserExists(gomock.Any(), "deleted@example.com").Return(false)
+	repo.EXPECT().AddUser(gomock.Any(), gomock.Any()).Return(response.Conflict(app_error.ErrUserAlreadyExists))
+
+	seeder := seeds.NewUsersSeeder("users", seeds.Dependencies{Repo: repo, UnitOfWork: unitOfWork{}, Crypto: crypto}, users)
+
+	result, err := seeder.Seed(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, seed.Result{Created: 1, Skipped: 2}, result)
+	assert.Equal(t, "users", seeder.Name())
+}
+
+func TestUsersSeeder_SeedFailures(t *testing.T) {
+	crypto := encryptions.NewCrypto("DoWithLogic!@#")
+
+	t.Run("invalid fixture seeds nothing", func(t *testing.T) {
+		repo := mocks.NewMockRepository(gomock.NewController(t))
+
+		users := append(seeds.Generated(), seeds.User{Name: "Invalid", ContactType: types.CONTACT_TYPE_EMAIL, ContactValue: "invalid@example.com", Password: "x", Status: "DELETED"})
+		seeder := seeds.NewUsersSeeder("users", seeds.Dependencies{Repo: repo, UnitOfWork: unitOfWork{}, Crypto: crypto}, users)
+
+		_, err := seeder.Seed(context.Background())
+		assert.ErrorContains(t, err, "invalid@example.com")
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		repo := mocks.NewMockRepository(gomock.NewController(t))
+		errFailed := errors.New("connection lost")
+
+		repo.EXPECT().IsUserExists(gomock.Any(), gomock.Any()).Return(false)
+		repo.EXPECT().AddUser(gomock.Any(), gomock.Any()).Return(errFailed)
+
+		seeder := seeds.NewUsersSeeder("users", seeds.Dependencies{Repo: repo, UnitOfWork: unitOfWork{}, Crypto: crypto}, seeds.Generated()[:1])
+
+		_, err := seeder.Seed(context.Background())
+		assert.ErrorIs(t, err, errFailed)
+	})
+}
+
+func TestGenerated(t *testing.T) {
+	generated := seeds.Generated()
+
+	contactValues := make(map[string]bool)
+	combinations := make(map[types.USER_STATUS]map[types.CONTACT_TYPE]bool)
+	for _, user := range generated {
+		require.NoError(t, user.Validate())
+
+		contactValues[user.ContactValue] = true
+
+		if combinations[user.Status] == nil {
+			combinations[user.Status] = make(map[types.CONTACT_TYPE]bool)
+		}
+		combinations[user.Status][user.ContactType] = true
+	}
+
+	assert.Len(t, contactValues, len(generated), "contact values are unique")
+	for _, status := range []types.USER_STATUS{types.ACTIVE, types.PENDING, types.REJECT, types.BANNED} {
+		assert.Len(t, combinations[status], 2, "status %s has a user for every contact type", status)
+	}
+}
@@ -2,6 +2,7 @@ package users
 
 import (
 	"context"
+	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
 )
@@ -10,6 +11,10 @@ type Usecase interface {
 	Login(ctx context.Context, request dtos.UserLoginRequest) (response dtos.UserLoginResponse, err error)
 	SignUp(ctx context.Context, request dtos.SignUpRequest) error
 	UserDetail(ctx context.Context, request dtos.UserDetailRequest) (userData dtos.User, err error)
+	ListUsers(ctx context.Context, request dtos.ListUsersRequest) (userList dtos.UserList, err error)
+	UsersByIDs(ctx context.Context, ids []int64) (usersData []dtos.User, err error)
 	UserUpdate(ctx context.Context, request dtos.UserUpdateRequest) error
 	TransitionUserStatus(ctx context.Context, request dtos.TransitionUserStatusRequest) error
+	PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (purged int64, err error)
+	ExpirePendingUsers(ctx context.Context, createdBefore time.Time) (expired int, err error)
 }
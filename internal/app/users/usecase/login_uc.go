@@ -15,7 +15,7 @@ func (uc *usecase) Login(ctx context.Context, request dtos.UserLoginRequest) (re
 	ctx, span := instrumentation.NewTraceSpan(ctx, "LoginUC")
 	defer span.End()
 
-	userData, err := uc.repo.UserDetail(ctx, entities.WithContactValue(request.ContactValue))
+	userData, err := uc.repo.UserDetail(ctx, entities.WithContactValue(request.ContactValue), entities.WithCredentials())
 	if err != nil {
 		return result, err
 	}
@@ -0,0 +1,54 @@
+package usecase
+
+import (
+	"context"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+)
+
+// expireBatchSize is the number of pending users read at once by ExpirePendingUsers.
+const expireBatchSize = 100
+
+// PurgeDeletedUsers permanently deletes the users soft deleted before deletedBefore.
+func (uc *usecase) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (purged int64, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "PurgeDeletedUsersUC")
+	defer span.End()
+
+	return uc.repo.PurgeDeletedUsers(ctx, deletedBefore)
+}
+
+// ExpirePendingUsers rejects the users still PENDING that signed up before
+// createdBefore. Every user is transitioned like by TransitionUserStatus, so its
+// status change is published and its cached entries invalidated.
+func (uc *usecase) ExpirePendingUsers(ctx context.Context, createdBefore time.Time) (expired int, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "ExpirePendingUsersUC")
+	defer span.End()
+
+	status := types.PENDING
+	filter := entities.UserFilter{Status: &status, CreatedBefore: &createdBefore, Limit: expireBatchSize}
+
+	for {
+		// The rejected users leave the filter, so every batch starts at the first page.
+		users, _, err := uc.repo.Users(ctx, filter)
+		if err != nil {
+			return expired, err
+		}
+
+		for _, user := range users {
+			request := dtos.TransitionUserStatusRequest{ID: user.ID, TransitionUserStatus: dtos.TransitionUserStatus{Status: types.REJECT}}
+			if err := uc.TransitionUserStatus(ctx, request); err != nil {
+				return expired, err
+			}
+
+			expired++
+		}
+
+		if len(users) < expireBatchSize {
+			return expired, nil
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package usecase_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/usecase"
+	mocks "github.com/DoWithLogic/golang-clean-architecture/mocks/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/mock/gomock"
+)
+
+// unitOfWork runs fn without a transaction.
+type unitOfWork struct{}
+
+func (unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error { return fn(ctx) }
+
+func newUseCase(t *testing.T) (users.Usecase, *mocks.MockRepository) {
+	t.Helper()
+
+	repo := mocks.NewMockRepository(gomock.NewController(t))
+	policy, err := password.NewPolicy(password.PolicyConfig{})
+	require.NoError(t, err)
+
+	return usecase.NewUseCase(usecase.Dependencies{
+		Repositories: usecase.Repositories{Repo: repo, UnitOfWork: unitOfWork{}},
+		Pkgs: usecase.Pkgs{
+			AppJwt:         jwt.NewJWTFactory(jwt.JWTConfig{Key: "secret-key"}, nil),
+			Crypto:         encryptions.NewCrypto("DoWithLogic!@#"),
+			PasswordPolicy: policy,
+		},
+	}), repo
+}
+
+func TestPurgeDeletedUsers(t *testing.T) {
+	uc, repo := newUseCase(t)
+	deletedBefore := time.Now().Add(-30 * 24 * time.Hour)
+
+	repo.EXPECT().PurgeDeletedUsers(gomock.Any(), deletedBefore).Return(int64(2), nil)
+
+	purged, err := uc.PurgeDeletedUsers(context.Background(), deletedBefore)
+	require.NoError(t, err)
+	assert.EqualValues(t, 2, purged)
+}
+
+func TestExpirePendingUsers(t *testing.T) {
+	uc, repo := newUseCase(t)
+	createdBefore := time.Now().Add(-7 * 24 * time.Hour)
+
+	pending := make([]entities.User, 100)
+	for i := range pending {
+		pending[i] = entities.User{ID: int64(i + 1), Status: types.PENDING}
+	}
+
+	// A full batch is followed by another read, the rejected users left the filter.
+	first := repo.EXPECT().Users(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error) {
+		assert.Equal(t, types.PENDING, *filter.Status)
+		assert.Equal(t, createdBefore, *filter.CreatedBefore)
+		assert.Zero(t, filter.Offset)
+		return pending, 101, nil
+	})
+	repo.EXPECT().Users(gomock.Any(), gomock.Any()).Return([]entities.User{{ID: 101, Status: types.PENDING}}, int64(1), nil).After(first)
+
+	repo.EXPECT().UserDetail(gomock.Any(), gomock.Any()).Return(entities.User{Status: types.PENDING}, nil).Times(101)
+	repo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, user *entities.UpdateUser) error {
+		assert.Equal(t, types.REJECT, *user.Status)
+		return nil
+	}).Times(101)
+	repo.EXPECT().AddEvents(gomock.Any(), gomock.Any()).Return(nil).Times(101)
+
+	expired, err := uc.ExpirePendingUsers(context.Background(), createdBefore)
+	require.NoError(t, err)
+	assert.Equal(t, 101, expired)
+}
+
+func TestExpirePendingUsers_StopsOnError(t *testing.T) {
+	uc, repo := newUseCase(t)
+	errFailed := errors.New("connection lost")
+
+	repo.EXPECT().Users(gomock.Any(), gomock.Any()).Return([]entities.User{{ID: 1}, {ID: 2}}, int64(2), nil)
+	repo.EXPECT().UserDetail(gomock.Any(), gomock.Any()).Return(entities.User{Status: types.PENDING}, nil)
+	repo.EXPECT().UpdateUser(gomock.Any(), gomock.Any()).Return(errFailed)
+
+	expired, err := uc.ExpirePendingUsers(context.Background(), time.Now())
+	require.ErrorIs(t, err, errFailed)
+	assert.Zero(t, expired)
+}
@@ -0,0 +1,34 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/invopop/validation"
+	"github.com/samber/lo"
+)
+
+// validatePassword applies the password policy to a plain text password and reports
+// any violation as a validation error on the password field. When user is an existing
+// user, its current and last HistorySize passwords cannot be reused.
+func (uc *usecase) validatePassword(ctx context.Context, plain string, user entities.User) error {
+	opts := []password.ValidateOption{password.WithPersonalInfo(user.Name, user.ContactValue)}
+
+	if user.ID != 0 && uc.passwordPolicy.HistorySize() > 0 {
+		histories, err := uc.repo.PasswordHistories(ctx, user.ID, uc.passwordPolicy.HistorySize())
+		if err != nil {
+			return response.InternalServerError(err)
+		}
+
+		hashes := lo.Map(histories, func(h entities.PasswordHistory, _ int) string { return h.Password })
+		opts = append(opts, password.WithHistory(append(hashes, user.Password), uc.crypto.EncodeSHA256))
+	}
+
+	if err := uc.passwordPolicy.Validate(plain, opts...); err != nil {
+		return response.BadRequest(validation.Errors{"password": err})
+	}
+
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
@@ -17,9 +18,22 @@ func (uc *usecase) SignUp(ctx context.Context, request dtos.SignUpRequest) error
 		return response.Conflict(app_error.ErrUserAlreadyExists)
 	}
 
-	if err := uc.repo.AddUser(ctx, request.ToUserEntity(uc.crypto.EncodeSHA256(request.Password))); err != nil {
+	newUser := request.ToUserEntity(uc.crypto.EncodeSHA256(request.Password))
+	if err := uc.validatePassword(ctx, request.Password, *newUser); err != nil {
 		return err
 	}
 
-	return nil
+	return uc.uow.Do(ctx, func(ctx context.Context) error {
+		// Insert a copy, a retried transaction must not reuse the ID of the failed attempt.
+		user := *newUser
+		if err := uc.repo.AddUser(ctx, &user); err != nil {
+			return err
+		}
+
+		if err := uc.repo.AddPasswordHistory(ctx, entities.NewPasswordHistory(user.ID, user.Password)); err != nil {
+			return err
+		}
+
+		return uc.repo.AddEvents(ctx, user.ToUserSignedUp())
+	})
 }
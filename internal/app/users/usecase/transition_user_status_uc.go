@@ -12,9 +12,20 @@ func (uc *usecase) TransitionUserStatus(ctx context.Context, request dtos.Transi
 	ctx, span := instrumentation.NewTraceSpan(ctx, "TransitionUserStatusUC")
 	defer span.End()
 
-	if _, err := uc.repo.UserDetail(ctx, entities.WithID(request.ID)); err != nil {
+	user, err := uc.repo.UserDetail(ctx, entities.WithID(request.ID))
+	if err != nil {
 		return err
 	}
 
-	return uc.repo.UpdateUser(ctx, request.ToUpdateStatusEntity())
+	return uc.uow.Do(ctx, func(ctx context.Context) error {
+		if err := uc.repo.UpdateUser(ctx, request.ToUpdateStatusEntity()); err != nil {
+			return err
+		}
+
+		if user.Status == request.Status {
+			return nil
+		}
+
+		return uc.repo.AddEvents(ctx, request.ToUserStatusChangedEvent(user.Status))
+	})
 }
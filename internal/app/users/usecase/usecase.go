@@ -2,15 +2,20 @@ package usecase
 
 import (
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
 	"github.com/invopop/validation"
 )
 
 type usecase struct {
 	repo   users.Repository
+	uow    datasources.UnitOfWork
 	appJwt *jwt.JWTFactory
 	crypto *encryptions.Crypto
+
+	passwordPolicy *password.Policy
 }
 
 type Dependencies struct {
@@ -22,19 +27,25 @@ type Dependencies struct {
 type UseCases struct{}
 
 type Repositories struct {
-	Repo users.Repository
+	Repo       users.Repository
+	UnitOfWork datasources.UnitOfWork
 }
 
 type Pkgs struct {
 	AppJwt *jwt.JWTFactory
 	Crypto *encryptions.Crypto
+
+	PasswordPolicy *password.Policy
 }
 
 func (d Dependencies) toUsecase() *usecase {
 	return &usecase{
 		repo:   d.Repo,
+		uow:    d.UnitOfWork,
 		appJwt: d.AppJwt,
 		crypto: d.Crypto,
+
+		passwordPolicy: d.PasswordPolicy,
 	}
 }
 
@@ -42,7 +53,9 @@ func NewUseCase(d Dependencies) users.Usecase {
 	err := validation.ValidateStruct(&d,
 		validation.Field(&d.AppJwt, validation.Required),
 		validation.Field(&d.Crypto, validation.Required),
+		validation.Field(&d.PasswordPolicy, validation.Required),
 		validation.Field(&d.Repo, validation.Required),
+		validation.Field(&d.UnitOfWork, validation.Required),
 	)
 
 	if err != nil {
@@ -0,0 +1,39 @@
+package usecase
+
+import (
+	"context"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+)
+
+func (uc *usecase) ListUsers(ctx context.Context, request dtos.ListUsersRequest) (userList dtos.UserList, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "ListUsersUC")
+	defer span.End()
+
+	users, total, err := uc.repo.Users(ctx, request.ToUserFilter())
+	if err != nil {
+		return userList, err
+	}
+
+	return dtos.ToUserListDTO(users, total, request), nil
+}
+
+// UsersByIDs returns the users with the given IDs in no particular order. Unknown
+// IDs are skipped.
+func (uc *usecase) UsersByIDs(ctx context.Context, ids []int64) (usersData []dtos.User, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "UsersByIDsUC")
+	defer span.End()
+
+	users, err := uc.repo.UsersByIDs(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	usersData = make([]dtos.User, 0, len(users))
+	for _, user := range users {
+		usersData = append(usersData, dtos.ToUserDTO(user))
+	}
+
+	return usersData, nil
+}
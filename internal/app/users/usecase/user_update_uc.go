@@ -2,9 +2,7 @@ package usecase
 
 import (
 	"context"
-	"database/sql"
 
-	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
 	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
@@ -16,7 +14,8 @@ func (uc *usecase) UserUpdate(ctx context.Context, request dtos.UserUpdateReques
 	ctx, span := instrumentation.NewTraceSpan(ctx, "UserUpdateUC")
 	defer span.End()
 
-	if _, err := uc.repo.UserDetail(ctx, entities.WithID(request.ID)); err != nil {
+	user, err := uc.repo.UserDetail(ctx, entities.WithID(request.ID), entities.WithCredentials())
+	if err != nil {
 		return err
 	}
 
@@ -28,13 +27,27 @@ func (uc *usecase) UserUpdate(ctx context.Context, request dtos.UserUpdateReques
 
 	var encryptedPassword *string
 	if request.Password != nil {
+		if err := uc.validatePassword(ctx, *request.Password, request.ApplyTo(user)); err != nil {
+			return err
+		}
+
 		if newPassword := uc.crypto.EncodeSHA256(*request.Password); newPassword != "" {
 			encryptedPassword = &newPassword
 		}
 	}
 
-	err := uc.repo.WithTx(ctx, &sql.TxOptions{}, func(tx users.Repository) error {
-		return tx.UpdateUser(ctx, request.ToUpdateUserEntity(encryptedPassword))
+	err = uc.uow.Do(ctx, func(ctx context.Context) error {
+		if err := uc.repo.UpdateUser(ctx, request.ToUpdateUserEntity(encryptedPassword)); err != nil {
+			return err
+		}
+
+		if encryptedPassword != nil {
+			if err := uc.repo.AddPasswordHistory(ctx, entities.NewPasswordHistory(request.ID, *encryptedPassword)); err != nil {
+				return err
+			}
+		}
+
+		return uc.repo.AddEvents(ctx, request.ToUserUpdatedEvent())
 	})
 
 	if err != nil {
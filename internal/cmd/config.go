@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"fmt"
+	"maps"
+	"reflect"
+	"slices"
+
+	"github.com/spf13/cobra"
+)
+
+func newConfigCommand(opts *options) *cobra.Command {
+	config := &cobra.Command{
+		Use:   "config",
+		Short: "Inspect the configuration",
+	}
+
+	config.AddCommand(&cobra.Command{
+		Use:   "print",
+		Short: "Print the loaded configuration with its secrets masked",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			redacted := cfg.Redacted()
+
+			settings := flatten(redacted)
+
+			t := table{header: []string{"KEY", "VALUE"}}
+			for _, key := range slices.Sorted(maps.Keys(settings)) {
+				t.rows = append(t.rows, []string{key, settings[key]})
+			}
+
+			return opts.print(cmd.OutOrStdout(), redacted, t)
+		},
+	})
+
+	return config
+}
+
+// flatten returns the settings of the config struct v by dotted key, like the
+// keys of the environment overrides.
+func flatten(v any) map[string]string {
+	settings := make(map[string]string)
+
+	var walk func(prefix string, value reflect.Value)
+	walk = func(prefix string, value reflect.Value) {
+		if value.Kind() != reflect.Struct {
+			settings[prefix] = fmt.Sprint(value.Interface())
+			return
+		}
+
+		for i := range value.NumField() {
+			field := value.Type().Field(i)
+			if !field.IsExported() {
+				continue
+			}
+
+			key := field.Name
+			if prefix != "" {
+				key = prefix + "." + key
+			}
+
+			walk(key, value.Field(i))
+		}
+	}
+
+	walk("", reflect.ValueOf(v))
+
+	return settings
+}
@@ -0,0 +1,122 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"text/tabwriter"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/migration"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+func newMigrateCommand(opts *options) *cobra.Command {
+	migrate := &cobra.Command{
+		Use:   "migrate",
+		Short: "Migrate the configured database with the migrations embedded in the binary",
+	}
+
+	migrate.AddCommand(
+		&cobra.Command{
+			Use:   "up",
+			Short: "Apply all pending migrations",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(opts, func(ctx context.Context, out io.Writer, migrator *migration.Migrator) error {
+				results, err := migrator.Up(ctx)
+				printResults(out, results...)
+
+				if err == nil && len(results) == 0 {
+					fmt.Fprintln(out, "No pending migrations")
+				}
+
+				return err
+			}),
+		},
+		&cobra.Command{
+			Use:   "down",
+			Short: "Roll back the latest migration",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(opts, func(ctx context.Context, out io.Writer, migrator *migration.Migrator) error {
+				result, err := migrator.Down(ctx)
+				if result != nil {
+					printResults(out, result)
+				}
+
+				return err
+			}),
+		},
+		&cobra.Command{
+			Use:   "redo",
+			Short: "Roll back the latest migration and apply it again",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(opts, func(ctx context.Context, out io.Writer, migrator *migration.Migrator) error {
+				results, err := migrator.Redo(ctx)
+				printResults(out, results...)
+
+				return err
+			}),
+		},
+		&cobra.Command{
+			Use:   "status",
+			Short: "List the migrations and whether they are applied",
+			Args:  cobra.NoArgs,
+			RunE: withMigrator(opts, func(ctx context.Context, out io.Writer, migrator *migration.Migrator) error {
+				statuses, err := migrator.Status(ctx)
+				if err != nil {
+					return err
+				}
+
+				w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+				fmt.Fprintln(w, "VERSION\tSTATE\tAPPLIED AT\tSOURCE")
+				for _, status := range statuses {
+					appliedAt := "-"
+					if !status.AppliedAt.IsZero() {
+						appliedAt = status.AppliedAt.UTC().Format(time.RFC3339)
+					}
+
+					fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", status.Source.Version, status.State, appliedAt, status.Source.Path)
+				}
+
+				return w.Flush()
+			}),
+		},
+	)
+
+	return migrate
+}
+
+// withMigrator connects to the configured database and runs fn with a migrator
+// for it.
+func withMigrator(opts *options, fn func(ctx context.Context, out io.Writer, migrator *migration.Migrator) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := opts.loadConfig()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+
+		db, err := datasources.NewDB(ctx, cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer lo.Must(db.DB()).Close()
+
+		migrator, err := server.NewMigrator(db, cfg)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cmd.OutOrStdout(), migrator)
+	}
+}
+
+func printResults(out io.Writer, results ...*migration.Result) {
+	for _, result := range results {
+		fmt.Fprintf(out, "%-4s %s (%s)\n", result.Direction, result.Source.Path, result.Duration.Round(time.Millisecond))
+	}
+}
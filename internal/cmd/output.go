@@ -0,0 +1,53 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// Formats of the --output flag.
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+// table is the tabular form of a command result.
+type table struct {
+	header []string
+	rows   [][]string
+}
+
+// validateOutput fails on a format the commands cannot write.
+func (o *options) validateOutput() error {
+	switch o.output {
+	case outputTable, outputJSON:
+		return nil
+	default:
+		return fmt.Errorf("unsupported output format %q, want %s or %s", o.output, outputTable, outputJSON)
+	}
+}
+
+// print writes the result of a command as indented JSON of v, or as t with
+// aligned columns, depending on the --output flag.
+func (o *options) print(out io.Writer, v any, t table) error {
+	if o.output == outputJSON {
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+
+		return encoder.Encode(v)
+	}
+
+	w := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if len(t.header) > 0 {
+		fmt.Fprintln(w, strings.Join(t.header, "\t"))
+	}
+
+	for _, row := range t.rows {
+		fmt.Fprintln(w, strings.Join(row, "\t"))
+	}
+
+	return w.Flush()
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOptions_Print(t *testing.T) {
+	result := struct {
+		ID   int64  `json:"id"`
+		Name string `json:"name"`
+	}{ID: 7, Name: "Jane"}
+
+	t.Run("table", func(t *testing.T) {
+		var out bytes.Buffer
+		opts := &options{output: outputTable}
+
+		require.NoError(t, opts.print(&out, result, table{header: []string{"ID", "NAME"}, rows: [][]string{{"7", "Jane"}}}))
+		assert.Equal(t, "ID  NAME\n7   Jane\n", out.String())
+	})
+
+	t.Run("json", func(t *testing.T) {
+		var out bytes.Buffer
+		opts := &options{output: outputJSON}
+
+		require.NoError(t, opts.print(&out, result, table{}))
+		assert.JSONEq(t, `{"id":7,"name":"Jane"}`, out.String())
+	})
+
+	t.Run("unsupported", func(t *testing.T) {
+		assert.Error(t, (&options{output: "yaml"}).validateOutput())
+	})
+}
+
+func TestFlatten(t *testing.T) {
+	type nested struct {
+		TTL   time.Duration
+		Hosts []string
+	}
+
+	settings := flatten(struct {
+		Name   string
+		Cache  nested
+		hidden string
+	}{Name: "users", Cache: nested{TTL: 5 * time.Minute, Hosts: []string{"a", "b"}}, hidden: "x"})
+
+	assert.Equal(t, map[string]string{
+		"Name":        "users",
+		"Cache.TTL":   "5m0s",
+		"Cache.Hosts": "[a b]",
+	}, settings)
+}
+
+func TestParseStatus(t *testing.T) {
+	status, err := parseStatus("active")
+	require.NoError(t, err)
+	assert.EqualValues(t, "ACTIVE", status)
+
+	status, err = parseStatus("closed")
+	require.NoError(t, err)
+	assert.Equal(t, types.BANNED, status)
+
+	_, err = parseStatus("BANNED")
+	assert.Error(t, err)
+}
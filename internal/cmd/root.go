@@ -0,0 +1,51 @@
+// Package cmd implements the command line of the service.
+package cmd
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/config"
+	"github.com/spf13/cobra"
+)
+
+// options are shared by every command.
+type options struct {
+	configName string
+	output     string
+}
+
+func (o *options) loadConfig() (config.Config, error) {
+	return config.LoadConfig(o.configName)
+}
+
+// NewRootCommand returns the command line of the service. Without a subcommand
+// it serves the API, like the serve command.
+func NewRootCommand() *cobra.Command {
+	opts := new(options)
+
+	serve := newServeCommand(opts)
+
+	root := &cobra.Command{
+		Use:          "golang-clean-architecture",
+		Short:        "Users service",
+		SilenceUsage: true,
+		Args:         cobra.NoArgs,
+		RunE:         serve.RunE,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			return opts.validateOutput()
+		},
+	}
+
+	root.PersistentFlags().StringVarP(&opts.configName, "config", "c", "config", "name of the config file in the config directory, without extension")
+
+	root.PersistentFlags().StringVarP(&opts.output, "output", "o", outputTable, "output format of the admin commands, table or json")
+
+	root.AddCommand(
+		serve,
+		newMigrateCommand(opts),
+		newUserCommand(opts),
+		newTokenCommand(opts),
+		newConfigCommand(opts),
+		newSeedCommand(opts),
+	)
+
+	return root
+}
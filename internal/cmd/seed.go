@@ -0,0 +1,102 @@
+package cmd
+
+import (
+	"context"
+	"strconv"
+	"strings"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+// seedSet is a set listed by seed list.
+type seedSet struct {
+	Name         string   `json:"name"`
+	Description  string   `json:"description"`
+	Environments []string `json:"environments"`
+}
+
+func newSeedCommand(opts *options) *cobra.Command {
+	seedCmd := &cobra.Command{
+		Use:   "seed",
+		Short: "Seed the configured database with the seed sets of its environment",
+	}
+
+	seedCmd.AddCommand(
+		&cobra.Command{
+			Use:   "list",
+			Short: "List the seed sets and the environments they run in",
+			Args:  cobra.NoArgs,
+			RunE: func(cmd *cobra.Command, args []string) error {
+				// Listing does not touch the database.
+				registry, err := server.NewSeedRegistry(nil, nil)
+				if err != nil {
+					return err
+				}
+
+				sets := lo.Map(registry.Sets(), func(set seed.Set, _ int) seedSet {
+					return seedSet{Name: set.Name, Description: set.Description, Environments: set.Environments}
+				})
+
+				t := table{header: []string{"NAME", "ENVIRONMENTS", "DESCRIPTION"}}
+				for _, set := range sets {
+					t.rows = append(t.rows, []string{set.Name, strings.Join(set.Environments, ","), set.Description})
+				}
+
+				return opts.print(cmd.OutOrStdout(), sets, t)
+			},
+		},
+		&cobra.Command{
+			Use:   "run [set...]",
+			Short: "Run the given seed sets, or the sets of App.Environment without arguments",
+			Long: "Run seed sets against the configured database. Seeds skip the records that already exist,\n" +
+				"so running them again is safe. A set only runs in the environments it is meant for.",
+			RunE: withSeedRegistry(opts, func(ctx context.Context, cmd *cobra.Command, args []string, env string, registry *seed.Registry) error {
+				reports, err := registry.Run(ctx, env, args...)
+
+				t := table{header: []string{"SET", "SEEDER", "CREATED", "SKIPPED"}}
+				for _, report := range reports {
+					t.rows = append(t.rows, []string{report.Set, report.Seeder, strconv.Itoa(report.Created), strconv.Itoa(report.Skipped)})
+				}
+
+				if printErr := opts.print(cmd.OutOrStdout(), reports, t); printErr != nil {
+					return printErr
+				}
+
+				return err
+			}),
+		},
+	)
+
+	return seedCmd
+}
+
+// withSeedRegistry connects to the configured database and runs fn with the
+// seed registry and the environment of the config.
+func withSeedRegistry(opts *options, fn func(ctx context.Context, cmd *cobra.Command, args []string, env string, registry *seed.Registry) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := opts.loadConfig()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+
+		db, err := datasources.NewDB(ctx, cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer lo.Must(db.DB()).Close()
+
+		registry, err := server.NewSeedRegistry(db, encryptions.NewCrypto(cfg.Authentication.Key))
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cmd, args, cfg.App.Environment, registry)
+	}
+}
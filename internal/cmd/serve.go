@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"context"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
+	"github.com/spf13/cobra"
+)
+
+func newServeCommand(opts *options) *cobra.Command {
+	return &cobra.Command{
+		Use:   "serve",
+		Short: "Serve the HTTP, gRPC and GraphQL APIs",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			cfg, err := opts.loadConfig()
+			if err != nil {
+				return err
+			}
+
+			srv, err := server.NewServer(context.Background(), cfg)
+			if err != nil {
+				return err
+			}
+
+			return srv.Run()
+		},
+	}
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
+	"github.com/spf13/cobra"
+)
+
+// revokedToken is the result of token revoke.
+type revokedToken struct {
+	UserID    int64     `json:"user_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+func newTokenCommand(opts *options) *cobra.Command {
+	token := &cobra.Command{
+		Use:   "token",
+		Short: "Manage issued access tokens",
+	}
+
+	var tokenStdin bool
+
+	revoke := &cobra.Command{
+		Use:   "revoke [token]",
+		Short: "Revoke an access token until it expires",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: withUsers(opts, func(ctx context.Context, cmd *cobra.Command, args []string, module *server.UsersModule) error {
+			var tokenString string
+			switch {
+			case tokenStdin && len(args) == 0:
+				line, err := readLine(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+
+				tokenString = line
+			case !tokenStdin && len(args) == 1:
+				tokenString = args[0]
+			default:
+				return errors.New("pass the token as argument or with --token-stdin")
+			}
+
+			claims, err := module.JWT.VerifyJWT(ctx, tokenString)
+			if err != nil {
+				return err
+			}
+
+			if claims.ExpiresAt == nil || claims.Data == nil {
+				return errors.New("token has no expiry or user, it was not issued by this service")
+			}
+
+			if err := module.JWT.AddToBlacklist(ctx, tokenString, claims.ExpiresAt.Time); err != nil {
+				return err
+			}
+
+			result := revokedToken{UserID: claims.Data.ID, ExpiresAt: claims.ExpiresAt.UTC()}
+
+			return opts.print(cmd.OutOrStdout(), result, table{
+				header: []string{"USER ID", "REVOKED UNTIL"},
+				rows:   [][]string{{strconv.FormatInt(result.UserID, 10), result.ExpiresAt.Format(time.RFC3339)}},
+			})
+		}),
+	}
+
+	revoke.Flags().BoolVar(&tokenStdin, "token-stdin", false, "read the token from the first line of stdin")
+
+	token.AddCommand(revoke)
+
+	return token
+}
@@ -0,0 +1,284 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/samber/lo"
+	"github.com/spf13/cobra"
+)
+
+func newUserCommand(opts *options) *cobra.Command {
+	user := &cobra.Command{
+		Use:   "user",
+		Short: "Manage users through the users usecase",
+	}
+
+	user.AddCommand(
+		newUserCreateCommand(opts),
+		&cobra.Command{
+			Use:   "get <id|contact-value>",
+			Short: "Show a user",
+			Args:  cobra.ExactArgs(1),
+			RunE: withUsers(opts, func(ctx context.Context, cmd *cobra.Command, args []string, module *server.UsersModule) error {
+				return printUser(ctx, opts, cmd.OutOrStdout(), module, userDetailRequest(args[0]))
+			}),
+		},
+		&cobra.Command{
+			Use:   "set-status <id> <ACTIVE|PENDING|REJECT|CLOSED>",
+			Short: "Transition the status of a user",
+			Args:  cobra.ExactArgs(2),
+			RunE: withUsers(opts, func(ctx context.Context, cmd *cobra.Command, args []string, module *server.UsersModule) error {
+				id, err := parseUserID(args[0])
+				if err != nil {
+					return err
+				}
+
+				status, err := parseStatus(args[1])
+				if err != nil {
+					return err
+				}
+
+				request := dtos.TransitionUserStatusRequest{ID: id, TransitionUserStatus: dtos.TransitionUserStatus{Status: status}}
+				if err := module.UseCase.TransitionUserStatus(ctx, request); err != nil {
+					return err
+				}
+
+				return printUser(ctx, opts, cmd.OutOrStdout(), module, dtos.UserDetailByIDRequest{ID: id})
+			}),
+		},
+		newUserResetPasswordCommand(opts),
+	)
+
+	return user
+}
+
+func newUserCreateCommand(opts *options) *cobra.Command {
+	var (
+		request       dtos.SignUpRequest
+		contactType   string
+		status        string
+		passwordStdin bool
+	)
+
+	create := &cobra.Command{
+		Use:   "create",
+		Short: "Create a user, for example to bootstrap an admin",
+		Long: "Create a user with the sign up rules of the API, then transition it to the requested status.\n" +
+			"The user stays PENDING when the transition fails.",
+		Args: cobra.NoArgs,
+		RunE: withUsers(opts, func(ctx context.Context, cmd *cobra.Command, args []string, module *server.UsersModule) error {
+			request.ContactType = types.CONTACT_TYPE(strings.ToUpper(contactType))
+
+			if passwordStdin {
+				password, err := readLine(cmd.InOrStdin())
+				if err != nil {
+					return err
+				}
+
+				request.Password = password
+			}
+
+			// Fail before creating the user on a status that cannot be applied.
+			userStatus, err := parseStatus(status)
+			if err != nil {
+				return err
+			}
+
+			if err := request.Validate(); err != nil {
+				return err
+			}
+
+			if err := module.UseCase.SignUp(ctx, request); err != nil {
+				return err
+			}
+
+			user, err := module.UseCase.UserDetail(ctx, dtos.UserDetailByContactValueRequest{ContactValue: request.ContactValue})
+			if err != nil {
+				return err
+			}
+
+			if userStatus != user.Status {
+				transition := dtos.TransitionUserStatusRequest{ID: user.ID, TransitionUserStatus: dtos.TransitionUserStatus{Status: userStatus}}
+				if err := module.UseCase.TransitionUserStatus(ctx, transition); err != nil {
+					return fmt.Errorf("user %d created, set status: %w", user.ID, err)
+				}
+			}
+
+			return printUser(ctx, opts, cmd.OutOrStdout(), module, dtos.UserDetailByIDRequest{ID: user.ID})
+		}),
+	}
+
+	flags := create.Flags()
+	flags.StringVar(&request.Name, "name", "", "name of the user")
+	flags.StringVar(&contactType, "contact-type", string(types.CONTACT_TYPE_EMAIL), "contact type, EMAIL or PHONE")
+	flags.StringVar(&request.ContactValue, "contact-value", "", "email address or phone number the user logs in with")
+	flags.StringVar(&request.Password, "password", "", "password of the user, prefer --password-stdin")
+	flags.BoolVar(&passwordStdin, "password-stdin", false, "read the password from the first line of stdin")
+	flags.StringVar(&status, "status", string(types.ACTIVE), "status of the user, ACTIVE, PENDING, REJECT or CLOSED (banned)")
+
+	create.MarkFlagsMutuallyExclusive("password", "password-stdin")
+
+	return create
+}
+
+func newUserResetPasswordCommand(opts *options) *cobra.Command {
+	var (
+		password      string
+		passwordStdin bool
+	)
+
+	reset := &cobra.Command{
+		Use:   "reset-password <id>",
+		Short: "Set a new password for a user, checked against the password policy",
+		Args:  cobra.ExactArgs(1),
+		RunE: withUsers(opts, func(ctx context.Context, cmd *cobra.Command, args []string, module *server.UsersModule) error {
+			id, err := parseUserID(args[0])
+			if err != nil {
+				return err
+			}
+
+			if passwordStdin {
+				if password, err = readLine(cmd.InOrStdin()); err != nil {
+					return err
+				}
+			}
+
+			if password == "" {
+				return errors.New("a password is required, use --password or --password-stdin")
+			}
+
+			request := dtos.UserUpdateRequest{ID: id, UserUpdate: dtos.UserUpdate{Password: &password}}
+			if err := module.UseCase.UserUpdate(ctx, request); err != nil {
+				return err
+			}
+
+			return printUser(ctx, opts, cmd.OutOrStdout(), module, dtos.UserDetailByIDRequest{ID: id})
+		}),
+	}
+
+	reset.Flags().StringVar(&password, "password", "", "new password, prefer --password-stdin")
+	reset.Flags().BoolVar(&passwordStdin, "password-stdin", false, "read the new password from the first line of stdin")
+	reset.MarkFlagsMutuallyExclusive("password", "password-stdin")
+
+	return reset
+}
+
+// withUsers connects to the configured database and Redis and runs fn with the
+// users module, so that commands apply the rules of the API and invalidate the
+// cache of the running servers.
+func withUsers(opts *options, fn func(ctx context.Context, cmd *cobra.Command, args []string, module *server.UsersModule) error) func(cmd *cobra.Command, args []string) error {
+	return func(cmd *cobra.Command, args []string) error {
+		cfg, err := opts.loadConfig()
+		if err != nil {
+			return err
+		}
+
+		ctx := cmd.Context()
+
+		db, err := datasources.NewDB(ctx, cfg.Database)
+		if err != nil {
+			return err
+		}
+		defer lo.Must(db.DB()).Close()
+
+		redisClient, err := appRedis.Connect(ctx, cfg.Redis)
+		if err != nil {
+			return fmt.Errorf("connect to redis: %w", err)
+		}
+		defer redisClient.Close()
+
+		module, err := server.NewUsersModule(cfg, db, redisClient)
+		if err != nil {
+			return err
+		}
+
+		return fn(ctx, cmd, args, module)
+	}
+}
+
+// printUser reads the user back and prints it without its password hash.
+func printUser(ctx context.Context, opts *options, out io.Writer, module *server.UsersModule, request dtos.UserDetailRequest) error {
+	user, err := module.UseCase.UserDetail(ctx, request)
+	if err != nil {
+		return err
+	}
+
+	return opts.print(out, redactUser(user), userTable(user))
+}
+
+func redactUser(user dtos.User) dtos.User {
+	user.Password = ""
+	return user
+}
+
+func userTable(user dtos.User) table {
+	updatedAt := "-"
+	if user.UpdatedAt != nil {
+		updatedAt = user.UpdatedAt.UTC().Format(time.RFC3339)
+	}
+
+	return table{
+		header: []string{"ID", "NAME", "CONTACT TYPE", "CONTACT VALUE", "LANGUAGE", "STATUS", "CREATED AT", "UPDATED AT"},
+		rows: [][]string{{
+			strconv.FormatInt(user.ID, 10),
+			user.Name,
+			string(user.ContactType),
+			user.ContactValue,
+			string(lo.FromPtr(user.Language)),
+			string(user.Status),
+			user.CreatedAt.UTC().Format(time.RFC3339),
+			updatedAt,
+		}},
+	}
+}
+
+// userDetailRequest looks a user up by ID when ref is numeric and by contact
+// value otherwise.
+func userDetailRequest(ref string) dtos.UserDetailRequest {
+	if id, err := parseUserID(ref); err == nil {
+		return dtos.UserDetailByIDRequest{ID: id}
+	}
+
+	return dtos.UserDetailByContactValueRequest{ContactValue: ref}
+}
+
+func parseUserID(s string) (int64, error) {
+	id, err := strconv.ParseInt(s, 10, 64)
+	if err != nil || id <= 0 {
+		return 0, fmt.Errorf("invalid user id %q", s)
+	}
+
+	return id, nil
+}
+
+func parseStatus(s string) (types.USER_STATUS, error) {
+	status := types.USER_STATUS(strings.ToUpper(s))
+	if !lo.Contains([]types.USER_STATUS{types.ACTIVE, types.PENDING, types.REJECT, types.BANNED}, status) {
+		return "", fmt.Errorf("invalid user status %q", s)
+	}
+
+	return status, nil
+}
+
+// readLine reads the first line of r. Secrets passed on stdin do not end up in
+// the shell history.
+func readLine(r io.Reader) (string, error) {
+	line, err := bufio.NewReader(r).ReadString('\n')
+	if err != nil && !errors.Is(err, io.EOF) {
+		return "", fmt.Errorf("read stdin: %w", err)
+	}
+
+	return strings.TrimRight(line, "\r\n"), nil
+}
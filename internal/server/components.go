@@ -0,0 +1,181 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/lifecycle"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/labstack/gommon/log"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// Components of the server. Workers and the servers depend on the resources
+// they use, so they stop before those are released.
+const (
+	componentTelemetry  = "telemetry"
+	componentDatabase   = "database"
+	componentRedis      = "redis"
+	componentMigrations = "migrations"
+	componentHTTP       = "http"
+	componentGRPC       = "grpc"
+)
+
+// resources are the components every server and worker depends on.
+var resources = []string{componentDatabase, componentRedis, componentMigrations}
+
+// registerComponents registers the resources and servers of the server.
+// Workers are registered with the handlers that need them.
+func (s *Server) registerComponents() {
+	var (
+		tracer *sdktrace.TracerProvider
+		meter  *sdkmetric.MeterProvider
+	)
+
+	s.lifecycle.Register(
+		lifecycle.Component{
+			Name: componentTelemetry,
+			Start: func(ctx context.Context) error {
+				if !s.cfg.Observability.Enable {
+					return nil
+				}
+
+				// Telemetry is best effort, the server runs without it.
+				var err error
+				if tracer, err = observability.InitTracerProvider(s.cfg.Observability, s.cfg.App); err != nil {
+					log.Warn("Failed to initialize tracer: ", err)
+				}
+
+				if meter, err = observability.InitMeterProvider(s.cfg.Observability, s.cfg.App); err != nil {
+					log.Warn("Failed to initialize meter: ", err)
+				}
+
+				return nil
+			},
+			Stop: func(ctx context.Context) error {
+				// Flush what the stopped components recorded.
+				var errs []error
+				if tracer != nil {
+					errs = append(errs, tracer.Shutdown(ctx))
+				}
+
+				if meter != nil {
+					errs = append(errs, meter.Shutdown(ctx))
+				}
+
+				return errors.Join(errs...)
+			},
+		},
+		lifecycle.Component{
+			Name:      componentDatabase,
+			DependsOn: []string{componentTelemetry},
+			Stop: func(ctx context.Context) error {
+				sqlDB, err := s.db.DB()
+				if err != nil {
+					return err
+				}
+
+				return sqlDB.Close()
+			},
+		},
+		lifecycle.Component{
+			Name:      componentRedis,
+			DependsOn: []string{componentTelemetry},
+			Run:       s.connectRedis,
+			Stop:      func(ctx context.Context) error { return s.redisClient.Close() },
+		},
+		lifecycle.Component{
+			Name:      componentMigrations,
+			DependsOn: []string{componentDatabase},
+			Start:     s.migrate,
+		},
+		lifecycle.Component{
+			Name:      componentHTTP,
+			DependsOn: resources,
+			Run: func(ctx context.Context) error {
+				err := s.echo.Start(fmt.Sprintf(":%s", s.cfg.Server.Port))
+				if errors.Is(err, http.ErrServerClosed) {
+					return nil
+				}
+
+				return err
+			},
+			// Stop accepting requests and wait for the in-flight ones.
+			Stop: s.echo.Shutdown,
+		},
+	)
+
+	if s.cfg.GRPC.Enable {
+		s.lifecycle.Register(lifecycle.Component{
+			Name:      componentGRPC,
+			DependsOn: resources,
+			Run:       func(ctx context.Context) error { return s.grpc.Start() },
+			Stop:      func(ctx context.Context) error { return s.grpc.Shutdown(ctx) },
+		})
+	}
+
+	// Fail readiness before anything stops, taking the server out of rotation
+	// while the servers and the workers drain.
+	s.lifecycle.OnShutdown(func() {
+		log.Info("Server is shutting down...")
+		s.health.Shutdown()
+	})
+}
+
+// migrate applies the pending migrations before serving when AutoMigrate is
+// on, replicas starting together wait for the one holding the migration lock.
+func (s *Server) migrate(ctx context.Context) error {
+	if !s.cfg.Migration.AutoMigrate {
+		return nil
+	}
+
+	results, err := s.migrator.Up(ctx)
+	if err != nil {
+		return err
+	}
+
+	for _, result := range results {
+		log.Infof("Applied migration %s in %s", result.Source.Path, result.Duration)
+	}
+
+	return nil
+}
+
+// connectRedis waits in the background until Redis answers, so that the
+// server starts while Redis is down and readiness reports it meanwhile.
+func (s *Server) connectRedis(ctx context.Context) error {
+	retry := s.cfg.Redis.Retry
+	retry.Attempts = appRedis.RetryForever
+
+	err := appRedis.Ping(ctx, s.redisClient, retry, func(attempt int, err error) {
+		log.Warnf("Redis is not answering (attempt %d): %v", attempt, err)
+	})
+	if err != nil {
+		// Shut down before Redis answered.
+		return nil
+	}
+
+	log.Info("Connected to Redis")
+
+	return nil
+}
+
+// addWorker registers a background worker that runs until shutdown. A worker
+// that fails is logged and does not stop the server.
+func (s *Server) addWorker(name string, run func(ctx context.Context) error, dependsOn ...string) {
+	s.lifecycle.Register(lifecycle.Component{
+		Name:      name,
+		DependsOn: append(dependsOn, resources...),
+		Run: func(ctx context.Context) error {
+			if err := run(ctx); err != nil {
+				log.Errorf("Background worker %s stopped: %v", name, err)
+			}
+
+			return nil
+		},
+	})
+}
@@ -1,21 +1,33 @@
 package server
 
 import (
+	"context"
 	"encoding/json"
 	"net/http"
 	"os"
 	"strings"
 
+	userGraphQL "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/graphql"
 	userV1 "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/http/v1"
-	userRepository "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/repository"
-	userUseCase "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/usecase"
-	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
-	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	userKafka "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/kafka"
+	userNotify "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/notify"
+	userRPC "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/delivery/rpc"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_grpc"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/featureflags"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/lifecycle"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/logging"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/notify"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
-	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/scheduler"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/webhooks"
 	"github.com/labstack/echo/v4"
+	"github.com/samber/lo"
 
 	echoSwagger "github.com/swaggo/echo-swagger"
 )
@@ -24,25 +36,31 @@ type routeMapper interface {
 	MapRoutes(api *echo.Group, mw *middleware.Middleware)
 }
 
-func (s *Server) setup() error {
+func (s *Server) setup() {
+	s.setupRuntimeSettings()
 	s.setupMiddleware()
 
-	api := s.echo.Group("/api/v1")
+	s.registerHealthChecks()
+	s.echo.GET("/healthz", s.healthz)
+	s.echo.GET("/readyz", s.readyz)
 
-	s.registerUtilityRoutes(api)
+	s.registerUtilityRoutes(s.echo.Group("/api/v1"))
 
 	middleware, handlers := s.buildHandlers()
 
-	for _, handler := range handlers {
-		handler.MapRoutes(api, middleware)
+	// Handlers are grouped by the path prefix they are mounted on.
+	for prefix, mappers := range handlers {
+		api := s.echo.Group(prefix)
+		for _, handler := range mappers {
+			handler.MapRoutes(api, middleware)
+		}
 	}
-
-	return nil
 }
 
 func (s *Server) setupMiddleware() {
 	logger := observability.NewZeroLogHook().Z()
 	s.echo.Use(logging.Middleware(logging.WithLogger(logger), logging.WithMaskedKeys("password", "token")))
+	s.echo.Use(featureflags.Middleware(s.flags))
 }
 
 func (s *Server) registerUtilityRoutes(api *echo.Group) {
@@ -77,28 +95,95 @@ func (s *Server) swaggerDoc(c echo.Context) error {
 	return c.Blob(http.StatusOK, echo.MIMEApplicationJSON, body)
 }
 
-func (s *Server) buildHandlers() (*middleware.Middleware, []routeMapper) {
-	redisManager := redis.NewRedisManager(nil)
+func (s *Server) buildHandlers() (*middleware.Middleware, map[string][]routeMapper) {
+	usersModule := lo.Must(NewUsersModule(s.cfg, s.db, s.redisClient))
+	s.addWorker("users-cache", usersModule.Cache.Listen)
 
-	jwtFactory := jwt.NewJWTFactory(s.cfg.JWT, redisManager)
-	crypto := encryptions.NewCrypto(s.cfg.Authentication.Key)
+	jwtFactory, userUC := usersModule.JWT, usersModule.UseCase
 
 	mw := middleware.New(jwtFactory)
 
-	userRepo := userRepository.NewRepository(s.db)
+	logger := observability.NewZeroLogHook().Z()
 
-	userUC := userUseCase.NewUseCase(userUseCase.Dependencies{
-		Repositories: userUseCase.Repositories{
-			Repo: userRepo,
-		},
-		Pkgs: userUseCase.Pkgs{
-			AppJwt: jwtFactory,
-			Crypto: crypto,
+	grpcOpts := []app_grpc.GRPCOptionFn{
+		app_grpc.WithAuth(jwtFactory),
+		app_grpc.WithLogger(logger),
+		app_grpc.WithInterceptors(featureflags.UnaryServerInterceptor(s.flags)),
+	}
+	if s.cfg.Observability.Enable {
+		grpcOpts = append(grpcOpts, app_grpc.WithTracing())
+	}
+
+	s.grpc = s.cfg.GRPC.New(grpcOpts...)
+	userRPC.NewHandlers(userUC).MapServices(s.grpc)
+
+	var (
+		publisher         outbox.Publisher = outbox.NewLogPublisher(logger)
+		relayDependencies []string         // The relay stops before the producer it publishes to.
+	)
+	if s.cfg.Kafka.Enable {
+		transport := kafka.NewTransport(s.cfg.Kafka)
+
+		router := kafka.NewRouter(s.cfg.Kafka, transport, logger)
+		userKafka.NewHandlers(userUC).MapTopics(router)
+		s.addWorker("kafka-router", router.Run)
+
+		producer := kafka.NewProducer(transport)
+		s.lifecycle.Register(lifecycle.Component{
+			Name: "kafka-producer",
+			Stop: func(ctx context.Context) error { return producer.Close() },
+		})
+		relayDependencies = append(relayDependencies, "kafka-producer")
+		publisher = userKafka.NewEventPublisher(producer)
+	}
+
+	if s.cfg.Webhooks.Enable {
+		publisher = outbox.NewMultiPublisher(publisher, s.webhookDispatcher)
+	}
+
+	if s.cfg.Notify.Enable {
+		publisher = outbox.NewMultiPublisher(publisher, userNotify.NewEventNotifier(userUC, s.notifier))
+	}
+
+	if s.cfg.Outbox.Enable {
+		relay := outbox.NewRelay(s.cfg.Outbox, outbox.NewGormStore(s.db), publisher, logger)
+
+		// A single relay publishes at a time, on the replica leading the election.
+		election := appRedis.NewElection(appRedis.NewLocker(s.redisClient), "outbox-relay", 0)
+		s.addWorker("outbox-relay", func(ctx context.Context) error { return election.Run(ctx, relay.Run) }, relayDependencies...)
+	}
+
+	s.addWorker("feature-flags", s.flags.Listen)
+
+	if s.cfg.Jobs.Enable {
+		s.addWorker("jobs", s.jobsWorker.Run)
+	}
+
+	// The admin API lists the tasks also while the scheduler is disabled.
+	lo.Must0(s.registerTasks(userUC))
+
+	if s.cfg.Scheduler.Enable {
+		s.addWorker("scheduler", s.scheduler.Run)
+	}
+
+	handlers := map[string][]routeMapper{
+		"/api/v1": {
+			userV1.NewHandlers(userUC),
+			featureflags.NewAdminHandlers(s.flags, s.cfg.Admin.UserIDs),
+			jobs.NewAdminHandlers(s.jobsBackend, s.jobsWorker, s.cfg.Admin.UserIDs),
+			scheduler.NewAdminHandlers(s.scheduler, s.cfg.Admin.UserIDs),
+			webhooks.NewAdminHandlers(s.webhooks, s.webhookDispatcher, s.cfg.Admin.UserIDs),
+			notify.NewAdminHandlers(s.notifier, s.cfg.Admin.UserIDs),
 		},
-	})
+	}
+
+	if s.cfg.GraphQL.Enable {
+		var graphqlOpts []app_graphql.GraphQLOptionFn
+		if s.cfg.Observability.Enable {
+			graphqlOpts = append(graphqlOpts, app_graphql.WithTracing())
+		}
 
-	handlers := []routeMapper{
-		userV1.NewHandlers(userUC),
+		handlers["/api"] = append(handlers["/api"], userGraphQL.NewHandlers(userUC, s.cfg.GraphQL, s.cfg.Admin.UserIDs, graphqlOpts...))
 	}
 
 	return mw, handlers
@@ -0,0 +1,55 @@
+package server
+
+import (
+	"context"
+	"net/http"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/health"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
+	"github.com/labstack/echo/v4"
+)
+
+// registerHealthChecks registers the checks of the dependencies the server
+// needs to take traffic.
+func (s *Server) registerHealthChecks() {
+	s.health.Register("database", health.CheckerFunc(func(ctx context.Context) error {
+		sqlDB, err := s.db.DB()
+		if err != nil {
+			return err
+		}
+
+		return sqlDB.PingContext(ctx)
+	}))
+
+	s.health.Register("redis", health.CheckerFunc(func(ctx context.Context) error {
+		return s.redisClient.Ping(ctx).Err()
+	}))
+
+	// The database misses migrations the binary expects.
+	s.health.Register("migrations", health.CheckerFunc(s.migrator.Check))
+
+	// Telemetry that cannot be exported is reported without taking the server
+	// out of rotation.
+	if s.cfg.Observability.Enable {
+		s.health.Register("telemetry", health.CheckerFunc(observability.CheckExporters), health.NonCritical())
+	}
+}
+
+// healthz reports whether the process is alive, failing it restarts the pod.
+func (s *Server) healthz(c echo.Context) error {
+	return healthReport(c, s.health.Liveness(c.Request().Context()))
+}
+
+// readyz reports whether the server can take traffic. It fails while a critical
+// dependency is down and as soon as shutdown starts.
+func (s *Server) readyz(c echo.Context) error {
+	return healthReport(c, s.health.Readiness(c.Request().Context()))
+}
+
+func healthReport(c echo.Context, report health.Report) error {
+	if !report.Healthy() {
+		return c.JSON(http.StatusServiceUnavailable, report)
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
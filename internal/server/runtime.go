@@ -0,0 +1,83 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"syscall"
+
+	"github.com/DoWithLogic/golang-clean-architecture/config"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/reload"
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+)
+
+// runtimeOverridesKey holds JSON overrides of the runtime settings shared by
+// every replica, e.g. SET config:runtime '{"Logger": {"Level": "warn"}}'.
+var runtimeOverridesKey = appRedis.REDIS_PREFIX_KEY_CONFIG.Key("runtime")
+
+// setupRuntimeSettings subscribes the logger and the HTTP middlewares to the
+// runtime settings, and with Reload.Enable watches the config files, SIGHUP
+// and the Redis overrides for changes.
+func (s *Server) setupRuntimeSettings() {
+	s.settings = reload.New(s.cfg.Runtime(), s.loadRuntimeSettings, observability.NewZeroLogHook().Z())
+
+	s.settings.Subscribe(func(settings config.RuntimeConfig) {
+		level := zerolog.TraceLevel
+		if settings.Logger.Level != "" {
+			level, _ = zerolog.ParseLevel(settings.Logger.Level)
+		}
+
+		zerolog.SetGlobalLevel(level)
+	})
+
+	s.settings.Subscribe(func(settings config.RuntimeConfig) {
+		s.echoRuntime.Update(settings.CORS, settings.RateLimit)
+	})
+
+	if !s.cfg.Reload.Enable {
+		return
+	}
+
+	s.addWorker("runtime-settings", func(ctx context.Context) error {
+		// The overrides of Redis apply from the start.
+		_ = s.settings.Reload(ctx, "startup")
+
+		return s.settings.Watch(ctx,
+			reload.OnSignal(syscall.SIGHUP),
+			reload.OnChange("config file changed", s.cfg.Reload.Interval, reload.Files(s.cfg.Files()...)),
+			reload.OnChange("redis overrides changed", s.cfg.Reload.Interval, s.runtimeOverrides),
+		)
+	})
+}
+
+// loadRuntimeSettings reads the config files and environment again, then
+// applies the Redis overrides on top.
+func (s *Server) loadRuntimeSettings(ctx context.Context) (config.RuntimeConfig, error) {
+	cfg, err := s.cfg.Reloaded()
+	if err != nil {
+		return config.RuntimeConfig{}, err
+	}
+
+	overrides, err := s.runtimeOverrides(ctx)
+	if err != nil {
+		return config.RuntimeConfig{}, err
+	}
+
+	if overrides == "" {
+		return cfg.Runtime(), nil
+	}
+
+	return cfg.Runtime().Override([]byte(overrides))
+}
+
+// runtimeOverrides returns the Redis overrides, empty when none are set.
+func (s *Server) runtimeOverrides(ctx context.Context) (string, error) {
+	overrides, err := s.redisClient.Get(ctx, runtimeOverridesKey).Result()
+	if errors.Is(err, redis.Nil) {
+		return "", nil
+	}
+
+	return overrides, err
+}
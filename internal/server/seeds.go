@@ -0,0 +1,58 @@
+package server
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/config"
+	"github.com/DoWithLogic/golang-clean-architecture/database"
+	userRepository "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/repository"
+	userSeeds "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/seeds"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
+	"gorm.io/gorm"
+)
+
+// NewSeedRegistry returns the seed sets of the application, by the App.Environment
+// they run in. The test environment is the one of the testutil suites. No set runs
+// in production.
+func NewSeedRegistry(db *gorm.DB, crypto *encryptions.Crypto) (*seed.Registry, error) {
+	fixtures := database.Seeds()
+
+	localUsers, err := userSeeds.FromFixture(fixtures, "local.yaml")
+	if err != nil {
+		return nil, err
+	}
+
+	demoUsers, err := userSeeds.FromFixture(fixtures, "demo.json")
+	if err != nil {
+		return nil, err
+	}
+
+	// Seeds skip the cache so they run without Redis, a cached "not found" of a
+	// seeded contact value expires after Cache.NegativeTTL.
+	users := userSeeds.Dependencies{
+		Repo:       userRepository.NewRepository(db),
+		UnitOfWork: datasources.NewUnitOfWork(db),
+		Crypto:     crypto,
+	}
+
+	return seed.NewRegistry(
+		seed.Set{
+			Name:         "users",
+			Description:  "A user in every status for every contact type",
+			Environments: []string{config.EnvironmentLocal, config.EnvironmentDevelopment, config.EnvironmentTest},
+			Seeders:      []seed.Seeder{userSeeds.NewUsersSeeder("generated users", users, userSeeds.Generated())},
+		},
+		seed.Set{
+			Name:         "local",
+			Description:  "Accounts to work with locally",
+			Environments: []string{config.EnvironmentLocal},
+			Seeders:      []seed.Seeder{userSeeds.NewUsersSeeder("local.yaml", users, localUsers)},
+		},
+		seed.Set{
+			Name:         "demo",
+			Description:  "Accounts of the demo environments",
+			Environments: []string{config.EnvironmentDevelopment, config.EnvironmentStaging},
+			Seeders:      []seed.Seeder{userSeeds.NewUsersSeeder("demo.json", users, demoUsers)},
+		},
+	), nil
+}
@@ -2,8 +2,6 @@ package server
 
 import (
 	"context"
-	"fmt"
-	"os"
 	"os/signal"
 	"syscall"
 	"time"
@@ -12,68 +10,150 @@ import (
 	"gorm.io/gorm"
 
 	"github.com/DoWithLogic/golang-clean-architecture/config"
+	"github.com/DoWithLogic/golang-clean-architecture/database"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_echo"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_grpc"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/featureflags"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/health"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/lifecycle"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/migration"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/notify"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
 	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/reload"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/scheduler"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/transporter"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/webhooks"
 	"github.com/labstack/echo/v4"
-	"github.com/labstack/gommon/log"
-
-	"github.com/samber/lo"
 
 	"github.com/go-redis/redis/v8"
 )
 
 type Server struct {
-	db          *gorm.DB      // Database connection.
-	echo        *echo.Echo    // Echo HTTP server instance.
-	cfg         config.Config // Configuration settings for the application.
-	redisClient *redis.Client
+	db          *gorm.DB              // Database connection.
+	echo        *echo.Echo            // Echo HTTP server instance.
+	cfg         config.Config         // Configuration settings for the application.
+	redisClient redis.UniversalClient // Standalone, Sentinel or Cluster client of Config.Redis.
+	grpc        *app_grpc.Server      // gRPC server running next to Echo when enabled.
+	migrator    *migration.Migrator
+	health      *health.Registry  // Liveness and readiness checks, readiness fails once shutdown starts.
+	echoRuntime *app_echo.Runtime // CORS and rate limits of the HTTP server, updated by the settings.
+
+	settings *reload.Reloader[config.RuntimeConfig] // Settings reloaded without restart.
+	flags    *featureflags.Store                    // Feature flags served to the handlers through the request context.
+
+	jobsBackend jobs.Backend // Queues of the background jobs, stored in Redis.
+	jobs        *jobs.Client // Enqueues the background jobs.
+	jobsWorker  *jobs.Worker // Runs the background jobs when Jobs.Enable is set.
+
+	scheduler *scheduler.Scheduler // Runs the periodic tasks in Server.TimeZone when Scheduler.Enable is set.
+	notifier  *notify.Notifier     // Sends emails and text messages to the users through the jobs, the security alerts when Notify.Enable is set.
+
+	webhooks          *webhooks.Store      // Subscriptions of the webhooks in the database, their delivery attempts in Redis.
+	webhookDispatcher *webhooks.Dispatcher // Delivers the outbox events to the subscriptions when Webhooks.Enable is set.
+
+	lifecycle *lifecycle.Manager // Starts the components in dependency order and stops them in reverse.
 }
 
-func NewServer(ctx context.Context, cfg config.Config) *Server {
-	serverOpts := []app_echo.EchoOptionFn{}
+// NewServer creates the server and its clients. Redis is connected in the
+// background once the server runs, readiness fails until it answers.
+func NewServer(ctx context.Context, cfg config.Config) (*Server, error) {
+	echoRuntime := app_echo.NewRuntime(cfg.Server.CORS, cfg.Server.RateLimit)
+
+	serverOpts := []app_echo.EchoOptionFn{app_echo.WithRuntime(echoRuntime)}
 	if cfg.Observability.Enable {
 		serverOpts = append(serverOpts, app_echo.WithTracing(cfg.App.Name))
 	}
 
+	db, err := datasources.NewDB(ctx, cfg.Database)
+	if err != nil {
+		return nil, err
+	}
+
+	redisClient, err := appRedis.NewClient(cfg.Redis)
+	if err != nil {
+		return nil, err
+	}
+
+	migrator, err := NewMigrator(db, cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	location, err := time.LoadLocation(cfg.Server.TimeZone)
+	if err != nil {
+		return nil, err
+	}
+
+	senders, err := notify.NewSenders(cfg.Notify)
+	if err != nil {
+		return nil, err
+	}
+
+	logger := observability.NewZeroLogHook().Z()
+
+	jobsBackend := jobs.NewRedisBackend(redisClient)
+	jobsClient := jobs.NewClient(jobsBackend)
+	jobsWorker := jobs.NewWorker(cfg.Jobs, jobsBackend, logger)
+
+	notifier := notify.NewNotifier(cfg.Notify, notify.DefaultTemplates(), senders, notify.NewRedisDeliveryLog(redisClient, cfg.Notify.DeliveryLogSize), jobsClient, logger)
+	notifier.Register(jobsWorker)
+
+	webhookStore := webhooks.NewStore(webhooks.NewGormRepository(db), redisClient, cfg.Webhooks)
+	webhookDispatcher := webhooks.NewDispatcher(cfg.Webhooks, webhookStore, []string{
+		entities.UserSignedUpEventType,
+		entities.UserUpdatedEventType,
+		entities.UserStatusChangedEventType,
+	}, jobsClient, transporter.NewClient(logger, transporter.WithConfig(cfg.HTTPClient)), logger)
+	webhookDispatcher.Register(jobsWorker)
+
 	return &Server{
-		db:          lo.Must(datasources.NewMySQLDB(ctx, cfg.Database)),
+		db:          db,
 		echo:        cfg.Server.New(serverOpts...),
 		cfg:         cfg,
-		redisClient: appRedis.NewRedisClient(ctx, cfg.Redis),
-	}
+		redisClient: redisClient,
+		migrator:    migrator,
+		health:      health.NewRegistry(cfg.Health),
+		echoRuntime: echoRuntime,
+		lifecycle:   lifecycle.New(cfg.Lifecycle),
+		flags:       featureflags.NewStore(redisClient, cfg.FeatureFlags, cfg.App.Environment),
+		jobsBackend: jobsBackend,
+		jobs:        jobsClient,
+		jobsWorker:  jobsWorker,
+		scheduler:   scheduler.NewScheduler(cfg.Scheduler, location, redisClient, logger),
+		notifier:    notifier,
+
+		webhooks:          webhookStore,
+		webhookDispatcher: webhookDispatcher,
+	}, nil
 }
 
-func (s *Server) Run() error {
-	if err := s.setup(); err != nil {
-		return err
+// NewMigrator creates a migrator for the embedded migrations of the configured driver.
+func NewMigrator(db *gorm.DB, cfg config.Config) (*migration.Migrator, error) {
+	sqlDB, err := db.DB()
+	if err != nil {
+		return nil, err
 	}
 
-	// Set up signal handling to gracefully shutdown the server upon receiving a SIGTERM or SIGINT signal.
-	// Using a buffered channel with capacity 1 to ensure signals are not missed.
-	quit := make(chan os.Signal, 1)
-	signal.Notify(quit, syscall.SIGTERM)
-	signal.Notify(quit, syscall.SIGINT)
-
-	// Start a goroutine to handle the shutdown signal.
-	go func() {
-		// Wait for the signal from the quit channel.
-		<-quit
-
-		// Log the shutdown process.
-		log.Info("Server is shutting down...")
+	migrations, err := database.Migrations(cfg.Database.Driver)
+	if err != nil {
+		return nil, err
+	}
 
-		// Create a context with a timeout of 10 seconds for the server shutdown.
-		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
-		defer cancel()
+	return migration.New(sqlDB, cfg.Database.Driver, migrations, cfg.Migration)
+}
 
-		// Close DB Connection
-		lo.Must(s.db.DB()).Close()
+// Run serves until SIGINT or SIGTERM, or until a component fails, then shuts
+// the components down in reverse order within Lifecycle.ShutdownTimeout.
+func (s *Server) Run() error {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
-		// Shutdown gracefully.
-		s.echo.Shutdown(ctx)
-	}()
+	s.registerComponents()
+	s.setup()
 
-	// Start the echo server and listen on the configured port.
-	return s.echo.Start(fmt.Sprintf(":%s", s.cfg.Server.Port))
+	return s.lifecycle.Run(ctx)
 }
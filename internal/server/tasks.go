@@ -0,0 +1,42 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/scheduler"
+	"github.com/labstack/gommon/log"
+)
+
+// registerTasks registers the maintenance tasks of the users. Scheduler.Tasks
+// overrides their schedules by name.
+func (s *Server) registerTasks(userUC users.Usecase) error {
+	retention := s.cfg.Retention.WithDefaults()
+
+	return errors.Join(
+		s.scheduler.Register(scheduler.Task{
+			Name:     "purge-deleted-accounts",
+			Schedule: "0 3 * * *",
+			Timeout:  time.Hour,
+			Run: func(ctx context.Context) error {
+				purged, err := userUC.PurgeDeletedUsers(ctx, time.Now().Add(-retention.DeletedUsers))
+				log.Infof("Purged %d deleted accounts", purged)
+
+				return err
+			},
+		}),
+		s.scheduler.Register(scheduler.Task{
+			Name:     "expire-pending-users",
+			Schedule: "30 3 * * *",
+			Timeout:  time.Hour,
+			Run: func(ctx context.Context) error {
+				expired, err := userUC.ExpirePendingUsers(ctx, time.Now().Add(-retention.PendingUsers))
+				log.Infof("Rejected %d expired pending users", expired)
+
+				return err
+			},
+		}),
+	)
+}
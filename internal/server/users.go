@@ -0,0 +1,63 @@
+package server
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/config"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
+	userEntities "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	userRepository "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/repository"
+	userUseCase "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/usecase"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/cache"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/degradation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"gorm.io/gorm"
+)
+
+// UsersModule is the users domain wired on the shared database and Redis. The
+// server and the admin commands both build it, so that they apply the same
+// rules and invalidate the same cache.
+type UsersModule struct {
+	UseCase users.Usecase
+	JWT     *jwt.JWTFactory
+	Cache   *cache.Cache[userEntities.User] // Its Listen worker keeps the local cache of a long running process in sync.
+}
+
+// NewUsersModule wires the users usecase with its repositories and packages.
+func NewUsersModule(cfg config.Config, db *gorm.DB, redisClient redis.UniversalClient) (*UsersModule, error) {
+	passwordPolicy, err := password.NewPolicy(cfg.PasswordPolicy)
+	if err != nil {
+		return nil, err
+	}
+
+	degradationCfg := cfg.Degradation.WithDefaults()
+	logger := observability.NewZeroLogHook().Z()
+
+	jwtFactory := jwt.NewJWTFactory(cfg.JWT, appRedis.NewRedisManager(redisClient),
+		jwt.WithBlacklistGuard(degradation.NewGuard("jwt_blacklist", degradationCfg.Blacklist, logger)),
+		jwt.WithBlacklistFallback(degradationCfg.BlacklistFallbackSize, degradationCfg.BlacklistFallbackTTL),
+	)
+
+	userCache := cache.New[userEntities.User]("users", redisClient, cfg.Cache,
+		cache.WithGuard(degradation.NewGuard("users_cache", degradationCfg.Cache, logger)),
+	)
+
+	userUC := userUseCase.NewUseCase(userUseCase.Dependencies{
+		Repositories: userUseCase.Repositories{
+			Repo:       userRepository.NewCachedRepository(userRepository.NewRepository(db), userCache),
+			UnitOfWork: datasources.NewUnitOfWork(db),
+		},
+		Pkgs: userUseCase.Pkgs{
+			AppJwt: jwtFactory,
+			Crypto: encryptions.NewCrypto(cfg.Authentication.Key),
+
+			PasswordPolicy: passwordPolicy,
+		},
+	})
+
+	return &UsersModule{UseCase: userUC, JWT: jwtFactory, Cache: userCache}, nil
+}
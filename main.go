@@ -1,12 +1,9 @@
 package main
 
 import (
-	"context"
+	"os"
 
-	"github.com/DoWithLogic/golang-clean-architecture/config"
-	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
-	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability"
-	"github.com/labstack/gommon/log"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/cmd"
 )
 
 // main is entrypoint of application
@@ -21,39 +18,7 @@ import (
 //	@in							header
 //	@name						Authorization
 func main() {
-	// Load the application configuration from the specified directory.
-	cfg, err := config.LoadConfig("config")
-	if err != nil {
-		// If an error occurs while loading the configuration, panic with the error.
-		panic(err)
-	}
-
-	// Initialize observability components if observability is enabled in the configuration.
-	if cfg.Observability.Enable {
-		// Initialize the tracer provider for distributed tracing.
-		tracer, err := observability.InitTracerProvider(cfg.Observability, cfg.App)
-		if err != nil {
-			log.Warn("Failed to initialize tracer: ", err)
-		}
-
-		// Initialize the meter provider for metrics collection.
-		meter, err := observability.InitMeterProvider(cfg.Observability, cfg.App)
-		if err != nil {
-			log.Warn("Failed to initialize meter: ", err)
-		}
-
-		// Ensure that the tracer and meter are shut down when the main function exits.
-		defer func() {
-			if tracer != nil {
-				tracer.Shutdown(context.Background())
-			}
-			if meter != nil {
-				meter.Shutdown(context.Background())
-			}
-		}()
-	}
-
-	if err := server.NewServer(context.Background(), cfg).Run(); err != nil {
-		panic(err)
+	if err := cmd.NewRootCommand().Execute(); err != nil {
+		os.Exit(1)
 	}
 }
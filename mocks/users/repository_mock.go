@@ -11,11 +11,11 @@ package mocks
 
 import (
 	context "context"
-	sql "database/sql"
 	reflect "reflect"
+	time "time"
 
-	users "github.com/DoWithLogic/golang-clean-architecture/internal/app/users"
 	entities "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	outbox "github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
 	gomock "go.uber.org/mock/gomock"
 )
 
@@ -23,7 +23,6 @@ import (
 type MockRepository struct {
 	ctrl     *gomock.Controller
 	recorder *MockRepositoryMockRecorder
-	isgomock struct{}
 }
 
 // MockRepositoryMockRecorder is the mock recorder for MockRepository.
@@ -43,6 +42,39 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 	return m.recorder
 }
 
+// AddEvents mocks base method.
+func (m *MockRepository) AddEvents(ctx context.Context, events ...outbox.Event) error {
+	m.ctrl.T.Helper()
+	varargs := []any{ctx}
+	for _, a := range events {
+		varargs = append(varargs, a)
+	}
+	ret := m.ctrl.Call(m, "AddEvents", varargs...)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddEvents indicates an expected call of AddEvents.
+func (mr *MockRepositoryMockRecorder) AddEvents(ctx any, events ...any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	varargs := append([]any{ctx}, events...)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddEvents", reflect.TypeOf((*MockRepository)(nil).AddEvents), varargs...)
+}
+
+// AddPasswordHistory mocks base method.
+func (m *MockRepository) AddPasswordHistory(ctx context.Context, history *entities.PasswordHistory) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "AddPasswordHistory", ctx, history)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// AddPasswordHistory indicates an expected call of AddPasswordHistory.
+func (mr *MockRepositoryMockRecorder) AddPasswordHistory(ctx, history any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "AddPasswordHistory", reflect.TypeOf((*MockRepository)(nil).AddPasswordHistory), ctx, history)
+}
+
 // AddUser mocks base method.
 func (m *MockRepository) AddUser(ctx context.Context, user *entities.User) error {
 	m.ctrl.T.Helper()
@@ -71,6 +103,36 @@ func (mr *MockRepositoryMockRecorder) IsUserExists(ctx, contactValue any) *gomoc
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "IsUserExists", reflect.TypeOf((*MockRepository)(nil).IsUserExists), ctx, contactValue)
 }
 
+// PasswordHistories mocks base method.
+func (m *MockRepository) PasswordHistories(ctx context.Context, userID int64, limit int) ([]entities.PasswordHistory, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PasswordHistories", ctx, userID, limit)
+	ret0, _ := ret[0].([]entities.PasswordHistory)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PasswordHistories indicates an expected call of PasswordHistories.
+func (mr *MockRepositoryMockRecorder) PasswordHistories(ctx, userID, limit any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PasswordHistories", reflect.TypeOf((*MockRepository)(nil).PasswordHistories), ctx, userID, limit)
+}
+
+// PurgeDeletedUsers mocks base method.
+func (m *MockRepository) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockRepositoryMockRecorder) PurgeDeletedUsers(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockRepository)(nil).PurgeDeletedUsers), ctx, deletedBefore)
+}
+
 // UpdateUser mocks base method.
 func (m *MockRepository) UpdateUser(ctx context.Context, user *entities.UpdateUser) error {
 	m.ctrl.T.Helper()
@@ -105,16 +167,33 @@ func (mr *MockRepositoryMockRecorder) UserDetail(ctx any, opts ...any) *gomock.C
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserDetail", reflect.TypeOf((*MockRepository)(nil).UserDetail), varargs...)
 }
 
-// WithTx mocks base method.
-func (m *MockRepository) WithTx(ctx context.Context, opt *sql.TxOptions, cb func(users.Repository) error) error {
+// Users mocks base method.
+func (m *MockRepository) Users(ctx context.Context, filter entities.UserFilter) ([]entities.User, int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "WithTx", ctx, opt, cb)
-	ret0, _ := ret[0].(error)
-	return ret0
+	ret := m.ctrl.Call(m, "Users", ctx, filter)
+	ret0, _ := ret[0].([]entities.User)
+	ret1, _ := ret[1].(int64)
+	ret2, _ := ret[2].(error)
+	return ret0, ret1, ret2
+}
+
+// Users indicates an expected call of Users.
+func (mr *MockRepositoryMockRecorder) Users(ctx, filter any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Users", reflect.TypeOf((*MockRepository)(nil).Users), ctx, filter)
+}
+
+// UsersByIDs mocks base method.
+func (m *MockRepository) UsersByIDs(ctx context.Context, ids []int64) ([]entities.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsersByIDs", ctx, ids)
+	ret0, _ := ret[0].([]entities.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
-// WithTx indicates an expected call of WithTx.
-func (mr *MockRepositoryMockRecorder) WithTx(ctx, opt, cb any) *gomock.Call {
+// UsersByIDs indicates an expected call of UsersByIDs.
+func (mr *MockRepositoryMockRecorder) UsersByIDs(ctx, ids any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "WithTx", reflect.TypeOf((*MockRepository)(nil).WithTx), ctx, opt, cb)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsersByIDs", reflect.TypeOf((*MockRepository)(nil).UsersByIDs), ctx, ids)
 }
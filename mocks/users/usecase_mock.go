@@ -12,6 +12,7 @@ package mocks
 import (
 	context "context"
 	reflect "reflect"
+	time "time"
 
 	dtos "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/dtos"
 	gomock "go.uber.org/mock/gomock"
@@ -21,7 +22,6 @@ import (
 type MockUsecase struct {
 	ctrl     *gomock.Controller
 	recorder *MockUsecaseMockRecorder
-	isgomock struct{}
 }
 
 // MockUsecaseMockRecorder is the mock recorder for MockUsecase.
@@ -41,6 +41,36 @@ func (m *MockUsecase) EXPECT() *MockUsecaseMockRecorder {
 	return m.recorder
 }
 
+// ExpirePendingUsers mocks base method.
+func (m *MockUsecase) ExpirePendingUsers(ctx context.Context, createdBefore time.Time) (int, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExpirePendingUsers", ctx, createdBefore)
+	ret0, _ := ret[0].(int)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExpirePendingUsers indicates an expected call of ExpirePendingUsers.
+func (mr *MockUsecaseMockRecorder) ExpirePendingUsers(ctx, createdBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExpirePendingUsers", reflect.TypeOf((*MockUsecase)(nil).ExpirePendingUsers), ctx, createdBefore)
+}
+
+// ListUsers mocks base method.
+func (m *MockUsecase) ListUsers(ctx context.Context, request dtos.ListUsersRequest) (dtos.UserList, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ListUsers", ctx, request)
+	ret0, _ := ret[0].(dtos.UserList)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ListUsers indicates an expected call of ListUsers.
+func (mr *MockUsecaseMockRecorder) ListUsers(ctx, request any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockUsecase)(nil).ListUsers), ctx, request)
+}
+
 // Login mocks base method.
 func (m *MockUsecase) Login(ctx context.Context, request dtos.UserLoginRequest) (dtos.UserLoginResponse, error) {
 	m.ctrl.T.Helper()
@@ -56,6 +86,21 @@ func (mr *MockUsecaseMockRecorder) Login(ctx, request any) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Login", reflect.TypeOf((*MockUsecase)(nil).Login), ctx, request)
 }
 
+// PurgeDeletedUsers mocks base method.
+func (m *MockUsecase) PurgeDeletedUsers(ctx context.Context, deletedBefore time.Time) (int64, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeDeletedUsers", ctx, deletedBefore)
+	ret0, _ := ret[0].(int64)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeDeletedUsers indicates an expected call of PurgeDeletedUsers.
+func (mr *MockUsecaseMockRecorder) PurgeDeletedUsers(ctx, deletedBefore any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeDeletedUsers", reflect.TypeOf((*MockUsecase)(nil).PurgeDeletedUsers), ctx, deletedBefore)
+}
+
 // SignUp mocks base method.
 func (m *MockUsecase) SignUp(ctx context.Context, request dtos.SignUpRequest) error {
 	m.ctrl.T.Helper()
@@ -112,3 +157,18 @@ func (mr *MockUsecaseMockRecorder) UserUpdate(ctx, request any) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UserUpdate", reflect.TypeOf((*MockUsecase)(nil).UserUpdate), ctx, request)
 }
+
+// UsersByIDs mocks base method.
+func (m *MockUsecase) UsersByIDs(ctx context.Context, ids []int64) ([]dtos.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UsersByIDs", ctx, ids)
+	ret0, _ := ret[0].([]dtos.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// UsersByIDs indicates an expected call of UsersByIDs.
+func (mr *MockUsecaseMockRecorder) UsersByIDs(ctx, ids any) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UsersByIDs", reflect.TypeOf((*MockUsecase)(nil).UsersByIDs), ctx, ids)
+}
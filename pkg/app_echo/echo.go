@@ -20,9 +20,13 @@ func (cfg EchoConfig) New(opts ...EchoOptionFn) *echo.Echo {
 		opt(request)
 	}
 
+	if request.Runtime == nil {
+		request.Runtime = NewRuntime(cfg.CORS, cfg.RateLimit)
+	}
+
 	e := echo.New()
 	e.Use(echoMiddleware.RecoverWithConfig(echoMiddleware.RecoverConfig{DisableStackAll: true}))
-	e.Use(echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig(*request.CORSConfig)))
+	e.Use(request.Runtime.Middleware())
 	e.Use(echoprometheus.NewMiddleware("http"))
 	e.Use(cacheWithRevalidation)
 
@@ -1,22 +1,93 @@
 package app_echo
 
 import (
-	"net/http"
+	"net/url"
+	"time"
 
-	echoMiddleware "github.com/labstack/echo/v4/middleware"
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
 )
 
 type EchoConfig struct {
-	Port  string // The port on which the server will listen.
-	Debug bool   // Indicates if debug mode is enabled.
+	Port      string          // The port on which the server will listen.
+	Debug     bool            // Indicates if debug mode is enabled.
+	TimeZone  string          // The IANA time zone of the service, e.g. Asia/Jakarta. Defaults to UTC.
+	CORS      CORSConfig      // Reloadable without restart.
+	RateLimit RateLimitConfig // Reloadable without restart.
 }
 
-type CORSConfig echoMiddleware.CORSConfig
+func (c EchoConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Port, validation.Required, is.Port),
+		validation.Field(&c.TimeZone, validation.By(isTimeZone)),
+		validation.Field(&c.CORS),
+		validation.Field(&c.RateLimit),
+	)
+}
+
+// CORSConfig holds the cross-origin settings of the HTTP API.
+type CORSConfig struct {
+	AllowOrigins     []string // Origins allowed to call the API, "*" for any. Defaults to any.
+	AllowMethods     []string // Defaults to the methods of the API.
+	AllowHeaders     []string // Defaults to the headers of the preflight request.
+	AllowCredentials bool
+	MaxAge           int // Seconds a preflight response is cached by browsers.
+}
+
+func (c CORSConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.AllowOrigins, validation.Each(validation.Required, validation.By(isOrigin))),
+		validation.Field(&c.MaxAge, validation.Min(0)),
+	)
+}
+
+// isOrigin fails on an origin that is neither "*" nor a scheme and a host.
+func isOrigin(value any) error {
+	origin, _ := value.(string)
+	if origin == "*" || origin == "" {
+		return nil
+	}
+
+	if u, err := url.Parse(origin); err != nil || u.Scheme == "" || u.Host == "" {
+		return validation.NewError("validation_origin", `must be "*" or an origin such as https://example.com`)
+	}
+
+	return nil
+}
+
+// RateLimitConfig limits the requests of every client IP.
+type RateLimitConfig struct {
+	Enable            bool
+	RequestsPerSecond float64  // Sustained rate of a client.
+	Burst             int      // Requests a client can make at once above the rate.
+	ExcludePaths      []string // Routes served without limit, such as the health probes.
+}
+
+func (c RateLimitConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.RequestsPerSecond, validation.When(c.Enable, validation.Required, validation.Min(0.0))),
+		validation.Field(&c.Burst, validation.Min(0)),
+	)
+}
+
+// isTimeZone fails on a time zone missing from the time zone database.
+func isTimeZone(value any) error {
+	name, _ := value.(string)
+	if name == "" {
+		return nil
+	}
+
+	if _, err := time.LoadLocation(name); err != nil {
+		return validation.NewError("validation_time_zone", "must be a valid IANA time zone")
+	}
+
+	return nil
+}
 
 type echoRequest struct {
 	IsObservabilityEnable bool
 	ServiceName           *string
-	CORSConfig            *CORSConfig
+	Runtime               *Runtime
 }
 
 type EchoOptionFn func(*echoRequest)
@@ -27,16 +98,13 @@ func WithTracing(serviceName string) EchoOptionFn {
 		e.ServiceName = &serviceName
 	}
 }
-func WithCORS(c CORSConfig) EchoOptionFn { return func(er *echoRequest) { er.CORSConfig = &c } }
 
-var defaultCORSConfig = CORSConfig{
-	AllowOrigins: []string{"*"},
-	AllowMethods: []string{http.MethodGet, http.MethodPut, http.MethodPost, http.MethodDelete},
-}
+// WithRuntime applies the CORS and rate limit settings of r, which can be
+// updated while the server runs, instead of the ones of the config.
+func WithRuntime(r *Runtime) EchoOptionFn { return func(er *echoRequest) { er.Runtime = r } }
 
 func defaultEchoRequest() *echoRequest {
 	return &echoRequest{
-		CORSConfig:            &defaultCORSConfig,
 		IsObservabilityEnable: false,
 	}
 }
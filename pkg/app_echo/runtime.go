@@ -0,0 +1,73 @@
+package app_echo
+
+import (
+	"errors"
+	"slices"
+	"sync/atomic"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/labstack/echo/v4"
+	"golang.org/x/time/rate"
+
+	echoMiddleware "github.com/labstack/echo/v4/middleware"
+)
+
+var ErrRateLimitExceeded = errors.New("rate limit exceeded, retry later")
+
+// Runtime serves the CORS and rate limit settings that can change while the
+// server runs. Every request goes through the settings of the last Update.
+type Runtime struct {
+	middleware atomic.Pointer[echo.MiddlewareFunc]
+}
+
+// NewRuntime creates a runtime serving cors and rateLimit.
+func NewRuntime(cors CORSConfig, rateLimit RateLimitConfig) *Runtime {
+	r := &Runtime{}
+	r.Update(cors, rateLimit)
+
+	return r
+}
+
+// Update replaces the settings at once for the next requests. The request
+// counts of the rate limiter start over.
+func (r *Runtime) Update(cors CORSConfig, rateLimit RateLimitConfig) {
+	corsMiddleware := echoMiddleware.CORSWithConfig(echoMiddleware.CORSConfig{
+		AllowOrigins:     cors.AllowOrigins,
+		AllowMethods:     cors.AllowMethods,
+		AllowHeaders:     cors.AllowHeaders,
+		AllowCredentials: cors.AllowCredentials,
+		MaxAge:           cors.MaxAge,
+	})
+
+	middleware := corsMiddleware
+	if rateLimit.Enable {
+		rateLimitMiddleware := newRateLimiter(rateLimit)
+		middleware = func(next echo.HandlerFunc) echo.HandlerFunc {
+			return corsMiddleware(rateLimitMiddleware(next))
+		}
+	}
+
+	r.middleware.Store(&middleware)
+}
+
+// Middleware applies the current settings.
+func (r *Runtime) Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			return (*r.middleware.Load())(next)(c)
+		}
+	}
+}
+
+func newRateLimiter(cfg RateLimitConfig) echo.MiddlewareFunc {
+	return echoMiddleware.RateLimiterWithConfig(echoMiddleware.RateLimiterConfig{
+		Skipper: func(c echo.Context) bool { return slices.Contains(cfg.ExcludePaths, c.Path()) },
+		Store: echoMiddleware.NewRateLimiterMemoryStoreWithConfig(echoMiddleware.RateLimiterMemoryStoreConfig{
+			Rate:  rate.Limit(cfg.RequestsPerSecond),
+			Burst: cfg.Burst,
+		}),
+		DenyHandler: func(c echo.Context, identifier string, err error) error {
+			return response.TooManyRequests(ErrRateLimitExceeded)
+		},
+	})
+}
@@ -0,0 +1,74 @@
+package app_echo
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+)
+
+func newRuntimeServer(r *Runtime) *echo.Echo {
+	e := echo.New()
+	e.HTTPErrorHandler = errorHandler
+	e.Use(r.Middleware())
+	e.GET("/users", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+	e.GET("/healthz", func(c echo.Context) error { return c.NoContent(http.StatusOK) })
+
+	return e
+}
+
+func serve(e *echo.Echo, path, origin string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, path, nil)
+	req.Header.Set(echo.HeaderOrigin, origin)
+
+	rec := httptest.NewRecorder()
+	e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestRuntime_UpdateCORS(t *testing.T) {
+	r := NewRuntime(CORSConfig{AllowOrigins: []string{"https://a.example.com"}}, RateLimitConfig{})
+	e := newRuntimeServer(r)
+
+	rec := serve(e, "/users", "https://b.example.com")
+	assert.Empty(t, rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+
+	r.Update(CORSConfig{AllowOrigins: []string{"https://b.example.com"}}, RateLimitConfig{})
+
+	rec = serve(e, "/users", "https://b.example.com")
+	assert.Equal(t, "https://b.example.com", rec.Header().Get(echo.HeaderAccessControlAllowOrigin))
+}
+
+func TestRuntime_UpdateRateLimit(t *testing.T) {
+	r := NewRuntime(CORSConfig{}, RateLimitConfig{})
+	e := newRuntimeServer(r)
+
+	for range 3 {
+		assert.Equal(t, http.StatusOK, serve(e, "/users", "").Code, "no limit when disabled")
+	}
+
+	r.Update(CORSConfig{}, RateLimitConfig{Enable: true, RequestsPerSecond: 0.001, Burst: 1, ExcludePaths: []string{"/healthz"}})
+
+	assert.Equal(t, http.StatusOK, serve(e, "/users", "").Code)
+	assert.Equal(t, http.StatusTooManyRequests, serve(e, "/users", "").Code)
+	assert.Equal(t, http.StatusOK, serve(e, "/healthz", "").Code, "excluded paths are not limited")
+
+	r.Update(CORSConfig{}, RateLimitConfig{})
+	assert.Equal(t, http.StatusOK, serve(e, "/users", "").Code)
+}
+
+func TestRateLimitConfig_Validate(t *testing.T) {
+	assert.NoError(t, RateLimitConfig{}.Validate())
+	assert.Error(t, RateLimitConfig{Enable: true}.Validate())
+	assert.Error(t, RateLimitConfig{Enable: true, RequestsPerSecond: -1}.Validate())
+	assert.NoError(t, RateLimitConfig{Enable: true, RequestsPerSecond: 10, Burst: 20}.Validate())
+}
+
+func TestCORSConfig_Validate(t *testing.T) {
+	assert.NoError(t, CORSConfig{AllowOrigins: []string{"*", "https://example.com", "http://localhost:3000"}}.Validate())
+	assert.Error(t, CORSConfig{AllowOrigins: []string{"example.com"}}.Validate())
+	assert.Error(t, CORSConfig{AllowOrigins: []string{""}}.Validate())
+}
@@ -0,0 +1,178 @@
+package app_graphql
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+
+	schemaAST "github.com/graph-gophers/graphql-go/ast"
+	"github.com/vektah/gqlparser/v2/ast"
+	"github.com/vektah/gqlparser/v2/parser"
+)
+
+// sizeArguments are the field arguments that set the number of returned items.
+var sizeArguments = []string{"first", "last", "limit", "perPage"}
+
+var ErrUnknownOperation = errors.New("unknown operation")
+
+// Complexity estimates the cost of executing an operation of query. Every field
+// costs 1, and fields with a size argument (first, last, limit or perPage) multiply
+// the cost of their selection by the requested size, its schema default or
+// defaultListSize.
+func Complexity(schema *schemaAST.Schema, query, operationName string, variables map[string]any, defaultListSize int) (int, error) {
+	doc, err := parser.ParseQuery(&ast.Source{Input: query})
+	if err != nil {
+		return 0, err
+	}
+
+	var operation *ast.OperationDefinition
+	switch {
+	case operationName != "":
+		operation = doc.Operations.ForName(operationName)
+	case len(doc.Operations) == 1:
+		operation = doc.Operations[0]
+	}
+
+	if operation == nil {
+		return 0, ErrUnknownOperation
+	}
+
+	rootType := schema.RootOperationTypes[string(operation.Operation)]
+	if rootType == nil {
+		return 0, ErrUnknownOperation
+	}
+
+	c := complexity{schema: schema, doc: doc, variables: variables, defaultListSize: defaultListSize}
+
+	return c.selectionSet(rootType.TypeName(), operation.SelectionSet, map[string]bool{}), nil
+}
+
+type complexity struct {
+	schema          *schemaAST.Schema
+	doc             *ast.QueryDocument
+	variables       map[string]any
+	defaultListSize int
+}
+
+func (c complexity) selectionSet(typeName string, set ast.SelectionSet, visiting map[string]bool) int {
+	total := 0
+
+	for _, selection := range set {
+		switch selection := selection.(type) {
+		case *ast.Field:
+			if strings.HasPrefix(selection.Name, "__") {
+				total++
+				continue
+			}
+
+			definition := c.fieldDefinition(typeName, selection.Name)
+			if definition == nil {
+				// Unknown fields are rejected when the query is validated.
+				total++
+				continue
+			}
+
+			total += 1 + c.size(selection, definition)*c.selectionSet(namedType(definition.Type), selection.SelectionSet, visiting)
+
+		case *ast.InlineFragment:
+			fragmentType := typeName
+			if selection.TypeCondition != "" {
+				fragmentType = selection.TypeCondition
+			}
+
+			total += c.selectionSet(fragmentType, selection.SelectionSet, visiting)
+
+		case *ast.FragmentSpread:
+			fragment := c.doc.Fragments.ForName(selection.Name)
+			if fragment == nil || visiting[fragment.Name] {
+				continue
+			}
+
+			visiting[fragment.Name] = true
+			total += c.selectionSet(fragment.TypeCondition, fragment.SelectionSet, visiting)
+			delete(visiting, fragment.Name)
+		}
+	}
+
+	return total
+}
+
+// size returns the number of items requested from field, or 1 when the field has
+// no size argument.
+func (c complexity) size(field *ast.Field, definition *schemaAST.FieldDefinition) int {
+	for _, name := range sizeArguments {
+		argumentDefinition := definition.Arguments.Get(name)
+		if argumentDefinition == nil {
+			continue
+		}
+
+		if argument := field.Arguments.ForName(name); argument != nil {
+			if value, ok := c.argumentValue(argument.Value); ok {
+				return max(value, 1)
+			}
+		}
+
+		if argumentDefinition.Default != nil {
+			if value, ok := toInt(argumentDefinition.Default.Deserialize(nil)); ok {
+				return max(value, 1)
+			}
+		}
+
+		return max(c.defaultListSize, 1)
+	}
+
+	return 1
+}
+
+func (c complexity) argumentValue(value *ast.Value) (int, bool) {
+	switch value.Kind {
+	case ast.IntValue:
+		n, err := strconv.Atoi(value.Raw)
+		return n, err == nil
+	case ast.Variable:
+		return toInt(c.variables[value.Raw])
+	default:
+		return 0, false
+	}
+}
+
+func (c complexity) fieldDefinition(typeName, fieldName string) *schemaAST.FieldDefinition {
+	switch t := c.schema.Types[typeName].(type) {
+	case *schemaAST.ObjectTypeDefinition:
+		return t.Fields.Get(fieldName)
+	case *schemaAST.InterfaceTypeDefinition:
+		return t.Fields.Get(fieldName)
+	default:
+		return nil
+	}
+}
+
+func namedType(t schemaAST.Type) string {
+	for {
+		switch wrapped := t.(type) {
+		case *schemaAST.NonNull:
+			t = wrapped.OfType
+		case *schemaAST.List:
+			t = wrapped.OfType
+		case schemaAST.NamedType:
+			return wrapped.TypeName()
+		default:
+			return t.String()
+		}
+	}
+}
+
+func toInt(value any) (int, bool) {
+	switch n := value.(type) {
+	case int:
+		return n, true
+	case int32:
+		return int(n), true
+	case int64:
+		return int(n), true
+	case float64:
+		return int(n), true
+	default:
+		return 0, false
+	}
+}
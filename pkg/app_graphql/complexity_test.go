@@ -0,0 +1,54 @@
+package app_graphql_test
+
+import (
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/stretchr/testify/require"
+)
+
+func TestComplexity(t *testing.T) {
+	schema := graphql.MustParseSchema(testSchema, nil).ASTSchema()
+
+	testCases := []struct {
+		name      string
+		query     string
+		operation string
+		variables map[string]any
+		want      int
+	}{
+		{name: "scalar fields", query: `{ item { id name } }`, want: 3},
+		{name: "size argument", query: `{ items(first: 5) { id name } }`, want: 1 + 5*2},
+		{name: "schema default size", query: `{ items { id } }`, want: 1 + 10*1},
+		{name: "default list size", query: `{ item { children { id } } }`, want: 1 + 1 + 20*1},
+		{name: "variable size", query: `query($n: Int) { items(first: $n) { id } }`, variables: map[string]any{"n": float64(3)}, want: 1 + 3*1},
+		{name: "nested sizes", query: `{ items(first: 2) { children(limit: 3) { id } } }`, want: 1 + 2*(1+3*1)},
+		{
+			name:  "fragments",
+			query: `{ item { ...fields ... on Item { id } } } fragment fields on Item { id name }`,
+			want:  1 + 3,
+		},
+		{
+			name:      "named operation",
+			query:     `query A { item { id } } query B { items(first: 4) { id } }`,
+			operation: "B",
+			want:      1 + 4*1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := app_graphql.Complexity(schema, tc.query, tc.operation, tc.variables, 20)
+			require.NoError(t, err)
+			require.Equal(t, tc.want, got)
+		})
+	}
+}
+
+func TestComplexity_UnknownOperation(t *testing.T) {
+	schema := graphql.MustParseSchema(testSchema, nil).ASTSchema()
+
+	_, err := app_graphql.Complexity(schema, `query A { item { id } } query B { item { id } }`, "", nil, 20)
+	require.ErrorIs(t, err, app_graphql.ErrUnknownOperation)
+}
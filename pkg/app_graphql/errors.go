@@ -0,0 +1,39 @@
+package app_graphql
+
+import (
+	"strings"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+)
+
+// Error is a resolver error with GraphQL error extensions.
+type Error struct {
+	err        error
+	extensions map[string]any
+}
+
+func (e *Error) Error() string              { return e.err.Error() }
+func (e *Error) Unwrap() error              { return e.err }
+func (e *Error) Extensions() map[string]any { return e.extensions }
+
+// ToError converts an error returned by a usecase into an Error whose extensions
+// carry the same information as the REST error response: the error code, e.g.
+// "NOT_FOUND", the HTTP status and the validation errors per field.
+func ToError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	res := response.ErrorBuilder(err)
+
+	extensions := map[string]any{
+		"code":   strings.ToUpper(res.Message.String()),
+		"status": res.Code,
+	}
+
+	if len(res.Errors) > 0 {
+		extensions["errors"] = res.Errors
+	}
+
+	return &Error{err: err, extensions: extensions}
+}
@@ -0,0 +1,76 @@
+package app_graphql
+
+import (
+	"net/http"
+
+	"github.com/graph-gophers/graphql-go"
+	gqlErrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/graph-gophers/graphql-go/trace/otel"
+	"github.com/labstack/echo/v4"
+)
+
+const codeComplexityLimitExceeded = "COMPLEXITY_LIMIT_EXCEEDED"
+
+// Handler executes GraphQL requests against a schema.
+type Handler struct {
+	cfg    GraphQLConfig
+	schema *graphql.Schema
+}
+
+type Request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// New parses the schema and binds it to resolver, see graphql.ParseSchema.
+func (cfg GraphQLConfig) New(schema string, resolver any, opts ...GraphQLOptionFn) (*Handler, error) {
+	request := defaultGraphQLRequest()
+	for _, opt := range opts {
+		opt(request)
+	}
+
+	schemaOpts := []graphql.SchemaOpt{graphql.MaxDepth(cfg.MaxDepth), graphql.MaxQueryLength(cfg.MaxQueryLength)}
+	if request.IsObservabilityEnable {
+		schemaOpts = append(schemaOpts, graphql.Tracer(otel.DefaultTracer()))
+	}
+
+	parsed, err := graphql.ParseSchema(schema, resolver, schemaOpts...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Handler{cfg: cfg, schema: parsed}, nil
+}
+
+// Handle serves a GraphQL request sent as a JSON POST body. Queries above the
+// configured complexity are rejected before any resolver runs.
+func (h *Handler) Handle(c echo.Context) error {
+	var request Request
+	if err := c.Bind(&request); err != nil {
+		return c.JSON(http.StatusBadRequest, graphql.Response{Errors: []*gqlErrors.QueryError{gqlErrors.Errorf("invalid request body")}})
+	}
+
+	if err := h.checkComplexity(request); err != nil {
+		return c.JSON(http.StatusOK, graphql.Response{Errors: []*gqlErrors.QueryError{err}})
+	}
+
+	return c.JSON(http.StatusOK, h.schema.Exec(c.Request().Context(), request.Query, request.OperationName, request.Variables))
+}
+
+func (h *Handler) checkComplexity(request Request) *gqlErrors.QueryError {
+	if h.cfg.MaxComplexity <= 0 {
+		return nil
+	}
+
+	// Invalid queries are reported by the schema when executed.
+	complexity, err := Complexity(h.schema.ASTSchema(), request.Query, request.OperationName, request.Variables, h.cfg.DefaultListSize)
+	if err != nil || complexity <= h.cfg.MaxComplexity {
+		return nil
+	}
+
+	queryErr := gqlErrors.Errorf("query complexity %d exceeds the limit of %d", complexity, h.cfg.MaxComplexity)
+	queryErr.Extensions = map[string]any{"code": codeComplexityLimitExceeded}
+
+	return queryErr
+}
@@ -0,0 +1,140 @@
+package app_graphql_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_graphql"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/graph-gophers/graphql-go"
+	"github.com/invopop/validation"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/require"
+)
+
+const testSchema = `
+schema { query: Query }
+
+type Query {
+  items(first: Int = 10): [Item!]!
+  item: Item
+  notFound: Item
+  invalid: Item
+}
+
+type Item {
+  id: ID!
+  name: String!
+  children(limit: Int): [Item!]!
+}
+`
+
+type queryResolver struct{}
+
+type itemResolver struct{ id int }
+
+func (queryResolver) Items(args struct{ First int32 }) []*itemResolver {
+	items := make([]*itemResolver, args.First)
+	for i := range items {
+		items[i] = &itemResolver{id: i}
+	}
+	return items
+}
+
+func (queryResolver) Item() *itemResolver { return &itemResolver{} }
+
+func (queryResolver) NotFound(ctx context.Context) (*itemResolver, error) {
+	return nil, app_graphql.ToError(response.NotFound(errors.New("item not found")))
+}
+
+func (queryResolver) Invalid(ctx context.Context) (*itemResolver, error) {
+	return nil, app_graphql.ToError(response.BadRequest(validation.Errors{"name": errors.New("cannot be blank")}))
+}
+
+func (r *itemResolver) ID() graphql.ID { return "1" }
+func (r *itemResolver) Name() string   { return "item" }
+func (r *itemResolver) Children(args struct{ Limit *int32 }) []*itemResolver {
+	return nil
+}
+
+type graphQLResponse struct {
+	Data   map[string]any `json:"data"`
+	Errors []struct {
+		Message    string         `json:"message"`
+		Extensions map[string]any `json:"extensions"`
+	} `json:"errors"`
+}
+
+func newHandler(t *testing.T, cfg app_graphql.GraphQLConfig) *app_graphql.Handler {
+	t.Helper()
+
+	handler, err := cfg.New(testSchema, &queryResolver{})
+	require.NoError(t, err)
+
+	return handler
+}
+
+func execute(t *testing.T, handler *app_graphql.Handler, query string) graphQLResponse {
+	t.Helper()
+
+	body, err := json.Marshal(app_graphql.Request{Query: query})
+	require.NoError(t, err)
+
+	req := httptest.NewRequest(http.MethodPost, "/graphql", strings.NewReader(string(body)))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	rec := httptest.NewRecorder()
+
+	require.NoError(t, handler.Handle(echo.New().NewContext(req, rec)))
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var res graphQLResponse
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+
+	return res
+}
+
+func TestHandler_Executes(t *testing.T) {
+	res := execute(t, newHandler(t, app_graphql.GraphQLConfig{}), `{ items(first: 2) { id name } }`)
+
+	require.Empty(t, res.Errors)
+	require.Len(t, res.Data["items"], 2)
+}
+
+func TestHandler_MapsAppErrorToExtensions(t *testing.T) {
+	handler := newHandler(t, app_graphql.GraphQLConfig{})
+
+	res := execute(t, handler, `{ notFound { id } }`)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "item not found", res.Errors[0].Message)
+	require.Equal(t, "NOT_FOUND", res.Errors[0].Extensions["code"])
+	require.EqualValues(t, http.StatusNotFound, res.Errors[0].Extensions["status"])
+
+	res = execute(t, handler, `{ invalid { id } }`)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "BAD_REQUEST", res.Errors[0].Extensions["code"])
+	require.Equal(t, map[string]any{"name": "cannot be blank"}, res.Errors[0].Extensions["errors"])
+}
+
+func TestHandler_EnforcesMaxDepth(t *testing.T) {
+	handler := newHandler(t, app_graphql.GraphQLConfig{MaxDepth: 2})
+
+	res := execute(t, handler, `{ item { children { children { id } } } }`)
+	require.NotEmpty(t, res.Errors)
+	require.Nil(t, res.Data)
+}
+
+func TestHandler_EnforcesMaxComplexity(t *testing.T) {
+	handler := newHandler(t, app_graphql.GraphQLConfig{MaxComplexity: 50})
+
+	res := execute(t, handler, `{ items(first: 10) { id name } }`)
+	require.Empty(t, res.Errors)
+
+	res = execute(t, handler, `{ items(first: 100) { id name } }`)
+	require.Len(t, res.Errors, 1)
+	require.Equal(t, "COMPLEXITY_LIMIT_EXCEEDED", res.Errors[0].Extensions["code"])
+}
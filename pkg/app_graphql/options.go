@@ -0,0 +1,35 @@
+package app_graphql
+
+import "github.com/invopop/validation"
+
+type GraphQLConfig struct {
+	Enable          bool // Serves the GraphQL endpoint.
+	MaxDepth        int  // The maximum field nesting depth of a query, 0 disables the check.
+	MaxComplexity   int  // The maximum complexity of a query, see Complexity. 0 disables the check.
+	MaxQueryLength  int  // The maximum length of a query in bytes, 0 disables the check.
+	DefaultListSize int  // The page size assumed for size arguments without a value or default.
+}
+
+func (c GraphQLConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxDepth, validation.Min(0)),
+		validation.Field(&c.MaxComplexity, validation.Min(0)),
+		validation.Field(&c.MaxQueryLength, validation.Min(0)),
+		validation.Field(&c.DefaultListSize, validation.Min(0)),
+	)
+}
+
+type graphQLRequest struct {
+	IsObservabilityEnable bool
+}
+
+type GraphQLOptionFn func(*graphQLRequest)
+
+// WithTracing starts a span for every query and resolved field.
+func WithTracing() GraphQLOptionFn {
+	return func(r *graphQLRequest) { r.IsObservabilityEnable = true }
+}
+
+func defaultGraphQLRequest() *graphQLRequest {
+	return &graphQLRequest{}
+}
@@ -0,0 +1,73 @@
+package app_grpc
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/invopop/validation"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var httpToGRPCCode = map[int]codes.Code{
+	http.StatusBadRequest:          codes.InvalidArgument,
+	http.StatusUnauthorized:        codes.Unauthenticated,
+	http.StatusForbidden:           codes.PermissionDenied,
+	http.StatusNotFound:            codes.NotFound,
+	http.StatusConflict:            codes.AlreadyExists,
+	http.StatusTooManyRequests:     codes.ResourceExhausted,
+	http.StatusInternalServerError: codes.Internal,
+	http.StatusServiceUnavailable:  codes.Unavailable,
+	http.StatusGatewayTimeout:      codes.DeadlineExceeded,
+}
+
+// ToStatus converts an error returned by a usecase into a gRPC status error.
+// AppError codes are mapped to the matching gRPC code and validation errors are
+// attached as BadRequest field violations. Other errors become codes.Internal.
+func ToStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var appErr *response.AppError
+	if !errors.As(err, &appErr) {
+		return status.Error(codes.Internal, response.InternalServerErrorMessage.String())
+	}
+
+	code, ok := httpToGRPCCode[appErr.Code]
+	if !ok {
+		code = codes.Unknown
+	}
+
+	st := status.New(code, appErr.Error())
+
+	var fieldErrs validation.Errors
+	if errors.As(appErr.Err, &fieldErrs) {
+		fields := make([]string, 0, len(fieldErrs))
+		for field := range fieldErrs {
+			fields = append(fields, field)
+		}
+		sort.Strings(fields)
+
+		violations := make([]*errdetails.BadRequest_FieldViolation, 0, len(fields))
+		for _, field := range fields {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: fieldErrs[field].Error(),
+			})
+		}
+
+		if detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations}); err == nil {
+			st = detailed
+		}
+	}
+
+	return st.Err()
+}
@@ -0,0 +1,116 @@
+package app_grpc
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// Server is a gRPC server with the standard interceptor chain and the health
+// service registered.
+type Server struct {
+	*grpc.Server
+
+	cfg    GRPCConfig
+	health *health.Server
+
+	mu     sync.RWMutex
+	public map[string]bool
+}
+
+// New creates a gRPC server. Interceptors run in the order: panic recovery,
+// request ID, tracing, logging, error mapping, authentication and the ones of
+// WithInterceptors.
+func (cfg GRPCConfig) New(opts ...GRPCOptionFn) *Server {
+	request := defaultGRPCRequest()
+	for _, opt := range opts {
+		opt(request)
+	}
+
+	s := &Server{cfg: cfg, health: health.NewServer(), public: make(map[string]bool)}
+
+	interceptors := []grpc.UnaryServerInterceptor{recoveryInterceptor, requestIDInterceptor}
+	if request.IsObservabilityEnable {
+		interceptors = append(interceptors, tracingInterceptor)
+	}
+
+	interceptors = append(interceptors, loggingInterceptor(request.Logger), errorInterceptor)
+	if request.JWTFactory != nil {
+		interceptors = append(interceptors, authInterceptor(request.JWTFactory, s.isPublic))
+	}
+
+	interceptors = append(interceptors, request.Interceptors...)
+
+	s.Server = grpc.NewServer(grpc.ChainUnaryInterceptor(interceptors...))
+
+	healthpb.RegisterHealthServer(s.Server, s.health)
+	if cfg.Reflection {
+		reflection.Register(s.Server)
+	}
+
+	return s
+}
+
+// AllowUnauthenticated lets the given full method names, e.g.
+// "/users.v1.UserService/Login", be called without a bearer token.
+func (s *Server) AllowUnauthenticated(fullMethods ...string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, method := range fullMethods {
+		s.public[method] = true
+	}
+}
+
+func (s *Server) isPublic(fullMethod string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.public[fullMethod]
+}
+
+// Start listens on the configured port and serves until the server is shut down.
+func (s *Server) Start() error {
+	listener, err := net.Listen("tcp", fmt.Sprintf(":%s", s.cfg.Port))
+	if err != nil {
+		return err
+	}
+
+	return s.Serve(listener)
+}
+
+// Serve marks every registered service as serving and accepts connections on listener.
+func (s *Server) Serve(listener net.Listener) error {
+	s.health.SetServingStatus("", healthpb.HealthCheckResponse_SERVING)
+	for name := range s.GetServiceInfo() {
+		s.health.SetServingStatus(name, healthpb.HealthCheckResponse_SERVING)
+	}
+
+	return s.Server.Serve(listener)
+}
+
+// Shutdown reports the services as not serving and waits for in-flight calls to
+// finish. Remaining calls are cancelled once ctx is done.
+func (s *Server) Shutdown(ctx context.Context) error {
+	s.health.Shutdown()
+
+	stopped := make(chan struct{})
+	go func() {
+		s.GracefulStop()
+		close(stopped)
+	}()
+
+	select {
+	case <-stopped:
+		return nil
+	case <-ctx.Done():
+		s.Stop()
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,187 @@
+package app_grpc_test
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/app_grpc"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/invopop/validation"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+// pingHandler answers with the ID of the authenticated user, or fails with the
+// error returned by handle.
+type pingHandler func(ctx context.Context) error
+
+var pingServiceDesc = grpc.ServiceDesc{
+	ServiceName: "test.PingService",
+	HandlerType: (*any)(nil),
+	Methods: []grpc.MethodDesc{
+		{MethodName: "Ping", Handler: ping},
+		{MethodName: "PublicPing", Handler: ping},
+	},
+}
+
+func ping(srv any, ctx context.Context, dec func(any) error, interceptor grpc.UnaryServerInterceptor) (any, error) {
+	in := new(emptypb.Empty)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+
+	handler := func(ctx context.Context, req any) (any, error) {
+		if err := srv.(pingHandler)(ctx); err != nil {
+			return nil, err
+		}
+
+		var userID int64
+		if claims, ok := ctx.Value(types.CredentialDataContextKey).(*jwt.JWTClaims); ok {
+			userID = claims.Data.ID
+		}
+
+		return wrapperspb.Int64(userID), nil
+	}
+
+	if interceptor == nil {
+		return handler(ctx, in)
+	}
+
+	return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/test.PingService/" + methodFromContext(ctx)}, handler)
+}
+
+func methodFromContext(ctx context.Context) string {
+	method, _ := grpc.Method(ctx)
+	return method[len("/test.PingService/"):]
+}
+
+func newTestServer(t *testing.T, handle pingHandler) (*grpc.ClientConn, *jwt.JWTFactory) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	redisClient, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+
+	jwtFactory := jwt.NewJWTFactory(
+		jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600},
+		redis.NewRedisManager(redisClient),
+	)
+
+	server := app_grpc.GRPCConfig{}.New(app_grpc.WithAuth(jwtFactory))
+	server.RegisterService(&pingServiceDesc, handle)
+	server.AllowUnauthenticated("/test.PingService/PublicPing")
+
+	listener := bufconn.Listen(1024 * 1024)
+	go server.Serve(listener)
+	t.Cleanup(func() { server.Shutdown(context.Background()) })
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	return conn, jwtFactory
+}
+
+func invoke(ctx context.Context, conn *grpc.ClientConn, method string, opts ...grpc.CallOption) (*wrapperspb.Int64Value, error) {
+	out := new(wrapperspb.Int64Value)
+	err := conn.Invoke(ctx, "/test.PingService/"+method, new(emptypb.Empty), out, opts...)
+
+	return out, err
+}
+
+func TestServer_Auth(t *testing.T) {
+	conn, jwtFactory := newTestServer(t, func(ctx context.Context) error { return nil })
+
+	_, err := invoke(t.Context(), conn, "Ping")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = invoke(metadata.AppendToOutgoingContext(t.Context(), "authorization", "Bearer invalid"), conn, "Ping")
+	require.Equal(t, codes.Unauthenticated, status.Code(err))
+
+	_, err = invoke(t.Context(), conn, "PublicPing")
+	require.NoError(t, err)
+
+	token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: 42}})
+	require.NoError(t, err)
+
+	out, err := invoke(metadata.AppendToOutgoingContext(t.Context(), "authorization", "Bearer "+token), conn, "Ping")
+	require.NoError(t, err)
+	require.EqualValues(t, 42, out.GetValue())
+}
+
+func TestServer_MapsErrors(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		wantCode codes.Code
+	}{
+		{name: "not found", err: response.NotFound(errors.New("user not found")), wantCode: codes.NotFound},
+		{name: "conflict", err: response.Conflict(errors.New("already exists")), wantCode: codes.AlreadyExists},
+		{name: "unexpected error", err: errors.New("boom"), wantCode: codes.Internal},
+		{name: "panic", wantCode: codes.Internal},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			conn, _ := newTestServer(t, func(ctx context.Context) error {
+				if tc.err == nil {
+					panic("unexpected")
+				}
+				return tc.err
+			})
+
+			_, err := invoke(t.Context(), conn, "PublicPing")
+			require.Equal(t, tc.wantCode, status.Code(err))
+		})
+	}
+}
+
+func TestToStatus_FieldViolations(t *testing.T) {
+	err := app_grpc.ToStatus(response.BadRequest(validation.Errors{"password": errors.New("is too short")}))
+
+	st := status.Convert(err)
+	require.Equal(t, codes.InvalidArgument, st.Code())
+	require.Len(t, st.Details(), 1)
+
+	badRequest, ok := st.Details()[0].(*errdetails.BadRequest)
+	require.True(t, ok)
+	require.Equal(t, "password", badRequest.GetFieldViolations()[0].GetField())
+	require.Equal(t, "is too short", badRequest.GetFieldViolations()[0].GetDescription())
+}
+
+func TestServer_RequestIDAndHealth(t *testing.T) {
+	conn, _ := newTestServer(t, func(ctx context.Context) error { return nil })
+
+	var header metadata.MD
+	_, err := invoke(metadata.AppendToOutgoingContext(t.Context(), "x-request-id", "req-1"), conn, "PublicPing", grpc.Header(&header))
+	require.NoError(t, err)
+	require.Equal(t, []string{"req-1"}, header.Get("x-request-id"))
+
+	header = nil
+	_, err = invoke(t.Context(), conn, "PublicPing", grpc.Header(&header))
+	require.NoError(t, err)
+	require.NotEmpty(t, header.Get("x-request-id"))
+
+	// The health service does not require authentication.
+	res, err := healthpb.NewHealthClient(conn).Check(t.Context(), &healthpb.HealthCheckRequest{})
+	require.NoError(t, err)
+	require.Equal(t, healthpb.HealthCheckResponse_SERVING, res.GetStatus())
+}
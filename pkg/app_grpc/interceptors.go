@@ -0,0 +1,154 @@
+package app_grpc
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/logging"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	requestIDMetadataKey     = "x-request-id"
+	authorizationMetadataKey = "authorization"
+)
+
+func recoveryInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp any, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = status.Errorf(codes.Internal, "panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, req)
+}
+
+// requestIDInterceptor forwards the x-request-id metadata of the caller, or
+// generates one, and stores it under logging.RequestIDContextKey.
+func requestIDInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	requestID := firstMetadataValue(ctx, requestIDMetadataKey)
+	if requestID == "" {
+		requestID = uuid.New().String()
+	}
+
+	_ = grpc.SetHeader(ctx, metadata.Pairs(requestIDMetadataKey, requestID))
+
+	return handler(context.WithValue(ctx, logging.RequestIDContextKey, requestID), req)
+}
+
+func tracingInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	ctx = otel.GetTextMapPropagator().Extract(ctx, metadataCarrier(md))
+
+	ctx, span := instrumentation.NewTraceSpan(ctx, info.FullMethod)
+	defer span.End()
+
+	resp, err := handler(ctx, req)
+	if err != nil {
+		instrumentation.RecordSpanError(span, err)
+	}
+
+	return resp, err
+}
+
+func loggingInterceptor(logger *zerolog.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		start := time.Now()
+
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		event := logger.Info()
+		if code == codes.Internal || code == codes.Unknown {
+			event = logger.Error().Err(err)
+		}
+
+		requestID, _ := ctx.Value(logging.RequestIDContextKey).(string)
+		event.Ctx(ctx).
+			Str("method", info.FullMethod).
+			Str("code", code.String()).
+			Str("request_id", requestID).
+			Dur("latency", time.Since(start)).
+			Msg("[GRPCServer]Request")
+
+		return resp, err
+	}
+}
+
+// errorInterceptor converts handler errors into gRPC status errors, see ToStatus.
+func errorInterceptor(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+
+	return resp, ToStatus(err)
+}
+
+// authInterceptor verifies the bearer token in the authorization metadata and stores
+// its claims under types.CredentialDataContextKey.
+func authInterceptor(jwtFactory *jwt.JWTFactory, isPublic func(fullMethod string) bool) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		if isPublic(info.FullMethod) || strings.HasPrefix(info.FullMethod, "/grpc.") {
+			return handler(ctx, req)
+		}
+
+		token := strings.TrimPrefix(firstMetadataValue(ctx, authorizationMetadataKey), "Bearer ")
+		if token == "" {
+			return nil, response.Unauthorized(app_error.ErrInvalidToken)
+		}
+
+		claims, err := jwtFactory.VerifyJWT(ctx, token)
+		if errors.Is(err, app_error.ErrTokenCheckUnavailable) {
+			// Fail-closed while the revoked tokens cannot be checked, the token may be valid.
+			return nil, err
+		}
+
+		if err != nil {
+			return nil, response.Unauthorized(app_error.ErrInvalidToken)
+		}
+
+		return handler(context.WithValue(ctx, types.CredentialDataContextKey, claims), req)
+	}
+}
+
+func firstMetadataValue(ctx context.Context, key string) string {
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+// metadataCarrier adapts incoming metadata to a propagation.TextMapCarrier.
+type metadataCarrier metadata.MD
+
+func (c metadataCarrier) Get(key string) string {
+	if values := metadata.MD(c).Get(key); len(values) > 0 {
+		return values[0]
+	}
+
+	return ""
+}
+
+func (c metadataCarrier) Set(key, value string) { metadata.MD(c).Set(key, value) }
+
+func (c metadataCarrier) Keys() []string {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+
+	return keys
+}
@@ -0,0 +1,58 @@
+package app_grpc
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
+	"github.com/rs/zerolog"
+	"google.golang.org/grpc"
+)
+
+type GRPCConfig struct {
+	Enable     bool   // Starts the gRPC server next to the HTTP server.
+	Port       string // The port on which the server will listen.
+	Reflection bool   // Registers the server reflection service, e.g. for grpcurl.
+}
+
+func (c GRPCConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Port, validation.When(c.Enable, validation.Required, is.Port)),
+	)
+}
+
+type grpcRequest struct {
+	IsObservabilityEnable bool
+	Logger                *zerolog.Logger
+	JWTFactory            *jwt.JWTFactory
+	Interceptors          []grpc.UnaryServerInterceptor
+}
+
+type GRPCOptionFn func(*grpcRequest)
+
+// WithTracing starts a span for every call, continuing the trace of the caller.
+func WithTracing() GRPCOptionFn {
+	return func(r *grpcRequest) { r.IsObservabilityEnable = true }
+}
+
+// WithLogger logs every call with its status code and latency.
+func WithLogger(logger *zerolog.Logger) GRPCOptionFn {
+	return func(r *grpcRequest) { r.Logger = logger }
+}
+
+// WithAuth requires a valid bearer token on every method that is not allowed
+// with AllowUnauthenticated.
+func WithAuth(jwtFactory *jwt.JWTFactory) GRPCOptionFn {
+	return func(r *grpcRequest) { r.JWTFactory = jwtFactory }
+}
+
+// WithInterceptors runs interceptors after the standard chain, once the caller
+// is authenticated.
+func WithInterceptors(interceptors ...grpc.UnaryServerInterceptor) GRPCOptionFn {
+	return func(r *grpcRequest) { r.Interceptors = append(r.Interceptors, interceptors...) }
+}
+
+func defaultGRPCRequest() *grpcRequest {
+	nop := zerolog.Nop()
+
+	return &grpcRequest{Logger: &nop}
+}
@@ -0,0 +1,324 @@
+// Package cache provides a generic two-tier cache with an in-process LRU in front
+// of Redis.
+//
+// Concurrent misses for the same key are collapsed with singleflight, "not found"
+// results can be cached for a shorter time, TTLs are jittered to avoid expiry
+// stampedes and instances invalidate each other's in-process layer over Redis
+// pub/sub.
+//
+//	users := cache.New[entities.User]("users", redisClient, cfg)
+//	go users.Listen(ctx)
+//
+//	user, err := users.GetOrLoad(ctx, "id:1", func(ctx context.Context) (entities.User, error) {
+//	    return repo.UserDetail(ctx, entities.WithID(1))
+//	})
+package cache
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/degradation"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/invopop/validation"
+	"golang.org/x/sync/singleflight"
+)
+
+var (
+	// ErrMiss is returned by Get when the key is not cached in any layer.
+	ErrMiss = errors.New("cache: miss")
+	// ErrNotFound is returned when the key is cached as not found. Loaders return
+	// an error wrapping ErrNotFound to have the result negatively cached.
+	ErrNotFound = errors.New("cache: not found")
+)
+
+// Config holds the configuration of a two-tier cache.
+type Config struct {
+	LocalSize   int           // Maximum number of in-process entries, 0 disables the in-process layer.
+	LocalTTL    time.Duration // Expiration of in-process entries.
+	TTL         time.Duration // Expiration of Redis entries.
+	NegativeTTL time.Duration // Expiration of "not found" entries, 0 disables negative caching.
+	Jitter      float64       // Fraction of the TTL randomly added or removed, e.g. 0.1 for ±10%.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.LocalSize, validation.Min(0)),
+		validation.Field(&c.LocalTTL, validation.Min(time.Duration(0))),
+		validation.Field(&c.TTL, validation.Min(time.Duration(0))),
+		validation.Field(&c.NegativeTTL, validation.Min(time.Duration(0))),
+		validation.Field(&c.Jitter, validation.Min(0.0), validation.Max(1.0)),
+	)
+}
+
+// LoaderFunc loads a value from the source of truth on a cache miss.
+type LoaderFunc[T any] func(ctx context.Context) (T, error)
+
+// envelope is the Redis representation of a cached value.
+type envelope[T any] struct {
+	Value    T    `json:"value"`
+	NotFound bool `json:"not_found,omitempty"`
+}
+
+// invalidation is the pub/sub message used to evict keys on other instances.
+type invalidation struct {
+	Origin string   `json:"origin"`
+	Keys   []string `json:"keys"`
+}
+
+// Cache is a typed two-tier cache. The zero value is not usable, use New.
+type Cache[T any] struct {
+	name     string
+	cfg      Config
+	local    *lru[T]
+	client   redis.UniversalClient
+	group    singleflight.Group
+	instance string
+	guard    *degradation.Guard
+}
+
+// Option configures a Cache.
+type Option func(*options)
+
+type options struct {
+	guard *degradation.Guard
+}
+
+// WithGuard applies the policy of guard when Redis cannot be read. Fail-open
+// treats it as a miss and loads the value, fail-closed returns the error
+// instead of loading. Without a guard Redis errors are misses.
+func WithGuard(guard *degradation.Guard) Option {
+	return func(o *options) { o.guard = guard }
+}
+
+// New creates a cache namespaced by name. client may be nil to only use the
+// in-process layer.
+func New[T any](name string, client redis.UniversalClient, cfg Config, opts ...Option) *Cache[T] {
+	var o options
+	for _, opt := range opts {
+		opt(&o)
+	}
+
+	return &Cache[T]{
+		name:     name,
+		cfg:      cfg,
+		local:    newLRU[T](cfg.LocalSize),
+		client:   client,
+		instance: uuid.NewString(),
+		guard:    o.guard,
+	}
+}
+
+// Get returns the cached value for key. It returns ErrNotFound for negatively cached
+// keys and ErrMiss when the key is not cached. When Redis fails it returns
+// ErrMiss, or degradation.ErrUnavailable under a fail-closed guard.
+func (c *Cache[T]) Get(ctx context.Context, key string) (value T, err error) {
+	if e, ok := c.local.get(key); ok {
+		if e.notFound {
+			return value, ErrNotFound
+		}
+
+		return e.value, nil
+	}
+
+	if c.client == nil {
+		return value, ErrMiss
+	}
+
+	raw, err := c.client.Get(ctx, c.redisKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			c.guard.Succeed(ctx)
+			return value, ErrMiss
+		}
+
+		if err := c.guard.Fail(ctx, err); err != nil {
+			return value, err
+		}
+
+		return value, ErrMiss
+	}
+
+	c.guard.Succeed(ctx)
+
+	var cached envelope[T]
+	if err := json.Unmarshal(raw, &cached); err != nil {
+		return value, ErrMiss
+	}
+
+	if cached.NotFound {
+		c.local.set(&entry[T]{key: key, notFound: true, expiresAt: c.expiresAt(c.localNegativeTTL())})
+		return value, ErrNotFound
+	}
+
+	c.local.set(&entry[T]{key: key, value: cached.Value, expiresAt: c.expiresAt(c.cfg.LocalTTL)})
+
+	return cached.Value, nil
+}
+
+// Set stores value in both layers.
+func (c *Cache[T]) Set(ctx context.Context, key string, value T) error {
+	c.local.set(&entry[T]{key: key, value: value, expiresAt: c.expiresAt(c.cfg.LocalTTL)})
+
+	return c.setRedis(ctx, key, envelope[T]{Value: value}, c.cfg.TTL)
+}
+
+// SetNotFound negatively caches key. It is a no-op when NegativeTTL is 0.
+func (c *Cache[T]) SetNotFound(ctx context.Context, key string) error {
+	if c.cfg.NegativeTTL <= 0 {
+		return nil
+	}
+
+	c.local.set(&entry[T]{key: key, notFound: true, expiresAt: c.expiresAt(c.localNegativeTTL())})
+
+	return c.setRedis(ctx, key, envelope[T]{NotFound: true}, c.cfg.NegativeTTL)
+}
+
+// Delete removes keys from both layers and asks the other instances to evict them
+// from their in-process layer.
+func (c *Cache[T]) Delete(ctx context.Context, keys ...string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+
+	c.local.delete(keys...)
+
+	if c.client == nil {
+		return nil
+	}
+
+	redisKeys := make([]string, 0, len(keys))
+	for _, key := range keys {
+		redisKeys = append(redisKeys, c.redisKey(key))
+	}
+
+	if err := c.client.Del(ctx, redisKeys...).Err(); err != nil {
+		return err
+	}
+
+	message, err := json.Marshal(invalidation{Origin: c.instance, Keys: keys})
+	if err != nil {
+		return err
+	}
+
+	return c.client.Publish(ctx, c.channel(), message).Err()
+}
+
+// GetOrLoad returns the cached value for key or calls loader on a miss. Concurrent
+// misses for the same key share a single loader call. Loader errors wrapping
+// ErrNotFound are negatively cached and returned as ErrNotFound. Under a
+// fail-closed guard a Redis failure is returned instead of loading.
+func (c *Cache[T]) GetOrLoad(ctx context.Context, key string, loader LoaderFunc[T]) (T, error) {
+	value, err := c.Get(ctx, key)
+	if !errors.Is(err, ErrMiss) {
+		return value, err
+	}
+
+	result, err, _ := c.group.Do(key, func() (any, error) {
+		if value, err := c.Get(ctx, key); !errors.Is(err, ErrMiss) {
+			return value, err
+		}
+
+		value, err := loader(ctx)
+		if err != nil {
+			if errors.Is(err, ErrNotFound) {
+				_ = c.SetNotFound(ctx, key)
+				return value, ErrNotFound
+			}
+
+			return value, err
+		}
+
+		_ = c.Set(ctx, key, value)
+
+		return value, nil
+	})
+
+	if result == nil {
+		var zero T
+		return zero, err
+	}
+
+	return result.(T), err
+}
+
+// Listen subscribes to invalidations published by other instances and evicts the
+// keys from the in-process layer until ctx is done. The subscription is made
+// again with a backoff while Redis is unavailable, and the whole in-process
+// layer is purged every time it is made since invalidations may have been
+// missed.
+func (c *Cache[T]) Listen(ctx context.Context) error {
+	if c.client == nil {
+		<-ctx.Done()
+		return nil
+	}
+
+	appRedis.Subscribe(ctx, c.client, c.channel(), appRedis.RetryConfig{}, c.local.purge, func(payload string) {
+		var event invalidation
+		if err := json.Unmarshal([]byte(payload), &event); err != nil || event.Origin == c.instance {
+			return
+		}
+
+		c.local.delete(event.Keys...)
+	})
+
+	return nil
+}
+
+func (c *Cache[T]) setRedis(ctx context.Context, key string, value envelope[T], ttl time.Duration) error {
+	if c.client == nil {
+		return nil
+	}
+
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+
+	return c.client.Set(ctx, c.redisKey(key), raw, c.jitter(ttl)).Err()
+}
+
+func (c *Cache[T]) redisKey(key string) string {
+	return fmt.Sprintf(appRedis.REDIS_PREFIX_KEY_CACHE.String(), c.name+":"+key)
+}
+
+func (c *Cache[T]) channel() string {
+	return fmt.Sprintf(appRedis.REDIS_PREFIX_KEY_CACHE.String(), c.name+":invalidations")
+}
+
+func (c *Cache[T]) localNegativeTTL() time.Duration {
+	if c.cfg.LocalTTL > 0 && c.cfg.LocalTTL < c.cfg.NegativeTTL {
+		return c.cfg.LocalTTL
+	}
+
+	return c.cfg.NegativeTTL
+}
+
+func (c *Cache[T]) expiresAt(ttl time.Duration) time.Time {
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(c.jitter(ttl))
+}
+
+// jitter randomly spreads ttl by ±Jitter so that keys written together do not
+// expire together.
+func (c *Cache[T]) jitter(ttl time.Duration) time.Duration {
+	if c.cfg.Jitter <= 0 || ttl <= 0 {
+		return ttl
+	}
+
+	spread := float64(ttl) * min(c.cfg.Jitter, 1)
+	if jittered := ttl + time.Duration((rand.Float64()*2-1)*spread); jittered > 0 {
+		return jittered
+	}
+
+	return ttl
+}
@@ -0,0 +1,235 @@
+package cache_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/cache"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/degradation"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	ID   int64  `json:"id"`
+	Name string `json:"name"`
+}
+
+var testConfig = cache.Config{
+	LocalSize:   2,
+	LocalTTL:    time.Minute,
+	TTL:         5 * time.Minute,
+	NegativeTTL: 30 * time.Second,
+	Jitter:      0.1,
+}
+
+func setupRedis(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return mr, client
+}
+
+func TestCache_GetOrLoad(t *testing.T) {
+	_, client := setupRedis(t)
+	ctx := context.Background()
+
+	c := cache.New[user]("users", client, testConfig)
+
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (user, error) {
+		calls.Add(1)
+		time.Sleep(20 * time.Millisecond)
+		return user{ID: 1, Name: "john"}, nil
+	}
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			got, err := c.GetOrLoad(ctx, "id:1", loader)
+			require.NoError(t, err)
+			require.Equal(t, "john", got.Name)
+		}()
+	}
+	wg.Wait()
+
+	require.Equal(t, int32(1), calls.Load(), "concurrent misses should share one load")
+
+	got, err := c.GetOrLoad(ctx, "id:1", loader)
+	require.NoError(t, err)
+	require.Equal(t, int64(1), got.ID)
+	require.Equal(t, int32(1), calls.Load())
+}
+
+func TestCache_NegativeCaching(t *testing.T) {
+	mr, client := setupRedis(t)
+	ctx := context.Background()
+
+	c := cache.New[user]("users", client, testConfig)
+
+	var calls atomic.Int32
+	loader := func(ctx context.Context) (user, error) {
+		calls.Add(1)
+		return user{}, errors.Join(cache.ErrNotFound, errors.New("user not found"))
+	}
+
+	for range 3 {
+		_, err := c.GetOrLoad(ctx, "id:404", loader)
+		require.ErrorIs(t, err, cache.ErrNotFound)
+	}
+
+	require.Equal(t, int32(1), calls.Load())
+
+	ttl := mr.TTL("cache:users:id:404")
+	require.Greater(t, ttl, time.Duration(0))
+	require.LessOrEqual(t, ttl, 33*time.Second)
+}
+
+func TestCache_LoaderError(t *testing.T) {
+	_, client := setupRedis(t)
+	ctx := context.Background()
+
+	c := cache.New[user]("users", client, testConfig)
+	errLoad := errors.New("database is down")
+
+	_, err := c.GetOrLoad(ctx, "id:1", func(ctx context.Context) (user, error) { return user{}, errLoad })
+	require.ErrorIs(t, err, errLoad)
+
+	_, err = c.Get(ctx, "id:1")
+	require.ErrorIs(t, err, cache.ErrMiss, "errors other than not found must not be cached")
+}
+
+func TestCache_SharedRedisLayer(t *testing.T) {
+	_, client := setupRedis(t)
+	ctx := context.Background()
+
+	first := cache.New[user]("users", client, testConfig)
+	second := cache.New[user]("users", client, testConfig)
+
+	require.NoError(t, first.Set(ctx, "id:1", user{ID: 1, Name: "john"}))
+
+	got, err := second.Get(ctx, "id:1")
+	require.NoError(t, err)
+	require.Equal(t, "john", got.Name)
+}
+
+func TestCache_LocalEviction(t *testing.T) {
+	ctx := context.Background()
+
+	c := cache.New[user]("users", nil, testConfig)
+
+	require.NoError(t, c.Set(ctx, "id:1", user{ID: 1}))
+	require.NoError(t, c.Set(ctx, "id:2", user{ID: 2}))
+	require.NoError(t, c.Set(ctx, "id:3", user{ID: 3}))
+
+	_, err := c.Get(ctx, "id:1")
+	require.ErrorIs(t, err, cache.ErrMiss, "least recently used entry should be evicted")
+
+	got, err := c.Get(ctx, "id:3")
+	require.NoError(t, err)
+	require.Equal(t, int64(3), got.ID)
+}
+
+func TestCache_Invalidation(t *testing.T) {
+	_, client := setupRedis(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := cache.New[user]("users", client, testConfig)
+	second := cache.New[user]("users", client, testConfig)
+
+	go func() { _ = second.Listen(ctx) }()
+
+	require.NoError(t, first.Set(ctx, "id:1", user{ID: 1, Name: "john"}))
+
+	_, err := second.Get(ctx, "id:1")
+	require.NoError(t, err)
+
+	// Give the subscription time to be established before publishing.
+	require.Eventually(t, func() bool {
+		return client.PubSubNumSub(ctx, "cache:users:invalidations").Val()["cache:users:invalidations"] == 1
+	}, time.Second, 10*time.Millisecond)
+
+	require.NoError(t, first.Delete(ctx, "id:1"))
+
+	require.Eventually(t, func() bool {
+		_, err := second.Get(ctx, "id:1")
+		return errors.Is(err, cache.ErrMiss)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCache_InvalidationStartsWhileRedisIsDown(t *testing.T) {
+	mr, client := setupRedis(t)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	first := cache.New[user]("users", client, testConfig)
+	second := cache.New[user]("users", client, testConfig)
+
+	mr.Close()
+	go func() { _ = second.Listen(ctx) }()
+
+	time.Sleep(20 * time.Millisecond) // Let the first subscriptions fail.
+	require.NoError(t, mr.Restart())
+
+	require.Eventually(t, func() bool {
+		return client.PubSubNumSub(ctx, "cache:users:invalidations").Val()["cache:users:invalidations"] == 1
+	}, 5*time.Second, 10*time.Millisecond, "the subscription is made once Redis answers")
+
+	require.NoError(t, first.Set(ctx, "id:1", user{ID: 1, Name: "john"}))
+	_, err := second.Get(ctx, "id:1")
+	require.NoError(t, err)
+
+	require.NoError(t, first.Delete(ctx, "id:1"))
+
+	require.Eventually(t, func() bool {
+		_, err := second.Get(ctx, "id:1")
+		return errors.Is(err, cache.ErrMiss)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestCache_RedisDown(t *testing.T) {
+	mr, client := setupRedis(t)
+	ctx := context.Background()
+
+	loader := func(ctx context.Context) (user, error) { return user{ID: 1, Name: "john"}, nil }
+
+	failOpen := degradation.NewGuard("users_cache", degradation.FailOpen, nil)
+	failClosed := degradation.NewGuard("users_cache", degradation.FailClosed, nil)
+
+	open := cache.New[user]("users", client, cache.Config{TTL: time.Minute}, cache.WithGuard(failOpen))
+	closed := cache.New[user]("users", client, cache.Config{TTL: time.Minute}, cache.WithGuard(failClosed))
+
+	mr.Close()
+
+	got, err := open.GetOrLoad(ctx, "id:1", loader)
+	require.NoError(t, err, "fail-open loads the value")
+	require.Equal(t, "john", got.Name)
+	require.True(t, failOpen.Degraded())
+
+	_, err = closed.GetOrLoad(ctx, "id:1", loader)
+	require.ErrorIs(t, err, degradation.ErrUnavailable, "fail-closed does not load")
+	require.True(t, failClosed.Degraded())
+
+	require.NoError(t, mr.Restart())
+
+	// The client waits a moment after failed dials before dialing again.
+	require.Eventually(t, func() bool {
+		_, err := closed.GetOrLoad(ctx, "id:1", loader)
+		return err == nil
+	}, 3*time.Second, 50*time.Millisecond)
+	require.False(t, failClosed.Degraded(), "the guard recovers once Redis answers")
+}
@@ -0,0 +1,105 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+)
+
+// entry is a value held by the in-process layer.
+type entry[T any] struct {
+	key       string
+	value     T
+	notFound  bool
+	expiresAt time.Time
+}
+
+func (e *entry[T]) expired(now time.Time) bool { return !e.expiresAt.IsZero() && now.After(e.expiresAt) }
+
+// lru is a size-bounded, thread-safe least recently used cache with per-entry expiry.
+type lru[T any] struct {
+	mu       sync.Mutex
+	capacity int
+	items    map[string]*list.Element
+	order    *list.List
+}
+
+func newLRU[T any](capacity int) *lru[T] {
+	return &lru[T]{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+	}
+}
+
+func (l *lru[T]) get(key string) (*entry[T], bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	element, ok := l.items[key]
+	if !ok {
+		return nil, false
+	}
+
+	e := element.Value.(*entry[T])
+	if e.expired(time.Now()) {
+		l.removeElement(element)
+		return nil, false
+	}
+
+	l.order.MoveToFront(element)
+
+	return e, true
+}
+
+func (l *lru[T]) set(e *entry[T]) {
+	if l.capacity <= 0 {
+		return
+	}
+
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if element, ok := l.items[e.key]; ok {
+		element.Value = e
+		l.order.MoveToFront(element)
+		return
+	}
+
+	l.items[e.key] = l.order.PushFront(e)
+
+	for l.order.Len() > l.capacity {
+		l.removeElement(l.order.Back())
+	}
+}
+
+func (l *lru[T]) delete(keys ...string) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	for _, key := range keys {
+		if element, ok := l.items[key]; ok {
+			l.removeElement(element)
+		}
+	}
+}
+
+func (l *lru[T]) purge() {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.items = make(map[string]*list.Element)
+	l.order.Init()
+}
+
+func (l *lru[T]) len() int {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	return l.order.Len()
+}
+
+func (l *lru[T]) removeElement(element *list.Element) {
+	l.order.Remove(element)
+	delete(l.items, element.Value.(*entry[T]).key)
+}
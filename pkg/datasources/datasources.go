@@ -0,0 +1,32 @@
+package datasources
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"gorm.io/gorm"
+)
+
+const (
+	DriverMySQL    = "mysql"
+	DriverPostgres = "postgres"
+)
+
+var ErrUnsupportedDriver = errors.New("unsupported database driver")
+
+// NewDB opens a connection to the database selected by cfg.Driver.
+//
+// Both drivers are opened with TranslateError enabled, so constraint violations
+// are reported as the same gorm errors, e.g. gorm.ErrDuplicatedKey, whichever
+// database is used.
+func NewDB(ctx context.Context, cfg DatabaseConfig, opts ...Option) (*gorm.DB, error) {
+	switch cfg.Driver {
+	case "", DriverMySQL:
+		return NewMySQLDB(ctx, cfg, opts...)
+	case DriverPostgres:
+		return NewPostgresDB(ctx, cfg, opts...)
+	default:
+		return nil, fmt.Errorf("%w: %q", ErrUnsupportedDriver, cfg.Driver)
+	}
+}
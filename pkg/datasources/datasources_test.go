@@ -0,0 +1,17 @@
+package datasources_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewDB_UnsupportedDriver(t *testing.T) {
+	t.Parallel()
+
+	db, err := datasources.NewDB(context.Background(), datasources.DatabaseConfig{Driver: "sqlite"})
+	require.ErrorIs(t, err, datasources.ErrUnsupportedDriver)
+	require.Nil(t, db)
+}
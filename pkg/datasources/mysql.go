@@ -26,7 +26,7 @@ func NewMySQLDB(ctx context.Context, cfg DatabaseConfig, opts ...Option) (*gorm.
 		c.nonProductionConnectionPool()
 	}
 
-	gormCfg := &gorm.Config{SkipDefaultTransaction: true}
+	gormCfg := &gorm.Config{SkipDefaultTransaction: true, TranslateError: true}
 	if !c.debug {
 		gormCfg.Logger = logger.Default.LogMode(logger.Silent)
 	}
@@ -5,10 +5,14 @@ import (
 	"time"
 
 	"github.com/go-sql-driver/mysql"
+	"github.com/invopop/validation"
+	"github.com/invopop/validation/is"
 )
 
 // DatabaseConfig holds the configuration for the database connection.
 type DatabaseConfig struct {
+	// Driver selects the database, DriverMySQL or DriverPostgres. Defaults to MySQL.
+	Driver string
 	// Host is the address of the database server.
 	Host string
 	// Port is the port number of the database server.
@@ -18,13 +22,23 @@ type DatabaseConfig struct {
 	// UserName is the username for database authentication.
 	UserName string
 	// Password is the password for database authentication.
-	Password string
+	Password string `secret:"true"`
 	// Schema is the database schema (used for PostgreSQL).
 	Schema string
 	// Debug enables query debugging when set to true.
 	Debug bool
 }
 
+func (c DatabaseConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Driver, validation.In("", DriverMySQL, DriverPostgres).Error("must be mysql or postgres")),
+		validation.Field(&c.Host, validation.Required),
+		validation.Field(&c.Port, validation.Required, is.Port),
+		validation.Field(&c.DBName, validation.Required),
+		validation.Field(&c.UserName, validation.Required),
+	)
+}
+
 func (d DatabaseConfig) toMYSQLConfig() mysql.Config {
 	return mysql.Config{
 		User:                 d.UserName,
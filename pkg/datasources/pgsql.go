@@ -17,9 +17,12 @@ func NewPostgresDB(ctx context.Context, cfg DatabaseConfig, opts ...Option) (*go
 	c := defaultConfig()
 	c.debug = cfg.Debug
 	c.schema = cfg.Schema
+	if c.schema == "" {
+		c.schema = "public"
+	}
 
 	c.dsn = fmt.Sprintf("user=%s password=%s host=%s port=%s dbname=%s search_path=%s sslmode=disable TimeZone=UTC",
-		cfg.UserName, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, cfg.Schema,
+		cfg.UserName, cfg.Password, cfg.Host, cfg.Port, cfg.DBName, c.schema,
 	)
 
 	for _, opt := range opts {
@@ -0,0 +1,252 @@
+package datasources
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"gorm.io/gorm"
+)
+
+// UnitOfWork runs functions in a database transaction that travels in the
+// context. Repositories pick the transaction up with Conn, so a single unit can
+// span the writes of several repositories.
+type UnitOfWork interface {
+	// Do runs fn in a transaction. The transaction is committed when fn returns
+	// nil and rolled back when fn returns an error or panics.
+	//
+	// When ctx already carries a transaction, fn runs inside a savepoint of that
+	// transaction instead, and an error only rolls back to the savepoint.
+	//
+	// A transaction that fails on a deadlock or a serialization failure is retried
+	// with a new transaction, so fn may run more than once and must not have side
+	// effects outside the database. Register those with AfterCommit.
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+type txContextKey struct{}
+
+// txScope is the transaction carried by the context of a unit of work, or one
+// of its savepoints.
+type txScope struct {
+	tx         *gorm.DB
+	savepoints *int // Number of savepoints created in the transaction, used to name them.
+	hooks      []func(ctx context.Context) error
+}
+
+type unitOfWork struct {
+	db *gorm.DB
+
+	txOptions       *sql.TxOptions
+	maxRetries      int
+	retryBackoff    time.Duration
+	maxRetryBackoff time.Duration
+}
+
+// UnitOfWorkOption configures a UnitOfWork.
+type UnitOfWorkOption func(*unitOfWork)
+
+// WithTxOptions sets the isolation level and read only mode of the transactions.
+func WithTxOptions(opts *sql.TxOptions) UnitOfWorkOption {
+	return func(u *unitOfWork) { u.txOptions = opts }
+}
+
+// WithMaxRetries sets how many times a transaction that failed on a deadlock or a
+// serialization failure is retried. Zero disables retries.
+func WithMaxRetries(n int) UnitOfWorkOption {
+	return func(u *unitOfWork) { u.maxRetries = n }
+}
+
+// WithRetryBackoff sets the delay before the first retry. It doubles on every
+// following retry, up to max.
+func WithRetryBackoff(base, max time.Duration) UnitOfWorkOption {
+	return func(u *unitOfWork) {
+		u.retryBackoff = base
+		u.maxRetryBackoff = max
+	}
+}
+
+// NewUnitOfWork creates a UnitOfWork that opens its transactions on db.
+func NewUnitOfWork(db *gorm.DB, opts ...UnitOfWorkOption) UnitOfWork {
+	u := &unitOfWork{
+		db:              db,
+		maxRetries:      3,
+		retryBackoff:    20 * time.Millisecond,
+		maxRetryBackoff: time.Second,
+	}
+
+	for _, opt := range opts {
+		opt(u)
+	}
+
+	return u
+}
+
+func (u *unitOfWork) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if parent, ok := ctx.Value(txContextKey{}).(*txScope); ok {
+		return u.savepoint(ctx, parent, fn)
+	}
+
+	for attempt := 0; ; attempt++ {
+		hooks, err := u.transaction(ctx, fn)
+		if err == nil {
+			return runHooks(ctx, hooks)
+		}
+
+		if attempt >= u.maxRetries || !IsRetryable(err) {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return err
+		case <-time.After(u.backoff(attempt)):
+		}
+	}
+}
+
+// transaction runs fn in a new transaction and returns the hooks registered for
+// after the commit.
+func (u *unitOfWork) transaction(ctx context.Context, fn func(ctx context.Context) error) (hooks []func(ctx context.Context) error, err error) {
+	tx := u.db.WithContext(ctx).Begin(u.txOptions)
+	if tx.Error != nil {
+		return nil, tx.Error
+	}
+
+	scope := &txScope{tx: tx, savepoints: new(int)}
+
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, scope)); err != nil {
+		if rollbackErr := tx.Rollback().Error; rollbackErr != nil {
+			return nil, errors.Join(err, fmt.Errorf("rollback: %w", rollbackErr))
+		}
+
+		return nil, err
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return nil, err
+	}
+
+	return scope.hooks, nil
+}
+
+// savepoint runs fn inside a savepoint of the parent transaction. The hooks of fn
+// are handed to the parent when fn succeeds and discarded otherwise.
+func (u *unitOfWork) savepoint(ctx context.Context, parent *txScope, fn func(ctx context.Context) error) error {
+	*parent.savepoints++
+	name := fmt.Sprintf("sp_%d", *parent.savepoints)
+
+	if err := parent.tx.SavePoint(name).Error; err != nil {
+		return err
+	}
+
+	scope := &txScope{tx: parent.tx, savepoints: parent.savepoints}
+
+	defer func() {
+		if p := recover(); p != nil {
+			parent.tx.RollbackTo(name)
+			panic(p)
+		}
+	}()
+
+	if err := fn(context.WithValue(ctx, txContextKey{}, scope)); err != nil {
+		if rollbackErr := parent.tx.RollbackTo(name).Error; rollbackErr != nil {
+			return errors.Join(err, fmt.Errorf("rollback to savepoint: %w", rollbackErr))
+		}
+
+		return err
+	}
+
+	parent.hooks = append(parent.hooks, scope.hooks...)
+
+	return nil
+}
+
+// backoff returns the delay before the given retry, with up to 50% jitter so
+// that the transactions that deadlocked each other do not retry in lockstep.
+func (u *unitOfWork) backoff(attempt int) time.Duration {
+	delay := min(u.retryBackoff<<attempt, u.maxRetryBackoff)
+	if delay <= 0 {
+		return 0
+	}
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// runHooks runs every hook, also after one of them failed. The transaction is
+// committed at this point, so the returned error does not mean it was rolled back.
+func runHooks(ctx context.Context, hooks []func(ctx context.Context) error) error {
+	var errs []error
+	for _, hook := range hooks {
+		if err := hook(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	if err := errors.Join(errs...); err != nil {
+		return fmt.Errorf("after commit: %w", err)
+	}
+
+	return nil
+}
+
+// Conn returns db bound to ctx and to the transaction carried by ctx, if any.
+// Repositories use it for every query so that they take part in a unit of work.
+func Conn(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if scope, ok := ctx.Value(txContextKey{}).(*txScope); ok {
+		return scope.tx.WithContext(ctx)
+	}
+
+	return db.WithContext(ctx)
+}
+
+// InTx reports whether ctx carries a transaction.
+func InTx(ctx context.Context) bool {
+	_, ok := ctx.Value(txContextKey{}).(*txScope)
+	return ok
+}
+
+// AfterCommit registers fn to run once the transaction carried by ctx commits.
+// fn is dropped when the transaction, or the savepoint it was registered in, is
+// rolled back. Outside of a unit of work fn runs immediately and its error is
+// returned.
+func AfterCommit(ctx context.Context, fn func(ctx context.Context) error) error {
+	scope, ok := ctx.Value(txContextKey{}).(*txScope)
+	if !ok {
+		return fn(ctx)
+	}
+
+	scope.hooks = append(scope.hooks, fn)
+
+	return nil
+}
+
+// IsRetryable reports whether err is a deadlock or a serialization failure, after
+// which the whole transaction can be retried.
+func IsRetryable(err error) bool {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) {
+		// ER_LOCK_DEADLOCK, InnoDB rolls back the transaction.
+		return mysqlErr.Number == 1213
+	}
+
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		// serialization_failure and deadlock_detected.
+		return pgErr.Code == "40001" || pgErr.Code == "40P01"
+	}
+
+	return false
+}
@@ -0,0 +1,264 @@
+package datasources_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/go-sql-driver/mysql"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/stretchr/testify/require"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newMockDB(t *testing.T) (*gorm.DB, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(
+		gormmysql.New(gormmysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{SkipDefaultTransaction: true, Logger: logger.Default.LogMode(logger.Silent)},
+	)
+	require.NoError(t, err)
+
+	t.Cleanup(func() { require.NoError(t, mock.ExpectationsWereMet()) })
+
+	return db, mock
+}
+
+func insert(ctx context.Context, db *gorm.DB, value int) error {
+	return datasources.Conn(ctx, db).Exec("INSERT INTO items VALUES (?)", value).Error
+}
+
+func TestUnitOfWork_CommitsAndRunsHooks(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	var hookCalled bool
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		require.True(t, datasources.InTx(ctx))
+
+		require.NoError(t, datasources.AfterCommit(ctx, func(ctx context.Context) error {
+			require.False(t, datasources.InTx(ctx))
+			hookCalled = true
+			return nil
+		}))
+		require.False(t, hookCalled, "hooks wait for the commit")
+
+		return insert(ctx, db, 1)
+	})
+
+	require.NoError(t, err)
+	require.True(t, hookCalled)
+}
+
+func TestUnitOfWork_RollsBackOnError(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectRollback()
+
+	errFailed := errors.New("failed")
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		require.NoError(t, datasources.AfterCommit(ctx, func(ctx context.Context) error {
+			t.Error("hook of a rolled back transaction must not run")
+			return nil
+		}))
+
+		require.NoError(t, insert(ctx, db, 1))
+
+		return errFailed
+	})
+
+	require.ErrorIs(t, err, errFailed)
+}
+
+func TestUnitOfWork_ReportsFailedRollback(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db)
+
+	errRollback := errors.New("connection lost")
+	mock.ExpectBegin()
+	mock.ExpectRollback().WillReturnError(errRollback)
+
+	errFailed := errors.New("failed")
+	err := uow.Do(context.Background(), func(ctx context.Context) error { return errFailed })
+
+	require.ErrorIs(t, err, errFailed)
+	require.ErrorIs(t, err, errRollback)
+}
+
+func TestUnitOfWork_RollsBackOnPanic(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db)
+
+	mock.ExpectBegin()
+	mock.ExpectRollback()
+
+	require.PanicsWithValue(t, "boom", func() {
+		_ = uow.Do(context.Background(), func(ctx context.Context) error { panic("boom") })
+	})
+}
+
+func TestUnitOfWork_NestedUsesSavepoints(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db)
+
+	mock.ExpectBegin()
+	mock.ExpectExec("SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectExec("ROLLBACK TO SAVEPOINT sp_1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("SAVEPOINT sp_2").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectExec("INSERT INTO items").WithArgs(2).WillReturnResult(sqlmock.NewResult(2, 1))
+	mock.ExpectCommit()
+
+	var hooks []string
+	hook := func(name string) func(ctx context.Context) error {
+		return func(ctx context.Context) error {
+			hooks = append(hooks, name)
+			return nil
+		}
+	}
+
+	errFailed := errors.New("failed")
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		err := uow.Do(ctx, func(ctx context.Context) error {
+			require.NoError(t, datasources.AfterCommit(ctx, hook("rolled back")))
+			require.NoError(t, insert(ctx, db, 1))
+			return errFailed
+		})
+		require.ErrorIs(t, err, errFailed)
+
+		return uow.Do(ctx, func(ctx context.Context) error {
+			require.NoError(t, datasources.AfterCommit(ctx, hook("released")))
+			return insert(ctx, db, 2)
+		})
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, []string{"released"}, hooks)
+}
+
+func TestUnitOfWork_RetriesDeadlock(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db, datasources.WithMaxRetries(1), datasources.WithRetryBackoff(0, 0))
+
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnError(&mysql.MySQLError{Number: 1213, Message: "Deadlock found"})
+	mock.ExpectRollback()
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	mock.ExpectCommit()
+
+	var calls, hookCalls int
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		calls++
+		require.NoError(t, datasources.AfterCommit(ctx, func(ctx context.Context) error {
+			hookCalls++
+			return nil
+		}))
+
+		return insert(ctx, db, 1)
+	})
+
+	require.NoError(t, err)
+	require.Equal(t, 2, calls)
+	require.Equal(t, 1, hookCalls, "hooks of the failed attempt are dropped")
+}
+
+func TestUnitOfWork_GivesUpAfterMaxRetries(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db, datasources.WithMaxRetries(1), datasources.WithRetryBackoff(0, 0))
+
+	deadlock := &mysql.MySQLError{Number: 1213, Message: "Deadlock found"}
+	for range 2 {
+		mock.ExpectBegin()
+		mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnError(deadlock)
+		mock.ExpectRollback()
+	}
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error { return insert(ctx, db, 1) })
+	require.ErrorIs(t, err, deadlock)
+}
+
+func TestUnitOfWork_DoesNotRetryOtherErrors(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db, datasources.WithRetryBackoff(0, 0))
+
+	duplicate := &mysql.MySQLError{Number: 1062, Message: "Duplicate entry"}
+	mock.ExpectBegin()
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnError(duplicate)
+	mock.ExpectRollback()
+
+	err := uow.Do(context.Background(), func(ctx context.Context) error { return insert(ctx, db, 1) })
+	require.ErrorIs(t, err, duplicate)
+}
+
+func TestUnitOfWork_ReportsHookErrors(t *testing.T) {
+	db, mock := newMockDB(t)
+	uow := datasources.NewUnitOfWork(db)
+
+	mock.ExpectBegin()
+	mock.ExpectCommit()
+
+	errHook := errors.New("cache unavailable")
+	var secondHookCalled bool
+	err := uow.Do(context.Background(), func(ctx context.Context) error {
+		_ = datasources.AfterCommit(ctx, func(ctx context.Context) error { return errHook })
+		_ = datasources.AfterCommit(ctx, func(ctx context.Context) error {
+			secondHookCalled = true
+			return nil
+		})
+
+		return nil
+	})
+
+	require.ErrorIs(t, err, errHook)
+	require.True(t, secondHookCalled)
+}
+
+func TestConn_OutsideUnitOfWork(t *testing.T) {
+	db, mock := newMockDB(t)
+
+	mock.ExpectExec("INSERT INTO items").WithArgs(1).WillReturnResult(sqlmock.NewResult(1, 1))
+	require.NoError(t, insert(context.Background(), db, 1))
+
+	errHook := errors.New("failed")
+	require.ErrorIs(t, datasources.AfterCommit(context.Background(), func(ctx context.Context) error { return errHook }), errHook)
+	require.False(t, datasources.InTx(context.Background()))
+}
+
+func TestIsRetryable(t *testing.T) {
+	testCases := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{name: "mysql deadlock", err: &mysql.MySQLError{Number: 1213}, want: true},
+		{name: "mysql duplicate entry", err: &mysql.MySQLError{Number: 1062}},
+		{name: "postgres serialization failure", err: &pgconn.PgError{Code: "40001"}, want: true},
+		{name: "postgres deadlock", err: &pgconn.PgError{Code: "40P01"}, want: true},
+		{name: "postgres unique violation", err: &pgconn.PgError{Code: "23505"}},
+		{name: "wrapped", err: errors.Join(errors.New("insert"), &pgconn.PgError{Code: "40P01"}), want: true},
+		{name: "other", err: errors.New("boom")},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			require.Equal(t, tc.want, datasources.IsRetryable(tc.err))
+		})
+	}
+}
@@ -0,0 +1,183 @@
+// Package degradation decides how the features depending on Redis behave while
+// it is unavailable, and reports when they run degraded.
+//
+//	guard := degradation.NewGuard("blacklist", cfg.Blacklist, logger)
+//
+//	revoked, err := isRevoked(ctx, token)
+//	if err != nil {
+//	    if err := guard.Fail(ctx, err); err != nil {
+//	        return err // fail-closed
+//	    }
+//	} else {
+//	    guard.Succeed(ctx)
+//	}
+package degradation
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Policy is what a feature does when its dependency is unavailable.
+type Policy string
+
+const (
+	// FailOpen carries on without the dependency, e.g. accepts a token whose
+	// revocation cannot be checked.
+	FailOpen Policy = "fail-open"
+	// FailClosed rejects what cannot be checked.
+	FailClosed Policy = "fail-closed"
+)
+
+// ErrUnavailable is returned under FailClosed while the dependency is unavailable.
+var ErrUnavailable = errors.New("degradation: dependency unavailable")
+
+// Config holds the policy of every feature depending on Redis.
+type Config struct {
+	// Blacklist applies when revoked tokens cannot be checked. Defaults to fail-closed.
+	Blacklist Policy
+	// BlacklistFallbackSize and BlacklistFallbackTTL bound the revoked tokens
+	// every instance remembers, still rejected while Redis is down. Default to
+	// 10000 tokens for 10 minutes.
+	BlacklistFallbackSize int
+	BlacklistFallbackTTL  time.Duration
+	// DisableBlacklistFallback stops the instances from remembering revoked
+	// tokens, BlacklistFallbackSize is then ignored.
+	DisableBlacklistFallback bool
+	// Cache applies when the cache cannot be read: fail-open loads from the
+	// database, fail-closed returns an error to spare it. Defaults to fail-open.
+	Cache Policy
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Blacklist, validPolicy),
+		validation.Field(&c.BlacklistFallbackSize, validation.Min(0)),
+		validation.Field(&c.BlacklistFallbackTTL, validation.Min(time.Duration(0))),
+		validation.Field(&c.Cache, validPolicy),
+	)
+}
+
+var validPolicy = validation.In(Policy(""), FailOpen, FailClosed).Error("must be fail-open or fail-closed")
+
+// WithDefaults returns the config with the defaults of the unset settings.
+func (c Config) WithDefaults() Config {
+	if c.Blacklist == "" {
+		c.Blacklist = FailClosed
+	}
+
+	switch {
+	case c.DisableBlacklistFallback:
+		c.BlacklistFallbackSize = 0
+	case c.BlacklistFallbackSize == 0:
+		c.BlacklistFallbackSize = 10000
+	}
+
+	if c.BlacklistFallbackTTL <= 0 {
+		c.BlacklistFallbackTTL = 10 * time.Minute
+	}
+
+	if c.Cache == "" {
+		c.Cache = FailOpen
+	}
+
+	return c
+}
+
+// Metrics of the degraded features, reported through the global meter provider.
+var (
+	meter = otel.Meter("github.com/DoWithLogic/golang-clean-architecture/pkg/degradation")
+
+	failures, _ = meter.Int64Counter("degradation.failures",
+		metric.WithDescription("Operations a feature ran without its unavailable dependency, by feature and policy."))
+	degraded, _ = meter.Int64UpDownCounter("degradation.degraded",
+		metric.WithDescription("Features running degraded, by feature and policy."))
+)
+
+// Guard applies the policy of a feature and tracks whether it runs degraded.
+// A nil Guard fails open without reporting.
+type Guard struct {
+	feature  string
+	policy   Policy
+	logger   *zerolog.Logger
+	attrs    metric.MeasurementOption
+	degraded atomic.Bool
+}
+
+// NewGuard creates the guard of feature, fail-open when policy is empty. A
+// nil logger discards the logs.
+func NewGuard(feature string, policy Policy, logger *zerolog.Logger) *Guard {
+	if policy == "" {
+		policy = FailOpen
+	}
+
+	if logger == nil {
+		nop := zerolog.Nop()
+		logger = &nop
+	}
+
+	return &Guard{
+		feature: feature,
+		policy:  policy,
+		logger:  logger,
+		attrs:   metric.WithAttributes(attribute.String("feature", feature), attribute.String("policy", string(policy))),
+	}
+}
+
+// Policy returns the policy of the feature.
+func (g *Guard) Policy() Policy {
+	if g == nil {
+		return FailOpen
+	}
+
+	return g.policy
+}
+
+// Degraded reports whether the dependency failed since it last answered.
+func (g *Guard) Degraded() bool {
+	return g != nil && g.degraded.Load()
+}
+
+// Fail records that the dependency failed with err. It returns nil under
+// FailOpen, so that the feature carries on, and err wrapped with
+// ErrUnavailable under FailClosed. Entering degraded mode is logged once.
+func (g *Guard) Fail(ctx context.Context, err error) error {
+	if g == nil {
+		return nil
+	}
+
+	failures.Add(ctx, 1, g.attrs)
+
+	if !g.degraded.Swap(true) {
+		degraded.Add(ctx, 1, g.attrs)
+		g.logger.Warn().Ctx(ctx).Err(err).
+			Str("feature", g.feature).
+			Str("policy", string(g.policy)).
+			Msg("feature degraded, its dependency is unavailable")
+	}
+
+	if g.policy == FailClosed {
+		return fmt.Errorf("%s: %w: %w", g.feature, ErrUnavailable, err)
+	}
+
+	return nil
+}
+
+// Succeed records that the dependency answered, leaving degraded mode.
+func (g *Guard) Succeed(ctx context.Context) {
+	if g == nil || !g.degraded.Load() || !g.degraded.Swap(false) {
+		return
+	}
+
+	degraded.Add(ctx, -1, g.attrs)
+	g.logger.Info().Ctx(ctx).Str("feature", g.feature).Msg("feature recovered, its dependency answers again")
+}
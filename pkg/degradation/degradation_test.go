@@ -0,0 +1,61 @@
+package degradation
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+)
+
+var errDown = errors.New("connection refused")
+
+func TestGuard(t *testing.T) {
+	var logs bytes.Buffer
+	logger := zerolog.New(&logs)
+	ctx := context.Background()
+
+	open := NewGuard("cache", FailOpen, &logger)
+	assert.NoError(t, open.Fail(ctx, errDown), "fail-open carries on")
+	assert.True(t, open.Degraded())
+
+	closed := NewGuard("blacklist", FailClosed, &logger)
+	err := closed.Fail(ctx, errDown)
+	assert.ErrorIs(t, err, ErrUnavailable)
+	assert.ErrorIs(t, err, errDown)
+
+	assert.Error(t, closed.Fail(ctx, errDown))
+	assert.Equal(t, 2, strings.Count(logs.String(), "feature degraded"), "entering degraded mode is logged once per feature")
+
+	closed.Succeed(ctx)
+	closed.Succeed(ctx)
+	assert.False(t, closed.Degraded())
+	assert.Equal(t, 1, strings.Count(logs.String(), "feature recovered"))
+}
+
+func TestGuard_Nil(t *testing.T) {
+	var guard *Guard
+
+	assert.NoError(t, guard.Fail(context.Background(), errDown))
+	assert.False(t, guard.Degraded())
+	assert.Equal(t, FailOpen, guard.Policy())
+}
+
+func TestConfig(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.Error(t, Config{Cache: "fail-safe"}.Validate())
+
+	assert.Equal(t, Config{
+		Blacklist:             FailClosed,
+		BlacklistFallbackSize: 10000,
+		BlacklistFallbackTTL:  10 * time.Minute,
+		Cache:                 FailOpen,
+	}, Config{}.WithDefaults())
+
+	disabled := Config{BlacklistFallbackSize: 500, DisableBlacklistFallback: true}.WithDefaults()
+	assert.Zero(t, disabled.BlacklistFallbackSize, "a zero size disables the fallback of the JWT factory")
+}
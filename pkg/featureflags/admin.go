@@ -0,0 +1,176 @@
+package featureflags
+
+import (
+	"errors"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/invopop/validation"
+	"github.com/labstack/echo/v4"
+)
+
+type adminHandlers struct {
+	store  *Store
+	admins []int64
+}
+
+// NewAdminHandlers creates the handlers of the admin API, which manages the
+// flags of store on behalf of the users of admins.
+func NewAdminHandlers(store *Store, admins []int64) *adminHandlers {
+	return &adminHandlers{store: store, admins: admins}
+}
+
+func (h *adminHandlers) MapRoutes(api *echo.Group, mw *middleware.Middleware) {
+	admin := api.Group("/admin/feature-flags", mw.JWTMiddleware(), middleware.RequireAdmin(h.admins))
+
+	admin.GET("", h.ListHandler)
+	admin.GET("/:key", h.GetHandler)
+	admin.PUT("/:key", h.SaveHandler)
+	admin.DELETE("/:key", h.DeleteHandler)
+	admin.POST("/:key/evaluate", h.EvaluateHandler)
+}
+
+// @Summary		List Feature Flags
+// @Description	List Feature Flags
+// @ID			list-feature-flags
+// @Tags		Feature Flags
+// @Produce		json
+// @Success		200		{object}	response.Success{data=[]featureflags.Flag}	"SUCCESS"
+// @Failure		500		{object}	response.FailedResponse						"INTERNAL_SERVER__ERROR"
+// @Router		/admin/feature-flags [get]
+// @Security	BearerToken
+func (h *adminHandlers) ListHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "ListFeatureFlagsHandler")
+	defer span.End()
+
+	flags, err := h.store.List(ctx)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(flags).Send(c)
+}
+
+// @Summary		Feature Flag Detail
+// @Description	Feature Flag Detail
+// @ID			feature-flag-detail
+// @Tags		Feature Flags
+// @Produce		json
+// @Param		key		path		string									true	"Flag Key"
+// @Success		200		{object}	response.Success{data=featureflags.Flag}	"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse					"NOT_FOUND"
+// @Router		/admin/feature-flags/{key} [get]
+// @Security	BearerToken
+func (h *adminHandlers) GetHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "FeatureFlagDetailHandler")
+	defer span.End()
+
+	flag, err := h.store.Get(ctx, c.Param("key"))
+	if err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(flag).Send(c)
+}
+
+// @Summary		Save Feature Flag
+// @Description	Create or replace a feature flag, applied by every instance within seconds
+// @ID			save-feature-flag
+// @Tags		Feature Flags
+// @Accept		json
+// @Produce		json
+// @Param		key		path		string									true	"Flag Key"
+// @Param		body	body		featureflags.Flag						true	"Feature Flag"
+// @Success		200		{object}	response.Success{data=featureflags.Flag}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse					"BAD_REQUEST"
+// @Router		/admin/feature-flags/{key} [put]
+// @Security	BearerToken
+func (h *adminHandlers) SaveHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "SaveFeatureFlagHandler")
+	defer span.End()
+
+	var flag Flag
+	if err := c.Bind(&flag); err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	flag.Key = c.Param("key")
+
+	saved, err := h.store.Save(ctx, flag)
+	if err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(saved).Send(c)
+}
+
+// @Summary		Delete Feature Flag
+// @Description	Delete Feature Flag
+// @ID			delete-feature-flag
+// @Tags		Feature Flags
+// @Produce		json
+// @Param		key		path		string						true	"Flag Key"
+// @Success		200		{object}	response.ResponseFormat		"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse		"NOT_FOUND"
+// @Router		/admin/feature-flags/{key} [delete]
+// @Security	BearerToken
+func (h *adminHandlers) DeleteHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "DeleteFeatureFlagHandler")
+	defer span.End()
+
+	if err := h.store.Delete(ctx, c.Param("key")); err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(nil).Send(c)
+}
+
+// @Summary		Evaluate Feature Flag
+// @Description	Evaluate a feature flag for a subject, to check its targeting
+// @ID			evaluate-feature-flag
+// @Tags		Feature Flags
+// @Accept		json
+// @Produce		json
+// @Param		key		path		string											true	"Flag Key"
+// @Param		body	body		featureflags.Subject							true	"Subject"
+// @Success		200		{object}	response.Success{data=featureflags.Evaluation}	"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse							"NOT_FOUND"
+// @Router		/admin/feature-flags/{key}/evaluate [post]
+// @Security	BearerToken
+func (h *adminHandlers) EvaluateHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "EvaluateFeatureFlagHandler")
+	defer span.End()
+
+	var subject Subject
+	if err := c.Bind(&subject); err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	// Evaluate the stored flag rather than the local copy, which may be a few
+	// seconds behind right after a change.
+	flag, err := h.store.Get(ctx, c.Param("key"))
+	if err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	if subject.Environment == "" {
+		subject.Environment = h.store.environment
+	}
+
+	return response.SuccessBuilder(flag.Evaluate(subject)).Send(c)
+}
+
+// storeError maps the errors of the store to responses.
+func storeError(err error) error {
+	var validationErr validation.Errors
+
+	switch {
+	case errors.Is(err, ErrFlagNotFound):
+		return response.NotFound(err)
+	case errors.As(err, &validationErr):
+		return response.BadRequest(err)
+	default:
+		return response.InternalServerError(err)
+	}
+}
@@ -0,0 +1,83 @@
+package featureflags
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type adminAPI struct {
+	e      *echo.Echo
+	tokens map[int64]string
+}
+
+func newAdminAPI(t *testing.T) *adminAPI {
+	t.Helper()
+
+	_, client := setupStore(t)
+
+	jwtFactory := jwt.NewJWTFactory(jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600}, appRedis.NewRedisManager(client))
+
+	api := &adminAPI{e: echo.New(), tokens: make(map[int64]string)}
+	for _, id := range []int64{1, 2} {
+		token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: id, ContactType: types.CONTACT_TYPE_EMAIL}})
+		require.NoError(t, err)
+		api.tokens[id] = token
+	}
+
+	NewAdminHandlers(NewStore(client, Config{}, "staging"), []int64{1}).MapRoutes(api.e.Group("/api/v1"), middleware.New(jwtFactory))
+
+	return api
+}
+
+func (a *adminAPI) do(method, path string, userID int64, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/admin/feature-flags"+path, strings.NewReader(body))
+	req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+	req.Header.Set(types.AuthorizationHeaderKey.String(), "Bearer "+a.tokens[userID])
+
+	rec := httptest.NewRecorder()
+	a.e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAdminHandlers(t *testing.T) {
+	api := newAdminAPI(t)
+
+	flag := `{"kind": "boolean", "enabled": true, "rules": [{"conditions": [{"attribute": "tenant", "operator": "in", "values": ["acme"]}], "serve": {"variant": "on"}}], "default": {"variant": "off"}}`
+
+	assert.Equal(t, http.StatusForbidden, api.do(http.MethodPut, "/beta", 2, flag).Code, "only admins manage flags")
+	assert.Equal(t, http.StatusForbidden, api.do(http.MethodGet, "", 2, "").Code)
+
+	assert.Equal(t, http.StatusBadRequest, api.do(http.MethodPut, "/beta", 1, `{"kind": "boolean"}`).Code)
+	assert.Equal(t, http.StatusNotFound, api.do(http.MethodGet, "/beta", 1, "").Code)
+
+	rec := api.do(http.MethodPut, "/beta", 1, flag)
+	require.Equal(t, http.StatusOK, rec.Code, rec.Body.String())
+
+	rec = api.do(http.MethodGet, "", 1, "")
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"key":"beta"`)
+
+	rec = api.do(http.MethodPost, "/beta/evaluate", 1, `{"tenant": "acme"}`)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var evaluated struct {
+		Data Evaluation `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &evaluated))
+	assert.Equal(t, Evaluation{Flag: "beta", Variant: VariantOn, Reason: ReasonRule, Rule: 0}, evaluated.Data)
+
+	assert.Equal(t, http.StatusOK, api.do(http.MethodDelete, "/beta", 1, "").Code)
+	assert.Equal(t, http.StatusNotFound, api.do(http.MethodDelete, "/beta", 1, "").Code)
+}
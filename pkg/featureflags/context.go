@@ -0,0 +1,86 @@
+package featureflags
+
+import (
+	"context"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+	"google.golang.org/grpc"
+)
+
+type (
+	storeContextKey   struct{}
+	subjectContextKey struct{}
+)
+
+// NewContext returns ctx with the flags of store, read by Enabled, Variant
+// and Evaluate.
+func NewContext(ctx context.Context, store *Store) context.Context {
+	return context.WithValue(ctx, storeContextKey{}, store)
+}
+
+// WithSubject returns ctx evaluating flags for subject rather than for the
+// authenticated user.
+func WithSubject(ctx context.Context, subject Subject) context.Context {
+	return context.WithValue(ctx, subjectContextKey{}, subject)
+}
+
+// SubjectFromContext returns the subject set with WithSubject, or the
+// authenticated user of the request.
+func SubjectFromContext(ctx context.Context) Subject {
+	if subject, ok := ctx.Value(subjectContextKey{}).(Subject); ok {
+		return subject
+	}
+
+	var subject Subject
+	if claims, ok := ctx.Value(types.CredentialDataContextKey).(*jwt.JWTClaims); ok && claims.Data != nil {
+		subject.UserID = strconv.FormatInt(claims.Data.ID, 10)
+	}
+
+	return subject
+}
+
+// Evaluate returns the variant of the flag key for the subject of ctx. Without
+// flags in ctx, every flag is not found.
+func Evaluate(ctx context.Context, key string) Evaluation {
+	store, ok := ctx.Value(storeContextKey{}).(*Store)
+	if !ok {
+		return Evaluation{Flag: key, Reason: ReasonNotFound, Rule: -1}
+	}
+
+	return store.Evaluate(ctx, key, SubjectFromContext(ctx))
+}
+
+// Enabled reports whether the boolean flag key is on for the subject of ctx.
+// Flags that do not exist are off.
+func Enabled(ctx context.Context, key string) bool {
+	return Evaluate(ctx, key).Variant == VariantOn
+}
+
+// Variant returns the variant of the flag key for the subject of ctx, empty
+// when the flag does not exist.
+func Variant(ctx context.Context, key string) string {
+	return Evaluate(ctx, key).Variant
+}
+
+// Middleware makes the flags of store available to the handlers and the
+// usecases through the request context.
+func Middleware(store *Store) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			c.SetRequest(c.Request().WithContext(NewContext(c.Request().Context(), store)))
+
+			return next(c)
+		}
+	}
+}
+
+// UnaryServerInterceptor makes the flags of store available to the gRPC
+// handlers and the usecases through the call context.
+func UnaryServerInterceptor(store *Store) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+		return handler(NewContext(ctx, store), req)
+	}
+}
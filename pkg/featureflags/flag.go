@@ -0,0 +1,308 @@
+// Package featureflags serves feature flags stored in Redis, targeted by rules
+// on the user, role, tenant and environment of a request and rolled out to a
+// deterministic percentage of users.
+//
+//	if featureflags.Enabled(ctx, "new-login-flow") {
+//	    ...
+//	}
+//
+//	switch featureflags.Variant(ctx, "checkout-layout") {
+//	case "compact":
+//	    ...
+//	}
+package featureflags
+
+import (
+	"fmt"
+	"hash/fnv"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/invopop/validation"
+	"github.com/samber/lo"
+)
+
+// Kind of a flag.
+type Kind string
+
+const (
+	KindBoolean      Kind = "boolean"      // Serves VariantOn or VariantOff.
+	KindMultivariant Kind = "multivariant" // Serves one of its Variants.
+)
+
+// Variants of boolean flags.
+const (
+	VariantOff = "off"
+	VariantOn  = "on"
+)
+
+// Attributes of a Subject that rules target.
+const (
+	AttributeUserID      = "user_id"
+	AttributeRole        = "role"
+	AttributeTenant      = "tenant"
+	AttributeEnvironment = "environment"
+)
+
+// Operators of a Condition.
+const (
+	OperatorIn    = "in"
+	OperatorNotIn = "not_in"
+)
+
+// Reasons of an Evaluation.
+const (
+	ReasonDisabled = "disabled"  // The flag is disabled and serves its first variant.
+	ReasonRule     = "rule"      // A rule matched.
+	ReasonDefault  = "default"   // No rule matched.
+	ReasonNotFound = "not_found" // The flag does not exist, no variant is served.
+)
+
+var keyPattern = regexp.MustCompile(`^[a-z0-9][a-z0-9._-]*$`)
+
+// Flag is a feature flag.
+type Flag struct {
+	Key         string `json:"key"`
+	Description string `json:"description,omitempty"`
+	Kind        Kind   `json:"kind"`
+	// Variants of a multivariant flag, the first one is served while the flag
+	// is disabled. Boolean flags have VariantOff and VariantOn.
+	Variants []string `json:"variants,omitempty"`
+	Enabled  bool     `json:"enabled"`
+	// Rules are checked in order, the first matching rule decides the variant.
+	Rules []Rule `json:"rules,omitempty"`
+	// Default is served when no rule matches.
+	Default   Serve     `json:"default"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Rule serves a variant to the subjects matching all its conditions.
+type Rule struct {
+	Conditions []Condition `json:"conditions"`
+	Serve      Serve       `json:"serve"`
+}
+
+// Condition matches an attribute of the subject against values.
+type Condition struct {
+	Attribute string   `json:"attribute"` // One of the Attribute constants.
+	Operator  string   `json:"operator"`  // OperatorIn or OperatorNotIn.
+	Values    []string `json:"values"`
+}
+
+// Serve is either a fixed variant or a percentage rollout.
+type Serve struct {
+	Variant string       `json:"variant,omitempty"`
+	Rollout []Allocation `json:"rollout,omitempty"`
+	// BucketBy is the attribute that places a subject in a rollout, user_id
+	// unless set. Subjects without the attribute share a bucket.
+	BucketBy string `json:"bucket_by,omitempty"`
+}
+
+// Allocation serves a variant to a percentage of the subjects.
+type Allocation struct {
+	Variant string `json:"variant"`
+	Percent int    `json:"percent"`
+}
+
+// Subject is who a flag is evaluated for.
+type Subject struct {
+	UserID      string `json:"user_id"`
+	Role        string `json:"role"`
+	Tenant      string `json:"tenant"`
+	Environment string `json:"environment"`
+}
+
+func (s Subject) attribute(name string) string {
+	switch name {
+	case AttributeUserID:
+		return s.UserID
+	case AttributeRole:
+		return s.Role
+	case AttributeTenant:
+		return s.Tenant
+	case AttributeEnvironment:
+		return s.Environment
+	default:
+		return ""
+	}
+}
+
+// Evaluation is the variant of a flag served to a subject.
+type Evaluation struct {
+	Flag    string `json:"flag"`
+	Variant string `json:"variant"`
+	Reason  string `json:"reason"`
+	Rule    int    `json:"rule"` // The index of the matching rule, -1 unless Reason is ReasonRule.
+}
+
+// AllVariants returns the variants the flag can serve.
+func (f Flag) AllVariants() []string {
+	if f.Kind == KindBoolean {
+		return []string{VariantOff, VariantOn}
+	}
+
+	return f.Variants
+}
+
+// Evaluate returns the variant of the flag for subject.
+func (f Flag) Evaluate(subject Subject) Evaluation {
+	evaluation := Evaluation{Flag: f.Key, Rule: -1}
+
+	if !f.Enabled {
+		evaluation.Reason = ReasonDisabled
+		if variants := f.AllVariants(); len(variants) > 0 {
+			evaluation.Variant = variants[0]
+		}
+
+		return evaluation
+	}
+
+	for i, rule := range f.Rules {
+		if rule.matches(subject) {
+			evaluation.Variant, evaluation.Reason, evaluation.Rule = rule.Serve.variant(f.Key, subject), ReasonRule, i
+			return evaluation
+		}
+	}
+
+	evaluation.Variant, evaluation.Reason = f.Default.variant(f.Key, subject), ReasonDefault
+
+	return evaluation
+}
+
+func (r Rule) matches(subject Subject) bool {
+	for _, condition := range r.Conditions {
+		if !condition.matches(subject) {
+			return false
+		}
+	}
+
+	return true
+}
+
+func (c Condition) matches(subject Subject) bool {
+	in := slices.Contains(c.Values, subject.attribute(c.Attribute))
+	if c.Operator == OperatorNotIn {
+		return !in
+	}
+
+	return in
+}
+
+// variant returns the fixed variant, or the allocation of the rollout the
+// bucket of subject falls in. A subject keeps its bucket for a flag, so
+// raising the percentage of a variant only adds subjects to it.
+func (s Serve) variant(flag string, subject Subject) string {
+	if len(s.Rollout) == 0 {
+		return s.Variant
+	}
+
+	bucketBy := s.BucketBy
+	if bucketBy == "" {
+		bucketBy = AttributeUserID
+	}
+
+	bucket := Bucket(flag, subject.attribute(bucketBy))
+	for _, allocation := range s.Rollout {
+		if bucket < allocation.Percent {
+			return allocation.Variant
+		}
+
+		bucket -= allocation.Percent
+	}
+
+	return s.Rollout[len(s.Rollout)-1].Variant
+}
+
+// Bucket places value in one of 100 buckets for flag. Buckets differ between
+// flags, so that the same users are not always the first to get a rollout.
+func Bucket(flag, value string) int {
+	hash := fnv.New32a()
+	_, _ = hash.Write([]byte(flag + "/" + value))
+
+	return int(hash.Sum32() % 100)
+}
+
+func (f Flag) Validate() error {
+	variants := f.AllVariants()
+
+	return validation.ValidateStruct(&f,
+		validation.Field(&f.Key, validation.Required, validation.Length(1, 100), validation.Match(keyPattern)),
+		validation.Field(&f.Kind, validation.Required, validation.In(KindBoolean, KindMultivariant)),
+		validation.Field(&f.Variants,
+			validation.When(f.Kind == KindMultivariant, validation.Required, validation.Length(2, 0), validation.By(unique)),
+			validation.When(f.Kind == KindBoolean, validation.Empty),
+		),
+		validation.Field(&f.Rules, validation.Each(validation.By(func(value any) error {
+			rule, _ := value.(Rule)
+			return rule.validate(variants)
+		}))),
+		validation.Field(&f.Default, validation.By(func(value any) error {
+			return f.Default.validate(variants)
+		})),
+	)
+}
+
+func (r Rule) validate(variants []string) error {
+	return validation.ValidateStruct(&r,
+		validation.Field(&r.Conditions, validation.Required),
+		validation.Field(&r.Serve, validation.By(func(value any) error {
+			return r.Serve.validate(variants)
+		})),
+	)
+}
+
+func (c Condition) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Attribute, validation.Required, validation.In(AttributeUserID, AttributeRole, AttributeTenant, AttributeEnvironment)),
+		validation.Field(&c.Operator, validation.Required, validation.In(OperatorIn, OperatorNotIn)),
+		validation.Field(&c.Values, validation.Required),
+	)
+}
+
+func (s Serve) validate(variants []string) error {
+	isVariant := validation.In(lo.ToAnySlice(variants)...).Error(fmt.Sprintf("must be one of %s", strings.Join(variants, ", ")))
+
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.Variant,
+			validation.When(len(s.Rollout) == 0, validation.Required, isVariant),
+			validation.When(len(s.Rollout) > 0, validation.Empty.Error("must be blank with a rollout")),
+		),
+		validation.Field(&s.Rollout, validation.Each(validation.By(func(value any) error {
+			allocation, _ := value.(Allocation)
+			return validation.ValidateStruct(&allocation,
+				validation.Field(&allocation.Variant, validation.Required, isVariant),
+				validation.Field(&allocation.Percent, validation.Min(0), validation.Max(100)),
+			)
+		})), validation.By(func(value any) error {
+			if len(s.Rollout) == 0 {
+				return nil
+			}
+
+			total := 0
+			for _, allocation := range s.Rollout {
+				total += allocation.Percent
+			}
+
+			if total != 100 {
+				return validation.NewError("validation_rollout_total", fmt.Sprintf("percentages must add up to 100, not %d", total))
+			}
+
+			return nil
+		})),
+		validation.Field(&s.BucketBy, validation.In(AttributeUserID, AttributeRole, AttributeTenant, AttributeEnvironment)),
+	)
+}
+
+// unique fails on a list of strings with duplicates.
+func unique(value any) error {
+	values, _ := value.([]string)
+	for i, v := range values {
+		if slices.Contains(values[:i], v) {
+			return validation.NewError("validation_unique", fmt.Sprintf("%q is listed twice", v))
+		}
+	}
+
+	return nil
+}
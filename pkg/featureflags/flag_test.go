@@ -0,0 +1,147 @@
+package featureflags
+
+import (
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestFlag_Evaluate(t *testing.T) {
+	flag := Flag{
+		Key:      "checkout-layout",
+		Kind:     KindMultivariant,
+		Variants: []string{"classic", "compact", "wide"},
+		Enabled:  true,
+		Rules: []Rule{
+			{
+				Conditions: []Condition{{Attribute: AttributeUserID, Operator: OperatorIn, Values: []string{"1", "2"}}},
+				Serve:      Serve{Variant: "wide"},
+			},
+			{
+				Conditions: []Condition{
+					{Attribute: AttributeTenant, Operator: OperatorIn, Values: []string{"acme"}},
+					{Attribute: AttributeEnvironment, Operator: OperatorNotIn, Values: []string{"production"}},
+				},
+				Serve: Serve{Variant: "compact"},
+			},
+		},
+		Default: Serve{Variant: "classic"},
+	}
+
+	tests := []struct {
+		name    string
+		subject Subject
+		want    Evaluation
+	}{
+		{
+			name:    "targeted user",
+			subject: Subject{UserID: "2", Tenant: "acme"},
+			want:    Evaluation{Flag: "checkout-layout", Variant: "wide", Reason: ReasonRule, Rule: 0},
+		},
+		{
+			name:    "every condition of a rule matches",
+			subject: Subject{UserID: "3", Tenant: "acme", Environment: "staging"},
+			want:    Evaluation{Flag: "checkout-layout", Variant: "compact", Reason: ReasonRule, Rule: 1},
+		},
+		{
+			name:    "a condition of the rule fails",
+			subject: Subject{UserID: "3", Tenant: "acme", Environment: "production"},
+			want:    Evaluation{Flag: "checkout-layout", Variant: "classic", Reason: ReasonDefault, Rule: -1},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, flag.Evaluate(tt.subject))
+		})
+	}
+
+	flag.Enabled = false
+	assert.Equal(t, Evaluation{Flag: "checkout-layout", Variant: "classic", Reason: ReasonDisabled, Rule: -1}, flag.Evaluate(Subject{UserID: "1"}))
+
+	boolean := Flag{Key: "new-login-flow", Kind: KindBoolean, Default: Serve{Variant: VariantOn}}
+	assert.Equal(t, VariantOff, boolean.Evaluate(Subject{}).Variant, "a disabled boolean flag is off")
+}
+
+func TestServe_Rollout(t *testing.T) {
+	flag := Flag{
+		Key:     "new-login-flow",
+		Kind:    KindBoolean,
+		Enabled: true,
+		Default: Serve{Rollout: []Allocation{{Variant: VariantOn, Percent: 20}, {Variant: VariantOff, Percent: 80}}},
+	}
+
+	on := make(map[string]bool)
+	for i := range 10000 {
+		userID := strconv.Itoa(i)
+		on[userID] = flag.Evaluate(Subject{UserID: userID}).Variant == VariantOn
+
+		assert.Equal(t, on[userID], flag.Evaluate(Subject{UserID: userID}).Variant == VariantOn, "a user keeps their variant")
+	}
+
+	count := 0
+	for _, enabled := range on {
+		if enabled {
+			count++
+		}
+	}
+	assert.InDelta(t, 2000, count, 200, "about 20%% of the users get the rollout")
+
+	flag.Default.Rollout = []Allocation{{Variant: VariantOn, Percent: 50}, {Variant: VariantOff, Percent: 50}}
+	for userID, enabled := range on {
+		if enabled {
+			assert.Equal(t, VariantOn, flag.Evaluate(Subject{UserID: userID}).Variant, "raising the rollout keeps the users that had it")
+		}
+	}
+}
+
+func TestBucket(t *testing.T) {
+	assert.Equal(t, Bucket("flag", "42"), Bucket("flag", "42"))
+	assert.GreaterOrEqual(t, Bucket("flag", "42"), 0)
+	assert.Less(t, Bucket("flag", "42"), 100)
+}
+
+func TestFlag_Validate(t *testing.T) {
+	valid := Flag{
+		Key:      "checkout-layout",
+		Kind:     KindMultivariant,
+		Variants: []string{"classic", "compact"},
+		Rules: []Rule{{
+			Conditions: []Condition{{Attribute: AttributeRole, Operator: OperatorIn, Values: []string{"staff"}}},
+			Serve:      Serve{Rollout: []Allocation{{Variant: "classic", Percent: 50}, {Variant: "compact", Percent: 50}}},
+		}},
+		Default: Serve{Variant: "classic"},
+	}
+	assert.NoError(t, valid.Validate())
+	assert.NoError(t, Flag{Key: "a.b_c-1", Kind: KindBoolean, Default: Serve{Variant: VariantOff}}.Validate())
+
+	tests := []struct {
+		name   string
+		modify func(f *Flag)
+		want   string
+	}{
+		{"invalid key", func(f *Flag) { f.Key = "Checkout Layout" }, "key"},
+		{"unknown kind", func(f *Flag) { f.Kind = "percent" }, "kind"},
+		{"single variant", func(f *Flag) { f.Variants = []string{"classic"} }, "variants"},
+		{"duplicate variant", func(f *Flag) { f.Variants = []string{"classic", "classic"} }, "listed twice"},
+		{"unknown default variant", func(f *Flag) { f.Default.Variant = "wide" }, "must be one of classic, compact"},
+		{"rule without conditions", func(f *Flag) { f.Rules[0].Conditions = nil }, "conditions"},
+		{"unknown attribute", func(f *Flag) { f.Rules[0].Conditions[0].Attribute = "country" }, "attribute"},
+		{"rollout not adding up", func(f *Flag) { f.Rules[0].Serve.Rollout[0].Percent = 40 }, "add up to 100, not 90"},
+		{"variant and rollout", func(f *Flag) { f.Rules[0].Serve.Variant = "classic" }, "must be blank with a rollout"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			flag := valid
+			flag.Rules = []Rule{{
+				Conditions: []Condition{{Attribute: AttributeRole, Operator: OperatorIn, Values: []string{"staff"}}},
+				Serve:      Serve{Rollout: []Allocation{{Variant: "classic", Percent: 50}, {Variant: "compact", Percent: 50}}},
+			}}
+			tt.modify(&flag)
+
+			assert.ErrorContains(t, flag.Validate(), tt.want)
+		})
+	}
+}
@@ -0,0 +1,237 @@
+package featureflags
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"github.com/invopop/validation"
+	"golang.org/x/sync/singleflight"
+)
+
+// ErrFlagNotFound is returned for a flag that does not exist.
+var ErrFlagNotFound = errors.New("feature flag not found")
+
+var (
+	// flagsKey is the Redis hash of the flags, by key.
+	flagsKey = appRedis.REDIS_PREFIX_KEY_CONFIG.Key("feature_flags")
+	// invalidationsChannel announces the keys of changed flags.
+	invalidationsChannel = appRedis.REDIS_PREFIX_KEY_CONFIG.Key("feature_flags:invalidations")
+)
+
+// Config holds the configuration of the feature flags.
+type Config struct {
+	// CacheTTL is how long an instance serves its copy of the flags before
+	// reading them again, in case it missed an invalidation. Defaults to 30
+	// seconds.
+	CacheTTL time.Duration
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.CacheTTL, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.CacheTTL <= 0 {
+		c.CacheTTL = 30 * time.Second
+	}
+
+	return c
+}
+
+// snapshot is the local copy of the flags.
+type snapshot struct {
+	flags    map[string]Flag
+	loadedAt time.Time
+}
+
+// Store keeps the flags in a Redis hash and evaluates them from a local copy.
+// Changes are published so that every instance reloads its copy.
+type Store struct {
+	cfg         Config
+	client      redis.UniversalClient
+	environment string
+	now         func() time.Time
+
+	group      singleflight.Group
+	snapshot   atomic.Pointer[snapshot]
+	generation atomic.Uint64 // Counts the invalidations, so that a load racing one is not kept.
+}
+
+// NewStore creates a store of the flags of client, evaluated for subjects of
+// environment unless they have their own.
+func NewStore(client redis.UniversalClient, cfg Config, environment string) *Store {
+	return &Store{cfg: cfg.withDefaults(), client: client, environment: environment, now: time.Now}
+}
+
+// List returns the flags sorted by key, read from Redis.
+func (s *Store) List(ctx context.Context) ([]Flag, error) {
+	flags, err := s.load(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := make([]Flag, 0, len(flags))
+	for _, flag := range flags {
+		list = append(list, flag)
+	}
+
+	slices.SortFunc(list, func(a, b Flag) int { return strings.Compare(a.Key, b.Key) })
+
+	return list, nil
+}
+
+// Get returns the flag key read from Redis, or ErrFlagNotFound.
+func (s *Store) Get(ctx context.Context, key string) (Flag, error) {
+	raw, err := s.client.HGet(ctx, flagsKey, key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return Flag{}, ErrFlagNotFound
+	}
+
+	if err != nil {
+		return Flag{}, err
+	}
+
+	var flag Flag
+	if err := json.Unmarshal(raw, &flag); err != nil {
+		return Flag{}, fmt.Errorf("decode flag %s: %w", key, err)
+	}
+
+	return flag, nil
+}
+
+// Save validates and creates or replaces flag, and returns it as stored.
+func (s *Store) Save(ctx context.Context, flag Flag) (Flag, error) {
+	if err := flag.Validate(); err != nil {
+		return Flag{}, err
+	}
+
+	flag.UpdatedAt = s.now().UTC()
+
+	raw, err := json.Marshal(flag)
+	if err != nil {
+		return Flag{}, err
+	}
+
+	if err := s.client.HSet(ctx, flagsKey, flag.Key, raw).Err(); err != nil {
+		return Flag{}, err
+	}
+
+	return flag, s.invalidate(ctx, flag.Key)
+}
+
+// Delete removes the flag key, or returns ErrFlagNotFound.
+func (s *Store) Delete(ctx context.Context, key string) error {
+	deleted, err := s.client.HDel(ctx, flagsKey, key).Result()
+	if err != nil {
+		return err
+	}
+
+	if deleted == 0 {
+		return ErrFlagNotFound
+	}
+
+	return s.invalidate(ctx, key)
+}
+
+// Evaluate returns the variant of the flag key for subject from the local
+// copy of the flags. When Redis is unavailable the last copy keeps being
+// served, and flags never loaded are not found.
+func (s *Store) Evaluate(ctx context.Context, key string, subject Subject) Evaluation {
+	if subject.Environment == "" {
+		subject.Environment = s.environment
+	}
+
+	flag, ok := s.flags(ctx)[key]
+	if !ok {
+		return Evaluation{Flag: key, Reason: ReasonNotFound, Rule: -1}
+	}
+
+	return flag.Evaluate(subject)
+}
+
+// Listen reloads the local copy of the flags whenever another instance changes
+// them, until ctx is done. The subscription is made again with a backoff while
+// Redis is unavailable, and the copy is reloaded every time it is made since
+// changes may have been missed.
+func (s *Store) Listen(ctx context.Context) error {
+	appRedis.Subscribe(ctx, s.client, invalidationsChannel, appRedis.RetryConfig{}, s.expire, func(string) { s.expire() })
+
+	return nil
+}
+
+// flags returns the local copy of the flags, loaded again once CacheTTL passed.
+func (s *Store) flags(ctx context.Context) map[string]Flag {
+	current := s.snapshot.Load()
+	if current != nil && s.now().Sub(current.loadedAt) < s.cfg.CacheTTL {
+		return current.flags
+	}
+
+	loaded, _, _ := s.group.Do("flags", func() (any, error) {
+		generation := s.generation.Load()
+
+		flags, err := s.load(context.WithoutCancel(ctx))
+		if err != nil {
+			// Serve the previous copy, and retry once CacheTTL passed again
+			// rather than on every evaluation.
+			flags = map[string]Flag{}
+			if current != nil {
+				flags = current.flags
+			}
+		}
+
+		if s.generation.Load() == generation {
+			s.snapshot.Store(&snapshot{flags: flags, loadedAt: s.now()})
+		}
+
+		return flags, nil
+	})
+
+	return loaded.(map[string]Flag)
+}
+
+// load reads every flag from Redis.
+func (s *Store) load(ctx context.Context) (map[string]Flag, error) {
+	values, err := s.client.HGetAll(ctx, flagsKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string]Flag, len(values))
+	for key, raw := range values {
+		var flag Flag
+		if err := json.Unmarshal([]byte(raw), &flag); err != nil {
+			return nil, fmt.Errorf("decode flag %s: %w", key, err)
+		}
+
+		flags[key] = flag
+	}
+
+	return flags, nil
+}
+
+// invalidate expires the local copy and asks the other instances to expire theirs.
+func (s *Store) invalidate(ctx context.Context, key string) error {
+	s.expire()
+
+	return s.client.Publish(ctx, invalidationsChannel, key).Err()
+}
+
+// expire has the local copy reloaded on the next evaluation. It is still
+// served if the reload fails.
+func (s *Store) expire() {
+	s.generation.Add(1)
+
+	if current := s.snapshot.Load(); current != nil {
+		s.snapshot.Store(&snapshot{flags: current.flags})
+	}
+}
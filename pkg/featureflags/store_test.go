@@ -0,0 +1,189 @@
+package featureflags
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func setupStore(t *testing.T) (*miniredis.Miniredis, *redis.Client) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return mr, client
+}
+
+func newLoginFlow(enabled bool) Flag {
+	return Flag{
+		Key:     "new-login-flow",
+		Kind:    KindBoolean,
+		Enabled: enabled,
+		Rules: []Rule{{
+			Conditions: []Condition{{Attribute: AttributeEnvironment, Operator: OperatorIn, Values: []string{"staging"}}},
+			Serve:      Serve{Variant: VariantOn},
+		}},
+		Default: Serve{Variant: VariantOff},
+	}
+}
+
+func TestStore_CRUD(t *testing.T) {
+	_, client := setupStore(t)
+	store := NewStore(client, Config{}, "staging")
+	ctx := t.Context()
+
+	_, err := store.Get(ctx, "new-login-flow")
+	assert.ErrorIs(t, err, ErrFlagNotFound)
+
+	saved, err := store.Save(ctx, newLoginFlow(true))
+	require.NoError(t, err)
+	assert.False(t, saved.UpdatedAt.IsZero())
+
+	got, err := store.Get(ctx, "new-login-flow")
+	require.NoError(t, err)
+	assert.Equal(t, saved.Rules, got.Rules)
+
+	_, err = store.Save(ctx, Flag{Key: "other", Kind: KindBoolean, Default: Serve{Variant: VariantOn}})
+	require.NoError(t, err)
+
+	flags, err := store.List(ctx)
+	require.NoError(t, err)
+	require.Len(t, flags, 2)
+	assert.Equal(t, "new-login-flow", flags[0].Key)
+	assert.Equal(t, "other", flags[1].Key)
+
+	_, err = store.Save(ctx, Flag{Key: "invalid", Kind: KindBoolean})
+	assert.Error(t, err, "invalid flags are not saved")
+
+	require.NoError(t, store.Delete(ctx, "other"))
+	assert.ErrorIs(t, store.Delete(ctx, "other"), ErrFlagNotFound)
+}
+
+func TestStore_Evaluate(t *testing.T) {
+	_, client := setupStore(t)
+	store := NewStore(client, Config{CacheTTL: time.Hour}, "staging")
+	ctx := t.Context()
+
+	assert.Equal(t, ReasonNotFound, store.Evaluate(ctx, "new-login-flow", Subject{}).Reason)
+
+	_, err := store.Save(ctx, newLoginFlow(true))
+	require.NoError(t, err)
+
+	assert.Equal(t, VariantOn, store.Evaluate(ctx, "new-login-flow", Subject{}).Variant, "the environment of the store applies")
+	assert.Equal(t, VariantOff, store.Evaluate(ctx, "new-login-flow", Subject{Environment: "production"}).Variant)
+
+	// Changes written by another instance are only seen after an invalidation.
+	other := NewStore(client, Config{}, "staging")
+	_, err = other.Save(ctx, newLoginFlow(false))
+	require.NoError(t, err)
+
+	assert.Equal(t, VariantOn, store.Evaluate(ctx, "new-login-flow", Subject{}).Variant, "served from the local copy")
+
+	store.expire()
+	assert.Equal(t, VariantOff, store.Evaluate(ctx, "new-login-flow", Subject{}).Variant)
+}
+
+func TestStore_ServesLastCopyWhenRedisIsDown(t *testing.T) {
+	mr, client := setupStore(t)
+	store := NewStore(client, Config{}, "staging")
+	ctx := t.Context()
+
+	_, err := store.Save(ctx, newLoginFlow(true))
+	require.NoError(t, err)
+	require.Equal(t, VariantOn, store.Evaluate(ctx, "new-login-flow", Subject{}).Variant)
+
+	mr.Close()
+	store.expire()
+
+	assert.Equal(t, VariantOn, store.Evaluate(ctx, "new-login-flow", Subject{}).Variant)
+}
+
+func TestStore_Listen(t *testing.T) {
+	_, client := setupStore(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	first := NewStore(client, Config{CacheTTL: time.Hour}, "staging")
+	second := NewStore(client, Config{CacheTTL: time.Hour}, "staging")
+
+	_, err := first.Save(ctx, newLoginFlow(true))
+	require.NoError(t, err)
+	require.Equal(t, VariantOn, second.Evaluate(ctx, "new-login-flow", Subject{}).Variant)
+
+	go func() { _ = second.Listen(ctx) }()
+	time.Sleep(50 * time.Millisecond) // Let the subscription start.
+
+	_, err = first.Save(ctx, newLoginFlow(false))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return second.Evaluate(ctx, "new-login-flow", Subject{}).Variant == VariantOff
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestStore_ListenStartsWhileRedisIsDown(t *testing.T) {
+	mr, client := setupStore(t)
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	first := NewStore(client, Config{CacheTTL: time.Hour}, "staging")
+	second := NewStore(client, Config{CacheTTL: time.Hour}, "staging")
+
+	_, err := first.Save(ctx, newLoginFlow(true))
+	require.NoError(t, err)
+	require.Equal(t, VariantOn, second.Evaluate(ctx, "new-login-flow", Subject{}).Variant)
+
+	mr.Close()
+	go func() { _ = second.Listen(ctx) }()
+
+	time.Sleep(20 * time.Millisecond) // Let the first subscriptions fail.
+	require.NoError(t, mr.Restart())
+
+	require.Eventually(t, func() bool {
+		return client.PubSubNumSub(ctx, invalidationsChannel).Val()[invalidationsChannel] == 1
+	}, 5*time.Second, 10*time.Millisecond, "the subscription is made once Redis answers")
+
+	_, err = first.Save(ctx, newLoginFlow(false))
+	require.NoError(t, err)
+
+	assert.Eventually(t, func() bool {
+		return second.Evaluate(ctx, "new-login-flow", Subject{}).Variant == VariantOff
+	}, time.Second, 10*time.Millisecond)
+}
+
+func TestContext(t *testing.T) {
+	_, client := setupStore(t)
+	store := NewStore(client, Config{}, "production")
+
+	_, err := store.Save(t.Context(), Flag{
+		Key:     "beta",
+		Kind:    KindBoolean,
+		Enabled: true,
+		Rules: []Rule{{
+			Conditions: []Condition{{Attribute: AttributeUserID, Operator: OperatorIn, Values: []string{"42"}}},
+			Serve:      Serve{Variant: VariantOn},
+		}},
+		Default: Serve{Variant: VariantOff},
+	})
+	require.NoError(t, err)
+
+	assert.False(t, Enabled(t.Context(), "beta"), "flags are off without a store in the context")
+
+	ctx := NewContext(t.Context(), store)
+	assert.False(t, Enabled(ctx, "beta"), "anonymous requests")
+	assert.Empty(t, Variant(ctx, "missing"))
+
+	authenticated := context.WithValue(ctx, types.CredentialDataContextKey, &jwt.JWTClaims{Data: &jwt.Data{ID: 42}})
+	assert.True(t, Enabled(authenticated, "beta"), "the authenticated user is the subject")
+
+	assert.False(t, Enabled(WithSubject(authenticated, Subject{UserID: "7"}), "beta"), "an explicit subject wins")
+}
@@ -0,0 +1,275 @@
+// Package health runs the liveness and readiness checks of the service.
+package health
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/invopop/validation"
+)
+
+// Status of a check or a report.
+type Status string
+
+const (
+	StatusOK           Status = "ok"
+	StatusFailed       Status = "failed"        // A check failed.
+	StatusDegraded     Status = "degraded"      // Only non critical checks failed, the service still takes traffic.
+	StatusUnavailable  Status = "unavailable"   // A critical check failed.
+	StatusShuttingDown Status = "shutting_down" // The service is shutting down and takes no new traffic.
+)
+
+// Config controls how checks run.
+type Config struct {
+	// Timeout bounds a check without its own timeout. Defaults to 2 seconds.
+	Timeout time.Duration
+	// CacheTTL is how long a result is served before the check runs again, so
+	// that frequent probes do not load the dependencies. Defaults to 5 seconds.
+	CacheTTL time.Duration
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Timeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.CacheTTL, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.Timeout <= 0 {
+		c.Timeout = 2 * time.Second
+	}
+
+	if c.CacheTTL <= 0 {
+		c.CacheTTL = 5 * time.Second
+	}
+
+	return c
+}
+
+// Checker checks a dependency, it returns nil when the dependency is usable.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function to a Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error { return f(ctx) }
+
+// Kind tells which probe a check belongs to.
+type Kind int
+
+const (
+	// Readiness checks decide whether the service takes traffic, typically the
+	// dependencies it needs to answer requests.
+	Readiness Kind = iota
+	// Liveness checks decide whether the process must be restarted. They must
+	// not depend on other services, or an outage restarts every replica.
+	Liveness
+)
+
+// CheckOption configures a registered check.
+type CheckOption func(*check)
+
+// WithTimeout bounds the check with timeout instead of Config.Timeout.
+func WithTimeout(timeout time.Duration) CheckOption {
+	return func(c *check) { c.timeout = timeout }
+}
+
+// WithCacheTTL caches the result of the check for ttl instead of Config.CacheTTL.
+func WithCacheTTL(ttl time.Duration) CheckOption {
+	return func(c *check) { c.cacheTTL = ttl }
+}
+
+// WithKind registers the check for the liveness or the readiness probe.
+func WithKind(kind Kind) CheckOption {
+	return func(c *check) { c.kind = kind }
+}
+
+// NonCritical reports a failure of the check without failing its probe.
+func NonCritical() CheckOption {
+	return func(c *check) { c.nonCritical = true }
+}
+
+// Result is the last result of a check.
+type Result struct {
+	Status    Status    `json:"status"`
+	Error     string    `json:"error,omitempty"`
+	Latency   float64   `json:"latency_ms"`
+	Critical  bool      `json:"critical"`
+	CheckedAt time.Time `json:"checked_at"`
+}
+
+// Report is the result of a probe.
+type Report struct {
+	Status Status            `json:"status"`
+	Checks map[string]Result `json:"checks"`
+}
+
+// Healthy reports whether the probe passed.
+func (r Report) Healthy() bool {
+	return r.Status == StatusOK || r.Status == StatusDegraded
+}
+
+type check struct {
+	name        string
+	checker     Checker
+	kind        Kind
+	timeout     time.Duration
+	cacheTTL    time.Duration
+	nonCritical bool
+
+	mu     sync.Mutex // Held while the check runs, so concurrent probes share a run.
+	result Result
+}
+
+// Registry holds the checks of the service.
+type Registry struct {
+	cfg Config
+	now func() time.Time
+
+	mu     sync.RWMutex
+	checks []*check
+
+	shuttingDown atomic.Bool
+}
+
+// NewRegistry creates an empty registry.
+func NewRegistry(cfg Config) *Registry {
+	return &Registry{cfg: cfg.withDefaults(), now: time.Now}
+}
+
+// Register adds a check named name, a readiness check unless WithKind says
+// otherwise. It panics when the name is taken.
+func (r *Registry) Register(name string, checker Checker, opts ...CheckOption) {
+	c := &check{
+		name:     name,
+		checker:  checker,
+		kind:     Readiness,
+		timeout:  r.cfg.Timeout,
+		cacheTTL: r.cfg.CacheTTL,
+	}
+
+	for _, opt := range opts {
+		opt(c)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, registered := range r.checks {
+		if registered.name == name {
+			panic(fmt.Sprintf("health: check %q registered twice", name))
+		}
+	}
+
+	r.checks = append(r.checks, c)
+}
+
+// Shutdown fails readiness from now on, so that load balancers stop sending
+// traffic while the in-flight requests drain.
+func (r *Registry) Shutdown() {
+	r.shuttingDown.Store(true)
+}
+
+// Liveness runs the liveness checks.
+func (r *Registry) Liveness(ctx context.Context) Report {
+	return r.run(ctx, Liveness)
+}
+
+// Readiness runs the readiness checks. Once Shutdown was called it reports
+// StatusShuttingDown without running them.
+func (r *Registry) Readiness(ctx context.Context) Report {
+	if r.shuttingDown.Load() {
+		return Report{Status: StatusShuttingDown, Checks: map[string]Result{}}
+	}
+
+	return r.run(ctx, Readiness)
+}
+
+// run runs the checks of kind concurrently.
+func (r *Registry) run(ctx context.Context, kind Kind) Report {
+	r.mu.RLock()
+	var checks []*check
+	for _, c := range r.checks {
+		if c.kind == kind {
+			checks = append(checks, c)
+		}
+	}
+	r.mu.RUnlock()
+
+	results := make([]Result, len(checks))
+
+	var wg sync.WaitGroup
+	for i, c := range checks {
+		wg.Go(func() { results[i] = r.result(ctx, c) })
+	}
+	wg.Wait()
+
+	report := Report{Status: StatusOK, Checks: make(map[string]Result, len(checks))}
+	for i, c := range checks {
+		report.Checks[c.name] = results[i]
+
+		if results[i].Status == StatusOK {
+			continue
+		}
+
+		if c.nonCritical {
+			if report.Status == StatusOK {
+				report.Status = StatusDegraded
+			}
+		} else {
+			report.Status = StatusUnavailable
+		}
+	}
+
+	return report
+}
+
+// result returns the cached result of c, or runs it when the cached one expired.
+func (r *Registry) result(ctx context.Context, c *check) Result {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if !c.result.CheckedAt.IsZero() && r.now().Sub(c.result.CheckedAt) < c.cacheTTL {
+		return c.result
+	}
+
+	checkCtx, cancel := context.WithTimeout(ctx, c.timeout)
+	defer cancel()
+
+	startedAt := r.now()
+	err := c.checker.Check(checkCtx)
+	if err == nil && checkCtx.Err() != nil {
+		// The checker ignored its context.
+		err = checkCtx.Err()
+	}
+
+	result := Result{
+		Status:    StatusOK,
+		Latency:   float64(r.now().Sub(startedAt).Microseconds()) / 1000,
+		Critical:  !c.nonCritical,
+		CheckedAt: startedAt,
+	}
+
+	if err != nil {
+		result.Status = StatusFailed
+		result.Error = err.Error()
+		if errors.Is(err, context.DeadlineExceeded) {
+			result.Error = fmt.Sprintf("timed out after %s", c.timeout)
+		}
+	}
+
+	// A probe that went away says nothing about the dependency, keep the
+	// previous result for the next one.
+	if ctx.Err() == nil {
+		c.result = result
+	}
+
+	return result
+}
@@ -0,0 +1,231 @@
+package health
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// counter counts its runs and returns err.
+type counter struct {
+	runs atomic.Int32
+	err  error
+}
+
+func (c *counter) Check(ctx context.Context) error {
+	c.runs.Add(1)
+	return c.err
+}
+
+func TestRegistry_Readiness(t *testing.T) {
+	errDown := errors.New("connection refused")
+
+	testCases := []struct {
+		name       string
+		register   func(r *Registry)
+		wantStatus Status
+		wantChecks map[string]Status
+	}{
+		{
+			name:       "no checks",
+			register:   func(r *Registry) {},
+			wantStatus: StatusOK,
+			wantChecks: map[string]Status{},
+		},
+		{
+			name: "all pass",
+			register: func(r *Registry) {
+				r.Register("database", &counter{})
+				r.Register("redis", &counter{})
+			},
+			wantStatus: StatusOK,
+			wantChecks: map[string]Status{"database": StatusOK, "redis": StatusOK},
+		},
+		{
+			name: "critical check fails",
+			register: func(r *Registry) {
+				r.Register("database", &counter{err: errDown})
+				r.Register("telemetry", &counter{err: errDown}, NonCritical())
+			},
+			wantStatus: StatusUnavailable,
+			wantChecks: map[string]Status{"database": StatusFailed, "telemetry": StatusFailed},
+		},
+		{
+			name: "non critical check fails",
+			register: func(r *Registry) {
+				r.Register("database", &counter{})
+				r.Register("telemetry", &counter{err: errDown}, NonCritical())
+			},
+			wantStatus: StatusDegraded,
+			wantChecks: map[string]Status{"database": StatusOK, "telemetry": StatusFailed},
+		},
+		{
+			name: "liveness checks are not run",
+			register: func(r *Registry) {
+				r.Register("deadlock", &counter{err: errDown}, WithKind(Liveness))
+			},
+			wantStatus: StatusOK,
+			wantChecks: map[string]Status{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			registry := NewRegistry(Config{})
+			tc.register(registry)
+
+			report := registry.Readiness(context.Background())
+			assert.Equal(t, tc.wantStatus, report.Status)
+
+			checks := make(map[string]Status, len(report.Checks))
+			for name, result := range report.Checks {
+				checks[name] = result.Status
+			}
+			assert.Equal(t, tc.wantChecks, checks)
+		})
+	}
+}
+
+func TestRegistry_Liveness(t *testing.T) {
+	registry := NewRegistry(Config{})
+	registry.Register("database", &counter{err: errors.New("down")})
+	registry.Register("goroutines", &counter{}, WithKind(Liveness))
+
+	report := registry.Liveness(context.Background())
+	assert.True(t, report.Healthy(), "readiness checks do not fail liveness")
+	assert.Equal(t, []string{"goroutines"}, keys(report.Checks))
+}
+
+func TestRegistry_ReportsErrorAndLatency(t *testing.T) {
+	registry := NewRegistry(Config{})
+	registry.Register("database", &counter{err: errors.New("connection refused")})
+	registry.Register("telemetry", &counter{}, NonCritical())
+
+	report := registry.Readiness(context.Background())
+
+	database := report.Checks["database"]
+	assert.Equal(t, "connection refused", database.Error)
+	assert.True(t, database.Critical)
+	assert.GreaterOrEqual(t, database.Latency, 0.0)
+	assert.False(t, database.CheckedAt.IsZero())
+
+	assert.False(t, report.Checks["telemetry"].Critical)
+	assert.Empty(t, report.Checks["telemetry"].Error)
+}
+
+func TestRegistry_Timeout(t *testing.T) {
+	registry := NewRegistry(Config{Timeout: time.Hour})
+
+	registry.Register("slow", CheckerFunc(func(ctx context.Context) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}), WithTimeout(10*time.Millisecond))
+
+	registry.Register("ignores context", CheckerFunc(func(ctx context.Context) error {
+		time.Sleep(20 * time.Millisecond)
+		return nil
+	}), WithTimeout(time.Millisecond))
+
+	report := registry.Readiness(context.Background())
+	assert.Equal(t, StatusUnavailable, report.Status)
+	assert.Equal(t, "timed out after 10ms", report.Checks["slow"].Error)
+	assert.Equal(t, "timed out after 1ms", report.Checks["ignores context"].Error)
+}
+
+func TestRegistry_CachesResults(t *testing.T) {
+	now := time.Now()
+	registry := NewRegistry(Config{CacheTTL: 5 * time.Second})
+	registry.now = func() time.Time { return now }
+
+	database := &counter{}
+	redis := &counter{}
+	registry.Register("database", database)
+	registry.Register("redis", redis, WithCacheTTL(time.Minute))
+
+	registry.Readiness(context.Background())
+	now = now.Add(4 * time.Second)
+	registry.Readiness(context.Background())
+	assert.EqualValues(t, 1, database.runs.Load(), "served from the cache")
+
+	now = now.Add(2 * time.Second)
+	registry.Readiness(context.Background())
+	assert.EqualValues(t, 2, database.runs.Load(), "the cached result expired")
+	assert.EqualValues(t, 1, redis.runs.Load(), "the check has its own TTL")
+}
+
+func TestRegistry_ConcurrentProbesShareARun(t *testing.T) {
+	registry := NewRegistry(Config{})
+
+	release := make(chan struct{})
+	var runs atomic.Int32
+	registry.Register("database", CheckerFunc(func(ctx context.Context) error {
+		runs.Add(1)
+		<-release
+		return nil
+	}))
+
+	var wg sync.WaitGroup
+	for range 10 {
+		wg.Go(func() { registry.Readiness(context.Background()) })
+	}
+
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.EqualValues(t, 1, runs.Load())
+}
+
+func TestRegistry_CanceledProbeIsNotCached(t *testing.T) {
+	registry := NewRegistry(Config{})
+	database := &counter{}
+	registry.Register("database", database)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	registry.Readiness(ctx)
+	registry.Readiness(context.Background())
+	assert.EqualValues(t, 2, database.runs.Load())
+
+	report := registry.Readiness(context.Background())
+	assert.Equal(t, StatusOK, report.Status)
+	assert.EqualValues(t, 2, database.runs.Load())
+}
+
+func TestRegistry_Shutdown(t *testing.T) {
+	registry := NewRegistry(Config{})
+	database := &counter{}
+	registry.Register("database", database)
+
+	require.True(t, registry.Readiness(context.Background()).Healthy())
+
+	registry.Shutdown()
+
+	report := registry.Readiness(context.Background())
+	assert.Equal(t, StatusShuttingDown, report.Status)
+	assert.False(t, report.Healthy())
+	assert.True(t, registry.Liveness(context.Background()).Healthy(), "the process stays alive while it drains")
+}
+
+func TestRegistry_RegisterTwicePanics(t *testing.T) {
+	registry := NewRegistry(Config{})
+	registry.Register("database", &counter{})
+
+	assert.Panics(t, func() { registry.Register("database", &counter{}) })
+}
+
+func keys(m map[string]Result) []string {
+	var names []string
+	for name := range m {
+		names = append(names, name)
+	}
+
+	return names
+}
@@ -0,0 +1,147 @@
+package jobs
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultDeadLimit is the number of dead jobs listed without a limit.
+const defaultDeadLimit = 50
+
+type adminHandlers struct {
+	backend Backend
+	queues  []string
+	admins  []int64
+}
+
+// NewAdminHandlers creates the handlers of the admin API, which inspects the
+// queues of worker and retries their dead jobs on behalf of the users of
+// admins.
+func NewAdminHandlers(backend Backend, worker *Worker, admins []int64) *adminHandlers {
+	return &adminHandlers{backend: backend, queues: worker.Queues(), admins: admins}
+}
+
+func (h *adminHandlers) MapRoutes(api *echo.Group, mw *middleware.Middleware) {
+	admin := api.Group("/admin/jobs", mw.JWTMiddleware(), middleware.RequireAdmin(h.admins))
+
+	admin.GET("", h.StatsHandler)
+	admin.GET("/:queue/dead", h.DeadHandler)
+	admin.POST("/:queue/dead/:id/retry", h.RetryHandler)
+	admin.DELETE("/:queue/dead/:id", h.PurgeHandler)
+}
+
+// @Summary		Job Queues
+// @Description	Count the ready, scheduled, in flight and dead jobs of every queue
+// @ID			job-queues
+// @Tags		Jobs
+// @Produce		json
+// @Success		200		{object}	response.Success{data=[]jobs.Stats}	"SUCCESS"
+// @Failure		500		{object}	response.FailedResponse				"INTERNAL_SERVER__ERROR"
+// @Router		/admin/jobs [get]
+// @Security	BearerToken
+func (h *adminHandlers) StatsHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "JobQueuesHandler")
+	defer span.End()
+
+	stats := make([]Stats, 0, len(h.queues))
+	for _, queue := range h.queues {
+		queueStats, err := h.backend.Stats(ctx, queue)
+		if err != nil {
+			return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+		}
+
+		stats = append(stats, queueStats)
+	}
+
+	return response.SuccessBuilder(stats).Send(c)
+}
+
+// @Summary		Dead Jobs
+// @Description	List the dead jobs of a queue, the last failed first
+// @ID			dead-jobs
+// @Tags		Jobs
+// @Produce		json
+// @Param		queue	path		string							true	"Queue"
+// @Param		limit	query		int								false	"Limit, 50 by default"
+// @Success		200		{object}	response.Success{data=[]jobs.Job}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse			"BAD_REQUEST"
+// @Router		/admin/jobs/{queue}/dead [get]
+// @Security	BearerToken
+func (h *adminHandlers) DeadHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "DeadJobsHandler")
+	defer span.End()
+
+	limit := defaultDeadLimit
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			return response.ErrorBuilder(response.BadRequest(errors.New("limit must be a positive number"))).Send(c)
+		}
+	}
+
+	jobs, err := h.backend.Dead(ctx, c.Param("queue"), limit)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(jobs).Send(c)
+}
+
+// @Summary		Retry Dead Job
+// @Description	Schedule a dead job again, due now with its attempts reset
+// @ID			retry-dead-job
+// @Tags		Jobs
+// @Produce		json
+// @Param		queue	path		string						true	"Queue"
+// @Param		id		path		string						true	"Job ID"
+// @Success		200		{object}	response.Success{data=jobs.Job}	"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse		"NOT_FOUND"
+// @Router		/admin/jobs/{queue}/dead/{id}/retry [post]
+// @Security	BearerToken
+func (h *adminHandlers) RetryHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "RetryDeadJobHandler")
+	defer span.End()
+
+	job, err := h.backend.Revive(ctx, c.Param("queue"), c.Param("id"))
+	if err != nil {
+		return response.ErrorBuilder(backendError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(job).Send(c)
+}
+
+// @Summary		Delete Dead Job
+// @Description	Delete Dead Job
+// @ID			delete-dead-job
+// @Tags		Jobs
+// @Produce		json
+// @Param		queue	path		string					true	"Queue"
+// @Param		id		path		string					true	"Job ID"
+// @Success		200		{object}	response.ResponseFormat	"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse	"NOT_FOUND"
+// @Router		/admin/jobs/{queue}/dead/{id} [delete]
+// @Security	BearerToken
+func (h *adminHandlers) PurgeHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "DeleteDeadJobHandler")
+	defer span.End()
+
+	if err := h.backend.Purge(ctx, c.Param("queue"), c.Param("id")); err != nil {
+		return response.ErrorBuilder(backendError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(nil).Send(c)
+}
+
+// backendError maps the errors of the backend to responses.
+func backendError(err error) error {
+	if errors.Is(err, ErrJobNotFound) {
+		return response.NotFound(err)
+	}
+
+	return response.InternalServerError(err)
+}
@@ -0,0 +1,98 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type adminAPI struct {
+	e       *echo.Echo
+	backend Backend
+	tokens  map[int64]string
+}
+
+func newAdminAPI(t *testing.T) *adminAPI {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	jwtFactory := jwt.NewJWTFactory(jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600}, appRedis.NewRedisManager(client))
+
+	api := &adminAPI{e: echo.New(), backend: NewRedisBackend(client), tokens: make(map[int64]string)}
+	for _, id := range []int64{1, 2} {
+		token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: id, ContactType: types.CONTACT_TYPE_EMAIL}})
+		require.NoError(t, err)
+		api.tokens[id] = token
+	}
+
+	cfg := testConfig()
+	NewAdminHandlers(api.backend, NewWorker(cfg, api.backend, nil), []int64{1}).MapRoutes(api.e.Group("/api/v1"), middleware.New(jwtFactory))
+
+	return api
+}
+
+func (a *adminAPI) do(method, path string, userID int64) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(method, "/api/v1/admin/jobs"+path, nil)
+	req.Header.Set(types.AuthorizationHeaderKey.String(), "Bearer "+a.tokens[userID])
+
+	rec := httptest.NewRecorder()
+	a.e.ServeHTTP(rec, req)
+
+	return rec
+}
+
+func TestAdminHandlers(t *testing.T) {
+	api := newAdminAPI(t)
+	ctx := context.Background()
+
+	require.NoError(t, api.backend.Enqueue(ctx, newJob("1", time.Now())))
+	job, err := api.backend.Reserve(ctx, "emails", time.Minute, 5)
+	require.NoError(t, err)
+	job.LastError = "smtp unavailable"
+	require.NoError(t, api.backend.Bury(ctx, job))
+
+	assert.Equal(t, http.StatusForbidden, api.do(http.MethodGet, "", 2).Code, "only admins manage jobs")
+	assert.Equal(t, http.StatusForbidden, api.do(http.MethodPost, "/emails/dead/1/retry", 2).Code)
+
+	rec := api.do(http.MethodGet, "", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var stats struct {
+		Data []Stats `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &stats))
+	assert.Equal(t, []Stats{{Queue: "emails", Dead: 1}}, stats.Data)
+
+	assert.Equal(t, http.StatusBadRequest, api.do(http.MethodGet, "/emails/dead?limit=0", 1).Code)
+
+	rec = api.do(http.MethodGet, "/emails/dead?limit=10", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"last_error":"smtp unavailable"`)
+
+	assert.Equal(t, http.StatusNotFound, api.do(http.MethodPost, "/emails/dead/2/retry", 1).Code)
+	require.Equal(t, http.StatusOK, api.do(http.MethodPost, "/emails/dead/1/retry", 1).Code)
+
+	reserved, err := api.backend.Reserve(ctx, "emails", time.Minute, 5)
+	require.NoError(t, err)
+	assert.Equal(t, "1", reserved.ID, "the retried job is due again")
+
+	require.NoError(t, api.backend.Bury(ctx, reserved))
+	require.Equal(t, http.StatusOK, api.do(http.MethodDelete, "/emails/dead/1", 1).Code)
+	assert.Equal(t, http.StatusNotFound, api.do(http.MethodDelete, "/emails/dead/1", 1).Code)
+}
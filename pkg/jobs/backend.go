@@ -0,0 +1,87 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+var (
+	// ErrNoJob is returned by Reserve when no job of the queue is due.
+	ErrNoJob = errors.New("jobs: no job due")
+	// ErrJobNotFound is returned when a dead job does not exist.
+	ErrJobNotFound = errors.New("jobs: job not found")
+	// ErrReservationLost is returned when settling a job whose reservation
+	// expired, the job may already run on another worker.
+	ErrReservationLost = errors.New("jobs: reservation lost")
+)
+
+// Backend stores the queues. A job is scheduled until it is due, then in
+// flight once a worker reserved it, until the worker settles it.
+type Backend interface {
+	// Enqueue schedules job on its queue, due at its RunAt.
+	Enqueue(ctx context.Context, job Job) error
+	// Reserve takes the next due job of queue, hidden from the other workers
+	// for visibility. A job still in flight after its visibility counts a
+	// failed attempt: it is due again, or buried once it reached its
+	// MaxAttempts, maxAttempts when it does not override it. It returns ErrNoJob
+	// when no job is due.
+	Reserve(ctx context.Context, queue string, visibility time.Duration, maxAttempts int) (Job, error)
+	// Ack removes a job that ran successfully. Ack, Retry and Bury return
+	// ErrReservationLost when the Reservation of job expired.
+	Ack(ctx context.Context, job Job) error
+	// Retry schedules a failed job again, due at its RunAt.
+	Retry(ctx context.Context, job Job) error
+	// Bury moves a failed job to the dead letter queue of its queue.
+	Bury(ctx context.Context, job Job) error
+
+	// Dead lists the dead jobs of queue, the last buried first.
+	Dead(ctx context.Context, queue string, limit int) ([]Job, error)
+	// Revive schedules a dead job again, due now with its attempts reset.
+	Revive(ctx context.Context, queue, id string) (Job, error)
+	// Purge deletes a dead job.
+	Purge(ctx context.Context, queue, id string) error
+	// Stats counts the jobs of queue.
+	Stats(ctx context.Context, queue string) (Stats, error)
+}
+
+// Stats counts the jobs of a queue.
+type Stats struct {
+	Queue     string `json:"queue"`
+	Ready     int64  `json:"ready"`     // Due and waiting for a worker.
+	Scheduled int64  `json:"scheduled"` // Due later, delayed or waiting to be retried.
+	InFlight  int64  `json:"in_flight"` // Reserved by a worker.
+	Dead      int64  `json:"dead"`
+}
+
+// errVisibilityTimeout is the last error of the jobs not settled within their
+// visibility timeout.
+const errVisibilityTimeout = "jobs: visibility timeout expired"
+
+// expired counts the failed attempt of a job not settled within its visibility
+// timeout.
+func expired(job Job) Job {
+	job.Attempt++
+	job.LastError = errVisibilityTimeout
+
+	return job
+}
+
+// buried reports whether a failed job reached its max attempts, maxAttempts
+// when it does not override them.
+func buried(job Job, maxAttempts int) bool {
+	if job.MaxAttempts > 0 {
+		maxAttempts = job.MaxAttempts
+	}
+
+	return maxAttempts > 0 && job.Attempt >= maxAttempts
+}
+
+// revived resets a dead job to run again now.
+func revived(job Job) Job {
+	job.Attempt = 0
+	job.LastError = ""
+	job.RunAt = time.Now()
+
+	return job
+}
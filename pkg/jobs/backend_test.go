@@ -0,0 +1,254 @@
+package jobs
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newRedisBackend(t *testing.T) Backend {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return NewRedisBackend(client)
+}
+
+// backends runs test against every backend.
+func backends(t *testing.T, test func(t *testing.T, backend Backend)) {
+	t.Run("redis", func(t *testing.T) { test(t, newRedisBackend(t)) })
+	t.Run("memory", func(t *testing.T) { test(t, NewMemoryBackend()) })
+}
+
+func newJob(id string, runAt time.Time) Job {
+	return Job{ID: id, Queue: "emails", Type: "send", Payload: []byte(`{}`), RunAt: runAt, EnqueuedAt: time.Now()}
+}
+
+func TestBackend_Reserve(t *testing.T) {
+	backends(t, func(t *testing.T, backend Backend) {
+		ctx := context.Background()
+		now := time.Now()
+
+		require.NoError(t, backend.Enqueue(ctx, newJob("later", now.Add(time.Hour))))
+		require.NoError(t, backend.Enqueue(ctx, newJob("second", now.Add(-time.Second))))
+		require.NoError(t, backend.Enqueue(ctx, newJob("first", now.Add(-time.Minute))))
+
+		stats, err := backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Queue: "emails", Ready: 2, Scheduled: 1}, stats)
+
+		job, err := backend.Reserve(ctx, "emails", time.Minute, 5)
+		require.NoError(t, err)
+		assert.Equal(t, "first", job.ID, "the job due first runs first")
+
+		job, err = backend.Reserve(ctx, "emails", time.Minute, 5)
+		require.NoError(t, err)
+		assert.Equal(t, "second", job.ID)
+
+		_, err = backend.Reserve(ctx, "emails", time.Minute, 5)
+		assert.ErrorIs(t, err, ErrNoJob, "delayed jobs wait until they are due")
+
+		stats, err = backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Queue: "emails", Scheduled: 1, InFlight: 2}, stats)
+
+		require.NoError(t, backend.Ack(ctx, job))
+
+		stats, err = backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), stats.InFlight)
+	})
+}
+
+func TestBackend_VisibilityTimeout(t *testing.T) {
+	backends(t, func(t *testing.T, backend Backend) {
+		ctx := context.Background()
+		require.NoError(t, backend.Enqueue(ctx, newJob("1", time.Now())))
+
+		job, err := backend.Reserve(ctx, "emails", 50*time.Millisecond, 5)
+		require.NoError(t, err)
+
+		_, err = backend.Reserve(ctx, "emails", time.Minute, 5)
+		assert.ErrorIs(t, err, ErrNoJob, "a reserved job is hidden from the other workers")
+
+		time.Sleep(100 * time.Millisecond)
+
+		again, err := backend.Reserve(ctx, "emails", time.Minute, 5)
+		require.NoError(t, err, "a job not settled in time runs again")
+		assert.Equal(t, job.ID, again.ID)
+		assert.Equal(t, 1, again.Attempt, "the expired reservation counts as a failed attempt")
+		assert.Equal(t, errVisibilityTimeout, again.LastError)
+	})
+}
+
+func TestBackend_SettlesOnlyTheCurrentReservation(t *testing.T) {
+	backends(t, func(t *testing.T, backend Backend) {
+		ctx := context.Background()
+		require.NoError(t, backend.Enqueue(ctx, newJob("1", time.Now())))
+
+		stale, err := backend.Reserve(ctx, "emails", time.Millisecond, 5)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		job, err := backend.Reserve(ctx, "emails", time.Minute, 5)
+		require.NoError(t, err)
+		require.Equal(t, stale.ID, job.ID)
+		assert.NotEqual(t, stale.Reservation, job.Reservation)
+
+		assert.ErrorIs(t, backend.Ack(ctx, stale), ErrReservationLost, "the job runs on another worker")
+		assert.ErrorIs(t, backend.Retry(ctx, stale), ErrReservationLost)
+		assert.ErrorIs(t, backend.Bury(ctx, stale), ErrReservationLost)
+
+		stats, err := backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Queue: "emails", InFlight: 1}, stats, "the stale reservation leaves the job in flight")
+
+		require.NoError(t, backend.Ack(ctx, job))
+		assert.ErrorIs(t, backend.Ack(ctx, job), ErrReservationLost, "a job is settled once")
+
+		stats, err = backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Queue: "emails"}, stats)
+	})
+}
+
+func TestBackend_VisibilityTimeoutDeadLetter(t *testing.T) {
+	backends(t, func(t *testing.T, backend Backend) {
+		ctx := context.Background()
+
+		// The payload holds fields named like the ones of the job.
+		job := newJob("1", time.Now())
+		job.Payload = []byte(`{"attempt":7,"max_attempts":1,"ids":[],"id":12345678901234567890}`)
+		job.LastError = "smtp unavailable"
+		require.NoError(t, backend.Enqueue(ctx, job))
+
+		for attempt := range 3 {
+			reserved, err := backend.Reserve(ctx, "emails", time.Millisecond, 3)
+			require.NoError(t, err)
+			assert.Equal(t, attempt, reserved.Attempt)
+			assert.JSONEq(t, string(job.Payload), string(reserved.Payload), "the payload is left untouched")
+
+			time.Sleep(5 * time.Millisecond)
+		}
+
+		_, err := backend.Reserve(ctx, "emails", time.Minute, 3)
+		assert.ErrorIs(t, err, ErrNoJob, "the job is buried at its max attempts")
+
+		dead, err := backend.Dead(ctx, "emails", 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, 3, dead[0].Attempt)
+		assert.Equal(t, errVisibilityTimeout, dead[0].LastError)
+		assert.Equal(t, string(job.Payload), string(dead[0].Payload))
+
+		// MaxAttempts of the job overrides the one of the queue.
+		override := newJob("2", time.Now())
+		override.MaxAttempts = 1
+		require.NoError(t, backend.Enqueue(ctx, override))
+
+		_, err = backend.Reserve(ctx, "emails", time.Millisecond, 3)
+		require.NoError(t, err)
+		time.Sleep(5 * time.Millisecond)
+
+		_, err = backend.Reserve(ctx, "emails", time.Minute, 3)
+		assert.ErrorIs(t, err, ErrNoJob)
+
+		stats, err := backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Queue: "emails", Dead: 2}, stats)
+	})
+}
+
+func TestRedisBackend_VisibilityTimeoutIgnoresTheJSONLayout(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	backend := NewRedisBackend(client)
+	ctx := context.Background()
+	keys := keysOf("emails")
+
+	// Jobs saved by another encoder, with their fields in another order, and a
+	// job that is not JSON at all.
+	require.NoError(t, backend.Enqueue(ctx, newJob("reordered", time.Now().Add(-time.Second))))
+	require.NoError(t, backend.Enqueue(ctx, newJob("corrupt", time.Now())))
+	require.NoError(t, client.HSet(ctx, keys.jobs,
+		"reordered", `{"last_error":"smtp unavailable","attempt":1,"type":"send","queue":"emails","id":"reordered","payload":{}}`,
+		"corrupt", `not a job`,
+	).Err())
+
+	_, err := backend.Reserve(ctx, "emails", time.Millisecond, 3)
+	require.NoError(t, err)
+	_, err = backend.Reserve(ctx, "emails", time.Millisecond, 3)
+	require.Error(t, err, "the corrupt job cannot be decoded")
+
+	time.Sleep(5 * time.Millisecond)
+
+	// Both jobs are due again, the corrupt one fails to decode and does not
+	// block the queue.
+	var job Job
+	for range 2 {
+		if job, err = backend.Reserve(ctx, "emails", time.Millisecond, 3); err == nil {
+			break
+		}
+	}
+	require.NoError(t, err)
+	assert.Equal(t, "reordered", job.ID)
+	assert.Equal(t, 2, job.Attempt)
+	assert.Equal(t, errVisibilityTimeout, job.LastError)
+
+	// The corrupt job keeps failing until it reaches the max attempts of the queue.
+	for range 3 {
+		time.Sleep(5 * time.Millisecond)
+		_, _ = backend.Reserve(ctx, "emails", time.Millisecond, 3)
+	}
+
+	stats, err := backend.Stats(ctx, "emails")
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Dead)
+}
+
+func TestBackend_DeadLetter(t *testing.T) {
+	backends(t, func(t *testing.T, backend Backend) {
+		ctx := context.Background()
+		require.NoError(t, backend.Enqueue(ctx, newJob("1", time.Now())))
+
+		job, err := backend.Reserve(ctx, "emails", time.Minute, 5)
+		require.NoError(t, err)
+
+		job.Attempt, job.LastError = 5, "smtp unavailable"
+		require.NoError(t, backend.Bury(ctx, job))
+
+		dead, err := backend.Dead(ctx, "emails", 10)
+		require.NoError(t, err)
+		require.Len(t, dead, 1)
+		assert.Equal(t, "smtp unavailable", dead[0].LastError)
+
+		_, err = backend.Revive(ctx, "emails", "missing")
+		assert.ErrorIs(t, err, ErrJobNotFound)
+
+		revived, err := backend.Revive(ctx, "emails", "1")
+		require.NoError(t, err)
+		assert.Zero(t, revived.Attempt)
+
+		job, err = backend.Reserve(ctx, "emails", time.Minute, 5)
+		require.NoError(t, err)
+		assert.Equal(t, "1", job.ID)
+		assert.Empty(t, job.LastError)
+
+		require.NoError(t, backend.Bury(ctx, job))
+		require.NoError(t, backend.Purge(ctx, "emails", "1"))
+		assert.ErrorIs(t, backend.Purge(ctx, "emails", "1"), ErrJobNotFound)
+
+		stats, err := backend.Stats(ctx, "emails")
+		require.NoError(t, err)
+		assert.Equal(t, Stats{Queue: "emails"}, stats)
+	})
+}
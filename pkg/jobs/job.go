@@ -0,0 +1,173 @@
+// Package jobs runs work outside of the request path, such as sending
+// verification codes, exports or webhooks.
+//
+// Jobs are enqueued on a queue of a Backend and run by the workers of the
+// queue, possibly on another instance. Delivery is at least once: a job whose
+// worker does not settle it within the visibility timeout of its queue is run
+// again, so handlers must be idempotent. Failed jobs are retried with an
+// exponential backoff, then moved to the dead letter queue of their queue.
+//
+//	var SendCode = jobs.NewType[SendCodePayload]("send_code", "notifications")
+//
+//	jobs.Handle(worker, SendCode, func(ctx context.Context, payload SendCodePayload) error {
+//	    return sender.Send(ctx, payload.Email, payload.Code)
+//	})
+//
+//	_, err := SendCode.Enqueue(ctx, client, SendCodePayload{Email: email, Code: code}, jobs.Delay(time.Minute))
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/logging"
+	"github.com/google/uuid"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+)
+
+// DefaultQueue is the queue of the job types created without one.
+const DefaultQueue = "default"
+
+// metadataRequestID is the metadata key of the request ID of the enqueuer.
+const metadataRequestID = "request_id"
+
+// Job is a unit of work run by the workers of its queue.
+type Job struct {
+	ID      string          `json:"id"`
+	Queue   string          `json:"queue"`
+	Type    string          `json:"type"`
+	Payload json.RawMessage `json:"payload"`
+	// Metadata holds the trace context and the request ID of the enqueuer.
+	Metadata map[string]string `json:"metadata,omitempty"`
+	// Attempt is the number of failed attempts so far.
+	Attempt int `json:"attempt"`
+	// MaxAttempts overrides the MaxAttempts of the queue when set.
+	MaxAttempts int       `json:"max_attempts,omitempty"`
+	RunAt       time.Time `json:"run_at"`
+	EnqueuedAt  time.Time `json:"enqueued_at"`
+	// LastError is the error of the last failed attempt.
+	LastError string `json:"last_error,omitempty"`
+	// Reservation identifies the reservation of the job by a worker. Once it
+	// expired, the job cannot be settled with it anymore.
+	Reservation string `json:"-"`
+}
+
+// Option configures an enqueued job.
+type Option func(*Job)
+
+// Delay runs the job once d has elapsed.
+func Delay(d time.Duration) Option {
+	return func(j *Job) { j.RunAt = time.Now().Add(d) }
+}
+
+// At runs the job at t.
+func At(t time.Time) Option {
+	return func(j *Job) { j.RunAt = t }
+}
+
+// MaxAttempts overrides the MaxAttempts of the queue.
+func MaxAttempts(n int) Option {
+	return func(j *Job) { j.MaxAttempts = n }
+}
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not retryable, e.g. for an invalid payload, so the job
+// goes straight to the dead letter queue.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+// Type is a job type with a payload of type T, run on its queue.
+type Type[T any] struct {
+	Name  string
+	Queue string
+}
+
+// NewType creates the job type name of queue, DefaultQueue when empty.
+func NewType[T any](name, queue string) Type[T] {
+	if queue == "" {
+		queue = DefaultQueue
+	}
+
+	return Type[T]{Name: name, Queue: queue}
+}
+
+// Enqueue enqueues a job of the type with payload.
+func (t Type[T]) Enqueue(ctx context.Context, client *Client, payload T, opts ...Option) (Job, error) {
+	return client.Enqueue(ctx, t.Queue, t.Name, payload, opts...)
+}
+
+// Client enqueues jobs.
+type Client struct {
+	backend Backend
+}
+
+func NewClient(backend Backend) *Client {
+	return &Client{backend: backend}
+}
+
+// Enqueue enqueues a job of jobType on queue with payload encoded as JSON. The
+// job runs with the trace context and the request ID of ctx.
+func (c *Client) Enqueue(ctx context.Context, queue, jobType string, payload any, opts ...Option) (Job, error) {
+	raw, err := json.Marshal(payload)
+	if err != nil {
+		return Job{}, fmt.Errorf("jobs: encode %s payload: %w", jobType, err)
+	}
+
+	now := time.Now()
+	job := Job{
+		ID:         uuid.NewString(),
+		Queue:      queue,
+		Type:       jobType,
+		Payload:    raw,
+		Metadata:   make(map[string]string),
+		RunAt:      now,
+		EnqueuedAt: now,
+	}
+
+	for _, opt := range opts {
+		opt(&job)
+	}
+
+	otel.GetTextMapPropagator().Inject(ctx, propagation.MapCarrier(job.Metadata))
+	if requestID, ok := ctx.Value(logging.RequestIDContextKey).(string); ok && requestID != "" {
+		job.Metadata[metadataRequestID] = requestID
+	}
+
+	if err := c.backend.Enqueue(ctx, job); err != nil {
+		return Job{}, err
+	}
+
+	return job, nil
+}
+
+// jobContext returns ctx with the trace context and the request ID of the enqueuer.
+func jobContext(ctx context.Context, job Job) context.Context {
+	ctx = otel.GetTextMapPropagator().Extract(ctx, propagation.MapCarrier(job.Metadata))
+
+	if requestID := job.Metadata[metadataRequestID]; requestID != "" {
+		ctx = context.WithValue(ctx, logging.RequestIDContextKey, requestID)
+	}
+
+	return ctx
+}
@@ -0,0 +1,235 @@
+package jobs
+
+import (
+	"cmp"
+	"context"
+	"maps"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// memoryQueue holds the jobs of a queue by state, with the time they are due,
+// visible again or buried at.
+type memoryQueue struct {
+	jobs         map[string]Job
+	scheduled    map[string]time.Time
+	inFlight     map[string]time.Time
+	dead         map[string]time.Time
+	reservations map[string]string // The reservations of the jobs in flight.
+}
+
+// MemoryBackend stores the queues in memory. It is meant for tests.
+type MemoryBackend struct {
+	mu     sync.Mutex
+	queues map[string]*memoryQueue
+}
+
+func NewMemoryBackend() *MemoryBackend {
+	return &MemoryBackend{queues: make(map[string]*memoryQueue)}
+}
+
+func (b *MemoryBackend) queue(name string) *memoryQueue {
+	q, ok := b.queues[name]
+	if !ok {
+		q = &memoryQueue{
+			jobs:         make(map[string]Job),
+			scheduled:    make(map[string]time.Time),
+			inFlight:     make(map[string]time.Time),
+			dead:         make(map[string]time.Time),
+			reservations: make(map[string]string),
+		}
+		b.queues[name] = q
+	}
+
+	return q
+}
+
+func (b *MemoryBackend) Enqueue(ctx context.Context, job Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(job.Queue)
+	q.jobs[job.ID] = job
+	q.scheduled[job.ID] = job.RunAt
+
+	return nil
+}
+
+func (b *MemoryBackend) Reserve(ctx context.Context, queue string, visibility time.Duration, maxAttempts int) (Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	now := time.Now()
+
+	for id, visibleAt := range q.inFlight {
+		if visibleAt.After(now) {
+			continue
+		}
+
+		delete(q.inFlight, id)
+		delete(q.reservations, id)
+
+		job := expired(q.jobs[id])
+		q.jobs[id] = job
+
+		if buried(job, maxAttempts) {
+			q.dead[id] = now
+		} else {
+			q.scheduled[id] = now
+		}
+	}
+
+	next, due := "", time.Time{}
+	for id, at := range q.scheduled {
+		if !at.After(now) && (next == "" || at.Before(due)) {
+			next, due = id, at
+		}
+	}
+
+	if next == "" {
+		return Job{}, ErrNoJob
+	}
+
+	delete(q.scheduled, next)
+	q.inFlight[next] = now.Add(visibility)
+
+	job := q.jobs[next]
+	job.Reservation = uuid.NewString()
+	q.reservations[next] = job.Reservation
+
+	return job, nil
+}
+
+func (b *MemoryBackend) Ack(ctx context.Context, job Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, err := b.settle(job)
+	if err != nil {
+		return err
+	}
+
+	delete(q.jobs, job.ID)
+
+	return nil
+}
+
+func (b *MemoryBackend) Retry(ctx context.Context, job Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, err := b.settle(job)
+	if err != nil {
+		return err
+	}
+
+	job.Reservation = ""
+	q.jobs[job.ID] = job
+	q.scheduled[job.ID] = job.RunAt
+
+	return nil
+}
+
+func (b *MemoryBackend) Bury(ctx context.Context, job Job) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q, err := b.settle(job)
+	if err != nil {
+		return err
+	}
+
+	job.Reservation = ""
+	q.jobs[job.ID] = job
+	q.dead[job.ID] = time.Now()
+
+	return nil
+}
+
+// settle ends the reservation of job and returns its queue, or
+// ErrReservationLost when the reservation expired.
+func (b *MemoryBackend) settle(job Job) (*memoryQueue, error) {
+	q := b.queue(job.Queue)
+	if reservation, ok := q.reservations[job.ID]; !ok || reservation != job.Reservation {
+		return nil, ErrReservationLost
+	}
+
+	delete(q.inFlight, job.ID)
+	delete(q.reservations, job.ID)
+
+	return q, nil
+}
+
+func (b *MemoryBackend) Dead(ctx context.Context, queue string, limit int) ([]Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+
+	ids := slices.SortedFunc(maps.Keys(q.dead), func(a, b string) int {
+		return cmp.Compare(q.dead[b].UnixNano(), q.dead[a].UnixNano())
+	})
+
+	jobs := make([]Job, 0, min(len(ids), limit))
+	for _, id := range ids[:min(len(ids), limit)] {
+		jobs = append(jobs, q.jobs[id])
+	}
+
+	return jobs, nil
+}
+
+func (b *MemoryBackend) Revive(ctx context.Context, queue, id string) (Job, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	if _, ok := q.dead[id]; !ok {
+		return Job{}, ErrJobNotFound
+	}
+
+	job := revived(q.jobs[id])
+
+	delete(q.dead, id)
+	q.jobs[id] = job
+	q.scheduled[id] = job.RunAt
+
+	return job, nil
+}
+
+func (b *MemoryBackend) Purge(ctx context.Context, queue, id string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	if _, ok := q.dead[id]; !ok {
+		return ErrJobNotFound
+	}
+
+	delete(q.dead, id)
+	delete(q.jobs, id)
+
+	return nil
+}
+
+func (b *MemoryBackend) Stats(ctx context.Context, queue string) (Stats, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	q := b.queue(queue)
+	now := time.Now()
+
+	stats := Stats{Queue: queue, InFlight: int64(len(q.inFlight)), Dead: int64(len(q.dead))}
+	for _, at := range q.scheduled {
+		if at.After(now) {
+			stats.Scheduled++
+		} else {
+			stats.Ready++
+		}
+	}
+
+	return stats, nil
+}
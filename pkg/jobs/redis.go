@@ -0,0 +1,363 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"time"
+
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// reserveScript takes the next due job of a queue and returns it with the
+// failed attempts of its expired reservations. The jobs whose visibility
+// expired count a failed attempt in the attempts hash, rather than in their
+// JSON, and are buried once they reach their max attempts. A job that cannot be
+// decoded counts the max attempts of the queue, so it is buried in turn.
+//
+//	KEYS: jobs hash, scheduled zset, in flight zset, dead zset, attempts hash,
+//	      reservations hash
+//	ARGV: now and the visibility deadline in milliseconds, the max attempts of
+//	      the queue, the reservation of the job
+var reserveScript = redis.NewScript(`
+local expired = redis.call("ZRANGEBYSCORE", KEYS[3], "-inf", ARGV[1])
+for _, id in ipairs(expired) do
+	redis.call("ZREM", KEYS[3], id)
+	redis.call("HDEL", KEYS[6], id)
+
+	local job = redis.call("HGET", KEYS[1], id)
+	if job then
+		local attempt, maxAttempts = 0, tonumber(ARGV[3])
+
+		local ok, decoded = pcall(cjson.decode, job)
+		if ok and type(decoded) == "table" then
+			attempt = tonumber(decoded.attempt) or 0
+			if (tonumber(decoded.max_attempts) or 0) > 0 then
+				maxAttempts = tonumber(decoded.max_attempts)
+			end
+		end
+
+		attempt = attempt + redis.call("HINCRBY", KEYS[5], id, 1)
+
+		if maxAttempts > 0 and attempt >= maxAttempts then
+			redis.call("ZADD", KEYS[4], ARGV[1], id)
+		else
+			redis.call("ZADD", KEYS[2], ARGV[1], id)
+		end
+	end
+end
+
+local ids = redis.call("ZRANGEBYSCORE", KEYS[2], "-inf", ARGV[1], "LIMIT", 0, 1)
+if #ids == 0 then
+	return false
+end
+
+redis.call("ZREM", KEYS[2], ids[1])
+
+local job = redis.call("HGET", KEYS[1], ids[1])
+if not job then
+	return false
+end
+
+redis.call("ZADD", KEYS[3], ARGV[2], ids[1])
+redis.call("HSET", KEYS[6], ids[1], ARGV[4])
+return {job, redis.call("HGET", KEYS[5], ids[1]) or "0"}
+`)
+
+// settleScript ends the reservation of a job, unless it expired. It deletes the
+// job, or saves it and moves it to the set of the fifth key when there is one.
+//
+//	KEYS: jobs hash, in flight zset, attempts hash, reservations hash, and the
+//	      zset the job moves to
+//	ARGV: the ID and the reservation of the job, its JSON and score in the zset
+var settleScript = redis.NewScript(`
+if redis.call("HGET", KEYS[4], ARGV[1]) ~= ARGV[2] then
+	return 0
+end
+
+redis.call("ZREM", KEYS[2], ARGV[1])
+redis.call("HDEL", KEYS[3], ARGV[1])
+redis.call("HDEL", KEYS[4], ARGV[1])
+
+if #KEYS == 5 then
+	redis.call("HSET", KEYS[1], ARGV[1], ARGV[3])
+	redis.call("ZADD", KEYS[5], ARGV[4], ARGV[1])
+else
+	redis.call("HDEL", KEYS[1], ARGV[1])
+end
+
+return 1
+`)
+
+type redisBackend struct {
+	client redis.UniversalClient
+}
+
+// NewRedisBackend creates a backend storing the queues in Redis. The keys of a
+// queue share a hash tag, so they live on the same node of a cluster.
+func NewRedisBackend(client redis.UniversalClient) Backend {
+	return &redisBackend{client: client}
+}
+
+// queueKeys are the keys of a queue. The attempts hash counts the expired
+// reservations of the jobs since they were last saved, the reservations hash
+// holds the reservations of the jobs in flight.
+type queueKeys struct {
+	jobs, scheduled, inFlight, dead, attempts, reservations string
+}
+
+func keysOf(queue string) queueKeys {
+	prefix := appRedis.REDIS_PREFIX_KEY_JOBS.Key("{" + queue + "}")
+
+	return queueKeys{
+		jobs:      prefix + ":jobs",
+		scheduled: prefix + ":scheduled",
+		inFlight:  prefix + ":in_flight",
+		dead:      prefix + ":dead",
+		attempts:  prefix + ":attempts",
+
+		reservations: prefix + ":reservations",
+	}
+}
+
+func (b *redisBackend) Enqueue(ctx context.Context, job Job) error {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	keys := keysOf(job.Queue)
+
+	_, err = b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HSet(ctx, keys.jobs, job.ID, raw)
+		pipe.ZAdd(ctx, keys.scheduled, &redis.Z{Score: score(job.RunAt), Member: job.ID})
+		return nil
+	})
+
+	return err
+}
+
+func (b *redisBackend) Reserve(ctx context.Context, queue string, visibility time.Duration, maxAttempts int) (Job, error) {
+	keys := keysOf(queue)
+	now := time.Now()
+	reservation := uuid.NewString()
+
+	values, err := reserveScript.Run(ctx, b.client,
+		[]string{keys.jobs, keys.scheduled, keys.inFlight, keys.dead, keys.attempts, keys.reservations},
+		score(now), score(now.Add(visibility)), maxAttempts, reservation,
+	).StringSlice()
+	if errors.Is(err, redis.Nil) {
+		return Job{}, ErrNoJob
+	}
+
+	if err != nil {
+		return Job{}, err
+	}
+
+	job, err := decodeJob(values[0], values[1])
+	job.Reservation = reservation
+
+	return job, err
+}
+
+func (b *redisBackend) Ack(ctx context.Context, job Job) error {
+	keys := keysOf(job.Queue)
+
+	return b.settle(ctx, job, []string{keys.jobs, keys.inFlight, keys.attempts, keys.reservations}, "", 0)
+}
+
+func (b *redisBackend) Retry(ctx context.Context, job Job) error {
+	return b.move(ctx, job, keysOf(job.Queue).scheduled, job.RunAt)
+}
+
+func (b *redisBackend) Bury(ctx context.Context, job Job) error {
+	return b.move(ctx, job, keysOf(job.Queue).dead, time.Now())
+}
+
+// move saves job, with the attempts of its expired reservations, and moves it
+// from the in flight jobs to set, scored at.
+func (b *redisBackend) move(ctx context.Context, job Job, set string, at time.Time) error {
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return err
+	}
+
+	keys := keysOf(job.Queue)
+
+	return b.settle(ctx, job, []string{keys.jobs, keys.inFlight, keys.attempts, keys.reservations, set}, string(raw), score(at))
+}
+
+// settle runs settleScript for job, or returns ErrReservationLost when the
+// reservation of job expired.
+func (b *redisBackend) settle(ctx context.Context, job Job, keys []string, raw string, at float64) error {
+	settled, err := settleScript.Run(ctx, b.client, keys, job.ID, job.Reservation, raw, at).Int()
+	if err != nil {
+		return err
+	}
+
+	if settled == 0 {
+		return ErrReservationLost
+	}
+
+	return nil
+}
+
+func (b *redisBackend) Dead(ctx context.Context, queue string, limit int) ([]Job, error) {
+	keys := keysOf(queue)
+
+	ids, err := b.client.ZRevRange(ctx, keys.dead, 0, int64(limit)-1).Result()
+	if err != nil || len(ids) == 0 {
+		return []Job{}, err
+	}
+
+	var values, attempts *redis.SliceCmd
+	if _, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.HMGet(ctx, keys.jobs, ids...)
+		attempts = pipe.HMGet(ctx, keys.attempts, ids...)
+		return nil
+	}); err != nil {
+		return nil, err
+	}
+
+	jobs := make([]Job, 0, len(ids))
+	for i, value := range values.Val() {
+		raw, ok := value.(string)
+		if !ok {
+			continue
+		}
+
+		expiredAttempts, _ := attempts.Val()[i].(string)
+
+		job, err := decodeJob(raw, expiredAttempts)
+		if err != nil {
+			return nil, err
+		}
+
+		jobs = append(jobs, job)
+	}
+
+	return jobs, nil
+}
+
+func (b *redisBackend) Revive(ctx context.Context, queue, id string) (Job, error) {
+	job, err := b.deadJob(ctx, queue, id)
+	if err != nil {
+		return Job{}, err
+	}
+
+	job = revived(job)
+
+	raw, err := json.Marshal(job)
+	if err != nil {
+		return Job{}, err
+	}
+
+	keys := keysOf(queue)
+
+	_, err = b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, keys.dead, id)
+		pipe.HSet(ctx, keys.jobs, id, raw)
+		pipe.HDel(ctx, keys.attempts, id)
+		pipe.ZAdd(ctx, keys.scheduled, &redis.Z{Score: score(job.RunAt), Member: id})
+		return nil
+	})
+	if err != nil {
+		return Job{}, err
+	}
+
+	return job, nil
+}
+
+func (b *redisBackend) Purge(ctx context.Context, queue, id string) error {
+	if _, err := b.deadJob(ctx, queue, id); err != nil {
+		return err
+	}
+
+	keys := keysOf(queue)
+
+	_, err := b.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.ZRem(ctx, keys.dead, id)
+		pipe.HDel(ctx, keys.jobs, id)
+		pipe.HDel(ctx, keys.attempts, id)
+		return nil
+	})
+
+	return err
+}
+
+// deadJob returns the dead job id of queue, or ErrJobNotFound.
+func (b *redisBackend) deadJob(ctx context.Context, queue, id string) (Job, error) {
+	keys := keysOf(queue)
+
+	if err := b.client.ZScore(ctx, keys.dead, id).Err(); err != nil {
+		if errors.Is(err, redis.Nil) {
+			return Job{}, ErrJobNotFound
+		}
+
+		return Job{}, err
+	}
+
+	raw, err := b.client.HGet(ctx, keys.jobs, id).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return Job{}, ErrJobNotFound
+		}
+
+		return Job{}, err
+	}
+
+	expiredAttempts, err := b.client.HGet(ctx, keys.attempts, id).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return Job{}, err
+	}
+
+	return decodeJob(raw, expiredAttempts)
+}
+
+// decodeJob decodes the JSON of a job and counts the failed attempts of its
+// expiredAttempts reservations.
+func decodeJob(raw, expiredAttempts string) (Job, error) {
+	var job Job
+	if err := json.Unmarshal([]byte(raw), &job); err != nil {
+		return Job{}, fmt.Errorf("jobs: decode job: %w", err)
+	}
+
+	n, _ := strconv.Atoi(expiredAttempts)
+	for range n {
+		job = expired(job)
+	}
+
+	return job, nil
+}
+
+func (b *redisBackend) Stats(ctx context.Context, queue string) (Stats, error) {
+	keys := keysOf(queue)
+	now := strconv.FormatInt(time.Now().UnixMilli(), 10)
+
+	var ready, scheduled, inFlight, dead *redis.IntCmd
+	_, err := b.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		ready = pipe.ZCount(ctx, keys.scheduled, "-inf", now)
+		scheduled = pipe.ZCount(ctx, keys.scheduled, "("+now, "+inf")
+		inFlight = pipe.ZCard(ctx, keys.inFlight)
+		dead = pipe.ZCard(ctx, keys.dead)
+		return nil
+	})
+	if err != nil {
+		return Stats{}, err
+	}
+
+	return Stats{
+		Queue:     queue,
+		Ready:     ready.Val(),
+		Scheduled: scheduled.Val(),
+		InFlight:  inFlight.Val(),
+		Dead:      dead.Val(),
+	}, nil
+}
+
+// score is the sorted set score of t, in milliseconds.
+func score(t time.Time) float64 { return float64(t.UnixMilli()) }
@@ -0,0 +1,259 @@
+package jobs
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"sync"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+)
+
+// Config holds the configuration of the job workers.
+type Config struct {
+	Enable       bool          // Runs the workers together with the server.
+	PollInterval time.Duration // How often an idle worker checks its queue. Defaults to 1 second.
+	Queues       []QueueConfig // The queues run by the workers, with DefaultQueue when missing.
+}
+
+// QueueConfig holds the configuration of a queue.
+type QueueConfig struct {
+	Name        string
+	Concurrency int // The number of jobs of the queue run at once per instance. Defaults to 1.
+	// VisibilityTimeout bounds a job, it runs again on another worker when it
+	// is not settled by then. Defaults to 5 minutes.
+	VisibilityTimeout time.Duration
+	MaxAttempts       int           // The number of attempts before a job is dead-lettered. Defaults to 5.
+	RetryBackoff      time.Duration // The delay before the first retry, doubled on every attempt. Defaults to 10 seconds.
+	MaxRetryBackoff   time.Duration // The upper bound of the retry delay. Defaults to 1 hour.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.PollInterval, validation.Min(time.Duration(0))),
+		validation.Field(&c.Queues),
+	)
+}
+
+func (c QueueConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required),
+		validation.Field(&c.Concurrency, validation.Min(0)),
+		validation.Field(&c.VisibilityTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxAttempts, validation.Min(0)),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+
+	return c
+}
+
+func (c QueueConfig) withDefaults() QueueConfig {
+	if c.Concurrency <= 0 {
+		c.Concurrency = 1
+	}
+
+	if c.VisibilityTimeout <= 0 {
+		c.VisibilityTimeout = 5 * time.Minute
+	}
+
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = 10 * time.Second
+	}
+
+	if c.MaxRetryBackoff <= 0 {
+		c.MaxRetryBackoff = time.Hour
+	}
+
+	return c
+}
+
+// queue returns the config of the queue name, with the defaults when it is not configured.
+func (c Config) queue(name string) QueueConfig {
+	for _, queue := range c.Queues {
+		if queue.Name == name {
+			return queue.withDefaults()
+		}
+	}
+
+	return QueueConfig{Name: name}.withDefaults()
+}
+
+// HandlerFunc runs a job. Returning an error retries the job, or dead-letters
+// it when the error is permanent or the job has run out of attempts.
+type HandlerFunc func(ctx context.Context, job Job) error
+
+// Worker runs the jobs of the configured queues and of the queues of the
+// registered job types.
+type Worker struct {
+	cfg      Config
+	backend  Backend
+	log      *zerolog.Logger
+	handlers map[string]HandlerFunc
+	queues   []string
+}
+
+func NewWorker(cfg Config, backend Backend, log *zerolog.Logger) *Worker {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	w := &Worker{cfg: cfg.withDefaults(), backend: backend, log: log, handlers: make(map[string]HandlerFunc)}
+	for _, queue := range cfg.Queues {
+		w.addQueue(queue.Name)
+	}
+
+	return w
+}
+
+// Handle registers the handler of the job type t, decoding its payload. A
+// payload that cannot be decoded is dead-lettered.
+func Handle[T any](w *Worker, t Type[T], handler func(ctx context.Context, payload T) error) {
+	w.HandleFunc(t.Queue, t.Name, func(ctx context.Context, job Job) error {
+		var payload T
+		if err := json.Unmarshal(job.Payload, &payload); err != nil {
+			return Permanent(fmt.Errorf("decode %s payload: %w", job.Type, err))
+		}
+
+		return handler(ctx, payload)
+	})
+}
+
+// HandleFunc registers the handler of the jobs of jobType enqueued on queue.
+func (w *Worker) HandleFunc(queue, jobType string, handler HandlerFunc) {
+	w.handlers[jobType] = handler
+	w.addQueue(queue)
+}
+
+func (w *Worker) addQueue(name string) {
+	for _, queue := range w.queues {
+		if queue == name {
+			return
+		}
+	}
+
+	w.queues = append(w.queues, name)
+}
+
+// Queues returns the names of the queues run by the worker.
+func (w *Worker) Queues() []string {
+	if len(w.queues) == 0 {
+		return []string{DefaultQueue}
+	}
+
+	return w.queues
+}
+
+// Run runs the jobs until ctx is done, with Concurrency workers per queue. The
+// jobs running when ctx is done are finished and settled before Run returns.
+func (w *Worker) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, name := range w.Queues() {
+		queue := w.cfg.queue(name)
+		for range queue.Concurrency {
+			wg.Go(func() { w.poll(ctx, queue) })
+		}
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// poll runs the due jobs of queue one at a time until ctx is done.
+func (w *Worker) poll(ctx context.Context, queue QueueConfig) {
+	for ctx.Err() == nil {
+		job, err := w.backend.Reserve(ctx, queue.Name, queue.VisibilityTimeout, queue.MaxAttempts)
+		if err == nil {
+			w.process(context.WithoutCancel(ctx), queue, job)
+			continue
+		}
+
+		if !errors.Is(err, ErrNoJob) && ctx.Err() == nil {
+			w.log.Err(err).Ctx(ctx).Str("queue", queue.Name).Msg("[Jobs]Reserve")
+		}
+
+		select {
+		case <-ctx.Done():
+		case <-time.After(w.cfg.PollInterval):
+		}
+	}
+}
+
+// process runs job and settles it: acknowledged on success, scheduled again
+// or dead-lettered on failure.
+func (w *Worker) process(ctx context.Context, queue QueueConfig, job Job) {
+	runCtx, span := instrumentation.NewTraceSpan(jobContext(ctx, job), "Job "+job.Type)
+	defer span.End()
+
+	err := w.run(runCtx, queue, job)
+	if err == nil {
+		if err := w.backend.Ack(ctx, job); err != nil {
+			w.log.Err(err).Ctx(runCtx).Str("queue", queue.Name).Str("job_id", job.ID).Msg("[Jobs]Ack")
+		}
+
+		return
+	}
+
+	instrumentation.RecordSpanError(span, err)
+
+	job.Attempt++
+	job.LastError = err.Error()
+
+	level, message, settle := zerolog.InfoLevel, "[Jobs]Retry", w.backend.Retry
+	if IsPermanent(err) || buried(job, queue.MaxAttempts) {
+		level, message, settle = zerolog.WarnLevel, "[Jobs]DeadLetter", w.backend.Bury
+	} else {
+		job.RunAt = time.Now().Add(backoff(queue, job.Attempt))
+	}
+
+	w.log.WithLevel(level).Err(err).Ctx(runCtx).Str("queue", queue.Name).Str("job_id", job.ID).Str("type", job.Type).Int("attempt", job.Attempt).Msg(message)
+
+	if err := settle(ctx, job); err != nil {
+		// The job runs again once its visibility timeout elapses, or already
+		// runs again when the reservation was lost.
+		w.log.Err(err).Ctx(runCtx).Str("queue", queue.Name).Str("job_id", job.ID).Msg("[Jobs]Settle")
+	}
+}
+
+// run runs the handler of job within the visibility timeout of queue, turning
+// a panic into an error.
+func (w *Worker) run(ctx context.Context, queue QueueConfig, job Job) (err error) {
+	handler, ok := w.handlers[job.Type]
+	if !ok {
+		return Permanent(fmt.Errorf("no handler for job type %s", job.Type))
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, queue.VisibilityTimeout)
+	defer cancel()
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return handler(ctx, job)
+}
+
+// backoff returns the delay before the retry following attempt, with up to 50%
+// jitter so that the jobs failed together do not retry together.
+func backoff(queue QueueConfig, attempt int) time.Duration {
+	delay := min(queue.RetryBackoff<<min(attempt-1, 32), queue.MaxRetryBackoff)
+
+	return delay/2 + rand.N(delay/2+1)
+}
@@ -0,0 +1,213 @@
+package jobs
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/logging"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type welcomeEmail struct {
+	UserID int64 `json:"user_id"`
+}
+
+var sendWelcomeEmail = NewType[welcomeEmail]("send_welcome_email", "emails")
+
+func testConfig() Config {
+	return Config{
+		PollInterval: 5 * time.Millisecond,
+		Queues:       []QueueConfig{{Name: "emails", Concurrency: 2, MaxAttempts: 3, RetryBackoff: time.Millisecond, MaxRetryBackoff: time.Millisecond}},
+	}
+}
+
+// runWorker runs w until the test ends.
+func runWorker(t *testing.T, w *Worker) {
+	t.Helper()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+		_ = w.Run(ctx)
+	}()
+
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+}
+
+func waitStats(t *testing.T, backend Backend, want Stats) {
+	t.Helper()
+
+	assert.EventuallyWithT(t, func(c *assert.CollectT) {
+		stats, err := backend.Stats(context.Background(), want.Queue)
+		require.NoError(c, err)
+		assert.Equal(c, want, stats)
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestWorker_RunsTypedJobs(t *testing.T) {
+	backend := NewMemoryBackend()
+	client := NewClient(backend)
+	w := NewWorker(testConfig(), backend, nil)
+
+	received := make(chan welcomeEmail, 1)
+	Handle(w, sendWelcomeEmail, func(ctx context.Context, payload welcomeEmail) error {
+		received <- payload
+		return nil
+	})
+
+	runWorker(t, w)
+
+	job, err := sendWelcomeEmail.Enqueue(context.Background(), client, welcomeEmail{UserID: 42})
+	require.NoError(t, err)
+	assert.Equal(t, "emails", job.Queue)
+
+	select {
+	case payload := <-received:
+		assert.Equal(t, welcomeEmail{UserID: 42}, payload)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the job did not run")
+	}
+
+	waitStats(t, backend, Stats{Queue: "emails"})
+}
+
+func TestWorker_Delay(t *testing.T) {
+	backend := NewMemoryBackend()
+	w := NewWorker(testConfig(), backend, nil)
+
+	var ranAt atomic.Int64
+	Handle(w, sendWelcomeEmail, func(ctx context.Context, payload welcomeEmail) error {
+		ranAt.Store(time.Now().UnixNano())
+		return nil
+	})
+
+	runWorker(t, w)
+
+	enqueuedAt := time.Now()
+	_, err := sendWelcomeEmail.Enqueue(context.Background(), NewClient(backend), welcomeEmail{}, Delay(100*time.Millisecond))
+	require.NoError(t, err)
+
+	require.Eventually(t, func() bool { return ranAt.Load() != 0 }, 2*time.Second, 5*time.Millisecond)
+	assert.GreaterOrEqual(t, time.Duration(ranAt.Load()-enqueuedAt.UnixNano()), 100*time.Millisecond)
+}
+
+func TestWorker_RetriesThenDeadLetters(t *testing.T) {
+	backend := NewMemoryBackend()
+	w := NewWorker(testConfig(), backend, nil)
+
+	var attempts atomic.Int32
+	Handle(w, sendWelcomeEmail, func(ctx context.Context, payload welcomeEmail) error {
+		if attempts.Add(1) == 2 {
+			panic("smtp client")
+		}
+
+		return errors.New("smtp unavailable")
+	})
+
+	runWorker(t, w)
+
+	_, err := sendWelcomeEmail.Enqueue(context.Background(), NewClient(backend), welcomeEmail{UserID: 42})
+	require.NoError(t, err)
+
+	waitStats(t, backend, Stats{Queue: "emails", Dead: 1})
+	assert.Equal(t, int32(3), attempts.Load(), "the job runs MaxAttempts times, panics included")
+
+	dead, err := backend.Dead(context.Background(), "emails", 10)
+	require.NoError(t, err)
+	require.Len(t, dead, 1)
+	assert.Equal(t, 3, dead[0].Attempt)
+	assert.Equal(t, "smtp unavailable", dead[0].LastError)
+}
+
+func TestWorker_PermanentErrors(t *testing.T) {
+	backend := NewMemoryBackend()
+	client := NewClient(backend)
+	w := NewWorker(testConfig(), backend, nil)
+
+	var attempts atomic.Int32
+	w.HandleFunc("emails", "send_invoice", func(ctx context.Context, job Job) error {
+		attempts.Add(1)
+		return Permanent(errors.New("invoice not found"))
+	})
+	Handle(w, sendWelcomeEmail, func(ctx context.Context, payload welcomeEmail) error { return nil })
+
+	runWorker(t, w)
+
+	ctx := context.Background()
+	_, err := client.Enqueue(ctx, "emails", "send_invoice", nil)
+	require.NoError(t, err)
+	_, err = client.Enqueue(ctx, "emails", sendWelcomeEmail.Name, "not an object")
+	require.NoError(t, err)
+	_, err = client.Enqueue(ctx, "emails", "unknown", nil)
+	require.NoError(t, err)
+
+	waitStats(t, backend, Stats{Queue: "emails", Dead: 3})
+	assert.Equal(t, int32(1), attempts.Load(), "permanent errors are not retried")
+}
+
+func TestWorker_PropagatesRequestID(t *testing.T) {
+	backend := NewMemoryBackend()
+	w := NewWorker(testConfig(), backend, nil)
+
+	requestIDs := make(chan any, 1)
+	Handle(w, sendWelcomeEmail, func(ctx context.Context, payload welcomeEmail) error {
+		requestIDs <- ctx.Value(logging.RequestIDContextKey)
+		return nil
+	})
+
+	runWorker(t, w)
+
+	ctx := context.WithValue(context.Background(), logging.RequestIDContextKey, "req-1")
+	_, err := sendWelcomeEmail.Enqueue(ctx, NewClient(backend), welcomeEmail{})
+	require.NoError(t, err)
+
+	select {
+	case requestID := <-requestIDs:
+		assert.Equal(t, "req-1", requestID)
+	case <-time.After(2 * time.Second):
+		t.Fatal("the job did not run")
+	}
+}
+
+func TestWorker_FinishesRunningJobsOnStop(t *testing.T) {
+	backend := NewMemoryBackend()
+	w := NewWorker(testConfig(), backend, nil)
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	Handle(w, sendWelcomeEmail, func(ctx context.Context, payload welcomeEmail) error {
+		close(started)
+		<-finish
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- w.Run(ctx) }()
+
+	_, err := sendWelcomeEmail.Enqueue(context.Background(), NewClient(backend), welcomeEmail{})
+	require.NoError(t, err)
+	<-started
+
+	cancel()
+	close(finish)
+	require.NoError(t, <-done)
+
+	stats, err := backend.Stats(context.Background(), "emails")
+	require.NoError(t, err)
+	assert.Equal(t, Stats{Queue: "emails"}, stats, "the running job completed and was acknowledged")
+}
+
+func TestQueueConfig_Validate(t *testing.T) {
+	assert.NoError(t, testConfig().Validate())
+	assert.Error(t, Config{Queues: []QueueConfig{{}}}.Validate(), "the queue name is required")
+	assert.Error(t, Config{Queues: []QueueConfig{{Name: "emails", Concurrency: -1}}}.Validate())
+}
@@ -2,14 +2,18 @@ package jwt
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"time"
 
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/cache"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/degradation"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
 	"github.com/golang-jwt/jwt/v5"
+	"github.com/invopop/validation"
 )
 
 // Data struct holds the user-related information that is embedded in the JWT token claims.
@@ -20,10 +24,17 @@ type Data struct {
 }
 
 type JWTConfig struct {
-	Key             string
+	Key             string `secret:"true"`
 	ExpiredInSecond int64
 }
 
+func (c JWTConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Key, validation.Required),
+		validation.Field(&c.ExpiredInSecond, validation.Min(int64(0))),
+	)
+}
+
 // JWTClaims defines the structure of the data stored in the JWT token.
 // It includes standard registered claims along with the custom data field for user-specific information.
 type JWTClaims struct {
@@ -34,14 +45,41 @@ type JWTClaims struct {
 // JWTFactory struct contains the configuration for JWT token creation and verification.
 // It interacts with Redis to handle blacklisting of JWT tokens.
 type JWTFactory struct {
-	cfg   JWTConfig          // Configuration for JWT token generation (e.g., secret key)
-	redis redis.RedisManager // Redis instance for storing blacklisted tokens
+	cfg     JWTConfig          // Configuration for JWT token generation (e.g., secret key)
+	redis   redis.RedisManager // Redis instance for storing blacklisted tokens
+	revoked *cache.Cache[bool] // Revoked tokens seen by this instance, still rejected while Redis is down
+	guard   *degradation.Guard // Policy applied when the blacklist cannot be checked
+}
+
+// FactoryOption configures a JWTFactory.
+type FactoryOption func(*JWTFactory)
+
+// WithBlacklistGuard applies the policy of guard when Redis cannot be asked
+// whether a token is revoked. Without it such tokens are accepted.
+func WithBlacklistGuard(guard *degradation.Guard) FactoryOption {
+	return func(f *JWTFactory) { f.guard = guard }
+}
+
+// WithBlacklistFallback remembers up to size revoked tokens for ttl. They are
+// rejected without asking Redis, so also while it is down. Defaults to 10000
+// tokens for 10 minutes, a zero size disables it.
+func WithBlacklistFallback(size int, ttl time.Duration) FactoryOption {
+	return func(f *JWTFactory) {
+		f.revoked = cache.New[bool]("jwt_blacklist", nil, cache.Config{LocalSize: size, LocalTTL: ttl})
+	}
 }
 
 // NewJWTFactory is a constructor function to create a new JWTFactory instance.
 // It takes the JWTConfig and Redis instance as arguments and returns a new JWTFactory object.
-func NewJWTFactory(c JWTConfig, r redis.RedisManager) *JWTFactory {
-	return &JWTFactory{c, r}
+func NewJWTFactory(c JWTConfig, r redis.RedisManager, opts ...FactoryOption) *JWTFactory {
+	f := &JWTFactory{cfg: c, redis: r}
+
+	WithBlacklistFallback(10000, 10*time.Minute)(f)
+	for _, opt := range opts {
+		opt(f)
+	}
+
+	return f
 }
 
 // CreateJWT generates a new JWT token with the provided claims.
@@ -51,12 +89,9 @@ func (f *JWTFactory) CreateJWT(claims *JWTClaims) (string, error) {
 }
 
 // VerifyJWT validates the JWT token passed as a string and returns the claims if the token is valid.
-// It checks if the token is blacklisted, validates the signing method, and verifies the token's integrity.
+// It validates the signing method and verifies the token's integrity and expiry first, so that forged
+// or expired tokens are rejected without asking Redis, then checks if the token is blacklisted.
 func (f *JWTFactory) VerifyJWT(ctx context.Context, tokenString string) (*JWTClaims, error) {
-	if f.IsTokenBlacklisted(ctx, tokenString) {
-		return nil, response.Unauthorized(app_error.ErrInvalidToken)
-	}
-
 	// Parse and validate the token
 	token, err := jwt.ParseWithClaims(tokenString, &JWTClaims{}, func(token *jwt.Token) (any, error) {
 		// Validate the signing method
@@ -74,23 +109,53 @@ func (f *JWTFactory) VerifyJWT(ctx context.Context, tokenString string) (*JWTCla
 		return nil, response.Unauthorized(app_error.ErrInvalidToken)
 	}
 
+	revoked, err := f.IsTokenBlacklisted(ctx, tokenString)
+	if err != nil {
+		if err := f.guard.Fail(ctx, err); err != nil {
+			return nil, response.ServiceUnavailable(app_error.ErrTokenCheckUnavailable)
+		}
+	} else {
+		f.guard.Succeed(ctx)
+	}
+
+	if revoked {
+		return nil, response.Unauthorized(app_error.ErrInvalidToken)
+	}
+
 	return token.Claims.(*JWTClaims), nil
 }
 
 // AddToBlacklist adds the JWT token to the blacklist with the specified expiration time.
 // It stores the token in Redis to prevent its use in future requests.
 func (f *JWTFactory) AddToBlacklist(context context.Context, token string, expiration time.Time) error {
+	// Remembered locally first, this instance rejects it even if Redis fails.
+	_ = f.revoked.Set(context, token, true)
+
 	return f.redis.Set(context, token, "revoked", time.Until(expiration))
 }
 
 // IsTokenBlacklisted checks if the provided JWT token is blacklisted in Redis.
 // If the token is found in Redis with the revoked value, it is considered blacklisted.
-func (f *JWTFactory) IsTokenBlacklisted(ctx context.Context, token string) bool {
+// It returns an error when Redis cannot be asked about a token not revoked locally.
+func (f *JWTFactory) IsTokenBlacklisted(ctx context.Context, token string) (bool, error) {
+	if _, err := f.revoked.Get(ctx, token); err == nil {
+		return true, nil
+	}
+
 	revoked, err := f.redis.Get(ctx, token)
+	if errors.Is(err, redis.ErrNotFound) {
+		return false, nil
+	}
+
 	if err != nil {
-		return false
+		return false, err
 	}
 
-	// Return true if the token is blacklisted, false otherwise
-	return revoked == "revoked"
+	if revoked != "revoked" {
+		return false, nil
+	}
+
+	_ = f.revoked.Set(ctx, token, true)
+
+	return true, nil
 }
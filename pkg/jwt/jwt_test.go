@@ -5,6 +5,7 @@ import (
 	"testing"
 	"time"
 
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/degradation"
 	pkgJWT "github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
@@ -31,7 +32,8 @@ func setupJWTFactory(t *testing.T) (*pkgJWT.JWTFactory, pkgJWT.JWTConfig, func()
 	}
 
 	// Initialize Redis client
-	redisClient := redis.NewRedisClient(context.Background(), redis.RedisConfig{Addr: mr.Addr()})
+	redisClient, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
 	securityFactory := pkgJWT.NewJWTFactory(cfg, redis.NewRedisManager(redisClient))
 
 	// Cleanup function
@@ -117,3 +119,70 @@ func TestJWTFactory(t *testing.T) {
 		})
 	}
 }
+
+func TestJWTFactory_RedisDown(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+
+	redisClient, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+
+	cfg := pkgJWT.JWTConfig{Key: faker.UUIDDigit(), ExpiredInSecond: 3600}
+
+	failClosed := degradation.NewGuard("jwt_blacklist", degradation.FailClosed, nil)
+	closedFactory := pkgJWT.NewJWTFactory(cfg, redis.NewRedisManager(redisClient), pkgJWT.WithBlacklistGuard(failClosed))
+	openFactory := pkgJWT.NewJWTFactory(cfg, redis.NewRedisManager(redisClient), pkgJWT.WithBlacklistGuard(degradation.NewGuard("jwt_blacklist", degradation.FailOpen, nil)))
+
+	claims := generateTestClaims(&cfg)
+	token, err := closedFactory.CreateJWT(claims)
+	require.NoError(t, err)
+
+	revokedClaims := generateTestClaims(&cfg)
+	revokedClaims.Data.ID = 2
+	revoked, err := closedFactory.CreateJWT(revokedClaims)
+	require.NoError(t, err)
+	require.NoError(t, closedFactory.AddToBlacklist(context.Background(), revoked, revokedClaims.ExpiresAt.Time))
+
+	mr.Close()
+
+	_, err = closedFactory.VerifyJWT(context.Background(), token)
+	assert.Equal(t, response.ServiceUnavailable(app_error.ErrTokenCheckUnavailable), err, "fail-closed rejects the tokens it cannot check")
+	assert.True(t, failClosed.Degraded())
+
+	_, err = closedFactory.VerifyJWT(context.Background(), revoked)
+	assert.Equal(t, response.Unauthorized(app_error.ErrInvalidToken), err, "the tokens revoked locally are still rejected")
+
+	got, err := openFactory.VerifyJWT(context.Background(), token)
+	require.NoError(t, err, "fail-open accepts the tokens it cannot check")
+	assert.Equal(t, claims, got)
+}
+
+func TestJWTFactory_VerifiesBeforeBlacklistCheck(t *testing.T) {
+	mr, err := miniredis.Run()
+	require.NoError(t, err)
+
+	redisClient, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+
+	cfg := pkgJWT.JWTConfig{Key: faker.UUIDDigit(), ExpiredInSecond: 3600}
+
+	guard := degradation.NewGuard("jwt_blacklist", degradation.FailClosed, nil)
+	factory := pkgJWT.NewJWTFactory(cfg, redis.NewRedisManager(redisClient), pkgJWT.WithBlacklistGuard(guard))
+
+	forged, err := pkgJWT.NewJWTFactory(pkgJWT.JWTConfig{Key: "another-key"}, nil).CreateJWT(generateTestClaims(&cfg))
+	require.NoError(t, err)
+
+	expiredClaims := generateTestClaims(&cfg)
+	expiredClaims.ExpiresAt = jwt.NewNumericDate(time.Now().Add(-time.Minute))
+	expired, err := factory.CreateJWT(expiredClaims)
+	require.NoError(t, err)
+
+	mr.Close()
+
+	for _, token := range []string{forged, expired} {
+		_, err = factory.VerifyJWT(context.Background(), token)
+		assert.Equal(t, response.Unauthorized(app_error.ErrInvalidToken), err, "invalid tokens are rejected without asking Redis")
+	}
+
+	assert.False(t, guard.Degraded(), "invalid tokens do not count as failed blacklist checks")
+}
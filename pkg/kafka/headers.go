@@ -0,0 +1,100 @@
+package kafka
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"go.opentelemetry.io/otel"
+)
+
+const (
+	HeaderAttempt       = "x-attempt"        // The number of failed handling attempts so far.
+	HeaderNotBefore     = "x-not-before"     // The earliest time a retried message is handled, RFC 3339.
+	HeaderOriginalTopic = "x-original-topic" // The topic a retried or dead-lettered message was consumed from.
+	HeaderError         = "x-error"          // The error of the last failed handling attempt.
+)
+
+// GetHeader returns the value of the last header with the given key.
+func GetHeader(msg Message, key string) (string, bool) {
+	for i := len(msg.Headers) - 1; i >= 0; i-- {
+		if msg.Headers[i].Key == key {
+			return string(msg.Headers[i].Value), true
+		}
+	}
+
+	return "", false
+}
+
+// SetHeader sets the header key to value, replacing existing headers with that key.
+func SetHeader(msg *Message, key, value string) {
+	headers := msg.Headers[:0:0]
+	for _, header := range msg.Headers {
+		if header.Key != key {
+			headers = append(headers, header)
+		}
+	}
+
+	msg.Headers = append(headers, Header{Key: key, Value: []byte(value)})
+}
+
+// headerCarrier adapts message headers to a propagation.TextMapCarrier.
+type headerCarrier struct {
+	msg *Message
+}
+
+func (c headerCarrier) Get(key string) string {
+	value, _ := GetHeader(*c.msg, key)
+	return value
+}
+
+func (c headerCarrier) Set(key, value string) { SetHeader(c.msg, key, value) }
+
+func (c headerCarrier) Keys() []string {
+	keys := make([]string, 0, len(c.msg.Headers))
+	for _, header := range c.msg.Headers {
+		keys = append(keys, header.Key)
+	}
+
+	return keys
+}
+
+// InjectTrace writes the trace context of ctx into the message headers.
+func InjectTrace(ctx context.Context, msg *Message) {
+	otel.GetTextMapPropagator().Inject(ctx, headerCarrier{msg: msg})
+}
+
+// ExtractTrace returns ctx with the trace context read from the message headers.
+func ExtractTrace(ctx context.Context, msg Message) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, headerCarrier{msg: &msg})
+}
+
+// attempts returns the number of failed handling attempts recorded on msg.
+func attempts(msg Message) int {
+	value, ok := GetHeader(msg, HeaderAttempt)
+	if !ok {
+		return 0
+	}
+
+	n, err := strconv.Atoi(value)
+	if err != nil || n < 0 {
+		return 0
+	}
+
+	return n
+}
+
+// notBefore returns the earliest time a retried message may be handled.
+func notBefore(msg Message) time.Time {
+	value, ok := GetHeader(msg, HeaderNotBefore)
+	if !ok {
+		return time.Time{}
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return t
+}
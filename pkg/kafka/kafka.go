@@ -0,0 +1,110 @@
+// Package kafka provides a small Kafka client layer on top of segmentio/kafka-go.
+//
+// A Router consumes topics with a consumer group and commits offsets only after a
+// message has been handled. Messages whose handler fails are forwarded to a retry
+// topic ("<topic>.retry") and, once MaxAttempts is reached or the error is
+// permanent, to a dead letter topic ("<topic>.dlq"). Trace context is propagated
+// through message headers in both directions.
+package kafka
+
+import (
+	"context"
+	"time"
+
+	"github.com/invopop/validation"
+	kafkago "github.com/segmentio/kafka-go"
+)
+
+type (
+	Message = kafkago.Message
+	Header  = kafkago.Header
+)
+
+// Config holds the configuration of the Kafka client.
+type Config struct {
+	Enable          bool          // Starts the consumers and the Kafka event publisher together with the server.
+	Brokers         []string      // The addresses of the bootstrap brokers.
+	ClientID        string        // The client ID reported to the brokers.
+	GroupID         string        // The consumer group of the consumers.
+	MaxAttempts     int           // The number of handling attempts before a message is dead-lettered.
+	RetryBackoff    time.Duration // The delay before the first retry, doubled on every attempt.
+	MaxRetryBackoff time.Duration // The upper bound of the retry delay.
+	MaxWait         time.Duration // The maximum time a fetch waits for new data.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Brokers, validation.When(c.Enable, validation.Required)),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 5
+	}
+
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = time.Second
+	}
+
+	if c.MaxRetryBackoff <= 0 {
+		c.MaxRetryBackoff = 5 * time.Minute
+	}
+
+	if c.MaxWait <= 0 {
+		c.MaxWait = time.Second
+	}
+
+	return c
+}
+
+// Reader fetches messages of a single topic for a consumer group. It is
+// implemented by *kafkago.Reader.
+type Reader interface {
+	FetchMessage(ctx context.Context) (Message, error)
+	CommitMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Writer writes messages to the topic set on each message. It is implemented by
+// *kafkago.Writer.
+type Writer interface {
+	WriteMessages(ctx context.Context, msgs ...Message) error
+	Close() error
+}
+
+// Transport creates the readers and writers used by the Router and the Producer.
+type Transport interface {
+	NewReader(topic string) Reader
+	NewWriter() Writer
+}
+
+type brokerTransport struct {
+	cfg Config
+}
+
+// NewTransport creates a Transport connected to the configured brokers.
+func NewTransport(cfg Config) Transport {
+	return &brokerTransport{cfg: cfg.withDefaults()}
+}
+
+func (t *brokerTransport) NewReader(topic string) Reader {
+	return kafkago.NewReader(kafkago.ReaderConfig{
+		Brokers: t.cfg.Brokers,
+		GroupID: t.cfg.GroupID,
+		Topic:   topic,
+		MaxWait: t.cfg.MaxWait,
+		Dialer:  &kafkago.Dialer{ClientID: t.cfg.ClientID, Timeout: 10 * time.Second, DualStack: true},
+		// Offsets are committed explicitly after a message has been handled.
+		CommitInterval: 0,
+	})
+}
+
+func (t *brokerTransport) NewWriter() Writer {
+	return &kafkago.Writer{
+		Addr:         kafkago.TCP(t.cfg.Brokers...),
+		Balancer:     &kafkago.Hash{},
+		RequiredAcks: kafkago.RequireAll,
+		Transport:    &kafkago.Transport{ClientID: t.cfg.ClientID},
+	}
+}
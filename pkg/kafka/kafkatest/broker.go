@@ -0,0 +1,174 @@
+// Package kafkatest provides an in-process stand-in for a Kafka cluster.
+package kafkatest
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+)
+
+// Broker keeps single partition topics in memory and tracks the committed offsets
+// of one consumer group. Readers of the same topic share the committed offset but
+// not their read position, so use one reader per topic.
+type Broker struct {
+	mu        sync.Mutex
+	topics    map[string][]kafka.Message
+	committed map[string]int64
+	changed   chan struct{}
+}
+
+var _ kafka.Transport = (*Broker)(nil)
+
+func NewBroker() *Broker {
+	return &Broker{
+		topics:    make(map[string][]kafka.Message),
+		committed: make(map[string]int64),
+		changed:   make(chan struct{}),
+	}
+}
+
+// Messages returns a copy of the messages written to topic.
+func (b *Broker) Messages(topic string) []kafka.Message {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return append([]kafka.Message(nil), b.topics[topic]...)
+}
+
+// Committed returns the next offset the consumer group reads from topic.
+func (b *Broker) Committed(topic string) int64 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	return b.committed[topic]
+}
+
+// WaitFor blocks until cond returns true, ctx is done or the timeout expires. cond
+// is evaluated every time a message is written or committed.
+func (b *Broker) WaitFor(ctx context.Context, timeout time.Duration, cond func(b *Broker) bool) bool {
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	for {
+		b.mu.Lock()
+		changed := b.changed
+		b.mu.Unlock()
+
+		if cond(b) {
+			return true
+		}
+
+		select {
+		case <-ctx.Done():
+			return cond(b)
+		case <-changed:
+		}
+	}
+}
+
+func (b *Broker) NewReader(topic string) kafka.Reader {
+	return &reader{broker: b, topic: topic, position: -1}
+}
+
+func (b *Broker) NewWriter() kafka.Writer {
+	return &writer{broker: b}
+}
+
+// notify wakes up waiting readers. It must be called with b.mu held.
+func (b *Broker) notify() {
+	close(b.changed)
+	b.changed = make(chan struct{})
+}
+
+type reader struct {
+	broker   *Broker
+	topic    string
+	position int64 // The next offset to fetch, -1 to start at the committed offset.
+	closed   bool
+}
+
+func (r *reader) FetchMessage(ctx context.Context) (kafka.Message, error) {
+	for {
+		r.broker.mu.Lock()
+		if r.closed {
+			r.broker.mu.Unlock()
+			return kafka.Message{}, errors.New("kafkatest: reader closed")
+		}
+
+		if r.position < 0 {
+			r.position = r.broker.committed[r.topic]
+		}
+
+		messages := r.broker.topics[r.topic]
+		if r.position < int64(len(messages)) {
+			msg := messages[r.position]
+			r.position++
+			r.broker.mu.Unlock()
+
+			return msg, nil
+		}
+
+		changed := r.broker.changed
+		r.broker.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return kafka.Message{}, ctx.Err()
+		case <-changed:
+		}
+	}
+}
+
+func (r *reader) CommitMessages(ctx context.Context, msgs ...kafka.Message) error {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	for _, msg := range msgs {
+		if msg.Offset+1 > r.broker.committed[msg.Topic] {
+			r.broker.committed[msg.Topic] = msg.Offset + 1
+		}
+	}
+
+	r.broker.notify()
+
+	return nil
+}
+
+func (r *reader) Close() error {
+	r.broker.mu.Lock()
+	defer r.broker.mu.Unlock()
+
+	r.closed = true
+
+	return nil
+}
+
+type writer struct {
+	broker *Broker
+}
+
+func (w *writer) WriteMessages(ctx context.Context, msgs ...kafka.Message) error {
+	w.broker.mu.Lock()
+	defer w.broker.mu.Unlock()
+
+	for _, msg := range msgs {
+		if msg.Topic == "" {
+			return errors.New("kafkatest: message topic is required")
+		}
+
+		msg.Headers = append([]kafka.Header(nil), msg.Headers...)
+		msg.Offset = int64(len(w.broker.topics[msg.Topic]))
+		msg.Time = time.Now()
+
+		w.broker.topics[msg.Topic] = append(w.broker.topics[msg.Topic], msg)
+	}
+
+	w.broker.notify()
+
+	return nil
+}
+
+func (w *writer) Close() error { return nil }
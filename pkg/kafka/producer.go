@@ -0,0 +1,43 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+)
+
+// Producer writes messages with the trace context of the caller in their headers.
+type Producer struct {
+	writer Writer
+}
+
+func NewProducer(transport Transport) *Producer {
+	return &Producer{writer: transport.NewWriter()}
+}
+
+// Produce writes the messages. Each message must have its Topic set.
+func (p *Producer) Produce(ctx context.Context, msgs ...Message) error {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "KafkaProduce")
+	defer span.End()
+
+	for i := range msgs {
+		if msgs[i].Topic == "" {
+			return errors.New("kafka: message topic is required")
+		}
+
+		InjectTrace(ctx, &msgs[i])
+	}
+
+	if err := p.writer.WriteMessages(ctx, msgs...); err != nil {
+		instrumentation.RecordSpanError(span, err)
+		return err
+	}
+
+	return nil
+}
+
+// Close flushes pending writes and releases the underlying writer.
+func (p *Producer) Close() error {
+	return p.writer.Close()
+}
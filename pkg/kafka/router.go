@@ -0,0 +1,202 @@
+package kafka
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/rs/zerolog"
+	"golang.org/x/sync/errgroup"
+)
+
+const (
+	retryTopicSuffix = ".retry"
+	dlqTopicSuffix   = ".dlq"
+)
+
+// RetryTopic returns the topic failed messages of topic are retried from.
+func RetryTopic(topic string) string { return topic + retryTopicSuffix }
+
+// DLQTopic returns the dead letter topic of topic.
+func DLQTopic(topic string) string { return topic + dlqTopicSuffix }
+
+// HandlerFunc handles a consumed message. Returning an error forwards the message
+// to the retry topic, or to the dead letter topic when the error is permanent or
+// the message has run out of attempts.
+type HandlerFunc func(ctx context.Context, msg Message) error
+
+type permanentError struct {
+	err error
+}
+
+func (e *permanentError) Error() string { return e.err.Error() }
+func (e *permanentError) Unwrap() error { return e.err }
+
+// Permanent marks err as not retryable, e.g. for malformed messages, so the message
+// goes straight to the dead letter topic.
+func Permanent(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	return &permanentError{err: err}
+}
+
+// IsPermanent reports whether err was marked with Permanent.
+func IsPermanent(err error) bool {
+	var permanent *permanentError
+	return errors.As(err, &permanent)
+}
+
+type route struct {
+	topic   string
+	handler HandlerFunc
+}
+
+// Router consumes the registered topics and their retry topics with the configured
+// consumer group.
+//
+// Offsets are committed after a message has been handled or forwarded to the retry
+// or dead letter topic, so a message is never lost but may be handled more than
+// once; handlers must be idempotent. Retried messages are consumed in order, each
+// one waiting until its backoff has elapsed.
+type Router struct {
+	cfg       Config
+	transport Transport
+	log       *zerolog.Logger
+	routes    []route
+}
+
+func NewRouter(cfg Config, transport Transport, log *zerolog.Logger) *Router {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	return &Router{cfg: cfg.withDefaults(), transport: transport, log: log}
+}
+
+// Handle registers the handler of topic.
+func (r *Router) Handle(topic string, handler HandlerFunc) {
+	r.routes = append(r.routes, route{topic: topic, handler: handler})
+}
+
+// Run consumes the registered topics until ctx is done. A message that is being
+// handled when ctx is done is finished and committed before Run returns.
+func (r *Router) Run(ctx context.Context) error {
+	producer := NewProducer(r.transport)
+	defer producer.Close()
+
+	group, ctx := errgroup.WithContext(ctx)
+	for _, route := range r.routes {
+		for _, topic := range []string{route.topic, RetryTopic(route.topic)} {
+			reader := r.transport.NewReader(topic)
+
+			group.Go(func() error {
+				defer reader.Close()
+				return r.consume(ctx, reader, producer, route)
+			})
+		}
+	}
+
+	return group.Wait()
+}
+
+func (r *Router) consume(ctx context.Context, reader Reader, producer *Producer, route route) error {
+	for {
+		msg, err := reader.FetchMessage(ctx)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil
+			}
+
+			return err
+		}
+
+		// Wait for the backoff of a retried message. On shutdown the message is left
+		// uncommitted and fetched again on the next start.
+		if wait := time.Until(notBefore(msg)); wait > 0 {
+			select {
+			case <-ctx.Done():
+				return nil
+			case <-time.After(wait):
+			}
+		}
+
+		// In-flight messages are finished even when ctx is done.
+		if err := r.process(context.WithoutCancel(ctx), ctx.Done(), producer, route, msg); err != nil {
+			return nil
+		}
+
+		if err := reader.CommitMessages(context.WithoutCancel(ctx), msg); err != nil {
+			return err
+		}
+	}
+}
+
+// process handles msg, forwarding it to the retry or dead letter topic on failure.
+// It keeps trying until the message is settled and only returns an error when done
+// is closed first.
+func (r *Router) process(ctx context.Context, done <-chan struct{}, producer *Producer, route route, msg Message) error {
+	for {
+		err := r.handle(ctx, producer, route, msg)
+		if err == nil {
+			return nil
+		}
+
+		r.log.Err(err).Ctx(ctx).Str("topic", msg.Topic).Int64("offset", msg.Offset).Msg("[KafkaRouter]Forward")
+
+		select {
+		case <-done:
+			return err
+		case <-time.After(r.cfg.RetryBackoff):
+		}
+	}
+}
+
+func (r *Router) handle(ctx context.Context, producer *Producer, route route, msg Message) error {
+	ctx, span := instrumentation.NewTraceSpan(ExtractTrace(ctx, msg), "KafkaConsume "+route.topic)
+	defer span.End()
+
+	err := route.handler(ctx, msg)
+	if err == nil {
+		return nil
+	}
+
+	instrumentation.RecordSpanError(span, err)
+
+	attempt := attempts(msg) + 1
+
+	forward := Message{
+		Key:     msg.Key,
+		Value:   msg.Value,
+		Headers: append([]Header(nil), msg.Headers...),
+	}
+	SetHeader(&forward, HeaderAttempt, strconv.Itoa(attempt))
+	SetHeader(&forward, HeaderOriginalTopic, route.topic)
+	SetHeader(&forward, HeaderError, err.Error())
+
+	if IsPermanent(err) || attempt >= r.cfg.MaxAttempts {
+		forward.Topic = DLQTopic(route.topic)
+
+		r.log.Warn().Err(err).Ctx(ctx).Str("topic", route.topic).Int("attempt", attempt).Msg("[KafkaRouter]DeadLetter")
+	} else {
+		forward.Topic = RetryTopic(route.topic)
+		SetHeader(&forward, HeaderNotBefore, time.Now().Add(r.backoff(attempt)).Format(time.RFC3339Nano))
+
+		r.log.Info().Err(err).Ctx(ctx).Str("topic", route.topic).Int("attempt", attempt).Msg("[KafkaRouter]Retry")
+	}
+
+	return producer.Produce(ctx, forward)
+}
+
+func (r *Router) backoff(attempt int) time.Duration {
+	backoff := r.cfg.RetryBackoff
+	for i := 1; i < attempt && backoff < r.cfg.MaxRetryBackoff; i++ {
+		backoff *= 2
+	}
+
+	return min(backoff, r.cfg.MaxRetryBackoff)
+}
@@ -0,0 +1,191 @@
+package kafka_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/kafka/kafkatest"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const topic = "users.commands"
+
+func newRouter(t *testing.T, broker *kafkatest.Broker, handler kafka.HandlerFunc) func() error {
+	t.Helper()
+
+	router := kafka.NewRouter(kafka.Config{
+		MaxAttempts:     3,
+		RetryBackoff:    10 * time.Millisecond,
+		MaxRetryBackoff: 20 * time.Millisecond,
+	}, broker, nil)
+	router.Handle(topic, handler)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- router.Run(ctx) }()
+
+	return func() error {
+		cancel()
+		return <-done
+	}
+}
+
+func produce(t *testing.T, ctx context.Context, broker *kafkatest.Broker, value string) {
+	t.Helper()
+	require.NoError(t, kafka.NewProducer(broker).Produce(ctx, kafka.Message{Topic: topic, Key: []byte("1"), Value: []byte(value)}))
+}
+
+func committed(topic string, offset int64) func(b *kafkatest.Broker) bool {
+	return func(b *kafkatest.Broker) bool { return b.Committed(topic) == offset }
+}
+
+func TestRouter_CommitsAfterSuccess(t *testing.T) {
+	broker := kafkatest.NewBroker()
+
+	var handled atomic.Int32
+	stop := newRouter(t, broker, func(ctx context.Context, msg kafka.Message) error {
+		handled.Add(1)
+		return nil
+	})
+
+	produce(t, context.Background(), broker, "first")
+	produce(t, context.Background(), broker, "second")
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, committed(topic, 2)))
+	require.NoError(t, stop())
+
+	require.EqualValues(t, 2, handled.Load())
+	require.Empty(t, broker.Messages(kafka.RetryTopic(topic)))
+}
+
+func TestRouter_RetriesFailedMessage(t *testing.T) {
+	broker := kafkatest.NewBroker()
+
+	var calls atomic.Int32
+	stop := newRouter(t, broker, func(ctx context.Context, msg kafka.Message) error {
+		if calls.Add(1) == 1 {
+			return errors.New("temporarily unavailable")
+		}
+		return nil
+	})
+
+	produce(t, context.Background(), broker, "payload")
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, committed(kafka.RetryTopic(topic), 1)))
+	require.NoError(t, stop())
+
+	require.EqualValues(t, 2, calls.Load())
+	require.EqualValues(t, 1, broker.Committed(topic))
+
+	retried := broker.Messages(kafka.RetryTopic(topic))
+	require.Len(t, retried, 1)
+	require.Equal(t, "payload", string(retried[0].Value))
+
+	attempt, _ := kafka.GetHeader(retried[0], kafka.HeaderAttempt)
+	require.Equal(t, "1", attempt)
+	require.Empty(t, broker.Messages(kafka.DLQTopic(topic)))
+}
+
+func TestRouter_DeadLettersAfterMaxAttempts(t *testing.T) {
+	broker := kafkatest.NewBroker()
+
+	var calls atomic.Int32
+	stop := newRouter(t, broker, func(ctx context.Context, msg kafka.Message) error {
+		calls.Add(1)
+		return errors.New("always failing")
+	})
+
+	produce(t, context.Background(), broker, "payload")
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, func(b *kafkatest.Broker) bool {
+		return len(b.Messages(kafka.DLQTopic(topic))) == 1 && b.Committed(kafka.RetryTopic(topic)) == 2
+	}))
+	require.NoError(t, stop())
+
+	require.EqualValues(t, 3, calls.Load())
+	require.Len(t, broker.Messages(kafka.RetryTopic(topic)), 2)
+
+	dead := broker.Messages(kafka.DLQTopic(topic))[0]
+	attempt, _ := kafka.GetHeader(dead, kafka.HeaderAttempt)
+	original, _ := kafka.GetHeader(dead, kafka.HeaderOriginalTopic)
+	reason, _ := kafka.GetHeader(dead, kafka.HeaderError)
+	require.Equal(t, "3", attempt)
+	require.Equal(t, topic, original)
+	require.Equal(t, "always failing", reason)
+}
+
+func TestRouter_PermanentErrorSkipsRetries(t *testing.T) {
+	broker := kafkatest.NewBroker()
+
+	stop := newRouter(t, broker, func(ctx context.Context, msg kafka.Message) error {
+		return kafka.Permanent(errors.New("malformed payload"))
+	})
+
+	produce(t, context.Background(), broker, "{")
+
+	require.True(t, broker.WaitFor(context.Background(), time.Second, committed(topic, 1)))
+	require.NoError(t, stop())
+
+	require.Empty(t, broker.Messages(kafka.RetryTopic(topic)))
+	require.Len(t, broker.Messages(kafka.DLQTopic(topic)), 1)
+}
+
+func TestRouter_PropagatesTraceContext(t *testing.T) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+
+	broker := kafkatest.NewBroker()
+
+	traceIDs := make(chan trace.TraceID, 1)
+	stop := newRouter(t, broker, func(ctx context.Context, msg kafka.Message) error {
+		traceIDs <- trace.SpanContextFromContext(ctx).TraceID()
+		return nil
+	})
+	defer stop()
+
+	spanContext := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{0x01, 0x02, 0x03},
+		SpanID:     trace.SpanID{0x04, 0x05},
+		TraceFlags: trace.FlagsSampled,
+	})
+	produce(t, trace.ContextWithSpanContext(context.Background(), spanContext), broker, "payload")
+
+	select {
+	case traceID := <-traceIDs:
+		require.Equal(t, spanContext.TraceID(), traceID)
+	case <-time.After(time.Second):
+		t.Fatal("message was not handled")
+	}
+}
+
+func TestRouter_FinishesInFlightMessageOnShutdown(t *testing.T) {
+	broker := kafkatest.NewBroker()
+
+	started, release := make(chan struct{}), make(chan struct{})
+	router := kafka.NewRouter(kafka.Config{}, broker, nil)
+	router.Handle(topic, func(ctx context.Context, msg kafka.Message) error {
+		close(started)
+		<-release
+		return ctx.Err()
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- router.Run(ctx) }()
+
+	produce(t, context.Background(), broker, "payload")
+	<-started
+
+	cancel()
+	close(release)
+
+	require.NoError(t, <-done)
+	require.EqualValues(t, 1, broker.Committed(topic))
+	require.Empty(t, broker.Messages(kafka.RetryTopic(topic)))
+}
@@ -0,0 +1,241 @@
+// Package lifecycle starts the components of the service in dependency order
+// and stops them in reverse order.
+package lifecycle
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/invopop/validation"
+)
+
+// Config bounds the startup and the shutdown of the components.
+type Config struct {
+	// StartTimeout bounds the Start hooks of all components. Defaults to 10 minutes.
+	StartTimeout time.Duration
+	// ShutdownTimeout is the overall deadline of the shutdown, shared by the Stop
+	// hooks of all components. Defaults to 30 seconds.
+	ShutdownTimeout time.Duration
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.StartTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.ShutdownTimeout, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.StartTimeout <= 0 {
+		c.StartTimeout = 10 * time.Minute
+	}
+
+	if c.ShutdownTimeout <= 0 {
+		c.ShutdownTimeout = 30 * time.Second
+	}
+
+	return c
+}
+
+// Component is a part of the service with a lifetime, such as a server, a
+// connection pool or a background worker. Every hook is optional.
+type Component struct {
+	Name string
+	// DependsOn names the components that start before this one and stop after it.
+	DependsOn []string
+
+	// Start prepares the component, the next components start once it returns.
+	Start func(ctx context.Context) error
+	// Run runs in the background until its context is canceled at shutdown. A
+	// Run that fails before shutdown shuts the service down, one that returns
+	// nil is done.
+	Run func(ctx context.Context) error
+	// Stop releases the component. It runs after the components that depend on
+	// it have stopped, and before Run is waited for.
+	Stop func(ctx context.Context) error
+}
+
+// Manager runs registered components.
+type Manager struct {
+	cfg        Config
+	components []Component
+	onShutdown []func()
+}
+
+// New creates a manager without components.
+func New(cfg Config) *Manager {
+	return &Manager{cfg: cfg.withDefaults()}
+}
+
+// Register adds a component. Components can be registered in any order, they
+// are sorted by their dependencies when the manager runs.
+func (m *Manager) Register(components ...Component) {
+	m.components = append(m.components, components...)
+}
+
+// OnShutdown adds fn to the hooks that run when the shutdown starts, before
+// any component stops.
+func (m *Manager) OnShutdown(fn func()) {
+	m.onShutdown = append(m.onShutdown, fn)
+}
+
+// started is a component that started, with its running Run hook if any.
+type started struct {
+	Component
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// Run starts the components and blocks until ctx is done or a Run hook fails,
+// then stops the started components in reverse order within the shutdown
+// deadline. It returns the errors of the failed hooks.
+func (m *Manager) Run(ctx context.Context) error {
+	ordered, err := m.order()
+	if err != nil {
+		return err
+	}
+
+	failed := make(chan error, len(ordered))
+
+	startCtx, cancelStart := context.WithTimeout(ctx, m.cfg.StartTimeout)
+	defer cancelStart()
+
+	var (
+		running []*started
+		errs    []error
+	)
+
+	for _, component := range ordered {
+		if component.Start != nil {
+			if err := component.Start(startCtx); err != nil {
+				errs = append(errs, fmt.Errorf("start %s: %w", component.Name, err))
+				break
+			}
+		}
+
+		running = append(running, m.run(component, failed))
+	}
+
+	if len(errs) == 0 {
+		select {
+		case <-ctx.Done():
+		case err := <-failed:
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(append(errs, m.stop(running))...)
+}
+
+// run starts the Run hook of component in the background. Its error is sent to
+// failed when it fails before its context is canceled.
+func (m *Manager) run(component Component, failed chan<- error) *started {
+	runCtx, cancel := context.WithCancel(context.Background())
+	s := &started{Component: component, cancel: cancel, done: make(chan struct{})}
+
+	if component.Run == nil {
+		close(s.done)
+		return s
+	}
+
+	go func() {
+		defer close(s.done)
+
+		if err := component.Run(runCtx); err != nil && runCtx.Err() == nil {
+			failed <- fmt.Errorf("run %s: %w", component.Name, err)
+		}
+	}()
+
+	return s
+}
+
+// stop stops the running components in reverse order. Components left when
+// the deadline passes are not waited for.
+func (m *Manager) stop(running []*started) error {
+	ctx, cancel := context.WithTimeout(context.Background(), m.cfg.ShutdownTimeout)
+	defer cancel()
+
+	for _, fn := range m.onShutdown {
+		fn()
+	}
+
+	var errs []error
+	for i := len(running) - 1; i >= 0; i-- {
+		component := running[i]
+
+		component.cancel()
+
+		if component.Stop != nil {
+			if err := component.Stop(ctx); err != nil {
+				errs = append(errs, fmt.Errorf("stop %s: %w", component.Name, err))
+			}
+		}
+
+		select {
+		case <-component.done:
+		case <-ctx.Done():
+			errs = append(errs, fmt.Errorf("stop %s: %w", component.Name, ctx.Err()))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// order sorts the components so that every component comes after its
+// dependencies, keeping the registration order otherwise.
+func (m *Manager) order() ([]Component, error) {
+	byName := make(map[string]Component, len(m.components))
+	for _, component := range m.components {
+		if _, ok := byName[component.Name]; ok {
+			return nil, fmt.Errorf("component %q registered twice", component.Name)
+		}
+
+		byName[component.Name] = component
+	}
+
+	const (
+		visiting = iota + 1
+		visited
+	)
+
+	state := make(map[string]int, len(m.components))
+	ordered := make([]Component, 0, len(m.components))
+
+	var visit func(component Component, path []string) error
+	visit = func(component Component, path []string) error {
+		switch state[component.Name] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %v", append(path, component.Name))
+		}
+
+		state[component.Name] = visiting
+
+		for _, name := range component.DependsOn {
+			dependency, ok := byName[name]
+			if !ok {
+				return fmt.Errorf("component %q depends on unknown component %q", component.Name, name)
+			}
+
+			if err := visit(dependency, append(path, component.Name)); err != nil {
+				return err
+			}
+		}
+
+		state[component.Name] = visited
+		ordered = append(ordered, component)
+
+		return nil
+	}
+
+	for _, component := range m.components {
+		if err := visit(component, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return ordered, nil
+}
@@ -0,0 +1,235 @@
+package lifecycle_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/lifecycle"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// journal records the hooks that ran, in order.
+type journal struct {
+	mu      sync.Mutex
+	entries []string
+}
+
+func (j *journal) record(entry string) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	j.entries = append(j.entries, entry)
+}
+
+func (j *journal) list() []string {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	return append([]string(nil), j.entries...)
+}
+
+func (j *journal) component(name string, dependsOn ...string) lifecycle.Component {
+	return lifecycle.Component{
+		Name:      name,
+		DependsOn: dependsOn,
+		Start: func(ctx context.Context) error {
+			j.record("start " + name)
+			return nil
+		},
+		Stop: func(ctx context.Context) error {
+			j.record("stop " + name)
+			return nil
+		},
+	}
+}
+
+func TestManager_StartsInDependencyOrderAndStopsInReverse(t *testing.T) {
+	var j journal
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(
+		j.component("http", "database", "redis"),
+		j.component("database", "telemetry"),
+		j.component("redis", "telemetry"),
+		j.component("telemetry"),
+	)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, manager.Run(ctx))
+	assert.Equal(t, []string{
+		"start telemetry", "start database", "start redis", "start http",
+		"stop http", "stop redis", "stop database", "stop telemetry",
+	}, j.list())
+}
+
+func TestManager_RunsUntilContextIsDone(t *testing.T) {
+	var j journal
+
+	worker := j.component("worker")
+	worker.Run = func(ctx context.Context) error {
+		j.record("run worker")
+		<-ctx.Done()
+		j.record("worker returned")
+		return ctx.Err()
+	}
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(worker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- manager.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(j.list()) == 2 }, time.Second, time.Millisecond)
+	cancel()
+
+	require.NoError(t, <-done, "a Run canceled at shutdown did not fail")
+	assert.Equal(t, []string{"start worker", "run worker", "stop worker", "worker returned"}, j.list())
+}
+
+func TestManager_FailedRunShutsDown(t *testing.T) {
+	var j journal
+	errListen := errors.New("address already in use")
+
+	server := j.component("http", "database")
+	server.Run = func(ctx context.Context) error { return errListen }
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(j.component("database"), server)
+
+	err := manager.Run(context.Background())
+	require.ErrorIs(t, err, errListen)
+	assert.ErrorContains(t, err, "run http")
+	assert.Equal(t, []string{"start database", "start http", "stop http", "stop database"}, j.list())
+}
+
+func TestManager_RunReturningNilIsDone(t *testing.T) {
+	var j journal
+
+	once := j.component("once")
+	once.Run = func(ctx context.Context) error { return nil }
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(once)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	require.NoError(t, manager.Run(ctx))
+	assert.ErrorIs(t, ctx.Err(), context.DeadlineExceeded, "the manager kept running until ctx was done")
+}
+
+func TestManager_FailedStartStopsStartedComponents(t *testing.T) {
+	var j journal
+	errMigrate := errors.New("migration failed")
+
+	migrations := j.component("migrations", "database")
+	migrations.Start = func(ctx context.Context) error { return errMigrate }
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(j.component("database"), migrations, j.component("http", "migrations"))
+
+	err := manager.Run(context.Background())
+	require.ErrorIs(t, err, errMigrate)
+	assert.ErrorContains(t, err, "start migrations")
+	assert.Equal(t, []string{"start database", "stop database"}, j.list())
+}
+
+func TestManager_ReportsStopErrorsAndKeepsStopping(t *testing.T) {
+	var j journal
+	errClose := errors.New("close failed")
+
+	database := j.component("database")
+	database.Stop = func(ctx context.Context) error { return errClose }
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(j.component("telemetry"), database, j.component("http", "database", "telemetry"))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	err := manager.Run(ctx)
+	require.ErrorIs(t, err, errClose)
+	assert.ErrorContains(t, err, "stop database")
+	assert.Equal(t, []string{"start telemetry", "start database", "start http", "stop http", "stop telemetry"}, j.list())
+}
+
+func TestManager_ShutdownDeadline(t *testing.T) {
+	var j journal
+
+	stuck := j.component("stuck")
+	stuck.Run = func(ctx context.Context) error {
+		time.Sleep(time.Second)
+		return nil
+	}
+
+	manager := lifecycle.New(lifecycle.Config{ShutdownTimeout: 20 * time.Millisecond})
+	manager.Register(j.component("database"), stuck)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	startedAt := time.Now()
+	err := manager.Run(ctx)
+
+	require.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, "stop stuck")
+	assert.Less(t, time.Since(startedAt), 500*time.Millisecond)
+	assert.Contains(t, j.list(), "stop database", "the next components still stop")
+}
+
+func TestManager_InvalidDependencies(t *testing.T) {
+	testCases := []struct {
+		name       string
+		components []lifecycle.Component
+		wantErr    string
+	}{
+		{
+			name:       "unknown dependency",
+			components: []lifecycle.Component{{Name: "http", DependsOn: []string{"database"}}},
+			wantErr:    `component "http" depends on unknown component "database"`,
+		},
+		{
+			name: "cycle",
+			components: []lifecycle.Component{
+				{Name: "a", DependsOn: []string{"b"}},
+				{Name: "b", DependsOn: []string{"a"}},
+			},
+			wantErr: "dependency cycle: [a b a]",
+		},
+		{
+			name:       "duplicated name",
+			components: []lifecycle.Component{{Name: "a"}, {Name: "a"}},
+			wantErr:    `component "a" registered twice`,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			manager := lifecycle.New(lifecycle.Config{})
+			manager.Register(tc.components...)
+
+			assert.EqualError(t, manager.Run(context.Background()), tc.wantErr)
+		})
+	}
+}
+
+func TestManager_OnShutdownRunsBeforeComponentsStop(t *testing.T) {
+	var j journal
+
+	manager := lifecycle.New(lifecycle.Config{})
+	manager.Register(j.component("database"), j.component("worker", "database"))
+	manager.OnShutdown(func() { j.record("shutdown") })
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	require.NoError(t, manager.Run(ctx))
+	assert.Equal(t, []string{"start database", "start worker", "shutdown", "stop worker", "stop database"}, j.list())
+}
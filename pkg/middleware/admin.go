@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"context"
+	"errors"
+	"slices"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+)
+
+// ErrNotAdmin is returned to the users missing from the admins.
+var ErrNotAdmin = errors.New("only admins can access this resource")
+
+// RequireAdmin rejects the users missing from ids. It runs after JWTMiddleware.
+func RequireAdmin(ids []int64) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			if !IsAdmin(c.Request().Context(), ids) {
+				return response.ErrorBuilder(response.Forbidden(ErrNotAdmin)).Send(c)
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// IsAdmin reports whether the user authenticated in ctx is one of ids.
+func IsAdmin(ctx context.Context, ids []int64) bool {
+	claims, ok := ctx.Value(types.CredentialDataContextKey).(*jwt.JWTClaims)
+
+	return ok && claims.Data != nil && slices.Contains(ids, claims.Data.ID)
+}
@@ -0,0 +1,41 @@
+package middleware_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+)
+
+func TestRequireAdmin(t *testing.T) {
+	m, token := newMiddleware(t)
+
+	tests := []struct {
+		name   string
+		admins []int64
+		want   int
+	}{
+		{name: "admin", admins: []int64{1}, want: http.StatusOK},
+		{name: "not an admin", admins: []int64{2}, want: http.StatusForbidden},
+		{name: "no admins", want: http.StatusForbidden},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := echo.New()
+			e.GET("/", func(c echo.Context) error { return c.NoContent(http.StatusOK) }, m.JWTMiddleware(), middleware.RequireAdmin(tt.admins))
+
+			req := httptest.NewRequest(http.MethodGet, "/", nil)
+			req.Header.Set(types.AuthorizationHeaderKey.String(), "Bearer "+token)
+			rec := httptest.NewRecorder()
+			e.ServeHTTP(rec, req)
+
+			if rec.Code != tt.want {
+				t.Fatalf("status = %d, want %d", rec.Code, tt.want)
+			}
+		})
+	}
+}
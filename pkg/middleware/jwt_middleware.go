@@ -6,6 +6,7 @@ import (
 
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
 	"github.com/labstack/echo/v4"
 )
@@ -33,6 +34,11 @@ func (m *Middleware) JWTMiddleware() echo.MiddlewareFunc {
 			tokenString = strings.TrimPrefix(tokenString, "Bearer ")
 
 			claims, err := m.jwtFactory.VerifyJWT(c.Request().Context(), tokenString)
+			if errors.Is(err, app_error.ErrTokenCheckUnavailable) {
+				// Fail-closed while the revoked tokens cannot be checked, the token may be valid.
+				return response.ErrorBuilder(err).Send(c)
+			}
+
 			if err != nil {
 				return response.ErrorBuilder(response.Unauthorized(ErrInvalidAuthenticationCredentials)).Send(c)
 			}
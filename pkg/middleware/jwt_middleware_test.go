@@ -22,14 +22,17 @@ func newMiddleware(t *testing.T) (*middleware.Middleware, string) {
 	}
 	defer mr.Close()
 
-	cfg := redis.RedisConfig{Addr: mr.Addr()}
+	redisClient, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	if err != nil {
+		t.Fatalf("NewClient() error = %v", err)
+	}
 
 	jwtFactory := jwt.NewJWTFactory(
 		jwt.JWTConfig{
 			Key:             "secret-key",
 			ExpiredInSecond: 3600,
 		},
-		redis.NewRedisManager(redis.NewRedisClient(t.Context(), cfg)),
+		redis.NewRedisManager(redisClient),
 	)
 
 	token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{
@@ -1,6 +1,8 @@
 package middleware
 
 import (
+	"context"
+
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
 	"github.com/labstack/echo/v4"
@@ -16,4 +18,7 @@ type embedClaimedDataIntoContextOpts struct {
 func embedClaimedDataIntoContext(c echo.Context, opts embedClaimedDataIntoContextOpts) {
 	// Store the token claims in the request context for later use
 	c.Set(types.CredentialDataContextKey.String(), opts.claimedData)
+
+	// Usecases only get the context of the request, e.g. to target feature flags.
+	c.SetRequest(c.Request().WithContext(context.WithValue(c.Request().Context(), types.CredentialDataContextKey, opts.claimedData)))
 }
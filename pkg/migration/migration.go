@@ -0,0 +1,116 @@
+// Package migration applies the SQL migrations embedded in the binary with goose.
+//
+// Migrations run under a database advisory lock, so replicas that start at the
+// same time with auto-migrate enabled wait for each other instead of applying
+// the same migration twice.
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/datasources"
+	"github.com/pressly/goose/v3"
+	"github.com/pressly/goose/v3/lock"
+)
+
+var ErrSchemaBehind = errors.New("database schema is behind the binary")
+
+// Migrator applies the migrations of fsys to a database.
+type Migrator struct {
+	provider *goose.Provider
+}
+
+// Result describes a migration that was applied or rolled back.
+type Result = goose.MigrationResult
+
+// Status describes a migration and whether it is applied.
+type Status = goose.MigrationStatus
+
+// New creates a Migrator for a database of the given datasources driver.
+func New(db *sql.DB, driver string, fsys fs.FS, cfg Config) (*Migrator, error) {
+	cfg = cfg.withDefaults()
+
+	switch driver {
+	case "", datasources.DriverMySQL:
+		return newMigrator(db, goose.DialectMySQL, fsys, newMySQLSessionLocker(cfg.LockTimeout))
+	case datasources.DriverPostgres:
+		locker, err := lock.NewPostgresSessionLocker(lock.WithLockTimeout(1, uint64(max(cfg.LockTimeout.Seconds(), 1))))
+		if err != nil {
+			return nil, err
+		}
+
+		return newMigrator(db, goose.DialectPostgres, fsys, locker)
+	default:
+		return nil, fmt.Errorf("%w: %q", datasources.ErrUnsupportedDriver, driver)
+	}
+}
+
+func newMigrator(db *sql.DB, dialect goose.Dialect, fsys fs.FS, locker lock.SessionLocker) (*Migrator, error) {
+	opts := []goose.ProviderOption{goose.WithDisableGlobalRegistry(true)}
+	if locker != nil {
+		opts = append(opts, goose.WithSessionLocker(locker))
+	}
+
+	provider, err := goose.NewProvider(dialect, db, fsys, opts...)
+	if err != nil {
+		return nil, fmt.Errorf("migration: %w", err)
+	}
+
+	return &Migrator{provider: provider}, nil
+}
+
+// Up applies all pending migrations.
+func (m *Migrator) Up(ctx context.Context) ([]*Result, error) {
+	return m.provider.Up(ctx)
+}
+
+// Down rolls back the latest applied migration.
+func (m *Migrator) Down(ctx context.Context) (*Result, error) {
+	return m.provider.Down(ctx)
+}
+
+// Redo rolls back the latest applied migration and applies it again.
+func (m *Migrator) Redo(ctx context.Context) ([]*Result, error) {
+	down, err := m.provider.Down(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	up, err := m.provider.UpByOne(ctx)
+	if err != nil {
+		return []*Result{down}, err
+	}
+
+	return []*Result{down, up}, nil
+}
+
+// Status returns every known migration with its state.
+func (m *Migrator) Status(ctx context.Context) ([]*Status, error) {
+	return m.provider.Status(ctx)
+}
+
+// Versions returns the schema version of the database and the latest version
+// known to the binary.
+func (m *Migrator) Versions(ctx context.Context) (current, latest int64, err error) {
+	return m.provider.GetVersions(ctx)
+}
+
+// Check returns ErrSchemaBehind when the database misses migrations the binary
+// expects. A database ahead of the binary, e.g. during a rollback of the
+// deployment, is accepted.
+func (m *Migrator) Check(ctx context.Context) error {
+	current, latest, err := m.Versions(ctx)
+	if err != nil {
+		return fmt.Errorf("migration: read schema version: %w", err)
+	}
+
+	if current < latest {
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaBehind, current, latest)
+	}
+
+	return nil
+}
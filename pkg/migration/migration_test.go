@@ -0,0 +1,101 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+
+	"github.com/pressly/goose/v3"
+	"github.com/stretchr/testify/require"
+	_ "modernc.org/sqlite"
+)
+
+var testMigrations = fstest.MapFS{
+	"1_create_users.sql": {Data: []byte(`-- +goose Up
+CREATE TABLE users (id INTEGER PRIMARY KEY, name TEXT NOT NULL);
+
+-- +goose Down
+DROP TABLE users;
+`)},
+	"2_add_users_status.sql": {Data: []byte(`-- +goose Up
+ALTER TABLE users ADD COLUMN status TEXT NOT NULL DEFAULT 'PENDING';
+
+-- +goose Down
+ALTER TABLE users DROP COLUMN status;
+`)},
+}
+
+func newTestMigrator(t *testing.T) (*Migrator, *sql.DB) {
+	t.Helper()
+
+	db, err := sql.Open("sqlite", filepath.Join(t.TempDir(), "test.db"))
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	migrator, err := newMigrator(db, goose.DialectSQLite3, testMigrations, nil)
+	require.NoError(t, err)
+
+	return migrator, db
+}
+
+func TestMigrator_UpAndCheck(t *testing.T) {
+	ctx := context.Background()
+	migrator, db := newTestMigrator(t)
+
+	require.Error(t, migrator.Check(ctx), "an unmigrated database is not ready")
+
+	results, err := migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+
+	current, latest, err := migrator.Versions(ctx)
+	require.NoError(t, err)
+	require.EqualValues(t, 2, current)
+	require.EqualValues(t, 2, latest)
+	require.NoError(t, migrator.Check(ctx))
+
+	_, err = db.ExecContext(ctx, "INSERT INTO users (id, name) VALUES (1, 'John')")
+	require.NoError(t, err)
+
+	// Running Up again is a no-op.
+	results, err = migrator.Up(ctx)
+	require.NoError(t, err)
+	require.Empty(t, results)
+}
+
+func TestMigrator_CheckSchemaBehind(t *testing.T) {
+	ctx := context.Background()
+	migrator, _ := newTestMigrator(t)
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	_, err = migrator.Down(ctx)
+	require.NoError(t, err)
+
+	require.ErrorIs(t, migrator.Check(ctx), ErrSchemaBehind)
+}
+
+func TestMigrator_RedoAndStatus(t *testing.T) {
+	ctx := context.Background()
+	migrator, _ := newTestMigrator(t)
+
+	_, err := migrator.Up(ctx)
+	require.NoError(t, err)
+
+	results, err := migrator.Redo(ctx)
+	require.NoError(t, err)
+	require.Len(t, results, 2)
+	require.EqualValues(t, 2, results[0].Source.Version)
+	require.Equal(t, "down", results[0].Direction)
+	require.Equal(t, "up", results[1].Direction)
+
+	statuses, err := migrator.Status(ctx)
+	require.NoError(t, err)
+	require.Len(t, statuses, 2)
+	for _, status := range statuses {
+		require.Equal(t, goose.StateApplied, status.State)
+	}
+}
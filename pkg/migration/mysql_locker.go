@@ -0,0 +1,56 @@
+package migration
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/pressly/goose/v3/lock"
+)
+
+// mysqlLockName names the advisory lock held while migrating. GET_LOCK locks are
+// server wide, so every replica migrating the same server uses the same lock.
+const mysqlLockName = "goose_migration"
+
+var ErrLockTimeout = errors.New("timed out waiting for the migration lock")
+
+// mysqlSessionLocker serializes migrations with the MySQL GET_LOCK advisory lock,
+// which is held by the connection goose migrates with.
+type mysqlSessionLocker struct {
+	timeout time.Duration
+}
+
+var _ lock.SessionLocker = (*mysqlSessionLocker)(nil)
+
+func newMySQLSessionLocker(timeout time.Duration) *mysqlSessionLocker {
+	return &mysqlSessionLocker{timeout: timeout}
+}
+
+func (l *mysqlSessionLocker) SessionLock(ctx context.Context, conn *sql.Conn) error {
+	// GET_LOCK returns 1 once the lock is acquired, 0 on timeout and NULL on error.
+	var acquired sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT GET_LOCK(?, ?)", mysqlLockName, int(l.timeout.Seconds())).Scan(&acquired); err != nil {
+		return fmt.Errorf("migration: acquire lock: %w", err)
+	}
+
+	if acquired.Int64 != 1 {
+		return fmt.Errorf("migration: %w after %s", ErrLockTimeout, l.timeout)
+	}
+
+	return nil
+}
+
+func (l *mysqlSessionLocker) SessionUnlock(ctx context.Context, conn *sql.Conn) error {
+	var released sql.NullInt64
+	if err := conn.QueryRowContext(ctx, "SELECT RELEASE_LOCK(?)", mysqlLockName).Scan(&released); err != nil {
+		return fmt.Errorf("migration: release lock: %w", err)
+	}
+
+	if released.Int64 != 1 {
+		return fmt.Errorf("migration: lock %q was not held by this session", mysqlLockName)
+	}
+
+	return nil
+}
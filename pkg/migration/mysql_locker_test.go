@@ -0,0 +1,37 @@
+package migration
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMySQLSessionLocker(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer db.Close()
+
+	conn, err := db.Conn(context.Background())
+	require.NoError(t, err)
+	defer conn.Close()
+
+	locker := newMySQLSessionLocker(30 * time.Second)
+
+	mock.ExpectQuery(`SELECT GET_LOCK\(\?, \?\)`).WithArgs(mysqlLockName, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	require.NoError(t, locker.SessionLock(context.Background(), conn))
+
+	mock.ExpectQuery(`SELECT RELEASE_LOCK\(\?\)`).WithArgs(mysqlLockName).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(1))
+	require.NoError(t, locker.SessionUnlock(context.Background(), conn))
+
+	// Another replica holds the lock for longer than the timeout.
+	mock.ExpectQuery(`SELECT GET_LOCK\(\?, \?\)`).WithArgs(mysqlLockName, 30).
+		WillReturnRows(sqlmock.NewRows([]string{"lock"}).AddRow(0))
+	require.ErrorIs(t, locker.SessionLock(context.Background(), conn), ErrLockTimeout)
+
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,30 @@
+package migration
+
+import (
+	"time"
+
+	"github.com/invopop/validation"
+)
+
+// Config controls how the service applies its migrations.
+type Config struct {
+	// AutoMigrate applies the pending migrations when the server starts.
+	AutoMigrate bool
+	// LockTimeout is how long to wait for another replica that is migrating the
+	// same database. Defaults to 5 minutes.
+	LockTimeout time.Duration
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.LockTimeout, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.LockTimeout <= 0 {
+		c.LockTimeout = 5 * time.Minute
+	}
+
+	return c
+}
@@ -0,0 +1,65 @@
+package notify
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultDeliveriesLimit is the number of delivery attempts listed without a limit.
+const defaultDeliveriesLimit = 50
+
+type adminHandlers struct {
+	notifier *Notifier
+	admins   []int64
+}
+
+// NewAdminHandlers creates the handlers of the admin API, which lists the
+// delivery log of notifier on behalf of the users of admins.
+func NewAdminHandlers(notifier *Notifier, admins []int64) *adminHandlers {
+	return &adminHandlers{notifier: notifier, admins: admins}
+}
+
+func (h *adminHandlers) MapRoutes(api *echo.Group, mw *middleware.Middleware) {
+	admin := api.Group("/admin/notifications", mw.JWTMiddleware(), middleware.RequireAdmin(h.admins))
+
+	admin.GET("/deliveries", h.DeliveriesHandler)
+}
+
+// @Summary		Notification Deliveries
+// @Description	List the delivery attempts of the notifications, the last first
+// @ID			notification-deliveries
+// @Tags		Notifications
+// @Produce		json
+// @Param		limit	query		int									false	"Limit, 50 by default"
+// @Success		200		{object}	response.Success{data=[]notify.Delivery}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse				"BAD_REQUEST"
+// @Router		/admin/notifications/deliveries [get]
+// @Security	BearerToken
+func (h *adminHandlers) DeliveriesHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "NotificationDeliveriesHandler")
+	defer span.End()
+
+	limit := defaultDeliveriesLimit
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			return response.ErrorBuilder(response.BadRequest(errors.New("limit must be a positive number"))).Send(c)
+		}
+	}
+
+	deliveries, err := h.notifier.Deliveries(ctx, limit)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	if deliveries == nil {
+		deliveries = []Delivery{}
+	}
+
+	return response.SuccessBuilder(deliveries).Send(c)
+}
@@ -0,0 +1,63 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandlers(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	deliveries := NewRedisDeliveryLog(client, 0)
+	notifier := NewNotifier(Config{}, DefaultTemplates(), Senders{}, deliveries, nil, nil)
+
+	jwtFactory := jwt.NewJWTFactory(jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600}, appRedis.NewRedisManager(client))
+	e := echo.New()
+	NewAdminHandlers(notifier, []int64{1}).MapRoutes(e.Group("/api/v1"), middleware.New(jwtFactory))
+
+	do := func(path string, userID int64) *httptest.ResponseRecorder {
+		token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: userID, ContactType: types.CONTACT_TYPE_EMAIL}})
+		require.NoError(t, err)
+
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/notifications"+path, nil)
+		req.Header.Set(types.AuthorizationHeaderKey.String(), "Bearer "+token)
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	for i := range 3 {
+		require.NoError(t, deliveries.Record(context.Background(), Delivery{ID: "1", UserID: 7, Template: TemplateSecurityAlert, Attempt: i + 1, Status: StatusSent, At: time.Now()}))
+	}
+
+	assert.Equal(t, http.StatusForbidden, do("/deliveries", 2).Code, "only admins read the delivery log")
+	assert.Equal(t, http.StatusBadRequest, do("/deliveries?limit=0", 1).Code)
+
+	rec := do("/deliveries?limit=2", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var body struct {
+		Data []Delivery `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &body))
+	require.Len(t, body.Data, 2)
+	assert.Equal(t, 3, body.Data[0].Attempt, "the last attempt first")
+}
@@ -0,0 +1,90 @@
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/go-redis/redis/v8"
+)
+
+// Status is the outcome of a delivery attempt.
+type Status string
+
+const (
+	StatusSent   Status = "sent"
+	StatusFailed Status = "failed"
+)
+
+// Delivery is an attempt to deliver a notification. It leaves out the
+// recipient address and the data of the message, which may be secret.
+type Delivery struct {
+	ID       string             `json:"id"` // The ID of the notification, shared by its attempts.
+	UserID   int64              `json:"user_id"`
+	Template Template           `json:"template"`
+	Channel  types.CONTACT_TYPE `json:"channel"`
+	Language types.LANGUAGE     `json:"language"`
+	Attempt  int                `json:"attempt"`
+	Status   Status             `json:"status"`
+	Error    string             `json:"error,omitempty"`
+	At       time.Time          `json:"at"`
+}
+
+// DeliveryLog records the delivery attempts.
+type DeliveryLog interface {
+	Record(ctx context.Context, delivery Delivery) error
+	// List returns the last limit attempts, the last first.
+	List(ctx context.Context, limit int) ([]Delivery, error)
+}
+
+// redisDeliveryLog keeps the last attempts in a Redis list.
+type redisDeliveryLog struct {
+	client redis.UniversalClient
+	size   int
+	key    string
+}
+
+// NewRedisDeliveryLog keeps the last size attempts, 1000 when zero.
+func NewRedisDeliveryLog(client redis.UniversalClient, size int) DeliveryLog {
+	if size <= 0 {
+		size = 1000
+	}
+
+	return &redisDeliveryLog{client: client, size: size, key: appRedis.REDIS_PREFIX_KEY_NOTIFY.Key("deliveries")}
+}
+
+func (l *redisDeliveryLog) Record(ctx context.Context, delivery Delivery) error {
+	raw, err := json.Marshal(delivery)
+	if err != nil {
+		return err
+	}
+
+	_, err = l.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, l.key, raw)
+		pipe.LTrim(ctx, l.key, 0, int64(l.size-1))
+		return nil
+	})
+
+	return err
+}
+
+func (l *redisDeliveryLog) List(ctx context.Context, limit int) ([]Delivery, error) {
+	values, err := l.client.LRange(ctx, l.key, 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	deliveries := make([]Delivery, 0, len(values))
+	for _, value := range values {
+		var delivery Delivery
+		if err := json.Unmarshal([]byte(value), &delivery); err != nil {
+			return nil, err
+		}
+
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
@@ -0,0 +1,264 @@
+// Package notify sends notifications such as verification codes, password
+// resets and security alerts to users, by email or text message depending on
+// their contact type, in their language.
+//
+// Notifications are rendered when sent, so that missing data fails right
+// away, then delivered by a background job: a failed delivery is retried with
+// the backoff of the jobs queue, and every attempt is recorded in the delivery
+// log.
+//
+//	err := notifier.Send(ctx, notify.Notification{
+//	    Recipient: notify.Recipient{UserID: user.ID, ContactType: user.ContactType, ContactValue: user.ContactValue, Language: user.Language},
+//	    Template:  notify.TemplateVerificationCode,
+//	    Data:      map[string]string{"Name": user.Name, "Code": code, "ExpiresIn": "5 minutes"},
+//	})
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+)
+
+// Drivers of the email and SMS senders.
+const (
+	DriverConsole = "console"
+	DriverFile    = "file"
+	DriverSMTP    = "smtp"
+)
+
+// DefaultQueue is the jobs queue delivering the notifications.
+const DefaultQueue = "notifications"
+
+// sendJobType is the job type delivering a notification.
+const sendJobType = "notify.send"
+
+// ErrNoSender is returned for a channel without a sender.
+var ErrNoSender = errors.New("notify: no sender for the channel")
+
+// Config holds the configuration of the notifications.
+type Config struct {
+	Enable          bool   // Sends the security alerts of the users.
+	Queue           string // The jobs queue delivering the notifications. Defaults to notifications.
+	DeliveryLogSize int    // The number of delivery attempts kept. Defaults to 1000.
+	Email           EmailConfig
+	SMS             SMSConfig
+}
+
+// EmailConfig holds the configuration of the email sender.
+type EmailConfig struct {
+	Driver string // smtp, console (default) or file.
+	From   string // The sender address, e.g. "Example <no-reply@example.com>".
+	File   string // The file the file driver appends to.
+	SMTP   SMTPConfig
+}
+
+// SMSConfig holds the configuration of the SMS sender. Providers plug in
+// as an SMSSender.
+type SMSConfig struct {
+	Driver string // console (default) or file.
+	File   string // The file the file driver appends to.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.DeliveryLogSize, validation.Min(0)),
+		validation.Field(&c.Email),
+		validation.Field(&c.SMS),
+	)
+}
+
+func (c EmailConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Driver, validation.In("", DriverConsole, DriverFile, DriverSMTP).Error("must be smtp, console or file")),
+		validation.Field(&c.From, validation.When(c.Driver == DriverSMTP, validation.Required)),
+		validation.Field(&c.File, validation.When(c.Driver == DriverFile, validation.Required)),
+		validation.Field(&c.SMTP, validation.Skip.When(c.Driver != DriverSMTP)),
+	)
+}
+
+func (c SMTPConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Host, validation.Required),
+		validation.Field(&c.Port, validation.Min(0), validation.Max(65535)),
+		validation.Field(&c.Timeout, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c SMSConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Driver, validation.In("", DriverConsole, DriverFile).Error("must be console or file")),
+		validation.Field(&c.File, validation.When(c.Driver == DriverFile, validation.Required)),
+	)
+}
+
+// Senders deliver the notifications of every channel.
+type Senders struct {
+	Email EmailSender
+	SMS   SMSSender
+}
+
+// NewSenders creates the senders of the configured drivers.
+func NewSenders(cfg Config) (Senders, error) {
+	var senders Senders
+
+	switch cfg.Email.Driver {
+	case DriverSMTP:
+		senders.Email = NewSMTPSender(cfg.Email.SMTP, cfg.Email.From)
+	case DriverFile:
+		sender, err := NewFileSender(cfg.Email.File)
+		if err != nil {
+			return Senders{}, err
+		}
+
+		senders.Email = sender
+	default:
+		senders.Email = NewConsoleSender()
+	}
+
+	switch cfg.SMS.Driver {
+	case DriverFile:
+		sender, err := NewFileSender(cfg.SMS.File)
+		if err != nil {
+			return Senders{}, err
+		}
+
+		senders.SMS = sender
+	default:
+		senders.SMS = NewConsoleSender()
+	}
+
+	return senders, nil
+}
+
+// Recipient is the user a notification is sent to.
+type Recipient struct {
+	UserID       int64              `json:"user_id"`
+	ContactType  types.CONTACT_TYPE `json:"contact_type"`
+	ContactValue string             `json:"contact_value"`
+	// Language defaults to DefaultLanguage when nil.
+	Language *types.LANGUAGE `json:"language,omitempty"`
+}
+
+// Notification is a message rendered from Template with Data for Recipient.
+type Notification struct {
+	Recipient Recipient         `json:"recipient"`
+	Template  Template          `json:"template"`
+	Data      map[string]string `json:"data"`
+}
+
+func (n Notification) language() types.LANGUAGE {
+	if n.Recipient.Language == nil {
+		return DefaultLanguage
+	}
+
+	return *n.Recipient.Language
+}
+
+// Notifier sends the notifications through the jobs queue.
+type Notifier struct {
+	templates  *Templates
+	senders    Senders
+	deliveries DeliveryLog
+	client     *jobs.Client
+	queue      string
+	log        *zerolog.Logger
+}
+
+// NewNotifier creates a notifier enqueuing the notifications with client and
+// delivering them with senders. A nil deliveries skips the delivery log.
+func NewNotifier(cfg Config, templates *Templates, senders Senders, deliveries DeliveryLog, client *jobs.Client, log *zerolog.Logger) *Notifier {
+	if cfg.Queue == "" {
+		cfg.Queue = DefaultQueue
+	}
+
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	return &Notifier{templates: templates, senders: senders, deliveries: deliveries, client: client, queue: cfg.Queue, log: log}
+}
+
+// Register makes worker deliver the notifications.
+func (n *Notifier) Register(worker *jobs.Worker) {
+	worker.HandleFunc(n.queue, sendJobType, n.deliver)
+}
+
+// Send renders notification to check it, then enqueues its delivery.
+func (n *Notifier) Send(ctx context.Context, notification Notification, opts ...jobs.Option) error {
+	if _, err := n.templates.Render(notification.Template, notification.language(), notification.Recipient.ContactType, notification.Data); err != nil {
+		return err
+	}
+
+	_, err := n.client.Enqueue(ctx, n.queue, sendJobType, notification, opts...)
+
+	return err
+}
+
+// deliver renders and sends the notification of job, and records the attempt.
+// A notification that cannot be rendered or has no sender is not retried.
+func (n *Notifier) deliver(ctx context.Context, job jobs.Job) error {
+	var notification Notification
+	if err := json.Unmarshal(job.Payload, &notification); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode notification: %w", err))
+	}
+
+	err := n.send(ctx, notification)
+
+	delivery := Delivery{
+		ID:       job.ID,
+		UserID:   notification.Recipient.UserID,
+		Template: notification.Template,
+		Channel:  notification.Recipient.ContactType,
+		Language: notification.language(),
+		Attempt:  job.Attempt + 1,
+		Status:   StatusSent,
+		At:       time.Now(),
+	}
+	if err != nil {
+		delivery.Status, delivery.Error = StatusFailed, err.Error()
+	}
+
+	if n.deliveries != nil {
+		if err := n.deliveries.Record(ctx, delivery); err != nil {
+			n.log.Err(err).Ctx(ctx).Str("id", job.ID).Msg("[Notify]DeliveryLog")
+		}
+	}
+
+	return err
+}
+
+func (n *Notifier) send(ctx context.Context, notification Notification) error {
+	channel := notification.Recipient.ContactType
+
+	msg, err := n.templates.Render(notification.Template, notification.language(), channel, notification.Data)
+	if err != nil {
+		return jobs.Permanent(err)
+	}
+
+	switch {
+	case channel == types.CONTACT_TYPE_EMAIL && n.senders.Email != nil:
+		return n.senders.Email.SendEmail(ctx, Email{To: notification.Recipient.ContactValue, Subject: msg.Subject, Body: msg.Body})
+	case channel == types.CONTACT_TYPE_PHONE && n.senders.SMS != nil:
+		return n.senders.SMS.SendSMS(ctx, SMS{To: notification.Recipient.ContactValue, Body: msg.Body})
+	default:
+		return jobs.Permanent(fmt.Errorf("%w %s", ErrNoSender, channel))
+	}
+}
+
+// Deliveries returns the last limit delivery attempts, the last first.
+func (n *Notifier) Deliveries(ctx context.Context, limit int) ([]Delivery, error) {
+	if n.deliveries == nil {
+		return nil, nil
+	}
+
+	return n.deliveries.List(ctx, limit)
+}
@@ -0,0 +1,141 @@
+package notify
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// flakySMS fails the first failures text messages.
+type flakySMS struct {
+	*MemorySender
+	failures atomic.Int32
+}
+
+func (s *flakySMS) SendSMS(ctx context.Context, sms SMS) error {
+	if s.failures.Add(-1) >= 0 {
+		return errors.New("provider unavailable")
+	}
+
+	return s.MemorySender.SendSMS(ctx, sms)
+}
+
+type testNotifier struct {
+	*Notifier
+	emails *MemorySender
+	sms    *flakySMS
+}
+
+func newTestNotifier(t *testing.T) *testNotifier {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	backend := jobs.NewMemoryBackend()
+	worker := jobs.NewWorker(jobs.Config{
+		PollInterval: 5 * time.Millisecond,
+		Queues:       []jobs.QueueConfig{{Name: DefaultQueue, MaxAttempts: 3, RetryBackoff: time.Millisecond, MaxRetryBackoff: time.Millisecond}},
+	}, backend, nil)
+
+	n := &testNotifier{emails: NewMemorySender(), sms: &flakySMS{MemorySender: NewMemorySender()}}
+	n.Notifier = NewNotifier(Config{}, DefaultTemplates(), Senders{Email: n.emails, SMS: n.sms}, NewRedisDeliveryLog(client, 0), jobs.NewClient(backend), nil)
+	n.Register(worker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = worker.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return n
+}
+
+func TestNotifier_SendsInTheLanguageAndChannelOfTheUser(t *testing.T) {
+	n := newTestNotifier(t)
+	ctx := context.Background()
+
+	indonesian := types.LANGUAGE_ID
+	require.NoError(t, n.Send(ctx, Notification{
+		Recipient: Recipient{UserID: 1, ContactType: types.CONTACT_TYPE_EMAIL, ContactValue: "budi@example.com", Language: &indonesian},
+		Template:  TemplateVerificationCode,
+		Data:      map[string]string{"Name": "Budi", "Code": "123456", "ExpiresIn": "5 menit"},
+	}))
+	require.NoError(t, n.Send(ctx, Notification{
+		Recipient: Recipient{UserID: 2, ContactType: types.CONTACT_TYPE_PHONE, ContactValue: "+628123456789"},
+		Template:  TemplateSecurityAlert,
+		Data:      map[string]string{"Name": "John", "Event": "new_login", "Time": "09:00"},
+	}))
+
+	require.Eventually(t, func() bool { return len(n.emails.Emails()) == 1 && len(n.sms.SMS()) == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	email := n.emails.Emails()[0]
+	assert.Equal(t, "budi@example.com", email.To)
+	assert.Equal(t, "Kode verifikasi Anda", email.Subject)
+	assert.Contains(t, email.Body, "123456")
+
+	sms := n.sms.SMS()[0]
+	assert.Equal(t, "+628123456789", sms.To)
+	assert.Contains(t, sms.Body, "Security alert: Your account was signed in from a new device", "English without a language")
+}
+
+func TestNotifier_RetriesAndLogsDeliveries(t *testing.T) {
+	n := newTestNotifier(t)
+	n.sms.failures.Store(2)
+
+	require.NoError(t, n.Send(context.Background(), Notification{
+		Recipient: Recipient{UserID: 2, ContactType: types.CONTACT_TYPE_PHONE, ContactValue: "+628123456789"},
+		Template:  TemplatePasswordReset,
+		Data:      map[string]string{"Name": "John", "Link": "https://example.com/reset", "ExpiresIn": "1 hour"},
+	}))
+
+	require.Eventually(t, func() bool { return len(n.sms.SMS()) == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	var deliveries []Delivery
+	require.Eventually(t, func() bool {
+		var err error
+		deliveries, err = n.Deliveries(context.Background(), 10)
+		return err == nil && len(deliveries) == 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, StatusSent, deliveries[0].Status)
+	assert.Equal(t, 3, deliveries[0].Attempt)
+	assert.Equal(t, StatusFailed, deliveries[2].Status)
+	assert.Equal(t, "provider unavailable", deliveries[2].Error)
+	assert.Equal(t, deliveries[0].ID, deliveries[2].ID, "the attempts share the ID of the notification")
+	assert.Equal(t, int64(2), deliveries[0].UserID)
+}
+
+func TestNotifier_Send_RejectsInvalidNotifications(t *testing.T) {
+	n := newTestNotifier(t)
+
+	err := n.Send(context.Background(), Notification{
+		Recipient: Recipient{ContactType: types.CONTACT_TYPE_EMAIL, ContactValue: "john@example.com"},
+		Template:  TemplatePasswordReset,
+		Data:      map[string]string{"Name": "John"},
+	})
+	assert.Error(t, err, "missing data fails before the notification is enqueued")
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{}.Validate())
+	assert.NoError(t, Config{Email: EmailConfig{Driver: DriverSMTP, From: "no-reply@example.com", SMTP: SMTPConfig{Host: "smtp.example.com"}}}.Validate())
+	assert.Error(t, Config{Email: EmailConfig{Driver: DriverSMTP, From: "no-reply@example.com"}}.Validate(), "the SMTP host is required")
+	assert.Error(t, Config{Email: EmailConfig{Driver: DriverFile}}.Validate(), "the file is required")
+	assert.Error(t, Config{SMS: SMSConfig{Driver: DriverSMTP}}.Validate())
+}
@@ -0,0 +1,117 @@
+package notify
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"slices"
+	"sync"
+)
+
+// Email is an email to send.
+type Email struct {
+	To      string
+	Subject string
+	Body    string // Plain text.
+}
+
+// SMS is a text message to send.
+type SMS struct {
+	To   string // Phone number in international format.
+	Body string
+}
+
+// EmailSender delivers emails, e.g. through SMTP.
+type EmailSender interface {
+	SendEmail(ctx context.Context, email Email) error
+}
+
+// SMSSender delivers text messages, through an SMS provider.
+type SMSSender interface {
+	SendSMS(ctx context.Context, sms SMS) error
+}
+
+// WriterSender writes the emails and text messages to a writer instead of
+// delivering them, for local development.
+type WriterSender struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewConsoleSender writes the messages to the standard output.
+func NewConsoleSender() *WriterSender {
+	return &WriterSender{w: os.Stdout}
+}
+
+// NewFileSender appends the messages to the file at path.
+func NewFileSender(path string) (*WriterSender, error) {
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return nil, fmt.Errorf("notify: open %s: %w", path, err)
+	}
+
+	return &WriterSender{w: file}, nil
+}
+
+func (s *WriterSender) SendEmail(ctx context.Context, email Email) error {
+	return s.write("--- EMAIL to %s\nSubject: %s\n\n%s\n\n", email.To, email.Subject, email.Body)
+}
+
+func (s *WriterSender) SendSMS(ctx context.Context, sms SMS) error {
+	return s.write("--- SMS to %s\n%s\n\n", sms.To, sms.Body)
+}
+
+func (s *WriterSender) write(format string, args ...any) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	_, err := fmt.Fprintf(s.w, format, args...)
+
+	return err
+}
+
+// MemorySender captures the emails and text messages, for tests.
+type MemorySender struct {
+	mu     sync.Mutex
+	emails []Email
+	sms    []SMS
+}
+
+func NewMemorySender() *MemorySender {
+	return &MemorySender{}
+}
+
+func (s *MemorySender) SendEmail(ctx context.Context, email Email) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.emails = append(s.emails, email)
+
+	return nil
+}
+
+func (s *MemorySender) SendSMS(ctx context.Context, sms SMS) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.sms = append(s.sms, sms)
+
+	return nil
+}
+
+// Emails returns the emails sent so far.
+func (s *MemorySender) Emails() []Email {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.emails)
+}
+
+// SMS returns the text messages sent so far.
+func (s *MemorySender) SMS() []SMS {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return slices.Clone(s.sms)
+}
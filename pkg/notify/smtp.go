@@ -0,0 +1,146 @@
+package notify
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"mime"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTPConfig holds the settings of the SMTP server sending the emails.
+type SMTPConfig struct {
+	Host string
+	Port int // Defaults to 587.
+	// Username and Password authenticate with PLAIN, only over TLS or to localhost.
+	Username string
+	Password string `secret:"true"`
+	// ImplicitTLS connects with TLS from the start, usually on port 465.
+	// Otherwise the connection is upgraded with STARTTLS when the server offers it.
+	ImplicitTLS bool
+	Timeout     time.Duration // Bounds sending an email. Defaults to 10 seconds.
+}
+
+// SMTPSender sends the emails through an SMTP server.
+type SMTPSender struct {
+	cfg  SMTPConfig
+	from string
+}
+
+// NewSMTPSender sends the emails from the address from through the server of cfg.
+func NewSMTPSender(cfg SMTPConfig, from string) *SMTPSender {
+	if cfg.Port == 0 {
+		cfg.Port = 587
+	}
+
+	if cfg.Timeout <= 0 {
+		cfg.Timeout = 10 * time.Second
+	}
+
+	return &SMTPSender{cfg: cfg, from: from}
+}
+
+func (s *SMTPSender) SendEmail(ctx context.Context, email Email) error {
+	msg, err := s.message(email)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, s.cfg.Timeout)
+	defer cancel()
+
+	conn, err := s.dial(ctx)
+	if err != nil {
+		return err
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		if err := conn.SetDeadline(deadline); err != nil {
+			return err
+		}
+	}
+
+	client, err := smtp.NewClient(conn, s.cfg.Host)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok && !s.cfg.ImplicitTLS {
+		if err := client.StartTLS(&tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}); err != nil {
+			return err
+		}
+	}
+
+	if s.cfg.Username != "" {
+		if err := client.Auth(smtp.PlainAuth("", s.cfg.Username, s.cfg.Password, s.cfg.Host)); err != nil {
+			return err
+		}
+	}
+
+	if err := client.Mail(s.from); err != nil {
+		return err
+	}
+
+	if err := client.Rcpt(email.To); err != nil {
+		return err
+	}
+
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+
+	if err := w.Close(); err != nil {
+		return err
+	}
+
+	return client.Quit()
+}
+
+func (s *SMTPSender) dial(ctx context.Context) (net.Conn, error) {
+	addr := net.JoinHostPort(s.cfg.Host, strconv.Itoa(s.cfg.Port))
+
+	if s.cfg.ImplicitTLS {
+		dialer := &tls.Dialer{Config: &tls.Config{ServerName: s.cfg.Host, MinVersion: tls.VersionTLS12}}
+		return dialer.DialContext(ctx, "tcp", addr)
+	}
+
+	var dialer net.Dialer
+
+	return dialer.DialContext(ctx, "tcp", addr)
+}
+
+// message formats email as a plain text MIME message.
+func (s *SMTPSender) message(email Email) ([]byte, error) {
+	// Line breaks in a header would inject other headers.
+	for _, header := range []string{s.from, email.To, email.Subject} {
+		if strings.ContainsAny(header, "\r\n") {
+			return nil, errors.New("notify: line break in an email header")
+		}
+	}
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "From: %s\r\n", s.from)
+	fmt.Fprintf(&buf, "To: %s\r\n", email.To)
+	fmt.Fprintf(&buf, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", email.Subject))
+	fmt.Fprintf(&buf, "Date: %s\r\n", time.Now().Format(time.RFC1123Z))
+	buf.WriteString("MIME-Version: 1.0\r\n")
+	buf.WriteString("Content-Type: text/plain; charset=utf-8\r\n")
+	buf.WriteString("Content-Transfer-Encoding: 8bit\r\n\r\n")
+	buf.WriteString(strings.ReplaceAll(strings.ReplaceAll(email.Body, "\r\n", "\n"), "\n", "\r\n"))
+	buf.WriteString("\r\n")
+
+	return buf.Bytes(), nil
+}
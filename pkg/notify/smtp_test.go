@@ -0,0 +1,27 @@
+package notify
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSMTPSender_Message(t *testing.T) {
+	sender := NewSMTPSender(SMTPConfig{Host: "smtp.example.com"}, "Example <no-reply@example.com>")
+
+	msg, err := sender.message(Email{To: "john@example.com", Subject: "Kode verifikasi Anda ✓", Body: "Halo John,\n\nKode 123456."})
+	require.NoError(t, err)
+
+	headers, body, ok := strings.Cut(string(msg), "\r\n\r\n")
+	require.True(t, ok)
+	assert.Contains(t, headers, "From: Example <no-reply@example.com>\r\n")
+	assert.Contains(t, headers, "To: john@example.com\r\n")
+	assert.Contains(t, headers, "Subject: =?utf-8?q?", "non-ASCII subjects are encoded")
+	assert.Contains(t, headers, "Content-Type: text/plain; charset=utf-8")
+	assert.Equal(t, "Halo John,\r\n\r\nKode 123456.\r\n", body)
+
+	_, err = sender.message(Email{To: "john@example.com\r\nBcc: eve@example.com", Subject: "Hi"})
+	assert.Error(t, err, "headers cannot be injected")
+}
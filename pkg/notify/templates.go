@@ -0,0 +1,124 @@
+package notify
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"strings"
+	"text/template"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+)
+
+// Template names a message sent to users.
+type Template string
+
+const (
+	// TemplateVerificationCode takes Name, Code and ExpiresIn.
+	TemplateVerificationCode Template = "verification_code"
+	// TemplatePasswordReset takes Name, Link and ExpiresIn.
+	TemplatePasswordReset Template = "password_reset"
+	// TemplateSecurityAlert takes Name, Time and Event, password_changed or new_login.
+	TemplateSecurityAlert Template = "security_alert"
+)
+
+// DefaultLanguage renders the messages of the users without a language, or
+// with a language the template is missing in.
+const DefaultLanguage = types.LANGUAGE_EN
+
+// ErrUnknownTemplate is returned when rendering a template that does not exist.
+var ErrUnknownTemplate = errors.New("notify: unknown template")
+
+//go:embed templates/*.tmpl
+var embedded embed.FS
+
+// Templates renders the messages in the language of their recipient. Every
+// template is a file <template>.<language>.tmpl defining "subject" and "email"
+// for emails, and "sms" for text messages. Data missing from a message is an
+// error rather than an empty string.
+type Templates struct {
+	templates map[string]*template.Template
+}
+
+// DefaultTemplates returns the templates embedded in the binary.
+func DefaultTemplates() *Templates {
+	templates, err := LoadTemplates(embedded)
+	if err != nil {
+		panic(err)
+	}
+
+	return templates
+}
+
+// LoadTemplates parses the *.tmpl files of fsys, in any directory.
+func LoadTemplates(fsys fs.FS) (*Templates, error) {
+	files, err := fs.Glob(fsys, "*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	nested, err := fs.Glob(fsys, "*/*.tmpl")
+	if err != nil {
+		return nil, err
+	}
+
+	t := &Templates{templates: make(map[string]*template.Template)}
+	for _, file := range append(files, nested...) {
+		name := strings.TrimSuffix(path.Base(file), ".tmpl")
+		if path.Ext(name) == "" {
+			return nil, fmt.Errorf("notify: template %s is not named <template>.<language>.tmpl", file)
+		}
+
+		parsed, err := template.New(name).Option("missingkey=error").ParseFS(fsys, file)
+		if err != nil {
+			return nil, err
+		}
+
+		t.templates[strings.ToLower(name)] = parsed
+	}
+
+	return t, nil
+}
+
+// Message is a rendered notification.
+type Message struct {
+	Subject string // Empty for text messages.
+	Body    string
+}
+
+// Render renders the template name for channel in language, or in
+// DefaultLanguage when it is missing in language.
+func (t *Templates) Render(name Template, language types.LANGUAGE, channel types.CONTACT_TYPE, data map[string]string) (Message, error) {
+	tmpl, ok := t.templates[strings.ToLower(string(name)+"."+string(language))]
+	if !ok {
+		if tmpl, ok = t.templates[strings.ToLower(string(name)+"."+string(DefaultLanguage))]; !ok {
+			return Message{}, fmt.Errorf("%w: %s", ErrUnknownTemplate, name)
+		}
+	}
+
+	if channel == types.CONTACT_TYPE_PHONE {
+		body, err := execute(tmpl, "sms", data)
+		return Message{Body: body}, err
+	}
+
+	subject, err := execute(tmpl, "subject", data)
+	if err != nil {
+		return Message{}, err
+	}
+
+	body, err := execute(tmpl, "email", data)
+
+	return Message{Subject: subject, Body: body}, err
+}
+
+func execute(tmpl *template.Template, name string, data map[string]string) (string, error) {
+	var buf bytes.Buffer
+	if err := tmpl.ExecuteTemplate(&buf, name, data); err != nil {
+		return "", fmt.Errorf("notify: render %s: %w", tmpl.Name(), err)
+	}
+
+	return strings.TrimSpace(buf.String()), nil
+}
@@ -0,0 +1,69 @@
+package notify
+
+import (
+	"testing"
+	"testing/fstest"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDefaultTemplates_RenderEveryLanguage(t *testing.T) {
+	templates := DefaultTemplates()
+
+	data := map[Template]map[string]string{
+		TemplateVerificationCode: {"Name": "John", "Code": "123456", "ExpiresIn": "5 minutes"},
+		TemplatePasswordReset:    {"Name": "John", "Link": "https://example.com/reset?token=abc", "ExpiresIn": "1 hour"},
+		TemplateSecurityAlert:    {"Name": "John", "Event": "password_changed", "Time": "2026-01-01 09:00 WIB"},
+	}
+
+	for name, values := range data {
+		for _, language := range []types.LANGUAGE{types.LANGUAGE_EN, types.LANGUAGE_ID} {
+			email, err := templates.Render(name, language, types.CONTACT_TYPE_EMAIL, values)
+			require.NoError(t, err, "%s in %s", name, language)
+			assert.NotEmpty(t, email.Subject)
+			assert.NotEmpty(t, email.Body)
+
+			sms, err := templates.Render(name, language, types.CONTACT_TYPE_PHONE, values)
+			require.NoError(t, err, "%s in %s", name, language)
+			assert.Empty(t, sms.Subject)
+			assert.NotContains(t, sms.Body, "\n", "a text message fits on a line")
+		}
+	}
+}
+
+func TestTemplates_Render(t *testing.T) {
+	templates := DefaultTemplates()
+	data := map[string]string{"Name": "Budi", "Code": "123456", "ExpiresIn": "5 menit"}
+
+	msg, err := templates.Render(TemplateVerificationCode, types.LANGUAGE_ID, types.CONTACT_TYPE_EMAIL, data)
+	require.NoError(t, err)
+	assert.Equal(t, "Kode verifikasi Anda", msg.Subject)
+	assert.Contains(t, msg.Body, "Halo Budi,")
+	assert.Contains(t, msg.Body, "123456")
+
+	msg, err = templates.Render(TemplateVerificationCode, "FR", types.CONTACT_TYPE_PHONE, data)
+	require.NoError(t, err)
+	assert.Contains(t, msg.Body, "Your verification code is 123456", "a missing language falls back to English")
+
+	_, err = templates.Render(TemplateVerificationCode, types.LANGUAGE_EN, types.CONTACT_TYPE_EMAIL, map[string]string{"Name": "John"})
+	assert.ErrorContains(t, err, "Code", "missing data is an error")
+
+	_, err = templates.Render("welcome", types.LANGUAGE_EN, types.CONTACT_TYPE_EMAIL, nil)
+	assert.ErrorIs(t, err, ErrUnknownTemplate)
+}
+
+func TestLoadTemplates(t *testing.T) {
+	templates, err := LoadTemplates(fstest.MapFS{
+		"welcome.en.tmpl": {Data: []byte(`{{define "subject"}}Welcome{{end}}{{define "email"}}Hi {{.Name}}{{end}}`)},
+	})
+	require.NoError(t, err)
+
+	msg, err := templates.Render("welcome", types.LANGUAGE_ID, types.CONTACT_TYPE_EMAIL, map[string]string{"Name": "John"})
+	require.NoError(t, err)
+	assert.Equal(t, Message{Subject: "Welcome", Body: "Hi John"}, msg)
+
+	_, err = LoadTemplates(fstest.MapFS{"welcome.tmpl": {Data: []byte(`{{define "sms"}}Hi{{end}}`)}})
+	assert.ErrorContains(t, err, "<template>.<language>.tmpl")
+}
@@ -0,0 +1,67 @@
+package observability
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+// exportState records the outcome of the last export of each signal.
+type exportState struct {
+	mu   sync.Mutex
+	errs map[string]error
+}
+
+var exports = &exportState{errs: make(map[string]error)}
+
+func (s *exportState) record(signal string, err error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.errs[signal] = err
+}
+
+// CheckExporters reports the error of the last failed export of spans or
+// metrics, until an export of the same signal succeeds again. It reads the
+// state left by the batch exports and does not export anything itself, so it
+// can back a readiness probe.
+func CheckExporters(ctx context.Context) error {
+	exports.mu.Lock()
+	defer exports.mu.Unlock()
+
+	for _, signal := range []string{"traces", "metrics"} {
+		if err := exports.errs[signal]; err != nil {
+			return fmt.Errorf("export %s: %w", signal, err)
+		}
+	}
+
+	return nil
+}
+
+// spanExporter records the outcome of each export of the wrapped exporter.
+type spanExporter struct {
+	sdktrace.SpanExporter
+}
+
+func (e spanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	err := e.SpanExporter.ExportSpans(ctx, spans)
+	exports.record("traces", err)
+
+	return err
+}
+
+// metricExporter records the outcome of each export of the wrapped exporter.
+type metricExporter struct {
+	sdkmetric.Exporter
+}
+
+func (e metricExporter) Export(ctx context.Context, metrics *metricdata.ResourceMetrics) error {
+	err := e.Exporter.Export(ctx, metrics)
+	exports.record("metrics", err)
+
+	return err
+}
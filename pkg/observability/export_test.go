@@ -0,0 +1,33 @@
+package observability
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+)
+
+type failingSpanExporter struct {
+	sdktrace.SpanExporter
+	err error
+}
+
+func (e *failingSpanExporter) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	return e.err
+}
+
+func TestCheckExporters(t *testing.T) {
+	collector := &failingSpanExporter{err: errors.New("collector unavailable")}
+	exporter := spanExporter{collector}
+
+	require.NoError(t, CheckExporters(context.Background()), "nothing exported yet")
+
+	require.Error(t, exporter.ExportSpans(context.Background(), nil))
+	require.ErrorIs(t, CheckExporters(context.Background()), collector.err)
+
+	collector.err = nil
+	require.NoError(t, exporter.ExportSpans(context.Background(), nil))
+	require.NoError(t, CheckExporters(context.Background()), "a successful export clears the error")
+}
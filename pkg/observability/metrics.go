@@ -56,7 +56,7 @@ func InitMeterProvider(observability config.ObservabilityConfig, app config.AppC
 
 	// Create and configure the MeterProvider with a periodic reader for exporting metrics.
 	mp := sdkmetric.NewMeterProvider(
-		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(exporter, sdkmetric.WithInterval(2*time.Second))),
+		sdkmetric.WithReader(sdkmetric.NewPeriodicReader(metricExporter{exporter}, sdkmetric.WithInterval(2*time.Second))),
 		sdkmetric.WithResource(initResource(app.Name, app.Version, app.Environment)),
 	)
 
@@ -44,7 +44,7 @@ func InitTracerProvider(observabilityCfg config.ObservabilityConfig, appCfg conf
 	// Configure and set up the tracer provider with the chosen exporter and other necessary configurations.
 	tracerProvider := sdktrace.NewTracerProvider(
 		sdktrace.WithSampler(sdktrace.AlwaysSample()),
-		sdktrace.WithBatcher(exporter),
+		sdktrace.WithBatcher(spanExporter{exporter}),
 		sdktrace.WithResource(initResource(appCfg.Name, appCfg.Version, appCfg.Environment)),
 	)
 
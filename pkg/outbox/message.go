@@ -0,0 +1,92 @@
+// Package outbox implements the transactional outbox pattern.
+//
+// Domain events are written to the outbox table in the same transaction as the
+// state change they describe, so they are recorded if and only if the change is
+// committed. A Relay then reads pending events and hands them to a Publisher with
+// at-least-once delivery, preserving the order of events of the same aggregate.
+package outbox
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Event is a domain event that can be recorded in the outbox. It is serialized
+// to JSON as the message payload.
+type Event interface {
+	AggregateType() string // The kind of entity the event belongs to, e.g. "user".
+	AggregateID() string   // The identifier of the entity, events are ordered per aggregate.
+	EventType() string     // The name of the event, e.g. "user.signed_up".
+}
+
+type Status string
+
+const (
+	StatusPending   Status = "PENDING"
+	StatusPublished Status = "PUBLISHED"
+	StatusDead      Status = "DEAD"
+)
+
+// Message is an event stored in the outbox table.
+type Message struct {
+	ID            int64           `gorm:"column:id;primaryKey;autoIncrement"`
+	AggregateType string          `gorm:"column:aggregate_type"`
+	AggregateID   string          `gorm:"column:aggregate_id"`
+	EventType     string          `gorm:"column:event_type"`
+	Payload       json.RawMessage `gorm:"column:payload"`
+	Status        Status          `gorm:"column:status"`
+	Attempts      int             `gorm:"column:attempts"`
+	LastError     *string         `gorm:"column:last_error"`
+	AvailableAt   time.Time       `gorm:"column:available_at"`
+	CreatedAt     time.Time       `gorm:"column:created_at"`
+	PublishedAt   *time.Time      `gorm:"column:published_at"`
+}
+
+func (Message) TableName() string { return "outbox_events" }
+
+// AggregateKey identifies the aggregate the message belongs to.
+func (m Message) AggregateKey() string { return m.AggregateType + ":" + m.AggregateID }
+
+// NewMessage converts an event into a pending outbox message.
+func NewMessage(event Event) (Message, error) {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return Message{}, fmt.Errorf("outbox: marshal %s: %w", event.EventType(), err)
+	}
+
+	now := time.Now()
+
+	return Message{
+		AggregateType: event.AggregateType(),
+		AggregateID:   event.AggregateID(),
+		EventType:     event.EventType(),
+		Payload:       payload,
+		Status:        StatusPending,
+		AvailableAt:   now,
+		CreatedAt:     now,
+	}, nil
+}
+
+// Add records events in the outbox using db. Pass the transaction that performs the
+// state change so that the events are committed or rolled back together with it.
+func Add(ctx context.Context, db *gorm.DB, events ...Event) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	messages := make([]Message, 0, len(events))
+	for _, event := range events {
+		message, err := NewMessage(event)
+		if err != nil {
+			return err
+		}
+
+		messages = append(messages, message)
+	}
+
+	return db.WithContext(ctx).Create(&messages).Error
+}
@@ -0,0 +1,93 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"sync"
+
+	"github.com/rs/zerolog"
+)
+
+// Publisher delivers outbox messages to other services. Delivery is at least once,
+// so consumers must be idempotent, e.g. by deduplicating on the message ID.
+type Publisher interface {
+	Publish(ctx context.Context, message Message) error
+}
+
+type logPublisher struct {
+	log *zerolog.Logger
+}
+
+// NewLogPublisher creates a Publisher that only logs the messages.
+func NewLogPublisher(log *zerolog.Logger) Publisher {
+	return &logPublisher{log: log}
+}
+
+func (p *logPublisher) Publish(ctx context.Context, message Message) error {
+	p.log.Info().Ctx(ctx).
+		Int64("id", message.ID).
+		Str("aggregate_type", message.AggregateType).
+		Str("aggregate_id", message.AggregateID).
+		Str("event_type", message.EventType).
+		RawJSON("payload", message.Payload).
+		Msg("[OutboxRelay]Published event")
+
+	return nil
+}
+
+type multiPublisher []Publisher
+
+// NewMultiPublisher creates a Publisher publishing every message to each of
+// publishers in order. A message failing on any of them is published again to
+// all of them by the relay, so each still receives it at least once.
+func NewMultiPublisher(publishers ...Publisher) Publisher {
+	return multiPublisher(publishers)
+}
+
+func (p multiPublisher) Publish(ctx context.Context, message Message) error {
+	var errs []error
+	for _, publisher := range p {
+		if err := publisher.Publish(ctx, message); err != nil {
+			errs = append(errs, err)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// MemoryPublisher records published messages in memory. It is meant for tests.
+type MemoryPublisher struct {
+	mu       sync.Mutex
+	messages []Message
+
+	// FailWith, when set, is called before recording a message and its error is
+	// returned instead of publishing.
+	FailWith func(message Message) error
+}
+
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+func (p *MemoryPublisher) Publish(ctx context.Context, message Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.FailWith != nil {
+		if err := p.FailWith(message); err != nil {
+			return err
+		}
+	}
+
+	p.messages = append(p.messages, message)
+
+	return nil
+}
+
+// Messages returns a copy of the published messages in publish order.
+func (p *MemoryPublisher) Messages() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	return append([]Message(nil), p.messages...)
+}
@@ -0,0 +1,191 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+)
+
+// Config holds the configuration of the outbox relay.
+type Config struct {
+	Enable       bool          // Starts the relay together with the server.
+	BatchSize    int           // The number of pending messages read per poll.
+	PollInterval time.Duration // The delay between polls when the outbox is drained.
+	MaxAttempts  int           // The number of failed attempts before a message is dead-lettered.
+	BaseBackoff  time.Duration // The delay before the first retry, doubled on every attempt.
+	MaxBackoff   time.Duration // The upper bound of the retry delay.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.BatchSize, validation.Min(0)),
+		validation.Field(&c.PollInterval, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxAttempts, validation.Min(0)),
+		validation.Field(&c.BaseBackoff, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxBackoff, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.BatchSize <= 0 {
+		c.BatchSize = 100
+	}
+
+	if c.PollInterval <= 0 {
+		c.PollInterval = time.Second
+	}
+
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 10
+	}
+
+	if c.BaseBackoff <= 0 {
+		c.BaseBackoff = time.Second
+	}
+
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 10 * time.Minute
+	}
+
+	return c
+}
+
+// Relay moves pending messages from the outbox to a Publisher.
+//
+// Messages of the same aggregate are published in the order they were recorded:
+// while a message waits for a retry, the later messages of its aggregate are held
+// back. Once a message exhausts MaxAttempts it is moved to the DEAD state and the
+// aggregate is released. A single relay should run at a time, e.g. under a
+// redis.Election, since nothing claims the messages it reads.
+type Relay struct {
+	cfg       Config
+	store     Store
+	publisher Publisher
+	log       *zerolog.Logger
+}
+
+func NewRelay(cfg Config, store Store, publisher Publisher, log *zerolog.Logger) *Relay {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	return &Relay{cfg: cfg.withDefaults(), store: store, publisher: publisher, log: log}
+}
+
+// Run polls the outbox until ctx is done.
+func (r *Relay) Run(ctx context.Context) error {
+	ticker := time.NewTicker(r.cfg.PollInterval)
+	defer ticker.Stop()
+
+	for {
+		published, err := r.ProcessOnce(ctx)
+		if err != nil {
+			r.log.Err(err).Ctx(ctx).Msg("[OutboxRelay]ProcessOnce")
+		}
+
+		// Keep draining while full batches are being published.
+		if err == nil && published == r.cfg.BatchSize {
+			continue
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// ProcessOnce publishes one batch of pending messages and returns the number of
+// messages that were published.
+func (r *Relay) ProcessOnce(ctx context.Context) (int, error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "OutboxRelay.ProcessOnce")
+	defer span.End()
+
+	messages, err := r.store.Pending(ctx, r.cfg.BatchSize)
+	if err != nil {
+		return 0, err
+	}
+
+	var (
+		now       = time.Now()
+		blocked   = make(map[string]bool)
+		published int
+	)
+
+	for _, message := range messages {
+		if ctx.Err() != nil {
+			return published, nil
+		}
+
+		key := message.AggregateKey()
+		if blocked[key] {
+			continue
+		}
+
+		if message.AvailableAt.After(now) {
+			blocked[key] = true
+			continue
+		}
+
+		if err := r.publisher.Publish(ctx, message); err != nil {
+			if r.fail(ctx, message, err) {
+				blocked[key] = true
+			}
+
+			continue
+		}
+
+		if err := r.store.MarkPublished(ctx, message.ID); err != nil {
+			// The message will be published again, which at-least-once allows.
+			return published, err
+		}
+
+		published++
+	}
+
+	return published, nil
+}
+
+// fail records a failed attempt and reports whether the message will be retried.
+func (r *Relay) fail(ctx context.Context, message Message, publishErr error) bool {
+	attempts := message.Attempts + 1
+
+	logEvent := r.log.Warn().Ctx(ctx).
+		Err(publishErr).
+		Int64("id", message.ID).
+		Str("event_type", message.EventType).
+		Int("attempts", attempts)
+
+	if attempts >= r.cfg.MaxAttempts {
+		logEvent.Msg("[OutboxRelay]Moving event to dead letter")
+
+		if err := r.store.MarkDead(ctx, message.ID, attempts, publishErr.Error()); err != nil {
+			r.log.Err(err).Ctx(ctx).Msg("[OutboxRelay]MarkDead")
+		}
+
+		return false
+	}
+
+	availableAt := time.Now().Add(r.backoff(attempts))
+	logEvent.Time("retry_at", availableAt).Msg("[OutboxRelay]Publish failed")
+
+	if err := r.store.MarkRetry(ctx, message.ID, attempts, availableAt, publishErr.Error()); err != nil {
+		r.log.Err(err).Ctx(ctx).Msg("[OutboxRelay]MarkRetry")
+	}
+
+	return true
+}
+
+func (r *Relay) backoff(attempts int) time.Duration {
+	delay := r.cfg.BaseBackoff
+	for i := 1; i < attempts && delay < r.cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+
+	return min(delay, r.cfg.MaxBackoff)
+}
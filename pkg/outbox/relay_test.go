@@ -0,0 +1,273 @@
+package outbox_test
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/stretchr/testify/require"
+)
+
+type userEvent struct {
+	UserID string `json:"user_id"`
+	Type   string `json:"-"`
+}
+
+func (e userEvent) AggregateType() string { return "user" }
+func (e userEvent) AggregateID() string   { return e.UserID }
+func (e userEvent) EventType() string     { return e.Type }
+
+// memoryStore is an in-memory outbox.Store.
+type memoryStore struct {
+	mu       sync.Mutex
+	messages map[int64]*outbox.Message
+	nextID   int64
+}
+
+func newMemoryStore(t *testing.T, events ...outbox.Event) *memoryStore {
+	s := &memoryStore{messages: make(map[int64]*outbox.Message)}
+	for _, event := range events {
+		message, err := outbox.NewMessage(event)
+		require.NoError(t, err)
+
+		s.nextID++
+		message.ID = s.nextID
+		s.messages[message.ID] = &message
+	}
+
+	return s
+}
+
+func (s *memoryStore) Pending(ctx context.Context, limit int) ([]outbox.Message, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	var all []outbox.Message
+	for _, message := range s.messages {
+		if message.Status == outbox.StatusPending {
+			all = append(all, *message)
+		}
+	}
+
+	sort.Slice(all, func(i, j int) bool { return all[i].ID < all[j].ID })
+
+	// Like gormStore, skip the messages backing off and the ones after them.
+	var (
+		now     = time.Now()
+		blocked = make(map[string]bool)
+		pending []outbox.Message
+	)
+	for _, message := range all {
+		if message.AvailableAt.After(now) {
+			blocked[message.AggregateKey()] = true
+		}
+
+		if !blocked[message.AggregateKey()] {
+			pending = append(pending, message)
+		}
+	}
+
+	return pending[:min(limit, len(pending))], nil
+}
+
+func (s *memoryStore) MarkPublished(ctx context.Context, id int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := time.Now()
+	s.messages[id].Status = outbox.StatusPublished
+	s.messages[id].PublishedAt = &now
+
+	return nil
+}
+
+func (s *memoryStore) MarkRetry(ctx context.Context, id int64, attempts int, availableAt time.Time, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[id].Attempts = attempts
+	s.messages[id].AvailableAt = availableAt
+	s.messages[id].LastError = &lastErr
+
+	return nil
+}
+
+func (s *memoryStore) MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.messages[id].Status = outbox.StatusDead
+	s.messages[id].Attempts = attempts
+	s.messages[id].LastError = &lastErr
+
+	return nil
+}
+
+func (s *memoryStore) get(id int64) outbox.Message {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return *s.messages[id]
+}
+
+func eventTypes(messages []outbox.Message) []string {
+	types := make([]string, 0, len(messages))
+	for _, message := range messages {
+		types = append(types, message.AggregateID+"/"+message.EventType)
+	}
+
+	return types
+}
+
+func TestRelay_PublishesInOrder(t *testing.T) {
+	store := newMemoryStore(t,
+		userEvent{UserID: "1", Type: "user.signed_up"},
+		userEvent{UserID: "2", Type: "user.signed_up"},
+		userEvent{UserID: "1", Type: "user.status_changed"},
+	)
+	publisher := outbox.NewMemoryPublisher()
+
+	relay := outbox.NewRelay(outbox.Config{BatchSize: 10}, store, publisher, nil)
+
+	published, err := relay.ProcessOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 3, published)
+
+	require.Equal(t, []string{"1/user.signed_up", "2/user.signed_up", "1/user.status_changed"}, eventTypes(publisher.Messages()))
+	require.JSONEq(t, `{"user_id":"1"}`, string(publisher.Messages()[0].Payload))
+
+	for id := int64(1); id <= 3; id++ {
+		require.Equal(t, outbox.StatusPublished, store.get(id).Status)
+	}
+}
+
+func TestRelay_RetryHoldsBackAggregate(t *testing.T) {
+	store := newMemoryStore(t,
+		userEvent{UserID: "1", Type: "user.signed_up"},
+		userEvent{UserID: "2", Type: "user.signed_up"},
+		userEvent{UserID: "1", Type: "user.status_changed"},
+	)
+
+	failing := true
+	publisher := outbox.NewMemoryPublisher()
+	publisher.FailWith = func(message outbox.Message) error {
+		if failing && message.ID == 1 {
+			return errors.New("broker unavailable")
+		}
+
+		return nil
+	}
+
+	relay := outbox.NewRelay(outbox.Config{BatchSize: 10, BaseBackoff: time.Hour, MaxBackoff: 2 * time.Hour}, store, publisher, nil)
+
+	published, err := relay.ProcessOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+	require.Equal(t, []string{"2/user.signed_up"}, eventTypes(publisher.Messages()))
+
+	first := store.get(1)
+	require.Equal(t, outbox.StatusPending, first.Status)
+	require.Equal(t, 1, first.Attempts)
+	require.True(t, first.AvailableAt.After(time.Now().Add(59*time.Minute)))
+	require.Equal(t, outbox.StatusPending, store.get(3).Status, "later events of the aggregate must wait")
+
+	// The failed message is not retried before its backoff elapses.
+	failing = false
+
+	published, err = relay.ProcessOnce(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, published)
+}
+
+func TestRelay_BackingOffMessagesDoNotFillTheBatch(t *testing.T) {
+	store := newMemoryStore(t,
+		userEvent{UserID: "1", Type: "user.signed_up"},
+		userEvent{UserID: "2", Type: "user.signed_up"},
+		userEvent{UserID: "3", Type: "user.signed_up"},
+		userEvent{UserID: "1", Type: "user.status_changed"},
+		userEvent{UserID: "4", Type: "user.signed_up"},
+	)
+
+	failing := true
+	publisher := outbox.NewMemoryPublisher()
+	publisher.FailWith = func(message outbox.Message) error {
+		if failing && message.ID <= 3 {
+			return errors.New("broker unavailable")
+		}
+
+		return nil
+	}
+
+	relay := outbox.NewRelay(outbox.Config{BatchSize: 2, BaseBackoff: time.Hour, MaxBackoff: 2 * time.Hour}, store, publisher, nil)
+
+	for range 3 {
+		_, err := relay.ProcessOnce(context.Background())
+		require.NoError(t, err)
+	}
+
+	require.Equal(t, []string{"4/user.signed_up"}, eventTypes(publisher.Messages()), "more messages back off than fit in a batch")
+	require.Equal(t, outbox.StatusPending, store.get(4).Status, "later events of the aggregate must wait")
+}
+
+func TestRelay_DeadLetter(t *testing.T) {
+	store := newMemoryStore(t,
+		userEvent{UserID: "1", Type: "user.signed_up"},
+		userEvent{UserID: "1", Type: "user.updated"},
+	)
+
+	publisher := outbox.NewMemoryPublisher()
+	publisher.FailWith = func(message outbox.Message) error {
+		if message.ID == 1 {
+			return errors.New("payload rejected")
+		}
+
+		return nil
+	}
+
+	relay := outbox.NewRelay(outbox.Config{BatchSize: 10, MaxAttempts: 1}, store, publisher, nil)
+
+	published, err := relay.ProcessOnce(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 1, published)
+
+	dead := store.get(1)
+	require.Equal(t, outbox.StatusDead, dead.Status)
+	require.Equal(t, "payload rejected", *dead.LastError)
+	require.Equal(t, []string{"1/user.updated"}, eventTypes(publisher.Messages()))
+}
+
+func TestRelay_Run(t *testing.T) {
+	store := newMemoryStore(t, userEvent{UserID: "1", Type: "user.signed_up"})
+	publisher := outbox.NewMemoryPublisher()
+
+	relay := outbox.NewRelay(outbox.Config{PollInterval: 10 * time.Millisecond}, store, publisher, nil)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- relay.Run(ctx) }()
+
+	require.Eventually(t, func() bool { return len(publisher.Messages()) == 1 }, time.Second, 10*time.Millisecond)
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestMultiPublisher(t *testing.T) {
+	store := newMemoryStore(t, userEvent{UserID: "1", Type: "user.signed_up"})
+
+	first, second := outbox.NewMemoryPublisher(), outbox.NewMemoryPublisher()
+	second.FailWith = func(message outbox.Message) error { return errors.New("webhooks unavailable") }
+
+	relay := outbox.NewRelay(outbox.Config{BatchSize: 10, MaxAttempts: 1}, store, outbox.NewMultiPublisher(first, second), nil)
+
+	published, err := relay.ProcessOnce(context.Background())
+	require.NoError(t, err)
+	require.Zero(t, published)
+
+	require.Len(t, first.Messages(), 1, "a failing publisher must not stop the others")
+	require.Equal(t, "webhooks unavailable", *store.get(1).LastError)
+}
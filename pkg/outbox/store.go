@@ -0,0 +1,70 @@
+package outbox
+
+import (
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// Store gives the relay access to the outbox table.
+type Store interface {
+	// Pending returns up to limit pending messages ordered by ID that are
+	// available, skipping the aggregates whose earlier message waits for a
+	// retry, so that messages backing off cannot fill the batch.
+	Pending(ctx context.Context, limit int) ([]Message, error)
+	MarkPublished(ctx context.Context, id int64) error
+	MarkRetry(ctx context.Context, id int64, attempts int, availableAt time.Time, lastErr string) error
+	MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error
+}
+
+type gormStore struct {
+	db *gorm.DB
+}
+
+// NewGormStore creates a Store backed by the outbox_events table.
+func NewGormStore(db *gorm.DB) Store {
+	return &gormStore{db: db}
+}
+
+func (s *gormStore) Pending(ctx context.Context, limit int) (messages []Message, err error) {
+	now := time.Now()
+
+	// An earlier message of the same aggregate still backing off holds it back.
+	backingOff := s.db.Table("outbox_events AS earlier").
+		Select("1").
+		Where("earlier.status = ? AND earlier.available_at > ?", StatusPending, now).
+		Where("earlier.aggregate_type = outbox_events.aggregate_type AND earlier.aggregate_id = outbox_events.aggregate_id AND earlier.id < outbox_events.id")
+
+	err = s.db.WithContext(ctx).
+		Where("status = ? AND available_at <= ?", StatusPending, now).
+		Where("NOT EXISTS (?)", backingOff).
+		Order("id ASC").
+		Limit(limit).
+		Find(&messages).Error
+
+	return messages, err
+}
+
+func (s *gormStore) MarkPublished(ctx context.Context, id int64) error {
+	return s.db.WithContext(ctx).Model(&Message{}).Where("id = ?", id).Updates(map[string]any{
+		"status":       StatusPublished,
+		"published_at": time.Now(),
+	}).Error
+}
+
+func (s *gormStore) MarkRetry(ctx context.Context, id int64, attempts int, availableAt time.Time, lastErr string) error {
+	return s.db.WithContext(ctx).Model(&Message{}).Where("id = ?", id).Updates(map[string]any{
+		"attempts":     attempts,
+		"available_at": availableAt,
+		"last_error":   lastErr,
+	}).Error
+}
+
+func (s *gormStore) MarkDead(ctx context.Context, id int64, attempts int, lastErr string) error {
+	return s.db.WithContext(ctx).Model(&Message{}).Where("id = ?", id).Updates(map[string]any{
+		"status":     StatusDead,
+		"attempts":   attempts,
+		"last_error": lastErr,
+	}).Error
+}
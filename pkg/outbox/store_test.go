@@ -0,0 +1,34 @@
+package outbox_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/stretchr/testify/require"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func TestGormStore_PendingSkipsMessagesBackingOff(t *testing.T) {
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(
+		gormmysql.New(gormmysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{SkipDefaultTransaction: true, Logger: logger.Default.LogMode(logger.Silent)},
+	)
+	require.NoError(t, err)
+
+	mock.ExpectQuery(`SELECT \* FROM .outbox_events. WHERE \(status = \? AND available_at <= \?\) AND NOT EXISTS \(SELECT 1 FROM outbox_events AS earlier WHERE \(earlier.status = \? AND earlier.available_at > \?\) AND \(earlier.aggregate_type = outbox_events.aggregate_type .*earlier.id < outbox_events.id\)\) ORDER BY id ASC LIMIT \?`).
+		WithArgs(outbox.StatusPending, sqlmock.AnyArg(), outbox.StatusPending, sqlmock.AnyArg(), 10).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "aggregate_type", "aggregate_id", "event_type", "status"}).AddRow(1, "user", "1", "user.signed_up", outbox.StatusPending))
+
+	messages, err := outbox.NewGormStore(db).Pending(context.Background(), 10)
+	require.NoError(t, err)
+	require.Len(t, messages, 1)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
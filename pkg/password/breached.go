@@ -0,0 +1,88 @@
+package password
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+)
+
+// BreachedList reports whether a password is known to have been exposed in a breach.
+type BreachedList interface {
+	IsBreached(password string) bool
+}
+
+// SHA1List is an in-memory, sorted set of SHA-1 password hashes.
+type SHA1List struct {
+	hashes [][sha1.Size]byte
+}
+
+// LoadSHA1List loads a breached-password file from path. See ReadSHA1List for the format.
+func LoadSHA1List(path string) (*SHA1List, error) {
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("open breached password list: %w", err)
+	}
+	defer file.Close()
+
+	return ReadSHA1List(file)
+}
+
+// ReadSHA1List reads one upper or lower case hex SHA-1 hash per line, optionally
+// followed by ":<count>" as in the Have I Been Pwned "ordered by hash" downloads.
+// Blank lines and lines starting with "#" are ignored. The input does not need to
+// be sorted, but sorted input avoids an extra sort on load.
+func ReadSHA1List(r io.Reader) (*SHA1List, error) {
+	list := new(SHA1List)
+
+	scanner := bufio.NewScanner(r)
+	for line := 1; scanner.Scan(); line++ {
+		text := strings.TrimSpace(scanner.Text())
+		if text == "" || strings.HasPrefix(text, "#") {
+			continue
+		}
+
+		hash, _, _ := strings.Cut(text, ":")
+
+		var sum [sha1.Size]byte
+		if n, err := hex.Decode(sum[:], []byte(hash)); err != nil || n != sha1.Size {
+			return nil, fmt.Errorf("breached password list: invalid SHA-1 hash on line %d", line)
+		}
+
+		list.hashes = append(list.hashes, sum)
+	}
+
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("read breached password list: %w", err)
+	}
+
+	isSorted := sort.SliceIsSorted(list.hashes, func(i, j int) bool {
+		return bytes.Compare(list.hashes[i][:], list.hashes[j][:]) < 0
+	})
+	if !isSorted {
+		sort.Slice(list.hashes, func(i, j int) bool {
+			return bytes.Compare(list.hashes[i][:], list.hashes[j][:]) < 0
+		})
+	}
+
+	return list, nil
+}
+
+// Len returns the number of hashes in the list.
+func (l *SHA1List) Len() int { return len(l.hashes) }
+
+// IsBreached reports whether the SHA-1 hash of password is in the list.
+func (l *SHA1List) IsBreached(password string) bool {
+	sum := sha1.Sum([]byte(password))
+
+	i := sort.Search(len(l.hashes), func(i int) bool {
+		return bytes.Compare(l.hashes[i][:], sum[:]) >= 0
+	})
+
+	return i < len(l.hashes) && l.hashes[i] == sum
+}
@@ -0,0 +1,50 @@
+package password_test
+
+import (
+	"crypto/sha1"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
+	"github.com/stretchr/testify/require"
+)
+
+func sha1Hex(text string) string {
+	return strings.ToUpper(fmt.Sprintf("%x", sha1.Sum([]byte(text))))
+}
+
+func TestReadSHA1List(t *testing.T) {
+	content := strings.Join([]string{
+		"# breached passwords",
+		sha1Hex("password123") + ":2254650",
+		"",
+		sha1Hex("letmein") + ":1",
+		strings.ToLower(sha1Hex("123456")),
+	}, "\n")
+
+	list, err := password.ReadSHA1List(strings.NewReader(content))
+	require.NoError(t, err)
+	require.Equal(t, 3, list.Len())
+
+	require.True(t, list.IsBreached("password123"))
+	require.True(t, list.IsBreached("letmein"))
+	require.True(t, list.IsBreached("123456"))
+	require.False(t, list.IsBreached("Str0ng#Pass"))
+}
+
+func TestReadSHA1List_InvalidHash(t *testing.T) {
+	_, err := password.ReadSHA1List(strings.NewReader("not-a-hash:10"))
+	require.ErrorContains(t, err, "line 1")
+}
+
+func TestLoadSHA1List(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "breached.txt")
+	require.NoError(t, os.WriteFile(path, []byte(sha1Hex("qwerty")+":10\n"), 0o600))
+
+	list, err := password.LoadSHA1List(path)
+	require.NoError(t, err)
+	require.True(t, list.IsBreached("qwerty"))
+}
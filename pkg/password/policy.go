@@ -0,0 +1,202 @@
+package password
+
+import (
+	"strings"
+	"unicode"
+
+	"github.com/invopop/validation"
+)
+
+var (
+	ErrTooShort         = validation.NewError("validation_password_too_short", "must be at least {{.min}} characters long")
+	ErrMissingUpper     = validation.NewError("validation_password_missing_upper", "must contain at least one uppercase letter")
+	ErrMissingLower     = validation.NewError("validation_password_missing_lower", "must contain at least one lowercase letter")
+	ErrMissingDigit     = validation.NewError("validation_password_missing_digit", "must contain at least one digit")
+	ErrMissingSymbol    = validation.NewError("validation_password_missing_symbol", "must contain at least one symbol")
+	ErrBannedSubstring  = validation.NewError("validation_password_banned_substring", "must not contain your personal information or commonly used words")
+	ErrRecentlyUsed     = validation.NewError("validation_password_recently_used", "must not match any of your last {{.count}} passwords")
+	ErrBreachedPassword = validation.NewError("validation_password_breached", "has appeared in a data breach, please choose a different one")
+)
+
+// minBannedSubstringLength prevents short personal values (e.g. a two letter name)
+// from rejecting most passwords.
+const minBannedSubstringLength = 3
+
+// PolicyConfig holds the configuration of the password policy.
+type PolicyConfig struct {
+	MinLength        int      // The minimum number of characters.
+	RequireUpper     bool     // Requires at least one uppercase letter.
+	RequireLower     bool     // Requires at least one lowercase letter.
+	RequireDigit     bool     // Requires at least one digit.
+	RequireSymbol    bool     // Requires at least one punctuation or symbol character.
+	BannedSubstrings []string // Case-insensitive words that must not appear in the password.
+	HistorySize      int      // The number of previous passwords that cannot be reused.
+	BreachedListPath string   // Path to a sorted SHA-1 breached-password file, empty to disable.
+}
+
+func (c PolicyConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MinLength, validation.Min(0)),
+		validation.Field(&c.BannedSubstrings, validation.Each(validation.Required)),
+		validation.Field(&c.HistorySize, validation.Min(0)),
+	)
+}
+
+// Policy validates passwords against the configured rules.
+type Policy struct {
+	cfg      PolicyConfig
+	breached BreachedList
+}
+
+// NewPolicy creates a Policy from the configuration. When BreachedListPath is set
+// the breached-password list is loaded from disk.
+func NewPolicy(cfg PolicyConfig) (*Policy, error) {
+	p := &Policy{cfg: cfg}
+
+	if cfg.BreachedListPath != "" {
+		list, err := LoadSHA1List(cfg.BreachedListPath)
+		if err != nil {
+			return nil, err
+		}
+
+		p.breached = list
+	}
+
+	return p, nil
+}
+
+// WithBreachedList replaces the breached-password list used by the policy.
+func (p *Policy) WithBreachedList(list BreachedList) *Policy {
+	p.breached = list
+	return p
+}
+
+// HistorySize returns the number of previous passwords that cannot be reused.
+func (p *Policy) HistorySize() int { return p.cfg.HistorySize }
+
+// validateRequest holds the per-call context used when validating a password.
+type validateRequest struct {
+	personalInfo []string
+	history      []string
+	hashFn       func(string) string
+}
+
+type ValidateOption func(*validateRequest)
+
+// WithPersonalInfo bans values such as the user's name or contact from the password.
+func WithPersonalInfo(values ...string) ValidateOption {
+	return func(r *validateRequest) { r.personalInfo = append(r.personalInfo, values...) }
+}
+
+// WithHistory rejects passwords whose hash, computed by hashFn, matches one of the
+// given hashes.
+func WithHistory(hashes []string, hashFn func(string) string) ValidateOption {
+	return func(r *validateRequest) {
+		r.history = hashes
+		r.hashFn = hashFn
+	}
+}
+
+// Validate checks the password against the policy and returns the first rule
+// that fails as a validation.Error, so it can be reported against a request field.
+func (p *Policy) Validate(password string, opts ...ValidateOption) error {
+	request := new(validateRequest)
+	for _, opt := range opts {
+		opt(request)
+	}
+
+	if len([]rune(password)) < p.cfg.MinLength {
+		return ErrTooShort.SetParams(map[string]any{"min": p.cfg.MinLength})
+	}
+
+	if err := p.validateCharacterClasses(password); err != nil {
+		return err
+	}
+
+	if p.containsBannedSubstring(password, request.personalInfo) {
+		return ErrBannedSubstring
+	}
+
+	if request.hashFn != nil && len(request.history) > 0 {
+		hashed := request.hashFn(password)
+		for _, previous := range request.history {
+			if previous == hashed {
+				return ErrRecentlyUsed.SetParams(map[string]any{"count": p.cfg.HistorySize})
+			}
+		}
+	}
+
+	if p.breached != nil && p.breached.IsBreached(password) {
+		return ErrBreachedPassword
+	}
+
+	return nil
+}
+
+func (p *Policy) validateCharacterClasses(password string) error {
+	var hasUpper, hasLower, hasDigit, hasSymbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsUpper(r):
+			hasUpper = true
+		case unicode.IsLower(r):
+			hasLower = true
+		case unicode.IsDigit(r):
+			hasDigit = true
+		case unicode.IsPunct(r) || unicode.IsSymbol(r):
+			hasSymbol = true
+		}
+	}
+
+	switch {
+	case p.cfg.RequireUpper && !hasUpper:
+		return ErrMissingUpper
+	case p.cfg.RequireLower && !hasLower:
+		return ErrMissingLower
+	case p.cfg.RequireDigit && !hasDigit:
+		return ErrMissingDigit
+	case p.cfg.RequireSymbol && !hasSymbol:
+		return ErrMissingSymbol
+	}
+
+	return nil
+}
+
+func (p *Policy) containsBannedSubstring(password string, personalInfo []string) bool {
+	lowered := strings.ToLower(password)
+
+	banned := append([]string{}, p.cfg.BannedSubstrings...)
+	for _, value := range personalInfo {
+		banned = append(banned, personalTokens(value)...)
+	}
+
+	for _, word := range banned {
+		word = strings.ToLower(strings.TrimSpace(word))
+		if len(word) < minBannedSubstringLength {
+			continue
+		}
+
+		if strings.Contains(lowered, word) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// personalTokens splits a personal value into the parts a user is likely to reuse,
+// e.g. "John Doe" yields "john doe", "john" and "doe", and "john@example.com" also
+// yields the local part "john".
+func personalTokens(value string) []string {
+	tokens := []string{value}
+
+	if local, _, ok := strings.Cut(value, "@"); ok {
+		tokens = append(tokens, local)
+	}
+
+	tokens = append(tokens, strings.FieldsFunc(value, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})...)
+
+	return tokens
+}
@@ -0,0 +1,85 @@
+package password_test
+
+import (
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/password"
+	"github.com/invopop/validation"
+	"github.com/stretchr/testify/require"
+)
+
+type fakeBreachedList map[string]bool
+
+func (f fakeBreachedList) IsBreached(password string) bool { return f[password] }
+
+func hash(text string) string { return fmt.Sprintf("%x", sha256.Sum256([]byte(text))) }
+
+func TestPolicy_Validate(t *testing.T) {
+	policy, err := password.NewPolicy(password.PolicyConfig{
+		MinLength:        8,
+		RequireUpper:     true,
+		RequireLower:     true,
+		RequireDigit:     true,
+		RequireSymbol:    true,
+		BannedSubstrings: []string{"qwerty"},
+		HistorySize:      3,
+	})
+	require.NoError(t, err)
+
+	policy.WithBreachedList(fakeBreachedList{"Breached#2024": true})
+
+	testCases := []struct {
+		name     string
+		password string
+		opts     []password.ValidateOption
+		wantErr  validation.Error
+	}{
+		{name: "valid password", password: "Str0ng#Pass"},
+		{name: "too short", password: "S#0rt", wantErr: password.ErrTooShort},
+		{name: "missing uppercase", password: "str0ng#pass", wantErr: password.ErrMissingUpper},
+		{name: "missing lowercase", password: "STR0NG#PASS", wantErr: password.ErrMissingLower},
+		{name: "missing digit", password: "Strong#Pass", wantErr: password.ErrMissingDigit},
+		{name: "missing symbol", password: "Str0ngPass", wantErr: password.ErrMissingSymbol},
+		{name: "banned substring", password: "Qwerty#123", wantErr: password.ErrBannedSubstring},
+		{
+			name:     "contains name",
+			password: "Martin#2024",
+			opts:     []password.ValidateOption{password.WithPersonalInfo("Martin Yonatan")},
+			wantErr:  password.ErrBannedSubstring,
+		},
+		{
+			name:     "contains email local part",
+			password: "Xx#dowithlogic1",
+			opts:     []password.ValidateOption{password.WithPersonalInfo("dowithlogic@example.com")},
+			wantErr:  password.ErrBannedSubstring,
+		},
+		{
+			name:     "recently used",
+			password: "Str0ng#Pass",
+			opts:     []password.ValidateOption{password.WithHistory([]string{hash("Other#Pass1"), hash("Str0ng#Pass")}, hash)},
+			wantErr:  password.ErrRecentlyUsed,
+		},
+		{name: "breached", password: "Breached#2024", wantErr: password.ErrBreachedPassword},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := policy.Validate(tc.password, tc.opts...)
+			if tc.wantErr == nil {
+				require.NoError(t, err)
+				return
+			}
+
+			var got validation.Error
+			require.ErrorAs(t, err, &got)
+			require.Equal(t, tc.wantErr.Code(), got.Code())
+		})
+	}
+}
+
+func TestNewPolicy_InvalidBreachedListPath(t *testing.T) {
+	_, err := password.NewPolicy(password.PolicyConfig{BreachedListPath: "does-not-exist.txt"})
+	require.Error(t, err)
+}
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"sync/atomic"
+	"time"
+)
+
+// Election elects a single leader among the instances campaigning for the
+// same name, e.g. to run scheduled jobs on one replica only.
+type Election struct {
+	locker *Locker
+	name   string
+	ttl    time.Duration
+	leader atomic.Bool
+}
+
+// NewElection campaigns for name with a leadership lease of ttl (DefaultLockTTL
+// when zero). The leader renews the lease every ttl/3 while it leads, so a
+// crashed leader is replaced within ttl.
+func NewElection(locker *Locker, name string, ttl time.Duration) *Election {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+
+	return &Election{locker: locker, name: name, ttl: ttl}
+}
+
+// IsLeader reports whether this instance currently leads.
+func (e *Election) IsLeader() bool { return e.leader.Load() }
+
+// Run campaigns until ctx is done and runs lead whenever elected. The context
+// of lead is canceled when the leadership is lost or ctx is done, and the
+// leadership is handed over as soon as lead returns, so another instance takes
+// over without waiting for it to expire. Run campaigns again when lead
+// returns nil, and returns the error of lead otherwise.
+func (e *Election) Run(ctx context.Context, lead func(ctx context.Context) error) error {
+	ticker := time.NewTicker(e.ttl / 3)
+	defer ticker.Stop()
+
+	for {
+		if err := e.term(ctx, lead); err != nil {
+			return err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// term leads once when elected.
+func (e *Election) term(ctx context.Context, lead func(ctx context.Context) error) error {
+	lock, err := e.locker.TryLock(ctx, e.name, e.ttl)
+	if err != nil {
+		// Another instance leads or Redis is unavailable, campaign again later.
+		return nil
+	}
+
+	leadCtx, release := lock.Hold(ctx)
+	defer release()
+
+	e.leader.Store(true)
+	defer e.leader.Store(false)
+
+	if err := lead(leadCtx); err != nil && leadCtx.Err() == nil {
+		return err
+	}
+
+	return nil
+}
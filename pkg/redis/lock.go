@@ -0,0 +1,208 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+var (
+	// ErrLockNotAcquired is returned when a lock is held by another owner.
+	ErrLockNotAcquired = errors.New("redis: lock held by another owner")
+	// ErrLockLost is returned when a lock expired or was taken over before its
+	// owner refreshed or released it.
+	ErrLockLost = errors.New("redis: lock lost")
+)
+
+// REDIS_PREFIX_KEY_LOCK prefixes the keys of the locks.
+const REDIS_PREFIX_KEY_LOCK RedisPrefixKey = "lock:%s"
+
+// DefaultLockTTL applies to the locks acquired with a zero TTL.
+const DefaultLockTTL = 30 * time.Second
+
+var (
+	// refreshScript extends the lock of KEYS[1] when ARGV[1] still owns it.
+	refreshScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("PEXPIRE", KEYS[1], ARGV[2])
+end
+return 0
+`)
+
+	// releaseScript deletes the lock of KEYS[1] when ARGV[1] still owns it.
+	releaseScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+)
+
+// Locker acquires locks shared by every instance using the same Redis.
+type Locker struct {
+	client redis.UniversalClient
+}
+
+func NewLocker(client redis.UniversalClient) *Locker {
+	return &Locker{client: client}
+}
+
+// Lock is a lock held until it is released or expires. Every acquisition has
+// its own owner token, so an owner never releases a lock another one took
+// over after it expired.
+type Lock struct {
+	client redis.UniversalClient
+	key    string
+	token  string
+	ttl    time.Duration
+}
+
+// TryLock acquires the lock name for ttl (DefaultLockTTL when zero), or
+// returns ErrLockNotAcquired when another owner holds it.
+func (l *Locker) TryLock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+
+	lock := &Lock{client: l.client, key: REDIS_PREFIX_KEY_LOCK.Key(name), token: uuid.NewString(), ttl: ttl}
+
+	acquired, err := l.client.SetNX(ctx, lock.key, lock.token, ttl).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	if !acquired {
+		return nil, ErrLockNotAcquired
+	}
+
+	return lock, nil
+}
+
+// Lock waits until it acquires the lock name or ctx is done.
+func (l *Locker) Lock(ctx context.Context, name string, ttl time.Duration) (*Lock, error) {
+	ticker := time.NewTicker(retryInterval(ttl))
+	defer ticker.Stop()
+
+	for {
+		lock, err := l.TryLock(ctx, name, ttl)
+		if !errors.Is(err, ErrLockNotAcquired) {
+			return lock, err
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// WithLock runs fn holding the lock name, waiting for it first. The lock is
+// renewed while fn runs and released when it returns. The context of fn is
+// canceled if the lock is lost, and WithLock then returns ErrLockLost.
+func (l *Locker) WithLock(ctx context.Context, name string, ttl time.Duration, fn func(ctx context.Context) error) error {
+	lock, err := l.Lock(ctx, name, ttl)
+	if err != nil {
+		return err
+	}
+
+	holdCtx, release := lock.Hold(ctx)
+	defer release()
+
+	err = fn(holdCtx)
+	if context.Cause(holdCtx) == ErrLockLost && ctx.Err() == nil {
+		return errors.Join(err, ErrLockLost)
+	}
+
+	return err
+}
+
+// Token returns the owner token of the lock.
+func (l *Lock) Token() string { return l.token }
+
+// Refresh extends the lock by its TTL, or returns ErrLockLost.
+func (l *Lock) Refresh(ctx context.Context) error {
+	refreshed, err := refreshScript.Run(ctx, l.client, []string{l.key}, l.token, l.ttl.Milliseconds()).Int64()
+	if err != nil {
+		return err
+	}
+
+	if refreshed == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Release releases the lock, or returns ErrLockLost when it was not held anymore.
+func (l *Lock) Release(ctx context.Context) error {
+	released, err := releaseScript.Run(ctx, l.client, []string{l.key}, l.token).Int64()
+	if err != nil {
+		return err
+	}
+
+	if released == 0 {
+		return ErrLockLost
+	}
+
+	return nil
+}
+
+// Hold refreshes the lock every third of its TTL until release is called,
+// which also releases the lock. The returned context is canceled with the
+// cause ErrLockLost when a refresh finds the lock lost, or when refreshes fail
+// until the lock may have expired.
+func (l *Lock) Hold(ctx context.Context) (context.Context, func()) {
+	holdCtx, cancel := context.WithCancelCause(ctx)
+	done := make(chan struct{})
+
+	go func() {
+		defer close(done)
+
+		ticker := time.NewTicker(l.ttl / 3)
+		defer ticker.Stop()
+
+		// The lock is safe until its TTL passes without a refresh.
+		expiresAt := time.Now().Add(l.ttl)
+
+		for {
+			select {
+			case <-holdCtx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			switch err := l.Refresh(holdCtx); {
+			case err == nil:
+				expiresAt = time.Now().Add(l.ttl)
+			case errors.Is(err, ErrLockLost), time.Now().After(expiresAt):
+				cancel(ErrLockLost)
+				return
+			}
+		}
+	}()
+
+	return holdCtx, func() {
+		cancel(context.Canceled)
+		<-done
+
+		// Release even when ctx is done, the lock would block the other owners
+		// until it expires otherwise.
+		releaseCtx, cancelRelease := context.WithTimeout(context.WithoutCancel(ctx), l.ttl)
+		defer cancelRelease()
+
+		_ = l.Release(releaseCtx)
+	}
+}
+
+// retryInterval is how often a busy lock is tried again.
+func retryInterval(ttl time.Duration) time.Duration {
+	if ttl <= 0 {
+		ttl = DefaultLockTTL
+	}
+
+	return min(max(ttl/10, 50*time.Millisecond), time.Second)
+}
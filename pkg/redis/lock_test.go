@@ -0,0 +1,168 @@
+package redis_test
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newLocker(t *testing.T) (*miniredis.Miniredis, *redis.Locker) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return mr, redis.NewLocker(client)
+}
+
+func TestLocker_TryLock(t *testing.T) {
+	mr, locker := newLocker(t)
+	ctx := t.Context()
+
+	lock, err := locker.TryLock(ctx, "migrations", time.Minute)
+	require.NoError(t, err)
+	mr.CheckGet(t, "lock:migrations", lock.Token())
+	assert.Equal(t, time.Minute, mr.TTL("lock:migrations"))
+
+	_, err = locker.TryLock(ctx, "migrations", time.Minute)
+	assert.ErrorIs(t, err, redis.ErrLockNotAcquired)
+
+	mr.FastForward(30 * time.Second)
+	require.NoError(t, lock.Refresh(ctx))
+	assert.Equal(t, time.Minute, mr.TTL("lock:migrations"))
+
+	require.NoError(t, lock.Release(ctx))
+	assert.False(t, mr.Exists("lock:migrations"))
+	assert.ErrorIs(t, lock.Release(ctx), redis.ErrLockLost)
+}
+
+func TestLocker_ReleaseKeepsTheLockOfAnotherOwner(t *testing.T) {
+	mr, locker := newLocker(t)
+	ctx := t.Context()
+
+	expired, err := locker.TryLock(ctx, "purge", time.Minute)
+	require.NoError(t, err)
+
+	mr.FastForward(time.Minute)
+
+	current, err := locker.TryLock(ctx, "purge", time.Minute)
+	require.NoError(t, err)
+
+	assert.ErrorIs(t, expired.Refresh(ctx), redis.ErrLockLost)
+	assert.ErrorIs(t, expired.Release(ctx), redis.ErrLockLost)
+	mr.CheckGet(t, "lock:purge", current.Token())
+}
+
+func TestLocker_Lock(t *testing.T) {
+	_, locker := newLocker(t)
+
+	held, err := locker.TryLock(t.Context(), "jobs", time.Second)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithTimeout(t.Context(), 100*time.Millisecond)
+	defer cancel()
+
+	_, err = locker.Lock(ctx, "jobs", time.Second)
+	assert.ErrorIs(t, err, context.DeadlineExceeded, "waits until ctx is done")
+
+	go func() {
+		time.Sleep(100 * time.Millisecond)
+		_ = held.Release(context.Background())
+	}()
+
+	lock, err := locker.Lock(t.Context(), "jobs", time.Second)
+	require.NoError(t, err)
+	assert.NotEqual(t, held.Token(), lock.Token())
+}
+
+func TestLocker_WithLock(t *testing.T) {
+	mr, locker := newLocker(t)
+
+	err := locker.WithLock(t.Context(), "jobs", 300*time.Millisecond, func(ctx context.Context) error {
+		// Outlive the TTL, the lock is renewed meanwhile.
+		time.Sleep(500 * time.Millisecond)
+		assert.NoError(t, ctx.Err())
+		assert.True(t, mr.Exists("lock:jobs"))
+
+		return nil
+	})
+	require.NoError(t, err)
+	assert.False(t, mr.Exists("lock:jobs"), "released once fn returns")
+
+	errFailed := errors.New("failed")
+	assert.ErrorIs(t, locker.WithLock(t.Context(), "jobs", time.Second, func(context.Context) error { return errFailed }), errFailed)
+	assert.False(t, mr.Exists("lock:jobs"))
+}
+
+func TestLocker_WithLockCancelsWhenLost(t *testing.T) {
+	mr, locker := newLocker(t)
+
+	err := locker.WithLock(t.Context(), "jobs", 300*time.Millisecond, func(ctx context.Context) error {
+		mr.Del("lock:jobs")
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+			return nil
+		}
+	})
+	assert.ErrorIs(t, err, redis.ErrLockLost)
+}
+
+func TestElection(t *testing.T) {
+	_, locker := newLocker(t)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	var leading atomic.Int32
+	lead := func(ctx context.Context) error {
+		if leading.Add(1) > 1 {
+			t.Error("two instances lead at once")
+		}
+		defer leading.Add(-1)
+
+		<-ctx.Done()
+		return nil
+	}
+
+	first := redis.NewElection(locker, "scheduler", 300*time.Millisecond)
+	second := redis.NewElection(locker, "scheduler", 300*time.Millisecond)
+
+	firstCtx, stopFirst := context.WithCancel(ctx)
+	firstDone := make(chan error, 1)
+	go func() { firstDone <- first.Run(firstCtx, lead) }()
+
+	require.Eventually(t, first.IsLeader, time.Second, 10*time.Millisecond)
+
+	go func() { _ = second.Run(ctx, lead) }()
+
+	time.Sleep(500 * time.Millisecond)
+	assert.True(t, first.IsLeader(), "the leadership is renewed")
+	assert.False(t, second.IsLeader())
+
+	// Stopping the leader hands the leadership over before it expires.
+	stopFirst()
+	require.NoError(t, <-firstDone)
+	assert.False(t, first.IsLeader())
+	assert.Eventually(t, second.IsLeader, 200*time.Millisecond, 10*time.Millisecond)
+}
+
+func TestElection_ReturnsLeadErrors(t *testing.T) {
+	mr, locker := newLocker(t)
+
+	errFailed := errors.New("failed")
+	err := redis.NewElection(locker, "scheduler", time.Second).Run(t.Context(), func(context.Context) error { return errFailed })
+	assert.ErrorIs(t, err, errFailed)
+	assert.False(t, mr.Exists("lock:scheduler"), "the leadership is handed over")
+}
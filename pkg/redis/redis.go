@@ -2,26 +2,267 @@ package redis
 
 import (
 	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"time"
 
 	"github.com/go-redis/redis/v8"
+	"github.com/invopop/validation"
+)
+
+// Modes of the Redis deployment.
+const (
+	ModeStandalone = "standalone"
+	ModeSentinel   = "sentinel"
+	ModeCluster    = "cluster"
 )
 
 type RedisConfig struct {
-	Addr     string // The address of the database.
-	Password string // The password for connecting to the database.
-	DB       int    // The name of the database.
+	// Mode selects the deployment, standalone (default), sentinel or cluster.
+	Mode string
+	// Addr is the address of the server in standalone mode.
+	Addr string
+	// Addrs are the addresses of the sentinels in sentinel mode, and of the seed
+	// nodes in cluster mode.
+	Addrs []string
+	// MasterName is the name of the master monitored by the sentinels.
+	MasterName string
+	// Username and Password authenticate to the servers, with ACLs for Username.
+	Username string
+	Password string `secret:"true"`
+	// SentinelPassword authenticates to the sentinels when they require it.
+	SentinelPassword string `secret:"true"`
+	// DB is the database of the server, not supported in cluster mode.
+	DB int
+
+	TLS TLSConfig
+
+	// PoolSize is the number of connections per node. Defaults to 10 per CPU.
+	PoolSize int
+	// MinIdleConns is the number of idle connections kept open per node.
+	MinIdleConns int
+
+	// DialTimeout bounds opening a connection. Defaults to 5 seconds.
+	DialTimeout time.Duration
+	// ReadTimeout and WriteTimeout bound the commands. Default to 3 seconds.
+	ReadTimeout  time.Duration
+	WriteTimeout time.Duration
+	// PoolTimeout bounds waiting for a connection when all are busy. Defaults to
+	// ReadTimeout plus one second.
+	PoolTimeout time.Duration
+
+	// Retry paces the connection attempts.
+	Retry RetryConfig
+}
+
+// TLSConfig encrypts the connections to Redis.
+type TLSConfig struct {
+	Enable bool
+	// CAFile verifies the servers with the PEM certificates of the file instead
+	// of the system ones.
+	CAFile string
+	// CertFile and KeyFile authenticate the client with a certificate.
+	CertFile string
+	KeyFile  string
+	// ServerName overrides the name the server certificates are verified for.
+	ServerName string
+	// InsecureSkipVerify accepts any server certificate, for tests only.
+	InsecureSkipVerify bool
+}
+
+// RetryForever makes Ping retry until its context is done.
+const RetryForever = -1
+
+// RetryConfig paces the connection attempts.
+type RetryConfig struct {
+	// Attempts is how many pings Connect sends before giving up. Defaults to 5.
+	Attempts int
+	// Backoff is the delay before the first retry, it doubles on every following
+	// retry up to MaxBackoff. Default to 100 milliseconds and 5 seconds.
+	Backoff    time.Duration
+	MaxBackoff time.Duration
+}
+
+func (c RedisConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Mode, validation.In("", ModeStandalone, ModeSentinel, ModeCluster).Error("must be standalone, sentinel or cluster")),
+		validation.Field(&c.Addr, validation.When(c.mode() == ModeStandalone, validation.Required)),
+		validation.Field(&c.Addrs, validation.When(c.mode() != ModeStandalone, validation.Required)),
+		validation.Field(&c.MasterName, validation.When(c.mode() == ModeSentinel, validation.Required)),
+		validation.Field(&c.DB, validation.Min(0), validation.When(c.mode() == ModeCluster, validation.Max(0).Error("must be 0 in cluster mode"))),
+		validation.Field(&c.TLS),
+		validation.Field(&c.PoolSize, validation.Min(0)),
+		validation.Field(&c.MinIdleConns, validation.Min(0)),
+		validation.Field(&c.DialTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.ReadTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.WriteTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.PoolTimeout, validation.Min(time.Duration(0))),
+		validation.Field(&c.Retry),
+	)
+}
+
+func (c TLSConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.CertFile, validation.When(c.KeyFile != "", validation.Required)),
+		validation.Field(&c.KeyFile, validation.When(c.CertFile != "", validation.Required)),
+	)
 }
 
-func NewRedisClient(ctx context.Context, cfg RedisConfig) *redis.Client {
-	client := redis.NewClient(&redis.Options{
-		Addr:     cfg.Addr,
-		Password: cfg.Password,
-		DB:       cfg.DB,
-	})
+func (c RetryConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Attempts, validation.Min(0)),
+		validation.Field(&c.Backoff, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxBackoff, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c RedisConfig) mode() string {
+	if c.Mode == "" {
+		return ModeStandalone
+	}
+
+	return c.Mode
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.Attempts == 0 {
+		c.Attempts = 5
+	}
+
+	if c.Backoff <= 0 {
+		c.Backoff = 100 * time.Millisecond
+	}
+
+	if c.MaxBackoff <= 0 {
+		c.MaxBackoff = 5 * time.Second
+	}
+
+	return c
+}
+
+// backoff returns the delay before the given retry, with up to 50% jitter so
+// that the replicas restarted together do not retry in lockstep.
+func (c RetryConfig) backoff(attempt int) time.Duration {
+	delay := min(c.Backoff<<min(attempt, 32), c.MaxBackoff)
+
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// NewClient creates a client for the configured deployment. It connects
+// lazily, use Ping to wait until Redis answers.
+func NewClient(cfg RedisConfig) (redis.UniversalClient, error) {
+	tlsConfig, err := cfg.TLS.Config()
+	if err != nil {
+		return nil, err
+	}
+
+	opts := &redis.UniversalOptions{
+		Addrs:            cfg.Addrs,
+		DB:               cfg.DB,
+		Username:         cfg.Username,
+		Password:         cfg.Password,
+		SentinelPassword: cfg.SentinelPassword,
+		MasterName:       cfg.MasterName,
+		PoolSize:         cfg.PoolSize,
+		MinIdleConns:     cfg.MinIdleConns,
+		DialTimeout:      cfg.DialTimeout,
+		ReadTimeout:      cfg.ReadTimeout,
+		WriteTimeout:     cfg.WriteTimeout,
+		PoolTimeout:      cfg.PoolTimeout,
+		TLSConfig:        tlsConfig,
+	}
+
+	switch cfg.mode() {
+	case ModeCluster:
+		return redis.NewClusterClient(opts.Cluster()), nil
+	case ModeSentinel:
+		return redis.NewFailoverClient(opts.Failover()), nil
+	default:
+		opts.Addrs = []string{cfg.Addr}
+		return redis.NewClient(opts.Simple()), nil
+	}
+}
+
+// Connect creates a client for the configured deployment and returns an error
+// when Redis does not answer within Retry.Attempts pings.
+func Connect(ctx context.Context, cfg RedisConfig) (redis.UniversalClient, error) {
+	client, err := NewClient(cfg)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := Ping(ctx, client, cfg.Retry, nil); err != nil {
+		client.Close()
+		return nil, err
+	}
+
+	return client, nil
+}
+
+// Ping pings client until Redis answers, waiting the backoff of retry between
+// the attempts. It gives up after retry.Attempts pings, or when ctx is done
+// with RetryForever. onFailure is called with every failed attempt when set.
+func Ping(ctx context.Context, client redis.UniversalClient, retry RetryConfig, onFailure func(attempt int, err error)) error {
+	retry = retry.withDefaults()
+
+	for attempt := 1; ; attempt++ {
+		err := client.Ping(ctx).Err()
+		if err == nil {
+			return nil
+		}
+
+		if onFailure != nil {
+			onFailure(attempt, err)
+		}
+
+		if attempt == retry.Attempts {
+			return fmt.Errorf("redis: no answer after %d attempts: %w", attempt, err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return errors.Join(ctx.Err(), err)
+		case <-time.After(retry.backoff(attempt - 1)):
+		}
+	}
+}
+
+// Config returns the TLS config of the client, nil when TLS is disabled.
+func (c TLSConfig) Config() (*tls.Config, error) {
+	if !c.Enable {
+		return nil, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         c.ServerName,
+		InsecureSkipVerify: c.InsecureSkipVerify,
+	}
+
+	if c.CAFile != "" {
+		pem, err := os.ReadFile(c.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis: read CA file: %w", err)
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("redis: no certificate in CA file %s", c.CAFile)
+		}
+	}
+
+	if c.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(c.CertFile, c.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("redis: load client certificate: %w", err)
+		}
 
-	if err := client.Ping(ctx).Err(); err != nil {
-		panic(err)
+		tlsConfig.Certificates = []tls.Certificate{cert}
 	}
 
-	return client
+	return tlsConfig, nil
 }
@@ -2,61 +2,320 @@ package redis
 
 import (
 	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"sync"
 	"time"
 
 	"github.com/go-redis/redis/v8"
 )
 
+// ErrNotFound is returned when reading a key or a field that does not exist.
+var ErrNotFound = errors.New("redis: not found")
+
 type RedisPrefixKey string
 
 const (
-	REDIS_PREFIX_KEY_CONFIG RedisPrefixKey = "config:%s"
-	REDIS_PREFIX_KEY_TOKEN  RedisPrefixKey = "token:%s"
+	REDIS_PREFIX_KEY_CONFIG    RedisPrefixKey = "config:%s"
+	REDIS_PREFIX_KEY_TOKEN     RedisPrefixKey = "token:%s"
+	REDIS_PREFIX_KEY_CACHE     RedisPrefixKey = "cache:%s"
+	REDIS_PREFIX_KEY_JOBS      RedisPrefixKey = "jobs:%s"
+	REDIS_PREFIX_KEY_SCHEDULER RedisPrefixKey = "scheduler:%s"
+	REDIS_PREFIX_KEY_NOTIFY    RedisPrefixKey = "notify:%s"
+	REDIS_PREFIX_KEY_WEBHOOKS  RedisPrefixKey = "webhooks:%s"
 )
 
 const REDIS_TOKEN_EXPIRATION_TIME = time.Minute * 60
 
+// DefaultExpiration applies to the keys set with a zero expiration.
+const DefaultExpiration = 6 * time.Hour
+
 func (rpk RedisPrefixKey) String() string { return string(rpk) }
 
+// Key returns the key of id under the prefix, e.g. config:feature_flags.
+func (rpk RedisPrefixKey) Key(id string) string { return fmt.Sprintf(string(rpk), id) }
+
+// Pattern returns the pattern matching every key under the prefix.
+func (rpk RedisPrefixKey) Pattern() string { return rpk.Key("*") }
+
+// ZMember is a member of a sorted set.
+type ZMember struct {
+	Member string
+	Score  float64
+}
+
 type RedisManager interface {
 	Set(ctx context.Context, key string, value string, expiration time.Duration) error
+	// SetNX sets key only when it does not exist, and reports whether it did.
+	SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error)
 	Get(ctx context.Context, key string) (data string, err error)
-	Del(ctx context.Context, key string) error
+	// MGet returns the values of the keys that exist.
+	MGet(ctx context.Context, keys ...string) (map[string]string, error)
+	Del(ctx context.Context, keys ...string) error
+	Exists(ctx context.Context, key string) (bool, error)
+	// Incr increments key and returns its value. A key created by the increment
+	// expires after ttl, later increments keep its expiration.
+	Incr(ctx context.Context, key string, ttl time.Duration) (int64, error)
+	// Scan returns every key under prefix, from every master in cluster mode.
+	// It iterates with SCAN, so keys written meanwhile may be missed.
+	Scan(ctx context.Context, prefix RedisPrefixKey) ([]string, error)
+	// Pipeline sends the commands queued by fn in a single round trip.
+	Pipeline(ctx context.Context, fn func(pipe redis.Pipeliner) error) ([]redis.Cmder, error)
+
+	HSet(ctx context.Context, key string, fields map[string]string) error
+	HGet(ctx context.Context, key string, field string) (string, error)
+	HGetAll(ctx context.Context, key string) (map[string]string, error)
+	HDel(ctx context.Context, key string, fields ...string) error
+
+	ZAdd(ctx context.Context, key string, members ...ZMember) error
+	// ZRangeByScore returns the members scored between min and max included,
+	// lowest score first.
+	ZRangeByScore(ctx context.Context, key string, min, max float64) ([]ZMember, error)
+	ZRem(ctx context.Context, key string, members ...string) error
+
 	Close() error
 }
 
 // redisManager is a concrete implementation of RedisClient
 type redisManager struct {
-	client *redis.Client
+	client redis.UniversalClient
 }
 
-func NewRedisManager(client *redis.Client) RedisManager {
+func NewRedisManager(client redis.UniversalClient) RedisManager {
 	return &redisManager{
 		client: client,
 	}
 }
 
 // Set sets a value in Redis with a specified expiration.
-// Duration to 0 will set expired to 21600 (6 hours)
+// A zero expiration applies DefaultExpiration (6 hours).
 func (r *redisManager) Set(ctx context.Context, key string, value string, expiration time.Duration) error {
-	if expiration == 0 {
-		expiration = 21600
-	}
+	return r.client.Set(ctx, key, value, withDefaultExpiration(expiration)).Err()
+}
 
-	return r.client.Set(ctx, key, value, expiration).Err()
+// SetNX sets a value in Redis when key does not exist, with the expiration of Set.
+func (r *redisManager) SetNX(ctx context.Context, key string, value string, expiration time.Duration) (bool, error) {
+	return r.client.SetNX(ctx, key, value, withDefaultExpiration(expiration)).Result()
 }
 
-// Get retrieves a value from Redis by key.
+// Get retrieves a value from Redis by key, or ErrNotFound.
 func (r *redisManager) Get(ctx context.Context, key string) (string, error) {
-	return r.client.Get(ctx, key).Result()
+	return notFound(r.client.Get(ctx, key).Result())
+}
+
+// MGet retrieves the values of keys in a single command.
+func (r *redisManager) MGet(ctx context.Context, keys ...string) (map[string]string, error) {
+	values := make(map[string]string, len(keys))
+	if len(keys) == 0 {
+		return values, nil
+	}
+
+	results, err := r.client.MGet(ctx, keys...).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i, result := range results {
+		if value, ok := result.(string); ok {
+			values[keys[i]] = value
+		}
+	}
+
+	return values, nil
+}
+
+// Del deletes keys from Redis.
+func (r *redisManager) Del(ctx context.Context, keys ...string) error {
+	return r.client.Del(ctx, keys...).Err()
+}
+
+// Exists reports whether key exists.
+func (r *redisManager) Exists(ctx context.Context, key string) (bool, error) {
+	count, err := r.client.Exists(ctx, key).Result()
+
+	return count > 0, err
+}
+
+// incrScript increments a key and sets the expiration of the key it creates,
+// atomically so that a counter never lives without one.
+var incrScript = redis.NewScript(`
+local value = redis.call("INCR", KEYS[1])
+if value == 1 then
+	redis.call("PEXPIRE", KEYS[1], ARGV[1])
+end
+return value
+`)
+
+// Incr increments a counter that expires ttl after its first increment.
+func (r *redisManager) Incr(ctx context.Context, key string, ttl time.Duration) (int64, error) {
+	return incrScript.Run(ctx, r.client, []string{key}, withDefaultExpiration(ttl).Milliseconds()).Int64()
+}
+
+// Scan lists the keys under prefix. In cluster mode every master is scanned,
+// since SCAN only iterates the keys of the node it is sent to.
+func (r *redisManager) Scan(ctx context.Context, prefix RedisPrefixKey) ([]string, error) {
+	cluster, ok := r.client.(*redis.ClusterClient)
+	if !ok {
+		return scan(ctx, r.client, prefix)
+	}
+
+	var (
+		mu   sync.Mutex
+		keys []string
+	)
+
+	err := cluster.ForEachMaster(ctx, func(ctx context.Context, master *redis.Client) error {
+		masterKeys, err := scan(ctx, master, prefix)
+
+		mu.Lock()
+		defer mu.Unlock()
+		keys = append(keys, masterKeys...)
+
+		return err
+	})
+
+	return keys, err
+}
+
+func scan(ctx context.Context, client redis.Cmdable, prefix RedisPrefixKey) ([]string, error) {
+	var keys []string
+
+	iter := client.Scan(ctx, 0, prefix.Pattern(), 100).Iterator()
+	for iter.Next(ctx) {
+		keys = append(keys, iter.Val())
+	}
+
+	return keys, iter.Err()
+}
+
+// Pipeline runs the commands of fn in a pipeline and returns them with their results.
+func (r *redisManager) Pipeline(ctx context.Context, fn func(pipe redis.Pipeliner) error) ([]redis.Cmder, error) {
+	return r.client.Pipelined(ctx, fn)
+}
+
+// HSet sets fields of the hash key.
+func (r *redisManager) HSet(ctx context.Context, key string, fields map[string]string) error {
+	values := make(map[string]any, len(fields))
+	for field, value := range fields {
+		values[field] = value
+	}
+
+	return r.client.HSet(ctx, key, values).Err()
+}
+
+// HGet retrieves a field of the hash key, or ErrNotFound.
+func (r *redisManager) HGet(ctx context.Context, key string, field string) (string, error) {
+	return notFound(r.client.HGet(ctx, key, field).Result())
+}
+
+// HGetAll retrieves the fields of the hash key, empty when it does not exist.
+func (r *redisManager) HGetAll(ctx context.Context, key string) (map[string]string, error) {
+	return r.client.HGetAll(ctx, key).Result()
+}
+
+// HDel deletes fields of the hash key.
+func (r *redisManager) HDel(ctx context.Context, key string, fields ...string) error {
+	return r.client.HDel(ctx, key, fields...).Err()
+}
+
+// ZAdd adds members to the sorted set key, or updates their score.
+func (r *redisManager) ZAdd(ctx context.Context, key string, members ...ZMember) error {
+	zs := make([]*redis.Z, 0, len(members))
+	for _, member := range members {
+		zs = append(zs, &redis.Z{Member: member.Member, Score: member.Score})
+	}
+
+	return r.client.ZAdd(ctx, key, zs...).Err()
 }
 
-// Del deletes a key from Redis.
-func (r *redisManager) Del(ctx context.Context, key string) error {
-	return r.client.Del(ctx, key).Err()
+// ZRangeByScore retrieves the members of the sorted set key between min and max.
+func (r *redisManager) ZRangeByScore(ctx context.Context, key string, min, max float64) ([]ZMember, error) {
+	zs, err := r.client.ZRangeByScoreWithScores(ctx, key, &redis.ZRangeBy{
+		Min: formatScore(min),
+		Max: formatScore(max),
+	}).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	members := make([]ZMember, 0, len(zs))
+	for _, z := range zs {
+		member, _ := z.Member.(string)
+		members = append(members, ZMember{Member: member, Score: z.Score})
+	}
+
+	return members, nil
+}
+
+// ZRem removes members from the sorted set key.
+func (r *redisManager) ZRem(ctx context.Context, key string, members ...string) error {
+	values := make([]any, 0, len(members))
+	for _, member := range members {
+		values = append(values, member)
+	}
+
+	return r.client.ZRem(ctx, key, values...).Err()
 }
 
 // Close closes the connection to the Redis server.
 func (r *redisManager) Close() error {
 	return r.client.Close()
 }
+
+// GetJSON retrieves the JSON value of key into a T, or ErrNotFound.
+func GetJSON[T any](ctx context.Context, m RedisManager, key string) (T, error) {
+	var value T
+
+	raw, err := m.Get(ctx, key)
+	if err != nil {
+		return value, err
+	}
+
+	if err := json.Unmarshal([]byte(raw), &value); err != nil {
+		return value, fmt.Errorf("decode %s: %w", key, err)
+	}
+
+	return value, nil
+}
+
+// SetJSON sets key to value encoded as JSON, with the expiration of Set.
+func SetJSON(ctx context.Context, m RedisManager, key string, value any, expiration time.Duration) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("encode %s: %w", key, err)
+	}
+
+	return m.Set(ctx, key, string(raw), expiration)
+}
+
+func withDefaultExpiration(expiration time.Duration) time.Duration {
+	if expiration == 0 {
+		return DefaultExpiration
+	}
+
+	return expiration
+}
+
+// notFound turns the missing key reply of Redis into ErrNotFound.
+func notFound(value string, err error) (string, error) {
+	if errors.Is(err, redis.Nil) {
+		return "", ErrNotFound
+	}
+
+	return value, err
+}
+
+// formatScore formats a score for a range, with the infinities of Redis.
+func formatScore(score float64) string {
+	switch {
+	case math.IsInf(score, 1):
+		return "+inf"
+	case math.IsInf(score, -1):
+		return "-inf"
+	default:
+		return strconv.FormatFloat(score, 'f', -1, 64)
+	}
+}
@@ -2,23 +2,31 @@ package redis_test
 
 import (
 	"context"
+	"math"
 	"testing"
+	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
-	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
+func newRedisManager(t *testing.T) (*miniredis.Miniredis, redis.RedisManager) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+
+	client, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+
+	return mr, redis.NewRedisManager(client)
+}
+
 func TestRedisManager(t *testing.T) {
 	// Start a miniredis server
-	mr, err := miniredis.Run()
-	if err != nil {
-		t.Fatalf("Failed to start miniredis: %v", err)
-	}
-	defer mr.Close()
-
-	// Create a Redis client connected to miniredis
-	redisManager := redis.NewRedisManager(redis.NewRedisClient(context.Background(), redis.RedisConfig{Addr: mr.Addr()}))
+	mr, redisManager := newRedisManager(t)
 
 	ctx := context.Background()
 
@@ -32,6 +40,7 @@ func TestRedisManager(t *testing.T) {
 
 		// Verify the value is stored in Redis
 		mr.CheckGet(t, key, value)
+		assert.Equal(t, redis.DefaultExpiration, mr.TTL(key))
 
 		// Test getting the value
 		retrievedValue, err := redisManager.Get(ctx, key)
@@ -39,6 +48,11 @@ func TestRedisManager(t *testing.T) {
 		assert.Equal(t, value, retrievedValue)
 	})
 
+	t.Run("Get missing key", func(t *testing.T) {
+		_, err := redisManager.Get(ctx, "missing_key")
+		assert.ErrorIs(t, err, redis.ErrNotFound)
+	})
+
 	t.Run("Delete key", func(t *testing.T) {
 		key := "deletable_key"
 		value := "to_be_deleted"
@@ -53,6 +67,128 @@ func TestRedisManager(t *testing.T) {
 
 		// Verify the key no longer exists
 		_, err = redisManager.Get(ctx, key)
+		assert.ErrorIs(t, err, redis.ErrNotFound)
+
+		exists, err := redisManager.Exists(ctx, key)
+		assert.NoError(t, err)
+		assert.False(t, exists)
+	})
+
+	t.Run("SetNX", func(t *testing.T) {
+		set, err := redisManager.SetNX(ctx, "lock", "first", time.Minute)
+		require.NoError(t, err)
+		assert.True(t, set)
+
+		set, err = redisManager.SetNX(ctx, "lock", "second", time.Minute)
+		require.NoError(t, err)
+		assert.False(t, set, "an existing key is kept")
+
+		mr.CheckGet(t, "lock", "first")
+		assert.Equal(t, time.Minute, mr.TTL("lock"))
+	})
+
+	t.Run("Incr", func(t *testing.T) {
+		for want := int64(1); want <= 3; want++ {
+			got, err := redisManager.Incr(ctx, "counter", time.Minute)
+			require.NoError(t, err)
+			assert.Equal(t, want, got)
+		}
+
+		assert.Equal(t, time.Minute, mr.TTL("counter"))
+
+		mr.FastForward(30 * time.Second)
+		_, err := redisManager.Incr(ctx, "counter", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, 30*time.Second, mr.TTL("counter"), "later increments keep the expiration")
+
+		mr.FastForward(30 * time.Second)
+		got, err := redisManager.Incr(ctx, "counter", time.Minute)
+		require.NoError(t, err)
+		assert.Equal(t, int64(1), got, "the counter starts over once expired")
+	})
+
+	t.Run("MGet", func(t *testing.T) {
+		require.NoError(t, redisManager.Set(ctx, "mget:a", "1", time.Minute))
+		require.NoError(t, redisManager.Set(ctx, "mget:b", "2", time.Minute))
+
+		values, err := redisManager.MGet(ctx, "mget:a", "mget:missing", "mget:b")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"mget:a": "1", "mget:b": "2"}, values)
+	})
+
+	t.Run("Pipeline", func(t *testing.T) {
+		cmds, err := redisManager.Pipeline(ctx, func(pipe goredis.Pipeliner) error {
+			pipe.Set(ctx, "pipelined", "value", time.Minute)
+			pipe.Get(ctx, "pipelined")
+			return nil
+		})
+		require.NoError(t, err)
+		require.Len(t, cmds, 2)
+		assert.Equal(t, "value", cmds[1].(*goredis.StringCmd).Val())
+	})
+
+	t.Run("Hash", func(t *testing.T) {
+		require.NoError(t, redisManager.HSet(ctx, "hash", map[string]string{"a": "1", "b": "2"}))
+
+		value, err := redisManager.HGet(ctx, "hash", "a")
+		require.NoError(t, err)
+		assert.Equal(t, "1", value)
+
+		_, err = redisManager.HGet(ctx, "hash", "missing")
+		assert.ErrorIs(t, err, redis.ErrNotFound)
+
+		require.NoError(t, redisManager.HDel(ctx, "hash", "a"))
+
+		fields, err := redisManager.HGetAll(ctx, "hash")
+		require.NoError(t, err)
+		assert.Equal(t, map[string]string{"b": "2"}, fields)
+	})
+
+	t.Run("Sorted set", func(t *testing.T) {
+		require.NoError(t, redisManager.ZAdd(ctx, "zset",
+			redis.ZMember{Member: "c", Score: 3},
+			redis.ZMember{Member: "a", Score: 1},
+			redis.ZMember{Member: "b", Score: 2.5},
+		))
+
+		members, err := redisManager.ZRangeByScore(ctx, "zset", 1, 2.5)
+		require.NoError(t, err)
+		assert.Equal(t, []redis.ZMember{{Member: "a", Score: 1}, {Member: "b", Score: 2.5}}, members)
+
+		require.NoError(t, redisManager.ZRem(ctx, "zset", "a"))
+
+		members, err = redisManager.ZRangeByScore(ctx, "zset", math.Inf(-1), math.Inf(1))
+		require.NoError(t, err)
+		assert.Equal(t, []redis.ZMember{{Member: "b", Score: 2.5}, {Member: "c", Score: 3}}, members)
+	})
+
+	t.Run("Scan", func(t *testing.T) {
+		for _, id := range []string{"1", "2", "3"} {
+			require.NoError(t, redisManager.Set(ctx, redis.REDIS_PREFIX_KEY_TOKEN.Key(id), "revoked", time.Minute))
+		}
+
+		keys, err := redisManager.Scan(ctx, redis.REDIS_PREFIX_KEY_TOKEN)
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"token:1", "token:2", "token:3"}, keys)
+	})
+
+	t.Run("JSON", func(t *testing.T) {
+		type session struct {
+			UserID int64    `json:"user_id"`
+			Scopes []string `json:"scopes"`
+		}
+
+		require.NoError(t, redis.SetJSON(ctx, redisManager, "session", session{UserID: 1, Scopes: []string{"read"}}, time.Minute))
+
+		got, err := redis.GetJSON[session](ctx, redisManager, "session")
+		require.NoError(t, err)
+		assert.Equal(t, session{UserID: 1, Scopes: []string{"read"}}, got)
+
+		_, err = redis.GetJSON[session](ctx, redisManager, "missing")
+		assert.ErrorIs(t, err, redis.ErrNotFound)
+
+		require.NoError(t, redisManager.Set(ctx, "not-json", "{", time.Minute))
+		_, err = redis.GetJSON[session](ctx, redisManager, "not-json")
 		assert.Error(t, err)
 	})
 
@@ -62,3 +198,31 @@ func TestRedisManager(t *testing.T) {
 		assert.NoError(t, err)
 	})
 }
+
+func TestRedisManager_ScanCluster(t *testing.T) {
+	first, second := miniredis.RunT(t), miniredis.RunT(t)
+
+	// Each master holds half of the slots.
+	client := goredis.NewClusterClient(&goredis.ClusterOptions{
+		ClusterSlots: func(ctx context.Context) ([]goredis.ClusterSlot, error) {
+			return []goredis.ClusterSlot{
+				{Start: 0, End: 8191, Nodes: []goredis.ClusterNode{{Addr: first.Addr()}}},
+				{Start: 8192, End: 16383, Nodes: []goredis.ClusterNode{{Addr: second.Addr()}}},
+			}, nil
+		},
+	})
+	t.Cleanup(func() { _ = client.Close() })
+
+	require.NoError(t, first.Set("token:1", "revoked"))
+	require.NoError(t, second.Set("token:2", "revoked"))
+	require.NoError(t, second.Set("session:1", "{}"))
+
+	keys, err := redis.NewRedisManager(client).Scan(context.Background(), redis.REDIS_PREFIX_KEY_TOKEN)
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"token:1", "token:2"}, keys, "every master is scanned")
+}
+
+func TestRedisPrefixKey(t *testing.T) {
+	assert.Equal(t, "config:feature_flags", redis.REDIS_PREFIX_KEY_CONFIG.Key("feature_flags"))
+	assert.Equal(t, "cache:*", redis.REDIS_PREFIX_KEY_CACHE.Pattern())
+}
@@ -2,46 +2,129 @@ package redis_test
 
 import (
 	"context"
+	"net"
 	"testing"
+	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
-	"github.com/alicebob/miniredis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
-func TestNewRedisClient(t *testing.T) {
-	// Start a miniredis instance
-	mr, err := miniredis.Run()
-	if err != nil {
-		t.Fatalf("Failed to start miniredis: %v", err)
-	}
-	defer mr.Close()
+var fastRetry = redis.RetryConfig{Attempts: 3, Backoff: time.Millisecond, MaxBackoff: time.Millisecond}
 
+func TestNewClient(t *testing.T) {
+	mr := miniredis.RunT(t)
 	ctx := context.Background()
 
-	// Mock Redis configuration
-	cfg := redis.RedisConfig{
-		Addr:     mr.Addr(), // Use the miniredis address
-		Password: "",        // No password for miniredis
-		DB:       0,         // Default database
+	client, err := redis.NewClient(redis.RedisConfig{Addr: mr.Addr()})
+	require.NoError(t, err)
+	defer client.Close()
+
+	// Perform a sample Redis operation to verify the client works
+	require.NoError(t, client.Set(ctx, "test_key", "test_value", 0).Err())
+	mr.CheckGet(t, "test_key", "test_value")
+
+	// Simulate a Redis server down scenario
+	mr.Close()
+	assert.Error(t, client.Ping(ctx).Err(), "Expected an error when pinging a closed Redis server")
+}
+
+func TestNewClient_Modes(t *testing.T) {
+	cases := map[string]struct {
+		cfg  redis.RedisConfig
+		want any
+	}{
+		"standalone": {cfg: redis.RedisConfig{Addr: "127.0.0.1:6379"}, want: &goredis.Client{}},
+		"sentinel":   {cfg: redis.RedisConfig{Mode: redis.ModeSentinel, Addrs: []string{"127.0.0.1:26379"}, MasterName: "main"}, want: &goredis.Client{}},
+		"cluster":    {cfg: redis.RedisConfig{Mode: redis.ModeCluster, Addrs: []string{"127.0.0.1:7000"}}, want: &goredis.ClusterClient{}},
 	}
 
-	// Call the NewRedisClient function
-	client := redis.NewRedisClient(ctx, cfg)
-	assert.NotNil(t, client, "Expected non-nil Redis client")
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			require.NoError(t, tc.cfg.Validate())
 
-	// Perform a sample Redis operation to verify the client works
-	err = client.Set(ctx, "test_key", "test_value", 0).Err()
-	assert.NoError(t, err, "Expected no error setting a key in Redis")
+			client, err := redis.NewClient(tc.cfg)
+			require.NoError(t, err)
+			defer client.Close()
 
-	value, err := client.Get(ctx, "test_key").Result()
-	assert.NoError(t, err, "Expected no error getting a key from Redis")
-	assert.Equal(t, "test_value", value, "Expected value to match the set value")
+			assert.IsType(t, tc.want, client)
+		})
+	}
+}
 
-	// Simulate a Redis server down scenario
+func TestNewClient_TLS(t *testing.T) {
+	_, err := redis.NewClient(redis.RedisConfig{Addr: "127.0.0.1:6379", TLS: redis.TLSConfig{Enable: true, CAFile: "missing.pem"}})
+	assert.ErrorContains(t, err, "read CA file")
+
+	tlsConfig, err := redis.TLSConfig{Enable: true, ServerName: "redis.internal"}.Config()
+	require.NoError(t, err)
+	assert.Equal(t, "redis.internal", tlsConfig.ServerName)
+
+	tlsConfig, err = redis.TLSConfig{ServerName: "redis.internal"}.Config()
+	require.NoError(t, err)
+	assert.Nil(t, tlsConfig, "TLS is disabled")
+}
+
+func TestRedisConfig_Validate(t *testing.T) {
+	assert.NoError(t, redis.RedisConfig{Addr: "127.0.0.1:6379"}.Validate())
+	assert.Error(t, redis.RedisConfig{}.Validate(), "the standalone address is required")
+	assert.Error(t, redis.RedisConfig{Mode: "replicated", Addr: "127.0.0.1:6379"}.Validate())
+	assert.Error(t, redis.RedisConfig{Mode: redis.ModeSentinel, Addrs: []string{"127.0.0.1:26379"}}.Validate(), "the master name is required")
+	assert.Error(t, redis.RedisConfig{Mode: redis.ModeCluster, Addrs: []string{"127.0.0.1:7000"}, DB: 1}.Validate())
+	assert.Error(t, redis.RedisConfig{Addr: "127.0.0.1:6379", TLS: redis.TLSConfig{Enable: true, CertFile: "client.pem"}}.Validate(), "the key is required with a certificate")
+}
+
+func TestConnect(t *testing.T) {
+	mr := miniredis.RunT(t)
+	ctx := context.Background()
+	addr := mr.Addr()
+
+	client, err := redis.Connect(ctx, redis.RedisConfig{Addr: addr, Retry: fastRetry})
+	assert.NoError(t, err, "Expected no error connecting to a running Redis server")
+	assert.NotNil(t, client, "Expected non-nil Redis client")
+	client.Close()
+
+	// Connecting to a stopped server returns an error instead of panicking.
 	mr.Close()
 
-	// Try to ping the closed Redis server
-	err = client.Ping(ctx).Err()
-	assert.Error(t, err, "Expected an error when pinging a closed Redis server")
+	client, err = redis.Connect(ctx, redis.RedisConfig{Addr: addr, Retry: fastRetry})
+	assert.ErrorContains(t, err, "no answer after 3 attempts")
+	assert.Nil(t, client)
+}
+
+func TestPing_RetriesUntilRedisAnswers(t *testing.T) {
+	// Reserve an address for a server started after the first attempts.
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	addr := listener.Addr().String()
+	require.NoError(t, listener.Close())
+
+	client, err := redis.NewClient(redis.RedisConfig{Addr: addr})
+	require.NoError(t, err)
+	defer client.Close()
+
+	var failures int
+	err = redis.Ping(context.Background(), client, redis.RetryConfig{Attempts: redis.RetryForever, Backoff: 10 * time.Millisecond, MaxBackoff: 10 * time.Millisecond}, func(attempt int, err error) {
+		failures++
+		if attempt == 3 {
+			mr := miniredis.NewMiniRedis()
+			require.NoError(t, mr.StartAddr(addr))
+			t.Cleanup(mr.Close)
+		}
+	})
+	require.NoError(t, err)
+	// The pool waits for a while after failed dials before dialing again.
+	assert.GreaterOrEqual(t, failures, 3)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	down, err := redis.NewClient(redis.RedisConfig{Addr: "127.0.0.1:1"})
+	require.NoError(t, err)
+	defer down.Close()
+
+	assert.ErrorIs(t, redis.Ping(ctx, down, redis.RetryConfig{Attempts: redis.RetryForever}, nil), context.Canceled)
 }
@@ -0,0 +1,73 @@
+package redis
+
+import (
+	"context"
+	"errors"
+	"net"
+	"time"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// Subscribe handles the payloads published on channel until ctx is done. The
+// subscription is made again, waiting the backoff of retry between the
+// attempts, whenever it fails or is lost, e.g. while Redis is down.
+// onSubscribe is called every time the subscription is made, before its
+// messages are handled, so that the caller can catch up with the messages
+// published while it was not subscribed.
+func Subscribe(ctx context.Context, client redis.UniversalClient, channel string, retry RetryConfig, onSubscribe func(), handle func(payload string)) {
+	retry = retry.withDefaults()
+
+	for attempt := 0; ; attempt++ {
+		if subscribe(ctx, client, channel, onSubscribe, handle) {
+			// The subscription was made, back off from the start when it is lost.
+			attempt = 0
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(retry.backoff(attempt)):
+		}
+	}
+}
+
+// pingInterval is how long a subscription waits for a message before checking
+// that Redis still answers.
+const pingInterval = time.Minute
+
+// subscribe handles the messages of one subscription until it is lost or ctx
+// is done, and reports whether the subscription was made. Every confirmation
+// of the subscription calls onSubscribe.
+func subscribe(ctx context.Context, client redis.UniversalClient, channel string, onSubscribe func(), handle func(payload string)) (subscribed bool) {
+	pubsub := client.Subscribe(ctx, channel)
+	defer pubsub.Close()
+
+	// Receiving does not watch ctx, closing the subscription stops it.
+	stop := context.AfterFunc(ctx, func() { _ = pubsub.Close() })
+	defer stop()
+
+	for {
+		message, err := pubsub.ReceiveTimeout(ctx, pingInterval)
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Timeout() && pubsub.Ping(ctx) == nil {
+				continue
+			}
+
+			// Subscribing again on a new connection is left to the caller, after
+			// its backoff.
+			return subscribed
+		}
+
+		switch message := message.(type) {
+		case *redis.Subscription:
+			if message.Kind == "subscribe" {
+				subscribed = true
+				onSubscribe()
+			}
+		case *redis.Message:
+			handle(message.Payload)
+		}
+	}
+}
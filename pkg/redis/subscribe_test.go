@@ -0,0 +1,122 @@
+package redis_test
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/alicebob/miniredis/v2"
+	goredis "github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSubscribe_StartsWhileRedisIsDown(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	mr.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	var (
+		mu            sync.Mutex
+		subscriptions int
+		payloads      []string
+	)
+	go func() {
+		defer close(done)
+
+		redis.Subscribe(ctx, client, "events", fastRetry,
+			func() {
+				mu.Lock()
+				defer mu.Unlock()
+				subscriptions++
+			},
+			func(payload string) {
+				mu.Lock()
+				defer mu.Unlock()
+				payloads = append(payloads, payload)
+			},
+		)
+	}()
+
+	time.Sleep(20 * time.Millisecond) // Let the first attempts fail.
+	require.NoError(t, mr.Restart())
+
+	require.Eventually(t, func() bool {
+		mr.Publish("events", "hello")
+
+		mu.Lock()
+		defer mu.Unlock()
+		return len(payloads) > 0
+	}, 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, 1, subscriptions)
+	require.Equal(t, "hello", payloads[0])
+	mu.Unlock()
+
+	cancel()
+	<-done
+}
+
+func TestSubscribe_SubscribesAgainAfterAnOutage(t *testing.T) {
+	mr := miniredis.RunT(t)
+	client := goredis.NewClient(&goredis.Options{Addr: mr.Addr(), MaxRetries: -1})
+	defer client.Close()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+
+	var (
+		mu            sync.Mutex
+		subscriptions int
+		payloads      []string
+	)
+	go func() {
+		defer close(done)
+
+		redis.Subscribe(ctx, client, "events", fastRetry,
+			func() {
+				mu.Lock()
+				defer mu.Unlock()
+				subscriptions++
+			},
+			func(payload string) {
+				mu.Lock()
+				defer mu.Unlock()
+				payloads = append(payloads, payload)
+			},
+		)
+	}()
+
+	received := func(payload string) func() bool {
+		return func() bool {
+			mr.Publish("events", payload)
+
+			mu.Lock()
+			defer mu.Unlock()
+			return slices.Contains(payloads, payload)
+		}
+	}
+
+	require.Eventually(t, received("before"), 5*time.Second, 10*time.Millisecond)
+
+	mr.Close()
+	time.Sleep(20 * time.Millisecond) // Let the subscription notice the outage.
+	require.NoError(t, mr.Restart())
+
+	require.Eventually(t, received("after"), 5*time.Second, 10*time.Millisecond)
+
+	mu.Lock()
+	require.Equal(t, 2, subscriptions, "the caller catches up after the outage")
+	mu.Unlock()
+
+	cancel()
+	<-done
+}
@@ -0,0 +1,250 @@
+// Package reload applies settings that change while the service runs, such as
+// the log level, without a restart.
+package reload
+
+import (
+	"context"
+	"fmt"
+	"maps"
+	"os"
+	"os/signal"
+	"reflect"
+	"slices"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/invopop/validation"
+	"github.com/rs/zerolog"
+)
+
+// Config controls how reloadable settings are watched.
+type Config struct {
+	Enable bool // Watches the settings while the server runs.
+	// Interval is how often the polled sources, such as the config files, are
+	// checked for changes. Defaults to 10 seconds.
+	Interval time.Duration
+}
+
+const defaultInterval = 10 * time.Second
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Interval, validation.Min(time.Duration(0))),
+	)
+}
+
+// Change is a setting that changed on reload.
+type Change struct {
+	Key  string `json:"key"`
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+// Trigger calls fire with the reason of a reload whenever its source may have
+// changed, until ctx is done.
+type Trigger func(ctx context.Context, fire func(reason string))
+
+// Reloader holds the current value of settings of type T, and hands every
+// change to its subscribers.
+type Reloader[T any] struct {
+	load   func(ctx context.Context) (T, error)
+	logger *zerolog.Logger
+
+	mu          sync.Mutex // Held while a reload applies, so subscribers see the changes in order.
+	current     atomic.Pointer[T]
+	subscribers []func(T)
+}
+
+// New creates a reloader holding initial, that reads the settings again with
+// load. Settings implementing Validate() error are validated before they apply.
+func New[T any](initial T, load func(ctx context.Context) (T, error), logger *zerolog.Logger) *Reloader[T] {
+	r := &Reloader[T]{load: load, logger: logger}
+	r.current.Store(&initial)
+
+	return r
+}
+
+// Current returns the settings applied last.
+func (r *Reloader[T]) Current() T {
+	return *r.current.Load()
+}
+
+// Subscribe calls fn with the current settings, then with the settings of
+// every applied change. fn receives the whole settings at once and must not
+// block.
+func (r *Reloader[T]) Subscribe(fn func(T)) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subscribers = append(r.subscribers, fn)
+	fn(*r.current.Load())
+}
+
+// Reload loads the settings and applies them when they changed. Settings that
+// fail to load or to validate are rejected and the current ones are kept.
+func (r *Reloader[T]) Reload(ctx context.Context, reason string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	next, err := r.load(ctx)
+	if err == nil {
+		if validatable, ok := any(next).(interface{ Validate() error }); ok {
+			err = validatable.Validate()
+		}
+	}
+
+	if err != nil {
+		r.logger.Warn().Err(err).Str("reason", reason).Msg("runtime settings rejected, keeping the current ones")
+		return err
+	}
+
+	changes := Diff(*r.current.Load(), next)
+	if len(changes) == 0 {
+		return nil
+	}
+
+	r.current.Store(&next)
+	for _, subscriber := range r.subscribers {
+		subscriber(next)
+	}
+
+	r.logger.Info().Str("reason", reason).Interface("changes", changes).Msg("runtime settings reloaded")
+
+	return nil
+}
+
+// Watch reloads the settings whenever a trigger fires, until ctx is done.
+func (r *Reloader[T]) Watch(ctx context.Context, triggers ...Trigger) error {
+	var wg sync.WaitGroup
+	for _, trigger := range triggers {
+		wg.Go(func() {
+			trigger(ctx, func(reason string) { _ = r.Reload(ctx, reason) })
+		})
+	}
+	wg.Wait()
+
+	return nil
+}
+
+// OnSignal fires when the process receives one of signals, e.g. SIGHUP.
+func OnSignal(signals ...os.Signal) Trigger {
+	return func(ctx context.Context, fire func(reason string)) {
+		received := make(chan os.Signal, 1)
+		signal.Notify(received, signals...)
+		defer signal.Stop(received)
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case sig := <-received:
+				fire(sig.String())
+			}
+		}
+	}
+}
+
+// OnChange fires with reason when the fingerprint of a source changes, checked
+// every interval (10 seconds when zero). A failing fingerprint is retried on
+// the next tick.
+func OnChange(reason string, interval time.Duration, fingerprint func(ctx context.Context) (string, error)) Trigger {
+	if interval <= 0 {
+		interval = defaultInterval
+	}
+
+	return func(ctx context.Context, fire func(reason string)) {
+		last, err := fingerprint(ctx)
+		known := err == nil
+
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+
+			current, err := fingerprint(ctx)
+			if err != nil {
+				continue
+			}
+
+			// The source may have changed while it was unreadable.
+			if !known || current != last {
+				fire(reason)
+			}
+
+			last, known = current, true
+		}
+	}
+}
+
+// Files fingerprints the size and the modification time of files, so that
+// OnChange fires when one of them is written, replaced or removed.
+func Files(paths ...string) func(ctx context.Context) (string, error) {
+	return func(ctx context.Context) (string, error) {
+		var fingerprint string
+		for _, path := range paths {
+			info, err := os.Stat(path)
+			if err != nil {
+				fingerprint += fmt.Sprintf("%s:missing;", path)
+				continue
+			}
+
+			fingerprint += fmt.Sprintf("%s:%d:%d;", path, info.Size(), info.ModTime().UnixNano())
+		}
+
+		return fingerprint, nil
+	}
+}
+
+// Diff returns the settings that differ between from and to, by their dotted
+// key, e.g. Logger.Level.
+func Diff[T any](from, to T) []Change {
+	before, after := flatten(reflect.ValueOf(from), ""), flatten(reflect.ValueOf(to), "")
+
+	var changes []Change
+	for key, value := range after {
+		if before[key] != value {
+			changes = append(changes, Change{Key: key, From: before[key], To: value})
+		}
+	}
+
+	slices.SortFunc(changes, func(a, b Change) int { return strings.Compare(a.Key, b.Key) })
+
+	return changes
+}
+
+// flatten returns the printed leaf values of v by their dotted key.
+func flatten(v reflect.Value, prefix string) map[string]string {
+	values := make(map[string]string)
+	if v.Kind() != reflect.Struct {
+		values[prefix] = fmt.Sprint(v.Interface())
+		return values
+	}
+
+	for i := range v.NumField() {
+		field := v.Type().Field(i)
+		if !field.IsExported() {
+			continue
+		}
+
+		key := field.Name
+		if prefix != "" {
+			key = prefix + "." + key
+		}
+
+		if field.Type.Kind() == reflect.Struct && field.Type != reflect.TypeFor[time.Time]() {
+			maps.Copy(values, flatten(v.Field(i), key))
+			continue
+		}
+
+		values[key] = fmt.Sprint(v.Field(i).Interface())
+	}
+
+	return values
+}
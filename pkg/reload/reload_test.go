@@ -0,0 +1,168 @@
+package reload
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type settings struct {
+	Level  string
+	Limits struct {
+		Rate  float64
+		Paths []string
+	}
+}
+
+func (s settings) Validate() error {
+	if s.Level == "" {
+		return errors.New("level is required")
+	}
+
+	return nil
+}
+
+func newReloader(t *testing.T, next *settings) *Reloader[settings] {
+	t.Helper()
+
+	logger := zerolog.Nop()
+
+	return New(settings{Level: "info"}, func(ctx context.Context) (settings, error) {
+		if next == nil {
+			return settings{}, errors.New("source unavailable")
+		}
+
+		return *next, nil
+	}, &logger)
+}
+
+func TestReloader_AppliesChanges(t *testing.T) {
+	next := settings{Level: "debug"}
+	next.Limits.Rate = 5
+
+	r := newReloader(t, &next)
+
+	var received []settings
+	r.Subscribe(func(s settings) { received = append(received, s) })
+
+	require.NoError(t, r.Reload(t.Context(), "test"))
+
+	assert.Equal(t, next, r.Current())
+	require.Len(t, received, 2, "the current settings, then the change")
+	assert.Equal(t, "info", received[0].Level)
+	assert.Equal(t, next, received[1])
+
+	require.NoError(t, r.Reload(t.Context(), "test"))
+	assert.Len(t, received, 2, "unchanged settings are not handed out again")
+}
+
+func TestReloader_RejectsInvalidSettings(t *testing.T) {
+	next := settings{}
+	r := newReloader(t, &next)
+
+	calls := 0
+	r.Subscribe(func(settings) { calls++ })
+
+	assert.EqualError(t, r.Reload(t.Context(), "test"), "level is required")
+	assert.Equal(t, "info", r.Current().Level, "the current settings are kept")
+	assert.Equal(t, 1, calls)
+
+	r = newReloader(t, nil)
+	assert.EqualError(t, r.Reload(t.Context(), "test"), "source unavailable")
+	assert.Equal(t, "info", r.Current().Level)
+}
+
+func TestReloader_Watch(t *testing.T) {
+	next := settings{Level: "warn"}
+	r := newReloader(t, &next)
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = r.Watch(ctx, func(ctx context.Context, fire func(reason string)) { fire("test") })
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Watch did not return after its triggers")
+	}
+
+	assert.Equal(t, "warn", r.Current().Level)
+}
+
+func TestOnChange(t *testing.T) {
+	var (
+		value atomic.Value
+		fired = make(chan string, 10)
+	)
+	value.Store("a")
+
+	ctx, cancel := context.WithCancel(t.Context())
+	defer cancel()
+
+	trigger := OnChange("changed", 5*time.Millisecond, func(ctx context.Context) (string, error) {
+		return value.Load().(string), nil
+	})
+	go trigger(ctx, func(reason string) { fired <- reason })
+
+	select {
+	case <-fired:
+		t.Fatal("fired without a change")
+	case <-time.After(30 * time.Millisecond):
+	}
+
+	value.Store("b")
+
+	select {
+	case reason := <-fired:
+		assert.Equal(t, "changed", reason)
+	case <-time.After(time.Second):
+		t.Fatal("did not fire on a change")
+	}
+}
+
+func TestFiles(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	fingerprint := Files(path)
+
+	missing, err := fingerprint(t.Context())
+	require.NoError(t, err)
+
+	require.NoError(t, os.WriteFile(path, []byte("a: 1"), 0o600))
+	written, err := fingerprint(t.Context())
+	require.NoError(t, err)
+	assert.NotEqual(t, missing, written)
+
+	require.NoError(t, os.WriteFile(path, []byte("a: 10"), 0o600))
+	rewritten, err := fingerprint(t.Context())
+	require.NoError(t, err)
+	assert.NotEqual(t, written, rewritten)
+}
+
+func TestDiff(t *testing.T) {
+	from := settings{Level: "info"}
+	from.Limits.Paths = []string{"/healthz"}
+
+	to := from
+	to.Level = "debug"
+	to.Limits.Rate = 2.5
+
+	assert.Equal(t, []Change{
+		{Key: "Level", From: "info", To: "debug"},
+		{Key: "Limits.Rate", From: "0", To: "2.5"},
+	}, Diff(from, to))
+
+	assert.Empty(t, Diff(from, from))
+}
@@ -5,6 +5,7 @@ import "errors"
 var (
 	ErrInvalidToken              = errors.New("invalid authentication token")
 	ErrFailedGetTokenInformation = errors.New("failed to get token information")
+	ErrTokenCheckUnavailable     = errors.New("token revocation cannot be checked, try again later")
 
 	ErrEmailAlreadyExist = errors.New("email already exist")
 	ErrInvalidUserType   = errors.New("invalid user_type")
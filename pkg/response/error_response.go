@@ -4,6 +4,7 @@ import (
 	"errors"
 	"net/http"
 
+	"github.com/invopop/validation"
 	"github.com/labstack/echo/v4"
 	"go.opentelemetry.io/otel/codes"
 	"go.opentelemetry.io/otel/trace"
@@ -11,9 +12,10 @@ import (
 
 // ErrorResponse represents a failed response structure for API responses.
 type ErrorResponse struct {
-	Code    int             `json:"code" example:"500"`                      // HTTP status code.
-	Message ResponseMessage `json:"message" example:"internal_server_error"` // Message corresponding to the status code.
-	Error   string          `json:"error" example:"{$err}"`                  // error message.
+	Code    int               `json:"code" example:"500"`                      // HTTP status code.
+	Message ResponseMessage   `json:"message" example:"internal_server_error"` // Message corresponding to the status code.
+	Error   string            `json:"error" example:"{$err}"`                  // error message.
+	Errors  map[string]string `json:"errors,omitempty"`                        // field-level validation errors.
 }
 
 // ErrorBuilder constructs a ErrorResponse based on the provided error.
@@ -26,6 +28,7 @@ func ErrorBuilder(err error) ErrorResponse {
 			Code:    ae.Code,
 			Message: ae.Message,
 			Error:   ae.Error(),
+			Errors:  fieldErrors(ae.Err),
 		}
 	}
 
@@ -42,6 +45,23 @@ func ErrorBuilder(err error) ErrorResponse {
 	return response
 }
 
+// fieldErrors flattens validation errors into a field to message map.
+func fieldErrors(err error) map[string]string {
+	var validationErrs validation.Errors
+	if !errors.As(err, &validationErrs) {
+		return nil
+	}
+
+	fields := make(map[string]string, len(validationErrs))
+	for field, fieldErr := range validationErrs {
+		if fieldErr != nil {
+			fields[field] = fieldErr.Error()
+		}
+	}
+
+	return fields
+}
+
 // Send sends the CustomResponse as a JSON response using the provided Echo context.
 func (x ErrorResponse) Send(c echo.Context) error {
 	err := errors.New(x.Error)
@@ -6,6 +6,7 @@ import (
 	"net/http/httptest"
 	"testing"
 
+	"github.com/invopop/validation"
 	"github.com/labstack/echo/v4"
 )
 
@@ -61,6 +62,24 @@ func TestErrorBuilder(t *testing.T) {
 			t.Fatalf("Error = %q, want %q", resp.Error, InternalServerErrorMessage.String())
 		}
 	})
+
+	t.Run("returns field errors for validation errors", func(t *testing.T) {
+		resp := ErrorBuilder(BadRequest(validation.Errors{
+			"password": errors.New("must be at least 8 characters long"),
+		}))
+
+		if resp.Code != http.StatusBadRequest {
+			t.Fatalf("Code = %d, want %d", resp.Code, http.StatusBadRequest)
+		}
+
+		if len(resp.Errors) != 1 {
+			t.Fatalf("Errors = %v, want 1 field error", resp.Errors)
+		}
+
+		if resp.Errors["password"] != "must be at least 8 characters long" {
+			t.Fatalf("Errors[password] = %q", resp.Errors["password"])
+		}
+	})
 }
 
 func TestErrorResponse_Send(t *testing.T) {
@@ -63,6 +63,14 @@ func TooManyRequests(err error) error {
 	return &AppError{Code: http.StatusTooManyRequests, Message: TooManyRequestsMessage, Err: err}
 }
 
+func ServiceUnavailable(err error) error {
+	return &AppError{
+		Code:    http.StatusServiceUnavailable,
+		Message: ServiceUnavailableMessage,
+		Err:     err,
+	}
+}
+
 func GatewayTimeout(err error) error {
 	return &AppError{
 		Code:    http.StatusGatewayTimeout,
@@ -87,6 +87,12 @@ func TestErrorConstructors(t *testing.T) {
 			code:    http.StatusTooManyRequests,
 			message: TooManyRequestsMessage,
 		},
+		{
+			name:    "ServiceUnavailable",
+			fn:      ServiceUnavailable,
+			code:    http.StatusServiceUnavailable,
+			message: ServiceUnavailableMessage,
+		},
 		{
 			name:    "GatewayTimeout",
 			fn:      GatewayTimeout,
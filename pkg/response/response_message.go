@@ -13,6 +13,7 @@ const (
 	ConflictMessage            ResponseMessage = "conflict"
 	GatewayTimeOutMessage      ResponseMessage = "gateway_timeout"
 	TooManyRequestsMessage     ResponseMessage = "too_many_requests"
+	ServiceUnavailableMessage  ResponseMessage = "service_unavailable"
 )
 
 func (rm ResponseMessage) String() string {
@@ -0,0 +1,89 @@
+package scheduler
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultRunsLimit is the number of runs listed without a limit.
+const defaultRunsLimit = 20
+
+type adminHandlers struct {
+	scheduler *Scheduler
+	admins    []int64
+}
+
+// NewAdminHandlers creates the handlers of the admin API, which lists the
+// tasks of scheduler and their runs to the users of admins.
+func NewAdminHandlers(scheduler *Scheduler, admins []int64) *adminHandlers {
+	return &adminHandlers{scheduler: scheduler, admins: admins}
+}
+
+func (h *adminHandlers) MapRoutes(api *echo.Group, mw *middleware.Middleware) {
+	admin := api.Group("/admin/scheduler", mw.JWTMiddleware(), middleware.RequireAdmin(h.admins))
+
+	admin.GET("/tasks", h.TasksHandler)
+	admin.GET("/tasks/:name/runs", h.RunsHandler)
+}
+
+// @Summary		Scheduled Tasks
+// @Description	List the scheduled tasks with their next and last run
+// @ID			scheduled-tasks
+// @Tags		Scheduler
+// @Produce		json
+// @Success		200		{object}	response.Success{data=[]scheduler.TaskInfo}	"SUCCESS"
+// @Failure		500		{object}	response.FailedResponse						"INTERNAL_SERVER__ERROR"
+// @Router		/admin/scheduler/tasks [get]
+// @Security	BearerToken
+func (h *adminHandlers) TasksHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "ScheduledTasksHandler")
+	defer span.End()
+
+	tasks, err := h.scheduler.Tasks(ctx)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(tasks).Send(c)
+}
+
+// @Summary		Scheduled Task Runs
+// @Description	List the runs of a scheduled task, the last first
+// @ID			scheduled-task-runs
+// @Tags		Scheduler
+// @Produce		json
+// @Param		name	path		string								true	"Task"
+// @Param		limit	query		int									false	"Limit, 20 by default"
+// @Success		200		{object}	response.Success{data=[]scheduler.Run}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse				"BAD_REQUEST"
+// @Failure		404		{object}	response.FailedResponse				"NOT_FOUND"
+// @Router		/admin/scheduler/tasks/{name}/runs [get]
+// @Security	BearerToken
+func (h *adminHandlers) RunsHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "ScheduledTaskRunsHandler")
+	defer span.End()
+
+	limit := defaultRunsLimit
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			return response.ErrorBuilder(response.BadRequest(errors.New("limit must be a positive number"))).Send(c)
+		}
+	}
+
+	history, err := h.scheduler.Runs(ctx, c.Param("name"), limit)
+	if err != nil {
+		if errors.Is(err, ErrTaskNotFound) {
+			return response.ErrorBuilder(response.NotFound(err)).Send(c)
+		}
+
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(history).Send(c)
+}
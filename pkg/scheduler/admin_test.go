@@ -0,0 +1,60 @@
+package scheduler
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandlers(t *testing.T) {
+	client := newClient(t)
+	jwtFactory := jwt.NewJWTFactory(jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600}, appRedis.NewRedisManager(client))
+
+	tokens := make(map[int64]string)
+	for _, id := range []int64{1, 2} {
+		token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: id, ContactType: types.CONTACT_TYPE_EMAIL}})
+		require.NoError(t, err)
+		tokens[id] = token
+	}
+
+	s := NewScheduler(Config{}, nil, client, nil)
+	require.NoError(t, s.Register(Task{Name: "purge", Schedule: "@daily", Run: func(ctx context.Context) error { return nil }}))
+	s.runOccurrence(context.Background(), s.tasks[0], time.Now().Truncate(time.Hour))
+
+	e := echo.New()
+	NewAdminHandlers(s, []int64{1}).MapRoutes(e.Group("/api/v1"), middleware.New(jwtFactory))
+
+	do := func(path string, userID int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/scheduler"+path, nil)
+		req.Header.Set(types.AuthorizationHeaderKey.String(), "Bearer "+tokens[userID])
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	assert.Equal(t, http.StatusForbidden, do("/tasks", 2).Code, "only admins inspect the tasks")
+
+	rec := do("/tasks", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"name":"purge"`)
+	assert.Contains(t, rec.Body.String(), `"status":"succeeded"`)
+
+	rec = do("/tasks/purge/runs?limit=5", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"task":"purge"`)
+
+	assert.Equal(t, http.StatusBadRequest, do("/tasks/purge/runs?limit=x", 1).Code)
+	assert.Equal(t, http.StatusNotFound, do("/tasks/missing/runs", 1).Code)
+}
@@ -0,0 +1,133 @@
+package scheduler
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/metric"
+)
+
+// Status is the outcome of a run.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+	// StatusSkipped is the status of an occurrence due while the previous run
+	// was still going on.
+	StatusSkipped Status = "skipped"
+)
+
+// Run is a recorded run of a task.
+type Run struct {
+	Task        string        `json:"task"`
+	ScheduledAt time.Time     `json:"scheduled_at"`
+	StartedAt   time.Time     `json:"started_at"`
+	Duration    time.Duration `json:"duration"` // In nanoseconds.
+	Status      Status        `json:"status"`
+	Error       string        `json:"error,omitempty"`
+	Instance    string        `json:"instance"` // The host name of the replica that ran the task.
+}
+
+// TaskInfo describes a registered task.
+type TaskInfo struct {
+	Name     string    `json:"name"`
+	Schedule string    `json:"schedule"`
+	Enabled  bool      `json:"enabled"`
+	NextRun  time.Time `json:"next_run,omitzero"` // Zero for a disabled task.
+	LastRun  *Run      `json:"last_run,omitempty"`
+}
+
+// Metrics of the runs, reported through the global meter provider.
+var (
+	meter = otel.Meter("github.com/DoWithLogic/golang-clean-architecture/pkg/scheduler")
+
+	runs, _ = meter.Int64Counter("scheduler.runs",
+		metric.WithDescription("Runs of the scheduled tasks, by task and status."))
+	runDuration, _ = meter.Float64Histogram("scheduler.run.duration",
+		metric.WithDescription("Duration of the runs of the scheduled tasks, by task and status."),
+		metric.WithUnit("s"))
+)
+
+// record reports run and adds it to the history of its task, trimmed to the
+// Config.History last runs.
+func (s *Scheduler) record(ctx context.Context, run Run) {
+	attrs := metric.WithAttributes(attribute.String("task", run.Task), attribute.String("status", string(run.Status)))
+	runs.Add(ctx, 1, attrs)
+	runDuration.Record(ctx, run.Duration.Seconds(), attrs)
+
+	raw, err := json.Marshal(run)
+	if err != nil {
+		s.log.Err(err).Str("task", run.Task).Msg("[Scheduler]Record")
+		return
+	}
+
+	key := s.historyKey(run.Task)
+	if _, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, raw)
+		pipe.LTrim(ctx, key, 0, int64(s.cfg.History-1))
+		return nil
+	}); err != nil {
+		s.log.Err(err).Str("task", run.Task).Msg("[Scheduler]Record")
+	}
+}
+
+// Runs returns the last limit recorded runs of the task name, the last first.
+func (s *Scheduler) Runs(ctx context.Context, name string, limit int) ([]Run, error) {
+	if _, err := s.task(name); err != nil {
+		return nil, err
+	}
+
+	values, err := s.client.LRange(ctx, s.historyKey(name), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	history := make([]Run, 0, len(values))
+	for _, value := range values {
+		var run Run
+		if err := json.Unmarshal([]byte(value), &run); err != nil {
+			return nil, err
+		}
+
+		history = append(history, run)
+	}
+
+	return history, nil
+}
+
+// Tasks describes the registered tasks with their last run.
+func (s *Scheduler) Tasks(ctx context.Context) ([]TaskInfo, error) {
+	now := time.Now()
+
+	infos := make([]TaskInfo, 0, len(s.tasks))
+	for _, t := range s.tasks {
+		info := TaskInfo{Name: t.Name, Schedule: t.Schedule, Enabled: !t.disabled}
+		if info.Enabled {
+			info.NextRun = s.next(t, now)
+		}
+
+		last, err := s.Runs(ctx, t.Name, 1)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(last) > 0 {
+			info.LastRun = &last[0]
+		}
+
+		infos = append(infos, info)
+	}
+
+	return infos, nil
+}
+
+// historyKey is the key of the runs of the task name.
+func (s *Scheduler) historyKey(name string) string {
+	return appRedis.REDIS_PREFIX_KEY_SCHEDULER.Key(name + ":runs")
+}
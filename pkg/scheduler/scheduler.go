@@ -0,0 +1,337 @@
+// Package scheduler runs periodic maintenance tasks, such as purging deleted
+// accounts or rotating keys, on cron schedules.
+//
+// Every replica runs the scheduler, and every occurrence of a task runs on a
+// single replica: the replicas race for the occurrence through a Redis lock,
+// after a random jitter so that the same replica does not always win. A run
+// still going on when the next occurrence is due makes that occurrence skip.
+// Every run is recorded with its status and duration.
+//
+//	err := s.Register(scheduler.Task{
+//	    Name:     "purge-deleted-accounts",
+//	    Schedule: "0 3 * * *", // 3 AM in the time zone of the scheduler
+//	    Run:      usersUC.PurgeDeleted,
+//	})
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"github.com/invopop/validation"
+	"github.com/robfig/cron/v3"
+	"github.com/rs/zerolog"
+)
+
+// ErrTaskNotFound is returned for a task that is not registered.
+var ErrTaskNotFound = errors.New("scheduler: task not found")
+
+// Config holds the configuration of the scheduler.
+type Config struct {
+	Enable bool // Runs the scheduled tasks together with the server.
+	// Jitter is the upper bound of the random delay before every run. Defaults
+	// to no delay.
+	Jitter  time.Duration
+	History int          // The number of runs kept per task. Defaults to 50.
+	Tasks   []TaskConfig // Overrides of the schedules of the registered tasks.
+}
+
+// TaskConfig overrides the schedule of a registered task.
+type TaskConfig struct {
+	Name     string
+	Schedule string // Replaces the schedule of the task when set.
+	Disable  bool   // Stops running the task.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Jitter, validation.Min(time.Duration(0))),
+		validation.Field(&c.History, validation.Min(0)),
+		validation.Field(&c.Tasks),
+	)
+}
+
+func (c TaskConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Name, validation.Required),
+		validation.Field(&c.Schedule, validation.By(isSchedule)),
+	)
+}
+
+// isSchedule fails on a cron expression that cannot be parsed.
+func isSchedule(value any) error {
+	spec, _ := value.(string)
+	if spec == "" {
+		return nil
+	}
+
+	if _, err := cron.ParseStandard(spec); err != nil {
+		return errors.New("must be a valid cron expression")
+	}
+
+	return nil
+}
+
+func (c Config) withDefaults() Config {
+	if c.History <= 0 {
+		c.History = 50
+	}
+
+	return c
+}
+
+// Task is a function run on a schedule.
+type Task struct {
+	Name string
+	// Schedule is a cron expression of five fields (minute, hour, day of month,
+	// month, day of week) or a descriptor such as @daily or @every 10m. It is
+	// evaluated in the time zone of the scheduler, unless it starts with
+	// CRON_TZ=<zone>. @every runs at the multiples of its delay, @every 10m at
+	// :00, :10, :20 and so on.
+	Schedule string
+	// Timeout bounds a run. Defaults to no timeout.
+	Timeout time.Duration
+	Run     func(ctx context.Context) error
+}
+
+// task is a registered task.
+type task struct {
+	Task
+	schedule cron.Schedule
+	disabled bool
+}
+
+// Scheduler runs the registered tasks on their schedules.
+type Scheduler struct {
+	cfg      Config
+	location *time.Location
+	client   redis.UniversalClient
+	locker   *appRedis.Locker
+	log      *zerolog.Logger
+	instance string
+	tasks    []*task
+}
+
+// NewScheduler creates a scheduler evaluating the schedules in location, UTC
+// when nil. The runs are coordinated and recorded through client.
+func NewScheduler(cfg Config, location *time.Location, client redis.UniversalClient, log *zerolog.Logger) *Scheduler {
+	if location == nil {
+		location = time.UTC
+	}
+
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	instance, _ := os.Hostname()
+
+	return &Scheduler{
+		cfg:      cfg.withDefaults(),
+		location: location,
+		client:   client,
+		locker:   appRedis.NewLocker(client),
+		log:      log,
+		instance: instance,
+	}
+}
+
+// Register registers t, with the schedule of its TaskConfig when there is one.
+// It returns an error when the schedule is invalid or the name already taken.
+func (s *Scheduler) Register(t Task) error {
+	if _, err := s.task(t.Name); err == nil {
+		return fmt.Errorf("scheduler: task %s already registered", t.Name)
+	}
+
+	registered := &task{Task: t}
+	for _, override := range s.cfg.Tasks {
+		if override.Name != t.Name {
+			continue
+		}
+
+		if override.Schedule != "" {
+			registered.Schedule = override.Schedule
+		}
+
+		registered.disabled = override.Disable
+	}
+
+	schedule, err := parseSchedule(registered.Schedule)
+	if err != nil {
+		return fmt.Errorf("scheduler: task %s: %w", t.Name, err)
+	}
+
+	registered.schedule = schedule
+	s.tasks = append(s.tasks, registered)
+
+	return nil
+}
+
+// parseSchedule parses spec. The occurrences of @every are aligned on multiples
+// of the delay, cron counts the delay from the time of the previous run, which
+// differs between the replicas and would make each of them claim its own
+// occurrence.
+func parseSchedule(spec string) (cron.Schedule, error) {
+	schedule, err := cron.ParseStandard(spec)
+	if err != nil {
+		return nil, err
+	}
+
+	if every, ok := schedule.(cron.ConstantDelaySchedule); ok {
+		return everySchedule{delay: every.Delay}, nil
+	}
+
+	return schedule, nil
+}
+
+// everySchedule is due at every multiple of delay.
+type everySchedule struct {
+	delay time.Duration
+}
+
+func (e everySchedule) Next(t time.Time) time.Time {
+	return t.Truncate(e.delay).Add(e.delay)
+}
+
+func (s *Scheduler) task(name string) (*task, error) {
+	for _, t := range s.tasks {
+		if t.Name == name {
+			return t, nil
+		}
+	}
+
+	return nil, ErrTaskNotFound
+}
+
+// next returns the first occurrence of t after now.
+func (s *Scheduler) next(t *task, now time.Time) time.Time {
+	return t.schedule.Next(now.In(s.location))
+}
+
+// Run runs the enabled tasks on their schedules until ctx is done. The runs
+// going on when ctx is done are canceled and awaited.
+func (s *Scheduler) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	for _, t := range s.tasks {
+		if t.disabled {
+			continue
+		}
+
+		wg.Go(func() { s.loop(ctx, t) })
+	}
+
+	wg.Wait()
+
+	return nil
+}
+
+// loop runs the occurrences of t until ctx is done.
+func (s *Scheduler) loop(ctx context.Context, t *task) {
+	for {
+		scheduledAt := s.next(t, time.Now())
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(time.Until(scheduledAt)):
+		}
+
+		if s.cfg.Jitter > 0 {
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(rand.N(s.cfg.Jitter)):
+			}
+		}
+
+		s.runOccurrence(ctx, t, scheduledAt)
+	}
+}
+
+// runOccurrence runs the occurrence of t due at scheduledAt, unless another
+// replica claimed it first, and records the run.
+func (s *Scheduler) runOccurrence(ctx context.Context, t *task, scheduledAt time.Time) {
+	log := s.log.With().Str("task", t.Name).Time("scheduled_at", scheduledAt).Logger()
+
+	// The claim expires once the next occurrence is due, the replicas lagging
+	// behind still see it claimed until then.
+	claimTTL := max(s.next(t, scheduledAt).Sub(scheduledAt), time.Minute)
+
+	_, err := s.locker.TryLock(ctx, s.claimKey(t, scheduledAt), claimTTL)
+	if errors.Is(err, appRedis.ErrLockNotAcquired) {
+		return
+	}
+
+	if err != nil {
+		if ctx.Err() == nil {
+			log.Err(err).Msg("[Scheduler]Claim")
+		}
+
+		return
+	}
+
+	run := Run{Task: t.Name, ScheduledAt: scheduledAt, StartedAt: time.Now(), Instance: s.instance}
+
+	// The previous run may still be going on, on this replica or another one.
+	lock, err := s.locker.TryLock(ctx, s.runningKey(t), appRedis.DefaultLockTTL)
+	switch {
+	case errors.Is(err, appRedis.ErrLockNotAcquired):
+		run.Status, run.Error = StatusSkipped, "the previous run is still going on"
+	case err != nil:
+		run.Status, run.Error = StatusFailed, err.Error()
+	default:
+		holdCtx, release := lock.Hold(ctx)
+		err = s.execute(holdCtx, t)
+		release()
+
+		run.Status = StatusSucceeded
+		if err != nil {
+			run.Status, run.Error = StatusFailed, err.Error()
+		}
+	}
+
+	run.Duration = time.Since(run.StartedAt)
+
+	event := log.Info()
+	if run.Status != StatusSucceeded {
+		event = log.Warn()
+	}
+	event.Str("status", string(run.Status)).Dur("duration", run.Duration).Str("error", run.Error).Msg("[Scheduler]Run")
+
+	s.record(context.WithoutCancel(ctx), run)
+}
+
+// execute runs t within its timeout, turning a panic into an error.
+func (s *Scheduler) execute(ctx context.Context, t *task) (err error) {
+	if t.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, t.Timeout)
+		defer cancel()
+	}
+
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+
+	return t.Run(ctx)
+}
+
+// claimKey is the lock name of the occurrence of t due at scheduledAt.
+func (s *Scheduler) claimKey(t *task, scheduledAt time.Time) string {
+	return appRedis.REDIS_PREFIX_KEY_SCHEDULER.Key(t.Name + ":" + strconv.FormatInt(scheduledAt.Unix(), 10))
+}
+
+// runningKey is the lock name held while t runs.
+func (s *Scheduler) runningKey(t *task) string {
+	return appRedis.REDIS_PREFIX_KEY_SCHEDULER.Key(t.Name + ":running")
+}
@@ -0,0 +1,238 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func newClient(t *testing.T) redis.UniversalClient {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	return client
+}
+
+func TestScheduler_Register(t *testing.T) {
+	s := NewScheduler(Config{Tasks: []TaskConfig{
+		{Name: "purge", Schedule: "30 2 * * *"},
+		{Name: "rotate-keys", Disable: true},
+	}}, nil, newClient(t), nil)
+
+	noop := func(ctx context.Context) error { return nil }
+
+	require.NoError(t, s.Register(Task{Name: "purge", Schedule: "@daily", Run: noop}))
+	require.NoError(t, s.Register(Task{Name: "rotate-keys", Schedule: "@weekly", Run: noop}))
+	assert.ErrorContains(t, s.Register(Task{Name: "purge", Schedule: "@daily", Run: noop}), "already registered")
+	assert.Error(t, s.Register(Task{Name: "expire", Schedule: "every day", Run: noop}))
+
+	tasks, err := s.Tasks(context.Background())
+	require.NoError(t, err)
+	require.Len(t, tasks, 2)
+
+	assert.Equal(t, "30 2 * * *", tasks[0].Schedule, "the config overrides the schedule")
+	assert.Equal(t, 2, tasks[0].NextRun.Hour())
+	assert.Equal(t, 30, tasks[0].NextRun.Minute())
+	assert.False(t, tasks[1].Enabled)
+	assert.True(t, tasks[1].NextRun.IsZero())
+}
+
+func TestScheduler_TimeZone(t *testing.T) {
+	jakarta, err := time.LoadLocation("Asia/Jakarta")
+	require.NoError(t, err)
+
+	s := NewScheduler(Config{}, jakarta, newClient(t), nil)
+	require.NoError(t, s.Register(Task{Name: "report", Schedule: "0 9 * * *", Run: func(ctx context.Context) error { return nil }}))
+
+	next := s.next(s.tasks[0], time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC))
+	assert.Equal(t, time.Date(2026, 1, 1, 2, 0, 0, 0, time.UTC), next.UTC(), "9 AM in Jakarta is 2 AM UTC")
+}
+
+func TestScheduler_RunsEveryOccurrenceOnce(t *testing.T) {
+	client := newClient(t)
+
+	var calls atomic.Int32
+	task := Task{Name: "purge", Schedule: "@hourly", Run: func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}}
+
+	// Three replicas sharing Redis race for the same occurrence.
+	var replicas []*Scheduler
+	for range 3 {
+		s := NewScheduler(Config{}, nil, client, nil)
+		require.NoError(t, s.Register(task))
+		replicas = append(replicas, s)
+	}
+
+	scheduledAt := time.Now().Truncate(time.Hour)
+
+	var wg sync.WaitGroup
+	for _, s := range replicas {
+		wg.Go(func() { s.runOccurrence(context.Background(), s.tasks[0], scheduledAt) })
+	}
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+
+	history, err := replicas[0].Runs(context.Background(), "purge", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 1)
+	assert.Equal(t, StatusSucceeded, history[0].Status)
+	assert.True(t, scheduledAt.Equal(history[0].ScheduledAt))
+
+	replicas[1].runOccurrence(context.Background(), replicas[1].tasks[0], scheduledAt.Add(time.Hour))
+	assert.Equal(t, int32(2), calls.Load(), "the next occurrence runs")
+}
+
+func TestScheduler_SkipsWhileRunning(t *testing.T) {
+	s := NewScheduler(Config{}, nil, newClient(t), nil)
+
+	started, finish := make(chan struct{}), make(chan struct{})
+	require.NoError(t, s.Register(Task{Name: "export", Schedule: "@hourly", Run: func(ctx context.Context) error {
+		close(started)
+		<-finish
+		return nil
+	}}))
+
+	scheduledAt := time.Now().Truncate(time.Hour)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		s.runOccurrence(context.Background(), s.tasks[0], scheduledAt)
+	}()
+	<-started
+
+	s.runOccurrence(context.Background(), s.tasks[0], scheduledAt.Add(time.Hour))
+	close(finish)
+	<-done
+
+	history, err := s.Runs(context.Background(), "export", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2)
+	assert.Equal(t, StatusSucceeded, history[0].Status)
+	assert.Equal(t, StatusSkipped, history[1].Status)
+}
+
+func TestScheduler_RecordsFailures(t *testing.T) {
+	s := NewScheduler(Config{History: 2}, nil, newClient(t), nil)
+
+	var calls atomic.Int32
+	require.NoError(t, s.Register(Task{Name: "rotate-keys", Schedule: "@hourly", Timeout: 10 * time.Millisecond, Run: func(ctx context.Context) error {
+		switch calls.Add(1) {
+		case 1:
+			panic("no key")
+		case 2:
+			<-ctx.Done()
+			return ctx.Err()
+		default:
+			return errors.New("vault sealed")
+		}
+	}}))
+
+	scheduledAt := time.Now().Truncate(time.Hour)
+	for i := range 3 {
+		s.runOccurrence(context.Background(), s.tasks[0], scheduledAt.Add(time.Duration(i)*time.Hour))
+	}
+
+	history, err := s.Runs(context.Background(), "rotate-keys", 10)
+	require.NoError(t, err)
+	require.Len(t, history, 2, "the history keeps the last runs")
+	assert.Equal(t, StatusFailed, history[0].Status)
+	assert.Equal(t, "vault sealed", history[0].Error)
+	assert.Equal(t, StatusFailed, history[1].Status)
+	assert.Equal(t, context.DeadlineExceeded.Error(), history[1].Error, "the run is canceled after its timeout")
+
+	_, err = s.Runs(context.Background(), "missing", 10)
+	assert.ErrorIs(t, err, ErrTaskNotFound)
+}
+
+func TestScheduler_Run(t *testing.T) {
+	s := NewScheduler(Config{Jitter: 10 * time.Millisecond}, nil, newClient(t), nil)
+
+	ran := make(chan struct{}, 1)
+	require.NoError(t, s.Register(Task{Name: "heartbeat", Schedule: "@every 1s", Run: func(ctx context.Context) error {
+		select {
+		case ran <- struct{}{}:
+		default:
+		}
+		return nil
+	}}))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- s.Run(ctx) }()
+
+	select {
+	case <-ran:
+	case <-time.After(3 * time.Second):
+		t.Fatal("the task did not run")
+	}
+
+	cancel()
+	require.NoError(t, <-done)
+}
+
+func TestScheduler_RunsEveryOccurrenceOnceWithADelay(t *testing.T) {
+	client := newClient(t)
+
+	var calls atomic.Int32
+	task := Task{Name: "heartbeat", Schedule: "@every 2s", Run: func(ctx context.Context) error {
+		calls.Add(1)
+		return nil
+	}}
+
+	var replicas []*Scheduler
+	for range 2 {
+		s := NewScheduler(Config{}, nil, client, nil)
+		require.NoError(t, s.Register(task))
+		replicas = append(replicas, s)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	var wg sync.WaitGroup
+
+	// The second replica starts a second later, the occurrences must not
+	// depend on when each replica started.
+	for i, s := range replicas {
+		if i > 0 {
+			time.Sleep(time.Second)
+		}
+
+		wg.Go(func() { _ = s.Run(ctx) })
+	}
+
+	time.Sleep(4 * time.Second)
+	cancel()
+	wg.Wait()
+
+	history, err := replicas[0].Runs(context.Background(), "heartbeat", 10)
+	require.NoError(t, err)
+	require.NotEmpty(t, history)
+	assert.Equal(t, int(calls.Load()), len(history))
+
+	for i, run := range history {
+		assert.Zero(t, run.ScheduledAt.UnixNano()%int64(2*time.Second), "the occurrences are aligned on the delay")
+		if i > 0 {
+			assert.False(t, run.ScheduledAt.Equal(history[i-1].ScheduledAt), "every occurrence runs once")
+		}
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	assert.NoError(t, Config{Tasks: []TaskConfig{{Name: "purge", Schedule: "0 3 * * *"}}}.Validate())
+	assert.Error(t, Config{Tasks: []TaskConfig{{Name: "purge", Schedule: "at 3"}}}.Validate())
+	assert.Error(t, Config{Tasks: []TaskConfig{{Schedule: "@daily"}}}.Validate(), "the task name is required")
+	assert.Error(t, Config{Jitter: -time.Second}.Validate())
+}
@@ -0,0 +1,156 @@
+// Package seed runs named sets of seed data, such as the users of a local or a
+// demo environment.
+package seed
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/fs"
+	"path"
+	"slices"
+
+	"gopkg.in/yaml.v3"
+)
+
+var (
+	// ErrUnknownSet is returned when running a set that is not registered.
+	ErrUnknownSet = errors.New("unknown seed set")
+
+	// ErrNotAllowed is returned when running a set in an environment it is not
+	// meant for.
+	ErrNotAllowed = errors.New("seed set not allowed in environment")
+)
+
+// Seeder inserts a group of records. Seeders run again on every run of their
+// set, so they must skip the records that already exist.
+type Seeder interface {
+	Name() string
+	Seed(ctx context.Context) (Result, error)
+}
+
+// Result counts the records of a seeder.
+type Result struct {
+	Created int `json:"created"`
+	Skipped int `json:"skipped"` // Records that already existed.
+}
+
+// Set is a named group of seeders run together, in order.
+type Set struct {
+	Name         string
+	Description  string
+	Environments []string // Environments the set runs in by default and is allowed in.
+	Seeders      []Seeder
+}
+
+// AllowedIn reports whether the set may run in env.
+func (s Set) AllowedIn(env string) bool {
+	return slices.Contains(s.Environments, env)
+}
+
+// Report is the result of a seeder of a set.
+type Report struct {
+	Set    string `json:"set"`
+	Seeder string `json:"seeder"`
+	Result
+}
+
+// Registry holds the seed sets of the application.
+type Registry struct {
+	sets []Set
+}
+
+// NewRegistry creates a registry of sets.
+func NewRegistry(sets ...Set) *Registry {
+	return &Registry{sets: sets}
+}
+
+// Sets returns the registered sets.
+func (r *Registry) Sets() []Set {
+	return r.sets
+}
+
+// Set returns the set registered under name.
+func (r *Registry) Set(name string) (Set, bool) {
+	for _, set := range r.sets {
+		if set.Name == name {
+			return set, true
+		}
+	}
+
+	return Set{}, false
+}
+
+// ForEnvironment returns the names of the sets that run in env by default.
+func (r *Registry) ForEnvironment(env string) []string {
+	var names []string
+	for _, set := range r.sets {
+		if set.AllowedIn(env) {
+			names = append(names, set.Name)
+		}
+	}
+
+	return names
+}
+
+// Run runs the named sets in env, or the sets of env when no name is given. It
+// fails before seeding anything when a set is unknown or not allowed in env,
+// and stops at the first seeder that fails.
+func (r *Registry) Run(ctx context.Context, env string, names ...string) ([]Report, error) {
+	if len(names) == 0 {
+		names = r.ForEnvironment(env)
+	}
+
+	sets := make([]Set, 0, len(names))
+	for _, name := range names {
+		set, ok := r.Set(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrUnknownSet, name)
+		}
+
+		if !set.AllowedIn(env) {
+			return nil, fmt.Errorf("%w: %q in %q", ErrNotAllowed, name, env)
+		}
+
+		sets = append(sets, set)
+	}
+
+	var reports []Report
+	for _, set := range sets {
+		for _, seeder := range set.Seeders {
+			result, err := seeder.Seed(ctx)
+			reports = append(reports, Report{Set: set.Name, Seeder: seeder.Name(), Result: result})
+
+			if err != nil {
+				return reports, fmt.Errorf("seed %s/%s: %w", set.Name, seeder.Name(), err)
+			}
+		}
+	}
+
+	return reports, nil
+}
+
+// Decode reads the fixture file name of fsys into v. Files with a .json
+// extension are decoded as JSON, .yaml and .yml files as YAML.
+func Decode(fsys fs.FS, name string, v any) error {
+	data, err := fs.ReadFile(fsys, name)
+	if err != nil {
+		return err
+	}
+
+	switch path.Ext(name) {
+	case ".json":
+		err = json.Unmarshal(data, v)
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(data, v)
+	default:
+		return fmt.Errorf("unsupported fixture format %q", name)
+	}
+
+	if err != nil {
+		return fmt.Errorf("decode %s: %w", name, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,131 @@
+package seed_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"testing/fstest"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type seederFunc struct {
+	name string
+	fn   func(ctx context.Context) (seed.Result, error)
+}
+
+func (s seederFunc) Name() string                                  { return s.name }
+func (s seederFunc) Seed(ctx context.Context) (seed.Result, error) { return s.fn(ctx) }
+
+func recorder(name string, calls *[]string, result seed.Result, err error) seed.Seeder {
+	return seederFunc{name: name, fn: func(ctx context.Context) (seed.Result, error) {
+		*calls = append(*calls, name)
+		return result, err
+	}}
+}
+
+func TestRegistry_Run(t *testing.T) {
+	var calls []string
+
+	registry := seed.NewRegistry(
+		seed.Set{Name: "base", Environments: []string{"local", "test"}, Seeders: []seed.Seeder{
+			recorder("users", &calls, seed.Result{Created: 2}, nil),
+			recorder("roles", &calls, seed.Result{Skipped: 1}, nil),
+		}},
+		seed.Set{Name: "demo", Environments: []string{"staging"}, Seeders: []seed.Seeder{
+			recorder("demo users", &calls, seed.Result{Created: 3}, nil),
+		}},
+	)
+
+	t.Run("sets of the environment", func(t *testing.T) {
+		calls = nil
+
+		reports, err := registry.Run(context.Background(), "local")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"users", "roles"}, calls)
+		assert.Equal(t, []seed.Report{
+			{Set: "base", Seeder: "users", Result: seed.Result{Created: 2}},
+			{Set: "base", Seeder: "roles", Result: seed.Result{Skipped: 1}},
+		}, reports)
+	})
+
+	t.Run("named sets", func(t *testing.T) {
+		calls = nil
+
+		_, err := registry.Run(context.Background(), "staging", "demo")
+		require.NoError(t, err)
+		assert.Equal(t, []string{"demo users"}, calls)
+	})
+
+	t.Run("no set for the environment", func(t *testing.T) {
+		calls = nil
+
+		reports, err := registry.Run(context.Background(), "production")
+		require.NoError(t, err)
+		assert.Empty(t, reports)
+		assert.Empty(t, calls)
+	})
+
+	t.Run("set not allowed in the environment", func(t *testing.T) {
+		calls = nil
+
+		_, err := registry.Run(context.Background(), "production", "base")
+		require.ErrorIs(t, err, seed.ErrNotAllowed)
+		assert.Empty(t, calls, "nothing is seeded")
+	})
+
+	t.Run("unknown set", func(t *testing.T) {
+		calls = nil
+
+		_, err := registry.Run(context.Background(), "local", "base", "missing")
+		require.ErrorIs(t, err, seed.ErrUnknownSet)
+		assert.Empty(t, calls, "nothing is seeded")
+	})
+}
+
+func TestRegistry_RunStopsAtFailedSeeder(t *testing.T) {
+	var calls []string
+	errFailed := errors.New("failed")
+
+	registry := seed.NewRegistry(seed.Set{Name: "base", Environments: []string{"test"}, Seeders: []seed.Seeder{
+		recorder("users", &calls, seed.Result{Created: 1}, errFailed),
+		recorder("roles", &calls, seed.Result{}, nil),
+	}})
+
+	reports, err := registry.Run(context.Background(), "test")
+	require.ErrorIs(t, err, errFailed)
+	assert.Equal(t, []string{"users"}, calls)
+	assert.Equal(t, []seed.Report{{Set: "base", Seeder: "users", Result: seed.Result{Created: 1}}}, reports)
+}
+
+func TestDecode(t *testing.T) {
+	type fixture struct {
+		Users []struct {
+			Name string `json:"name" yaml:"name"`
+		} `json:"users" yaml:"users"`
+	}
+
+	fsys := fstest.MapFS{
+		"users.json":  {Data: []byte(`{"users": [{"name": "Jane"}]}`)},
+		"users.yaml":  {Data: []byte("users:\n  - name: Jane\n")},
+		"users.yml":   {Data: []byte("users:\n  - name: Jane\n")},
+		"users.toml":  {Data: []byte(`[[users]]`)},
+		"broken.json": {Data: []byte(`{`)},
+	}
+
+	for _, name := range []string{"users.json", "users.yaml", "users.yml"} {
+		t.Run(name, func(t *testing.T) {
+			var got fixture
+			require.NoError(t, seed.Decode(fsys, name, &got))
+			require.Len(t, got.Users, 1)
+			assert.Equal(t, "Jane", got.Users[0].Name)
+		})
+	}
+
+	var got fixture
+	assert.ErrorContains(t, seed.Decode(fsys, "users.toml", &got), "unsupported fixture format")
+	assert.ErrorContains(t, seed.Decode(fsys, "broken.json", &got), "decode broken.json")
+	assert.Error(t, seed.Decode(fsys, "missing.json", &got))
+}
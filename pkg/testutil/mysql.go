@@ -20,6 +20,8 @@ type MYSQLSuite struct {
 }
 
 func (s *MYSQLSuite) Setup() {
+	testcontainers.SkipIfProviderIsNotHealthy(s.T())
+
 	s.Ctx = context.Background()
 	s.Dialect = goose.DialectMySQL
 
@@ -42,7 +44,7 @@ func (s *MYSQLSuite) Setup() {
 	dsn, err := s.Container.ConnectionString(s.Ctx, "charset=utf8", "parseTime=true", "loc=UTC", "multiStatements=true")
 	s.Require().NoError(err)
 
-	s.DB, err = gorm.Open(gormmysql.Open(dsn))
+	s.DB, err = gorm.Open(gormmysql.Open(dsn), &gorm.Config{TranslateError: true})
 	s.Require().NoError(err)
 
 	db, err := s.DB.DB()
@@ -21,6 +21,8 @@ type PostgresSuite struct {
 }
 
 func (s *PostgresSuite) Setup() {
+	testcontainers.SkipIfProviderIsNotHealthy(s.T())
+
 	s.Ctx = context.Background()
 
 	testcontainers.WithLogger(log.Default())
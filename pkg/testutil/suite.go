@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
 	"github.com/pressly/goose/v3"
 	"github.com/stretchr/testify/suite"
 	"gorm.io/gorm"
@@ -14,6 +15,9 @@ const (
 	migrationVersionTable = "_goose_db_version"
 )
 
+// SeedEnvironment is the environment the suites run seed sets in.
+const SeedEnvironment = "test"
+
 type DBSuite struct {
 	suite.Suite
 
@@ -27,6 +31,15 @@ type DBSuite struct {
 	Dialect goose.Dialect
 }
 
+// Seed runs the seed sets of registry for the test environment, or the given
+// sets, against the database of the suite.
+func (s *DBSuite) Seed(registry *seed.Registry, sets ...string) []seed.Report {
+	reports, err := registry.Run(s.Ctx, SeedEnvironment, sets...)
+	s.Require().NoError(err)
+
+	return reports
+}
+
 func (s *DBSuite) setupGoose(db *sql.DB) {
 	if s.MigrationsDir != "" {
 		goose.SetDialect(string(s.Dialect))
@@ -0,0 +1,98 @@
+package transporter
+
+import (
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/invopop/validation"
+	"github.com/valyala/fasthttp"
+)
+
+// Config holds the configuration of the HTTP client.
+type Config struct {
+	Retry RetryConfig
+}
+
+// RetryConfig holds the retry policy of the requests.
+type RetryConfig struct {
+	MaxAttempts int // The attempts per request, 1 disables the retries. Defaults to 3.
+	// RetryBackoff bounds the delay before the first retry, doubled on every
+	// attempt up to MaxRetryBackoff. The delay is random below the bound.
+	// Defaults to 100ms.
+	RetryBackoff    time.Duration
+	MaxRetryBackoff time.Duration // Defaults to 5 seconds.
+	// MaxRetryAfter is the longest Retry-After honoured, a response asking
+	// to wait longer fails the request. Defaults to 30 seconds.
+	MaxRetryAfter time.Duration
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.Retry),
+	)
+}
+
+func (c RetryConfig) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.MaxAttempts, validation.Min(0)),
+		validation.Field(&c.RetryBackoff, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxRetryBackoff, validation.Min(time.Duration(0))),
+		validation.Field(&c.MaxRetryAfter, validation.Min(time.Duration(0))),
+	)
+}
+
+func (c RetryConfig) withDefaults() RetryConfig {
+	if c.MaxAttempts <= 0 {
+		c.MaxAttempts = 3
+	}
+
+	if c.RetryBackoff <= 0 {
+		c.RetryBackoff = 100 * time.Millisecond
+	}
+
+	if c.MaxRetryBackoff <= 0 {
+		c.MaxRetryBackoff = 5 * time.Second
+	}
+
+	if c.MaxRetryAfter <= 0 {
+		c.MaxRetryAfter = 30 * time.Second
+	}
+
+	return c
+}
+
+// backoff returns the delay after the failed attempt, with full jitter so that
+// clients failing together do not retry together.
+func (c RetryConfig) backoff(attempt int) time.Duration {
+	delay := min(c.RetryBackoff<<min(attempt-1, 32), c.MaxRetryBackoff)
+
+	return rand.N(delay + 1)
+}
+
+// retryableStatus reports whether a response with statusCode may succeed when
+// sent again.
+func retryableStatus(statusCode int) bool {
+	return statusCode == fasthttp.StatusTooManyRequests || statusCode >= fasthttp.StatusInternalServerError
+}
+
+// parseRetryAfter returns the delay of a Retry-After header, in seconds or as
+// an HTTP date, and zero when it is missing or invalid.
+func parseRetryAfter(value string) time.Duration {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return max(time.Duration(seconds)*time.Second, 0)
+	}
+
+	if at, err := http.ParseTime(value); err == nil {
+		return max(time.Until(at), 0)
+	}
+
+	return 0
+}
@@ -0,0 +1,30 @@
+package transporter
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestRetryConfig_Backoff(t *testing.T) {
+	cfg := RetryConfig{RetryBackoff: 100 * time.Millisecond, MaxRetryBackoff: time.Second}
+
+	for range 100 {
+		assert.LessOrEqual(t, cfg.backoff(1), 100*time.Millisecond)
+		assert.LessOrEqual(t, cfg.backoff(3), 400*time.Millisecond)
+		assert.LessOrEqual(t, cfg.backoff(50), time.Second, "the backoff is capped")
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	assert.Equal(t, 2*time.Second, parseRetryAfter("2"))
+	assert.Zero(t, parseRetryAfter(""))
+	assert.Zero(t, parseRetryAfter("soon"))
+	assert.Zero(t, parseRetryAfter("-1"))
+	assert.Zero(t, parseRetryAfter(time.Now().Add(-time.Hour).UTC().Format(http.TimeFormat)), "a past date retries right away")
+
+	delay := parseRetryAfter(time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	assert.InDelta(t, time.Minute, delay, float64(2*time.Second))
+}
@@ -8,6 +8,7 @@ import (
 	"io"
 	"mime/multipart"
 	"reflect"
+	"strings"
 	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
@@ -15,6 +16,7 @@ import (
 	"github.com/pkg/errors"
 	"github.com/rs/zerolog"
 	"github.com/valyala/fasthttp"
+	"go.opentelemetry.io/otel/attribute"
 )
 
 type (
@@ -29,6 +31,9 @@ type (
 		Headers  map[string]string
 		Body     any
 		Files    map[string]File
+		// Idempotent retries a POST or PATCH request, e.g. one sent with an
+		// idempotency key. Other methods are always retried.
+		Idempotent bool
 	}
 )
 
@@ -45,14 +50,54 @@ func (r Request) validateBody() error {
 	return nil
 }
 
+// StatusError is returned for a response with a status code outside of 2xx.
+type StatusError struct {
+	StatusCode int
+	Body       []byte
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("unexpected status code: %v", e.StatusCode)
+}
+
+// header returns the value of the header key set by the request, in any case.
+func (r Request) header(key string) (string, bool) {
+	for header, value := range r.Headers {
+		if strings.EqualFold(header, key) {
+			return value, true
+		}
+	}
+
+	return "", false
+}
+
+// retryable reports whether the request may be sent more than once.
+func (r Request) retryable() bool {
+	switch strings.ToUpper(r.Method) {
+	case fasthttp.MethodGet, fasthttp.MethodHead, fasthttp.MethodOptions, fasthttp.MethodTrace, fasthttp.MethodPut, fasthttp.MethodDelete:
+		return true
+	default:
+		return r.Idempotent
+	}
+}
+
 type AppHttp struct {
 	client *fasthttp.Client
 	log    *zerolog.Logger
+	retry  RetryConfig
+}
+
+// AppHttpOptionFn customizes the client created by NewClient.
+type AppHttpOptionFn func(*AppHttp)
+
+// WithConfig applies the retry policy of cfg.
+func WithConfig(cfg Config) AppHttpOptionFn {
+	return func(c *AppHttp) { c.retry = cfg.Retry.withDefaults() }
 }
 
 // NewClient creates a new fasthttp client with default settings
-func NewClient(log *zerolog.Logger) *AppHttp {
-	return &AppHttp{
+func NewClient(log *zerolog.Logger, opts ...AppHttpOptionFn) *AppHttp {
+	c := &AppHttp{
 		client: &fasthttp.Client{
 			// Maximum number of connections allowed per host. This controls the number of keep-alive connections.
 			MaxConnsPerHost: 50,
@@ -64,11 +109,24 @@ func NewClient(log *zerolog.Logger) *AppHttp {
 			ReadTimeout: 10 * time.Second,
 			// Maximum time allowed for writing a request to the server.
 			WriteTimeout: 10 * time.Second,
+			// Retries are made by DoHttpRequest, following RetryConfig.
+			MaxIdemponentCallAttempts: 1,
 		},
-		log: log,
+		log:   log,
+		retry: RetryConfig{}.withDefaults(),
+	}
+
+	for _, opt := range opts {
+		opt(c)
 	}
+
+	return c
 }
 
+// DoHttpRequest sends req and decodes a JSON response into res when it is not
+// nil. Connection errors, 429 and 5xx responses are retried following the
+// RetryConfig of the client, for idempotent methods or when req.Idempotent is
+// set.
 func (c *AppHttp) DoHttpRequest(ctx context.Context, req Request, res any) error {
 	ctx, span := instrumentation.NewTraceSpan(ctx, "DoHttpRequest")
 	defer span.End()
@@ -77,6 +135,72 @@ func (c *AppHttp) DoHttpRequest(ctx context.Context, req Request, res any) error
 		return err
 	}
 
+	// Build the body once, so that every attempt sends the same bytes.
+	body, err := c.prepareRequestBody(req)
+	if err != nil {
+		return err
+	}
+
+	maxAttempts := 1
+	if req.retryable() {
+		maxAttempts = c.retry.MaxAttempts
+	}
+
+	for attempt := 1; ; attempt++ {
+		retryAfter, err := c.doAttempt(ctx, req, body, attempt, res)
+		if err == nil || retryAfter < 0 || attempt >= maxAttempts {
+			return err
+		}
+
+		delay := c.retry.backoff(attempt)
+		if retryAfter > 0 {
+			if retryAfter > c.retry.MaxRetryAfter {
+				return err
+			}
+
+			delay = retryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < delay {
+			return err
+		}
+
+		c.log.Warn().Ctx(ctx).
+			Err(err).
+			Str("method", req.Method).
+			Str("endpoint", req.Endpoint).
+			Int("attempt", attempt).
+			Dur("delay", delay).
+			Msg("[DoHttpRequest]Retrying request")
+
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return err
+		case <-timer.C:
+		}
+	}
+}
+
+// doAttempt makes one attempt of req. A failed attempt returns a negative
+// retryAfter when it must not be retried, the Retry-After of the response
+// when it has one, and zero otherwise.
+func (c *AppHttp) doAttempt(ctx context.Context, req Request, body requestBody, attempt int, res any) (retryAfter time.Duration, err error) {
+	ctx, span := instrumentation.NewTraceSpan(ctx, "DoHttpRequest.Attempt")
+	defer func() {
+		if err != nil {
+			instrumentation.RecordSpanError(span, err)
+		}
+		span.End()
+	}()
+
+	span.SetAttributes(
+		attribute.String("http.method", req.Method),
+		attribute.String("http.url", req.Endpoint),
+		attribute.Int("http.attempt", attempt),
+	)
+
 	request := fasthttp.AcquireRequest()
 	response := fasthttp.AcquireResponse()
 	defer func() {
@@ -91,9 +215,9 @@ func (c *AppHttp) DoHttpRequest(ctx context.Context, req Request, res any) error
 		request.Header.Set(key, value)
 	}
 
-	// Handle body or multipart files
-	if err := c.prepareRequestBody(request, req); err != nil {
-		return err
+	if body.contentType != "" {
+		request.SetBody(body.content)
+		request.Header.Set("Content-Type", body.contentType)
 	}
 
 	// Log request
@@ -101,85 +225,103 @@ func (c *AppHttp) DoHttpRequest(ctx context.Context, req Request, res any) error
 	c.log.Info().Ctx(ctx).
 		Str("method", req.Method).
 		Str("endpoint", req.Endpoint).
+		Int("attempt", attempt).
 		Interface("headers", req.Headers).
 		Interface("body", string(request.Body())).
 		Msg("[DoHttpRequest]Sending request")
 
 	// Execute request
 	if err := c.client.Do(request, response); err != nil {
-		c.log.Err(err).Ctx(ctx).Msg("[DoHttpRequest]client.Do")
-		return errors.Wrap(err, "failed to execute HTTP request")
+		c.log.Err(err).Ctx(ctx).Int("attempt", attempt).Msg("[DoHttpRequest]client.Do")
+		return 0, errors.Wrap(err, "failed to execute HTTP request")
 	}
 
+	span.SetAttributes(attribute.Int("http.status_code", response.StatusCode()))
+
 	// Log response
 	c.log.Info().Ctx(ctx).
 		Int("status_code", response.StatusCode()).
+		Int("attempt", attempt).
 		Dur("duration", time.Since(start)).
 		RawJSON("response", response.Body()).
 		Msg("[DoHttpRequest]Received response")
 
 	// Check status code and decode response
 	if err := c.checkStatusCode(response); err != nil {
-		return err
+		if !retryableStatus(response.StatusCode()) {
+			return -1, err
+		}
+
+		return parseRetryAfter(string(response.Header.Peek(fasthttp.HeaderRetryAfter))), err
 	}
 
 	if res != nil {
 		if err := json.Unmarshal(response.Body(), res); err != nil {
 			c.log.Err(err).Ctx(ctx).Msg("[DoHttpRequest]json.Unmarshal")
-			return errors.Wrap(err, "failed to decode response")
+			return -1, errors.Wrap(err, "failed to decode response")
 		}
 	}
 
-	return nil
+	return 0, nil
 }
 
 func (c *AppHttp) checkStatusCode(response *fasthttp.Response) error {
-	if response.StatusCode() != fasthttp.StatusOK {
-		return fmt.Errorf("unexpected status code: %v", response.StatusCode())
+	if response.StatusCode() < fasthttp.StatusOK || response.StatusCode() >= fasthttp.StatusMultipleChoices {
+		return &StatusError{StatusCode: response.StatusCode(), Body: append([]byte(nil), response.Body()...)}
 	}
 
 	return nil
 }
 
-func (c *AppHttp) prepareRequestBody(request *fasthttp.Request, req Request) error {
+// requestBody is the body of a request, sent again as is by every attempt.
+type requestBody struct {
+	content     []byte
+	contentType string // Empty without a body.
+}
+
+func (c *AppHttp) prepareRequestBody(req Request) (requestBody, error) {
 	if req.Files != nil {
-		return c.prepareMultipartBody(request, req)
+		return c.prepareMultipartBody(req)
 	}
 
 	if req.Body != nil {
 		jsonBody, err := json.Marshal(req.Body)
 		if err != nil {
-			return errors.Wrap(err, "failed to marshal request body")
+			return requestBody{}, errors.Wrap(err, "failed to marshal request body")
+		}
+
+		// Keep a JSON media type set by the caller, e.g. application/cloudevents+json.
+		contentType := echo.MIMEApplicationJSON
+		if value, ok := req.header(echo.HeaderContentType); ok {
+			contentType = value
 		}
 
-		request.SetBody(jsonBody)
-		request.Header.Set("Content-Type", echo.MIMEApplicationJSON)
+		return requestBody{content: jsonBody, contentType: contentType}, nil
 	}
 
-	return nil
+	return requestBody{}, nil
 }
 
-func (c *AppHttp) prepareMultipartBody(request *fasthttp.Request, req Request) error {
+// prepareMultipartBody reads the files once into the body, since their
+// readers cannot be read again by a retry.
+func (c *AppHttp) prepareMultipartBody(req Request) (requestBody, error) {
 	var buffer bytes.Buffer
 	writer := multipart.NewWriter(&buffer)
 
 	for key, file := range req.Files {
 		part, err := writer.CreateFormFile(key, file.FileName)
 		if err != nil {
-			return errors.Wrap(err, "failed to create form file")
+			return requestBody{}, errors.Wrap(err, "failed to create form file")
 		}
 
 		if _, err := io.Copy(part, file.File); err != nil {
-			return errors.Wrap(err, "failed to copy file to form")
+			return requestBody{}, errors.Wrap(err, "failed to copy file to form")
 		}
 	}
 
 	if err := writer.Close(); err != nil {
-		return errors.Wrap(err, "failed to close writer")
+		return requestBody{}, errors.Wrap(err, "failed to close writer")
 	}
 
-	request.SetBody(buffer.Bytes())
-	request.Header.Set("Content-Type", writer.FormDataContentType())
-
-	return nil
+	return requestBody{content: buffer.Bytes(), contentType: writer.FormDataContentType()}, nil
 }
@@ -3,15 +3,20 @@ package transporter_test
 import (
 	"context"
 	"encoding/json"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
+	"sync/atomic"
 	"testing"
+	"time"
 
 	"github.com/DoWithLogic/golang-clean-architecture/pkg/transporter"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 )
 
 const contentType = "Content-Type"
@@ -156,3 +161,166 @@ func TestDoHttpRequestWithFormFile(t *testing.T) {
 	assert.NoError(t, err)
 	assert.True(t, res.Success)
 }
+
+func TestDoHttpRequestStatusCodes(t *testing.T) {
+	logger := zerolog.Nop()
+	appHttp := transporter.NewClient(&logger)
+
+	status := http.StatusAccepted
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "application/cloudevents+json", r.Header.Get(contentType), "the content type of the caller is kept")
+		w.WriteHeader(status)
+	}))
+	defer server.Close()
+
+	req := transporter.Request{
+		Method:   http.MethodPost,
+		Endpoint: server.URL,
+		Headers:  map[string]string{contentType: "application/cloudevents+json"},
+		Body:     map[string]string{"id": "1"},
+	}
+
+	assert.NoError(t, appHttp.DoHttpRequest(context.Background(), req, nil), "every 2xx status succeeds")
+
+	status = http.StatusServiceUnavailable
+	err := appHttp.DoHttpRequest(context.Background(), req, nil)
+
+	var statusErr *transporter.StatusError
+	assert.ErrorAs(t, err, &statusErr)
+	assert.Equal(t, http.StatusServiceUnavailable, statusErr.StatusCode)
+}
+
+// flakyServer answers with status, and the headers of headers, until
+// failures attempts failed.
+func flakyServer(t *testing.T, failures int32, status int, headers map[string]string) (*httptest.Server, *atomic.Int32, *[]string) {
+	var attempts atomic.Int32
+	var bodies []string
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		bodies = append(bodies, string(body))
+
+		if attempts.Add(1) <= failures {
+			for key, value := range headers {
+				w.Header().Set(key, value)
+			}
+			w.WriteHeader(status)
+			return
+		}
+
+		json.NewEncoder(w).Encode(map[string]bool{"success": true})
+	}))
+	t.Cleanup(server.Close)
+
+	return server, &attempts, &bodies
+}
+
+func TestDoHttpRequestRetries(t *testing.T) {
+	logger := zerolog.Nop()
+	appHttp := transporter.NewClient(&logger, transporter.WithConfig(transporter.Config{Retry: transporter.RetryConfig{MaxAttempts: 3, RetryBackoff: time.Millisecond}}))
+
+	t.Run("idempotent methods are retried on 5xx", func(t *testing.T) {
+		server, attempts, bodies := flakyServer(t, 2, http.StatusServiceUnavailable, nil)
+
+		var res struct {
+			Success bool `json:"success"`
+		}
+		err := appHttp.DoHttpRequest(context.Background(), transporter.Request{Method: http.MethodPut, Endpoint: server.URL, Body: map[string]string{"key": "value"}}, &res)
+
+		require.NoError(t, err)
+		assert.True(t, res.Success)
+		assert.Equal(t, int32(3), attempts.Load())
+		assert.Equal(t, []string{`{"key":"value"}`, `{"key":"value"}`, `{"key":"value"}`}, *bodies, "the body is sent again")
+	})
+
+	t.Run("the last error is returned after the max attempts", func(t *testing.T) {
+		server, attempts, _ := flakyServer(t, 5, http.StatusBadGateway, nil)
+
+		err := appHttp.DoHttpRequest(context.Background(), transporter.Request{Method: http.MethodGet, Endpoint: server.URL}, nil)
+
+		var statusErr *transporter.StatusError
+		require.ErrorAs(t, err, &statusErr)
+		assert.Equal(t, http.StatusBadGateway, statusErr.StatusCode)
+		assert.Equal(t, int32(3), attempts.Load())
+	})
+
+	t.Run("other statuses are not retried", func(t *testing.T) {
+		server, attempts, _ := flakyServer(t, 1, http.StatusBadRequest, nil)
+
+		assert.Error(t, appHttp.DoHttpRequest(context.Background(), transporter.Request{Method: http.MethodGet, Endpoint: server.URL}, nil))
+		assert.Equal(t, int32(1), attempts.Load())
+	})
+
+	t.Run("POST is only retried when idempotent", func(t *testing.T) {
+		server, attempts, _ := flakyServer(t, 1, http.StatusServiceUnavailable, nil)
+		req := transporter.Request{Method: http.MethodPost, Endpoint: server.URL, Body: map[string]string{"key": "value"}}
+
+		assert.Error(t, appHttp.DoHttpRequest(context.Background(), req, nil))
+		assert.Equal(t, int32(1), attempts.Load())
+
+		attempts.Store(0)
+		req.Idempotent = true
+
+		assert.NoError(t, appHttp.DoHttpRequest(context.Background(), req, nil))
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+
+	t.Run("multipart files are sent again", func(t *testing.T) {
+		server, attempts, bodies := flakyServer(t, 1, http.StatusInternalServerError, nil)
+
+		err := appHttp.DoHttpRequest(context.Background(), transporter.Request{
+			Method:     http.MethodPost,
+			Endpoint:   server.URL,
+			Files:      map[string]transporter.File{"file": {FileName: "report.txt", File: strings.NewReader("This is a test file.")}},
+			Idempotent: true,
+		}, nil)
+
+		require.NoError(t, err)
+		assert.Equal(t, int32(2), attempts.Load())
+		assert.Contains(t, (*bodies)[1], "This is a test file.")
+		assert.Equal(t, (*bodies)[0], (*bodies)[1])
+	})
+
+	t.Run("connection errors are retried", func(t *testing.T) {
+		var attempts atomic.Int32
+		server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if attempts.Add(1) == 1 {
+				conn, _, _ := w.(http.Hijacker).Hijack()
+				conn.Close()
+				return
+			}
+
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer server.Close()
+
+		assert.NoError(t, appHttp.DoHttpRequest(context.Background(), transporter.Request{Method: http.MethodGet, Endpoint: server.URL}, nil))
+		assert.Equal(t, int32(2), attempts.Load())
+	})
+}
+
+func TestDoHttpRequestRetryAfter(t *testing.T) {
+	logger := zerolog.Nop()
+	appHttp := transporter.NewClient(&logger, transporter.WithConfig(transporter.Config{Retry: transporter.RetryConfig{RetryBackoff: time.Millisecond, MaxRetryAfter: 2 * time.Second}}))
+
+	server, attempts, _ := flakyServer(t, 1, http.StatusTooManyRequests, map[string]string{"Retry-After": "1"})
+
+	start := time.Now()
+	require.NoError(t, appHttp.DoHttpRequest(context.Background(), transporter.Request{Method: http.MethodGet, Endpoint: server.URL}, nil))
+	assert.GreaterOrEqual(t, time.Since(start), time.Second, "the retry waits for Retry-After")
+	assert.Equal(t, int32(2), attempts.Load())
+
+	server, attempts, _ = flakyServer(t, 1, http.StatusTooManyRequests, map[string]string{"Retry-After": "60"})
+
+	err := appHttp.DoHttpRequest(context.Background(), transporter.Request{Method: http.MethodGet, Endpoint: server.URL}, nil)
+	assert.Error(t, err, "a Retry-After longer than MaxRetryAfter fails the request")
+	assert.Equal(t, int32(1), attempts.Load())
+
+	server, attempts, _ = flakyServer(t, 1, http.StatusServiceUnavailable, map[string]string{"Retry-After": "1"})
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	assert.Error(t, appHttp.DoHttpRequest(ctx, transporter.Request{Method: http.MethodGet, Endpoint: server.URL}, nil), "no retry past the deadline of the context")
+	assert.Equal(t, int32(1), attempts.Load())
+}
@@ -0,0 +1,259 @@
+package webhooks
+
+import (
+	"errors"
+	"strconv"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/observability/instrumentation"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/labstack/echo/v4"
+)
+
+// defaultAttemptsLimit is the number of attempts listed without a limit.
+const defaultAttemptsLimit = 50
+
+// SubscriptionRequest creates or updates a subscription.
+type SubscriptionRequest struct {
+	URL    string   `json:"url"`
+	Events []string `json:"events"`
+	// Secret signs the requests, random when empty. It is ignored on update.
+	Secret string `json:"secret"`
+	// Enabled enables or disables the subscription on update.
+	Enabled *bool `json:"enabled"`
+}
+
+type adminHandlers struct {
+	store      *Store
+	dispatcher *Dispatcher
+	admins     []int64
+}
+
+// NewAdminHandlers creates the handlers of the admin API, which manages the
+// subscriptions and their deliveries on behalf of the users of admins.
+func NewAdminHandlers(store *Store, dispatcher *Dispatcher, admins []int64) *adminHandlers {
+	return &adminHandlers{store: store, dispatcher: dispatcher, admins: admins}
+}
+
+func (h *adminHandlers) MapRoutes(api *echo.Group, mw *middleware.Middleware) {
+	admin := api.Group("/admin/webhooks", mw.JWTMiddleware(), middleware.RequireAdmin(h.admins))
+
+	admin.POST("", h.CreateHandler)
+	admin.GET("", h.ListHandler)
+	admin.GET("/:id", h.GetHandler)
+	admin.PUT("/:id", h.UpdateHandler)
+	admin.DELETE("/:id", h.DeleteHandler)
+	admin.GET("/:id/deliveries", h.AttemptsHandler)
+	admin.POST("/:id/deliveries/:delivery/redeliver", h.RedeliverHandler)
+}
+
+// @Summary		Create Webhook Subscription
+// @Description	Subscribe a URL to events, the response holds the signing secret
+// @ID			create-webhook
+// @Tags		Webhooks
+// @Accept		json
+// @Produce		json
+// @Param		request	body		webhooks.SubscriptionRequest				true	"Subscription"
+// @Success		200		{object}	response.Success{data=webhooks.Subscription}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse					"BAD_REQUEST"
+// @Router		/admin/webhooks [post]
+// @Security	BearerToken
+func (h *adminHandlers) CreateHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "CreateWebhookHandler")
+	defer span.End()
+
+	var request SubscriptionRequest
+	if err := c.Bind(&request); err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	sub := Subscription{URL: request.URL, Events: request.Events, Secret: request.Secret}
+	if err := sub.validate(h.dispatcher.Events()); err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	sub, err := h.store.Create(ctx, sub)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(sub).Send(c)
+}
+
+// @Summary		Webhook Subscriptions
+// @Description	List the webhook subscriptions, without their secrets
+// @ID			webhooks
+// @Tags		Webhooks
+// @Produce		json
+// @Success		200		{object}	response.Success{data=[]webhooks.Subscription}	"SUCCESS"
+// @Router		/admin/webhooks [get]
+// @Security	BearerToken
+func (h *adminHandlers) ListHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "WebhooksHandler")
+	defer span.End()
+
+	subs, err := h.store.List(ctx)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	for i := range subs {
+		subs[i].Secret = ""
+	}
+
+	return response.SuccessBuilder(subs).Send(c)
+}
+
+// @Summary		Webhook Subscription
+// @Description	Webhook Subscription, without its secret
+// @ID			webhook
+// @Tags		Webhooks
+// @Produce		json
+// @Param		id		path		string										true	"Subscription ID"
+// @Success		200		{object}	response.Success{data=webhooks.Subscription}	"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse					"NOT_FOUND"
+// @Router		/admin/webhooks/{id} [get]
+// @Security	BearerToken
+func (h *adminHandlers) GetHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "WebhookHandler")
+	defer span.End()
+
+	sub, err := h.store.Get(ctx, c.Param("id"))
+	if err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	sub.Secret = ""
+
+	return response.SuccessBuilder(sub).Send(c)
+}
+
+// @Summary		Update Webhook Subscription
+// @Description	Change the URL and events of a subscription, or enable it again after it was disabled
+// @ID			update-webhook
+// @Tags		Webhooks
+// @Accept		json
+// @Produce		json
+// @Param		id		path		string										true	"Subscription ID"
+// @Param		request	body		webhooks.SubscriptionRequest				true	"Subscription"
+// @Success		200		{object}	response.Success{data=webhooks.Subscription}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse					"BAD_REQUEST"
+// @Failure		404		{object}	response.FailedResponse					"NOT_FOUND"
+// @Router		/admin/webhooks/{id} [put]
+// @Security	BearerToken
+func (h *adminHandlers) UpdateHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "UpdateWebhookHandler")
+	defer span.End()
+
+	var request SubscriptionRequest
+	if err := c.Bind(&request); err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	sub := Subscription{ID: c.Param("id"), URL: request.URL, Events: request.Events, Enabled: request.Enabled == nil || *request.Enabled}
+	if err := sub.validate(h.dispatcher.Events()); err != nil {
+		return response.ErrorBuilder(response.BadRequest(err)).Send(c)
+	}
+
+	sub, err := h.store.Update(ctx, sub)
+	if err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	sub.Secret = ""
+
+	return response.SuccessBuilder(sub).Send(c)
+}
+
+// @Summary		Delete Webhook Subscription
+// @Description	Delete Webhook Subscription
+// @ID			delete-webhook
+// @Tags		Webhooks
+// @Produce		json
+// @Param		id		path		string					true	"Subscription ID"
+// @Success		200		{object}	response.ResponseFormat	"SUCCESS"
+// @Failure		404		{object}	response.FailedResponse	"NOT_FOUND"
+// @Router		/admin/webhooks/{id} [delete]
+// @Security	BearerToken
+func (h *adminHandlers) DeleteHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "DeleteWebhookHandler")
+	defer span.End()
+
+	if err := h.store.Delete(ctx, c.Param("id")); err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(nil).Send(c)
+}
+
+// @Summary		Webhook Deliveries
+// @Description	List the delivery attempts of a subscription, the last first
+// @ID			webhook-deliveries
+// @Tags		Webhooks
+// @Produce		json
+// @Param		id		path		string									true	"Subscription ID"
+// @Param		limit	query		int										false	"Limit, 50 by default"
+// @Success		200		{object}	response.Success{data=[]webhooks.Attempt}	"SUCCESS"
+// @Failure		400		{object}	response.FailedResponse				"BAD_REQUEST"
+// @Failure		404		{object}	response.FailedResponse				"NOT_FOUND"
+// @Router		/admin/webhooks/{id}/deliveries [get]
+// @Security	BearerToken
+func (h *adminHandlers) AttemptsHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "WebhookDeliveriesHandler")
+	defer span.End()
+
+	limit := defaultAttemptsLimit
+	if value := c.QueryParam("limit"); value != "" {
+		var err error
+		if limit, err = strconv.Atoi(value); err != nil || limit <= 0 {
+			return response.ErrorBuilder(response.BadRequest(errors.New("limit must be a positive number"))).Send(c)
+		}
+	}
+
+	if _, err := h.store.Get(ctx, c.Param("id")); err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	attempts, err := h.store.Attempts(ctx, c.Param("id"), limit)
+	if err != nil {
+		return response.ErrorBuilder(response.InternalServerError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(attempts).Send(c)
+}
+
+// @Summary		Redeliver Webhook
+// @Description	Deliver the event of a logged delivery again, with the same event ID
+// @ID			redeliver-webhook
+// @Tags		Webhooks
+// @Produce		json
+// @Param		id			path		string					true	"Subscription ID"
+// @Param		delivery	path		string					true	"Delivery ID"
+// @Success		200			{object}	response.Success{data=string}	"SUCCESS"
+// @Failure		404			{object}	response.FailedResponse	"NOT_FOUND"
+// @Failure		409			{object}	response.FailedResponse	"CONFLICT"
+// @Router		/admin/webhooks/{id}/deliveries/{delivery}/redeliver [post]
+// @Security	BearerToken
+func (h *adminHandlers) RedeliverHandler(c echo.Context) error {
+	ctx, span := instrumentation.NewTraceSpan(c.Request().Context(), "RedeliverWebhookHandler")
+	defer span.End()
+
+	deliveryID, err := h.dispatcher.Redeliver(ctx, c.Param("id"), c.Param("delivery"))
+	if err != nil {
+		return response.ErrorBuilder(storeError(err)).Send(c)
+	}
+
+	return response.SuccessBuilder(deliveryID).Send(c)
+}
+
+// storeError maps the errors of the store to responses.
+func storeError(err error) error {
+	switch {
+	case errors.Is(err, ErrSubscriptionNotFound), errors.Is(err, ErrDeliveryNotFound):
+		return response.NotFound(err)
+	case errors.Is(err, ErrSubscriptionDisabled):
+		return response.Conflict(err)
+	default:
+		return response.InternalServerError(err)
+	}
+}
@@ -0,0 +1,98 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jwt"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/middleware"
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/labstack/echo/v4"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAdminHandlers(t *testing.T) {
+	d, store := newTestDispatcher(t, Config{})
+	r := newReceiver(t)
+
+	jwtFactory := jwt.NewJWTFactory(jwt.JWTConfig{Key: "secret-key", ExpiredInSecond: 3600}, appRedis.NewRedisManager(store.client))
+
+	tokens := make(map[int64]string)
+	for _, id := range []int64{1, 2} {
+		token, err := jwtFactory.CreateJWT(&jwt.JWTClaims{Data: &jwt.Data{ID: id, ContactType: types.CONTACT_TYPE_EMAIL}})
+		require.NoError(t, err)
+		tokens[id] = token
+	}
+
+	e := echo.New()
+	NewAdminHandlers(store, d, []int64{1}).MapRoutes(e.Group("/api/v1"), middleware.New(jwtFactory))
+
+	do := func(method, path, body string, userID int64) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(method, "/api/v1/admin/webhooks"+path, strings.NewReader(body))
+		req.Header.Set(echo.HeaderContentType, echo.MIMEApplicationJSON)
+		req.Header.Set(types.AuthorizationHeaderKey.String(), "Bearer "+tokens[userID])
+
+		rec := httptest.NewRecorder()
+		e.ServeHTTP(rec, req)
+
+		return rec
+	}
+
+	assert.Equal(t, http.StatusForbidden, do(http.MethodGet, "", "", 2).Code, "only admins manage the subscriptions")
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodPost, "", `{"url":"`+r.URL+`","events":["user.deleted"]}`, 1).Code)
+
+	rec := do(http.MethodPost, "", `{"url":"`+r.URL+`","events":["user.signed_up"]}`, 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var created struct {
+		Data Subscription `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &created))
+	assert.NotEmpty(t, created.Data.Secret, "the secret is returned on creation")
+	id := created.Data.ID
+
+	rec = do(http.MethodGet, "", "", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"id":"`+id+`"`)
+	assert.NotContains(t, rec.Body.String(), created.Data.Secret)
+
+	rec = do(http.MethodPut, "/"+id, `{"url":"`+r.URL+`","events":["user.signed_up","user.status_changed"],"enabled":true}`, 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+	assert.Contains(t, rec.Body.String(), `"events":["user.signed_up","user.status_changed"]`)
+	assert.NotContains(t, rec.Body.String(), created.Data.Secret)
+
+	require.NoError(t, d.Publish(context.Background(), message(7, statusChanged)))
+	require.Eventually(t, func() bool {
+		attempts, err := store.Attempts(context.Background(), id, 1)
+		return err == nil && len(attempts) == 1
+	}, 2*time.Second, 5*time.Millisecond)
+
+	rec = do(http.MethodGet, "/"+id+"/deliveries?limit=5", "", 1)
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var attempts struct {
+		Data []Attempt `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &attempts))
+	require.Len(t, attempts.Data, 1)
+	assert.Equal(t, StatusSucceeded, attempts.Data[0].Status)
+
+	assert.Equal(t, http.StatusBadRequest, do(http.MethodGet, "/"+id+"/deliveries?limit=x", "", 1).Code)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodPost, "/"+id+"/deliveries/"+attempts.Data[0].DeliveryID+"/redeliver", "", 1).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodPost, "/"+id+"/deliveries/missing/redeliver", "", 1).Code)
+
+	require.Equal(t, http.StatusOK, do(http.MethodPut, "/"+id, `{"url":"`+r.URL+`","events":["user.signed_up"],"enabled":false}`, 1).Code)
+	assert.Equal(t, http.StatusConflict, do(http.MethodPost, "/"+id+"/deliveries/"+attempts.Data[0].DeliveryID+"/redeliver", "", 1).Code)
+
+	assert.Equal(t, http.StatusOK, do(http.MethodDelete, "/"+id, "", 1).Code)
+	assert.Equal(t, http.StatusNotFound, do(http.MethodGet, "/"+id, "", 1).Code)
+}
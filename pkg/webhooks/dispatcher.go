@@ -0,0 +1,235 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/transporter"
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+// deliverJobType is the job type delivering an event to a subscription.
+const deliverJobType = "webhooks.deliver"
+
+// delivery is the payload of a delivery job.
+type delivery struct {
+	SubscriptionID string          `json:"subscription_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Event          json.RawMessage `json:"event"`
+}
+
+// Dispatcher enqueues a delivery of every outbox event to the subscriptions of
+// its type, and delivers them from the jobs queue.
+type Dispatcher struct {
+	cfg    Config
+	store  *Store
+	events []string
+	jobs   *jobs.Client
+	http   *transporter.AppHttp
+	log    *zerolog.Logger
+}
+
+// NewDispatcher creates a dispatcher of the event types of events, sending the
+// requests with appHttp.
+func NewDispatcher(cfg Config, store *Store, events []string, client *jobs.Client, appHttp *transporter.AppHttp, log *zerolog.Logger) *Dispatcher {
+	if log == nil {
+		nop := zerolog.Nop()
+		log = &nop
+	}
+
+	return &Dispatcher{cfg: cfg.withDefaults(), store: store, events: events, jobs: client, http: appHttp, log: log}
+}
+
+// Events returns the event types subscriptions can receive.
+func (d *Dispatcher) Events() []string {
+	return d.events
+}
+
+// Register makes worker deliver the webhooks.
+func (d *Dispatcher) Register(worker *jobs.Worker) {
+	worker.HandleFunc(d.cfg.Queue, deliverJobType, d.deliver)
+}
+
+// Publish enqueues a delivery of message to every enabled subscription of its
+// event type. It implements outbox.Publisher. A message the relay publishes
+// again, e.g. because another publisher failed, is only enqueued for the
+// subscriptions it was not enqueued for yet.
+func (d *Dispatcher) Publish(ctx context.Context, message outbox.Message) error {
+	if !slices.Contains(d.events, message.EventType) {
+		return nil
+	}
+
+	subs, err := d.store.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	event, err := json.Marshal(CloudEvent{
+		SpecVersion:     "1.0",
+		ID:              strconv.FormatInt(message.ID, 10),
+		Source:          d.cfg.Source,
+		Type:            message.EventType,
+		Subject:         message.AggregateID,
+		Time:            message.CreatedAt.UTC(),
+		DataContentType: "application/json",
+		Data:            message.Payload,
+	})
+	if err != nil {
+		return err
+	}
+
+	eventID := strconv.FormatInt(message.ID, 10)
+	for _, sub := range subs {
+		if !sub.Subscribes(message.EventType) {
+			continue
+		}
+
+		claimed, err := d.store.claimDelivery(ctx, eventID, sub.ID)
+		if err != nil {
+			return err
+		}
+
+		if !claimed {
+			continue
+		}
+
+		if _, err := d.enqueue(ctx, delivery{SubscriptionID: sub.ID, EventID: eventID, EventType: message.EventType, Event: event}); err != nil {
+			return errors.Join(err, d.store.releaseDelivery(ctx, eventID, sub.ID))
+		}
+	}
+
+	return nil
+}
+
+// Redeliver delivers again the event of the delivery deliveryID of the
+// subscription id, and returns the ID of the new delivery.
+func (d *Dispatcher) Redeliver(ctx context.Context, id, deliveryID string) (string, error) {
+	sub, err := d.store.Get(ctx, id)
+	if err != nil {
+		return "", err
+	}
+
+	if !sub.Enabled {
+		return "", ErrSubscriptionDisabled
+	}
+
+	attempts, err := d.store.Attempts(ctx, id, d.cfg.LogSize)
+	if err != nil {
+		return "", err
+	}
+
+	for _, attempt := range attempts {
+		if attempt.DeliveryID == deliveryID {
+			return d.enqueue(ctx, delivery{SubscriptionID: id, EventID: attempt.EventID, EventType: attempt.EventType, Event: attempt.Event})
+		}
+	}
+
+	return "", ErrDeliveryNotFound
+}
+
+func (d *Dispatcher) enqueue(ctx context.Context, payload delivery) (string, error) {
+	job, err := d.jobs.Enqueue(ctx, d.cfg.Queue, deliverJobType, payload)
+
+	return job.ID, err
+}
+
+// deliver sends the event of job to its subscription and logs the attempt. A
+// failed attempt is retried, unless it disables the subscription.
+func (d *Dispatcher) deliver(ctx context.Context, job jobs.Job) error {
+	var payload delivery
+	if err := json.Unmarshal(job.Payload, &payload); err != nil {
+		return jobs.Permanent(fmt.Errorf("decode delivery: %w", err))
+	}
+
+	sub, err := d.store.Get(ctx, payload.SubscriptionID)
+	if errors.Is(err, ErrSubscriptionNotFound) {
+		return jobs.Permanent(err)
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if !sub.Enabled {
+		return jobs.Permanent(ErrSubscriptionDisabled)
+	}
+
+	timestamp := time.Now().Unix()
+	start := time.Now()
+
+	err = d.http.DoHttpRequest(ctx, transporter.Request{
+		Method:   http.MethodPost,
+		Endpoint: sub.URL,
+		Headers: map[string]string{
+			echo.HeaderContentType: ContentType,
+			HeaderDelivery:         job.ID,
+			HeaderTimestamp:        strconv.FormatInt(timestamp, 10),
+			HeaderSignature:        Sign(sub.Secret, timestamp, payload.Event),
+		},
+		Body: payload.Event,
+	}, nil)
+
+	attempt := Attempt{
+		ID:             uuid.NewString(),
+		DeliveryID:     job.ID,
+		SubscriptionID: sub.ID,
+		EventID:        payload.EventID,
+		EventType:      payload.EventType,
+		Attempt:        job.Attempt + 1,
+		Status:         StatusSucceeded,
+		Duration:       time.Since(start),
+		At:             start,
+		Event:          payload.Event,
+	}
+
+	if err != nil {
+		attempt.Status, attempt.Error = StatusFailed, err.Error()
+
+		var statusErr *transporter.StatusError
+		if errors.As(err, &statusErr) {
+			attempt.StatusCode = statusErr.StatusCode
+		}
+	}
+
+	if logErr := d.store.Log(ctx, attempt); logErr != nil {
+		d.log.Err(logErr).Ctx(ctx).Str("subscription_id", sub.ID).Msg("[Webhooks]Log")
+	}
+
+	if err == nil {
+		// The event was delivered, retrying would deliver it again.
+		if resetErr := d.store.ResetFailures(ctx, sub.ID); resetErr != nil {
+			d.log.Err(resetErr).Ctx(ctx).Str("subscription_id", sub.ID).Msg("[Webhooks]ResetFailures")
+		}
+
+		return nil
+	}
+
+	failures, failErr := d.store.RecordFailure(ctx, sub.ID)
+	if failErr != nil {
+		return errors.Join(err, failErr)
+	}
+
+	if failures >= int64(d.cfg.DisableAfter) {
+		reason := fmt.Sprintf("disabled after %d consecutive failed attempts", failures)
+		if disableErr := d.store.Disable(ctx, sub.ID, reason); disableErr != nil {
+			return errors.Join(err, disableErr)
+		}
+
+		d.log.Warn().Ctx(ctx).Str("subscription_id", sub.ID).Str("url", sub.URL).Int64("failures", failures).Msg("[Webhooks]Disable")
+
+		return jobs.Permanent(err)
+	}
+
+	return err
+}
@@ -0,0 +1,65 @@
+package webhooks
+
+import (
+	"context"
+	"errors"
+
+	"gorm.io/gorm"
+)
+
+// Repository keeps the subscriptions.
+type Repository interface {
+	Create(ctx context.Context, sub Subscription) error
+	// Get returns ErrSubscriptionNotFound for a subscription that does not exist.
+	Get(ctx context.Context, id string) (Subscription, error)
+	// List returns the subscriptions, the oldest first.
+	List(ctx context.Context) ([]Subscription, error)
+	// Update saves the URL, events and state of sub. MySQL does not count the
+	// rows an update leaves unchanged, so a missing subscription is not reported.
+	Update(ctx context.Context, sub Subscription) error
+	// Delete returns ErrSubscriptionNotFound for a subscription that does not exist.
+	Delete(ctx context.Context, id string) error
+}
+
+type gormRepository struct {
+	db *gorm.DB
+}
+
+// NewGormRepository creates a Repository backed by the webhook_subscriptions table.
+func NewGormRepository(db *gorm.DB) Repository {
+	return &gormRepository{db: db}
+}
+
+func (r *gormRepository) Create(ctx context.Context, sub Subscription) error {
+	return r.db.WithContext(ctx).Create(&sub).Error
+}
+
+func (r *gormRepository) Get(ctx context.Context, id string) (sub Subscription, err error) {
+	err = r.db.WithContext(ctx).Where("id = ?", id).Take(&sub).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return sub, ErrSubscriptionNotFound
+	}
+
+	return sub, err
+}
+
+func (r *gormRepository) List(ctx context.Context) (subs []Subscription, err error) {
+	err = r.db.WithContext(ctx).Order("created_at ASC").Order("id ASC").Find(&subs).Error
+
+	return subs, err
+}
+
+func (r *gormRepository) Update(ctx context.Context, sub Subscription) error {
+	return r.db.WithContext(ctx).Model(&Subscription{}).Where("id = ?", sub.ID).
+		Select("url", "events", "enabled", "disabled_reason", "updated_at").
+		Updates(&sub).Error
+}
+
+func (r *gormRepository) Delete(ctx context.Context, id string) error {
+	result := r.db.WithContext(ctx).Where("id = ?", id).Delete(&Subscription{})
+	if result.Error == nil && result.RowsAffected == 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	return result.Error
+}
@@ -0,0 +1,75 @@
+package webhooks
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	gormmysql "gorm.io/driver/mysql"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+func newMockRepository(t *testing.T) (Repository, sqlmock.Sqlmock) {
+	t.Helper()
+
+	sqlDB, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() { sqlDB.Close() })
+
+	db, err := gorm.Open(
+		gormmysql.New(gormmysql.Config{Conn: sqlDB, SkipInitializeWithVersion: true}),
+		&gorm.Config{SkipDefaultTransaction: true, Logger: logger.Default.LogMode(logger.Silent)},
+	)
+	require.NoError(t, err)
+
+	return NewGormRepository(db), mock
+}
+
+func TestGormRepository_GetDecodesEvents(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	mock.ExpectQuery("SELECT \\* FROM `webhook_subscriptions` WHERE id = \\? LIMIT \\?").
+		WithArgs("sub-1", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "url", "events", "secret", "enabled"}).
+			AddRow("sub-1", "https://example.com/hook", `["user.signed_up"]`, "secret", true))
+	mock.ExpectQuery("SELECT \\* FROM `webhook_subscriptions` WHERE id = \\? LIMIT \\?").
+		WithArgs("missing", 1).
+		WillReturnRows(sqlmock.NewRows([]string{"id"}))
+
+	sub, err := repo.Get(context.Background(), "sub-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{signedUp}, sub.Events)
+
+	_, err = repo.Get(context.Background(), "missing")
+	assert.ErrorIs(t, err, ErrSubscriptionNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormRepository_UpdateSavesDisabledState(t *testing.T) {
+	repo, mock := newMockRepository(t)
+	sub := Subscription{ID: "sub-1", URL: "https://example.com/hook", Events: []string{signedUp}, UpdatedAt: time.Now()}
+
+	// Enabled is false, it must be written all the same.
+	mock.ExpectExec("UPDATE `webhook_subscriptions` SET `url`=\\?,`events`=\\?,`enabled`=\\?,`disabled_reason`=\\?,`updated_at`=\\? WHERE id = \\?").
+		WithArgs(sub.URL, `["user.signed_up"]`, false, "failing", sqlmock.AnyArg(), sub.ID).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+
+	sub.DisabledReason = "failing"
+	require.NoError(t, repo.Update(context.Background(), sub))
+	require.NoError(t, mock.ExpectationsWereMet())
+}
+
+func TestGormRepository_DeleteMissing(t *testing.T) {
+	repo, mock := newMockRepository(t)
+
+	mock.ExpectExec("DELETE FROM `webhook_subscriptions` WHERE id = \\?").
+		WithArgs("missing").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+
+	assert.ErrorIs(t, repo.Delete(context.Background(), "missing"), ErrSubscriptionNotFound)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
@@ -0,0 +1,299 @@
+package webhooks
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"net/url"
+	"slices"
+	"strconv"
+	"time"
+
+	appRedis "github.com/DoWithLogic/golang-clean-architecture/pkg/redis"
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+	"github.com/invopop/validation"
+)
+
+var (
+	// ErrSubscriptionNotFound is returned for a subscription that does not exist.
+	ErrSubscriptionNotFound = errors.New("webhook subscription not found")
+	// ErrSubscriptionDisabled is returned when delivering to a disabled subscription.
+	ErrSubscriptionDisabled = errors.New("webhook subscription disabled")
+	// ErrDeliveryNotFound is returned for a delivery missing from the log.
+	ErrDeliveryNotFound = errors.New("webhook delivery not found")
+)
+
+// failuresKey is the Redis hash of the consecutive failed attempts, by subscription ID.
+var failuresKey = appRedis.REDIS_PREFIX_KEY_WEBHOOKS.Key("failures")
+
+// Subscription sends the events of Events to URL.
+type Subscription struct {
+	ID     string   `json:"id" gorm:"column:id;primaryKey"`
+	URL    string   `json:"url" gorm:"column:url"`
+	Events []string `json:"events" gorm:"column:events;serializer:json"`
+	// Secret signs the requests. It is only returned when the subscription is
+	// created.
+	Secret  string `json:"secret,omitempty" gorm:"column:secret"`
+	Enabled bool   `json:"enabled" gorm:"column:enabled"`
+	// DisabledReason explains why the subscription was disabled automatically.
+	DisabledReason string `json:"disabled_reason,omitempty" gorm:"column:disabled_reason"`
+	// ConsecutiveFailures counts the failed attempts since the last success,
+	// kept in Redis.
+	ConsecutiveFailures int64     `json:"consecutive_failures" gorm:"-"`
+	CreatedAt           time.Time `json:"created_at" gorm:"column:created_at"`
+	UpdatedAt           time.Time `json:"updated_at" gorm:"column:updated_at"`
+}
+
+func (Subscription) TableName() string { return "webhook_subscriptions" }
+
+// validate checks the subscription accepts the events of supported.
+func (s Subscription) validate(supported []string) error {
+	return validation.ValidateStruct(&s,
+		validation.Field(&s.URL, validation.Required, validation.By(isWebhookURL)),
+		validation.Field(&s.Events, validation.Required, validation.Each(validation.In(toAny(supported)...).Error("must be one of the supported events"))),
+		validation.Field(&s.Secret, validation.Length(16, 0)),
+	)
+}
+
+// isWebhookURL fails on a URL that is not absolute HTTP or HTTPS.
+func isWebhookURL(value any) error {
+	raw, _ := value.(string)
+
+	u, err := url.Parse(raw)
+	if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+		return errors.New("must be an absolute http or https URL")
+	}
+
+	return nil
+}
+
+func toAny(values []string) []any {
+	out := make([]any, len(values))
+	for i, value := range values {
+		out[i] = value
+	}
+
+	return out
+}
+
+// Subscribes reports whether the subscription receives the events of eventType.
+func (s Subscription) Subscribes(eventType string) bool {
+	return s.Enabled && slices.Contains(s.Events, eventType)
+}
+
+// Status is the outcome of an attempt.
+type Status string
+
+const (
+	StatusSucceeded Status = "succeeded"
+	StatusFailed    Status = "failed"
+)
+
+// Attempt is a logged attempt to deliver an event to a subscription.
+type Attempt struct {
+	ID             string `json:"id"`
+	DeliveryID     string `json:"delivery_id"` // Shared by the attempts of a delivery.
+	SubscriptionID string `json:"subscription_id"`
+	EventID        string `json:"event_id"`
+	EventType      string `json:"event_type"`
+	Attempt        int    `json:"attempt"`
+	Status         Status `json:"status"`
+	// StatusCode is the status of the response of a failed attempt, zero when
+	// the receiver did not answer.
+	StatusCode int           `json:"status_code,omitempty"`
+	Error      string        `json:"error,omitempty"`
+	Duration   time.Duration `json:"duration"` // In nanoseconds.
+	At         time.Time     `json:"at"`
+	// Event is the CloudEvent sent, delivered again on a manual redelivery.
+	Event json.RawMessage `json:"event"`
+}
+
+// Store keeps the subscriptions in repo, and their failures and attempts in
+// Redis.
+type Store struct {
+	repo    Repository
+	client  redis.UniversalClient
+	logSize int
+}
+
+func NewStore(repo Repository, client redis.UniversalClient, cfg Config) *Store {
+	return &Store{repo: repo, client: client, logSize: cfg.withDefaults().LogSize}
+}
+
+// Create creates sub enabled, with a random secret when it has none.
+func (s *Store) Create(ctx context.Context, sub Subscription) (Subscription, error) {
+	if sub.Secret == "" {
+		secret := make([]byte, 32)
+		if _, err := rand.Read(secret); err != nil {
+			return Subscription{}, err
+		}
+
+		sub.Secret = hex.EncodeToString(secret)
+	}
+
+	now := time.Now()
+	sub.ID, sub.Enabled, sub.CreatedAt, sub.UpdatedAt = uuid.NewString(), true, now, now
+
+	return sub, s.repo.Create(ctx, sub)
+}
+
+// Get returns the subscription id.
+func (s *Store) Get(ctx context.Context, id string) (Subscription, error) {
+	sub, err := s.repo.Get(ctx, id)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	failures, err := s.client.HGet(ctx, failuresKey, id).Int64()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		return Subscription{}, err
+	}
+
+	sub.ConsecutiveFailures = failures
+
+	return sub, nil
+}
+
+// List returns the subscriptions, the oldest first.
+func (s *Store) List(ctx context.Context) ([]Subscription, error) {
+	subs, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	failures, err := s.client.HGetAll(ctx, failuresKey).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range subs {
+		subs[i].ConsecutiveFailures, _ = strconv.ParseInt(failures[subs[i].ID], 10, 64)
+	}
+
+	return subs, nil
+}
+
+// Update saves the URL, events and state of sub. Enabling it again clears its
+// failures.
+func (s *Store) Update(ctx context.Context, sub Subscription) (Subscription, error) {
+	current, err := s.Get(ctx, sub.ID)
+	if err != nil {
+		return Subscription{}, err
+	}
+
+	if sub.Enabled && !current.Enabled {
+		if err := s.ResetFailures(ctx, sub.ID); err != nil {
+			return Subscription{}, err
+		}
+
+		current.DisabledReason, current.ConsecutiveFailures = "", 0
+	}
+
+	current.URL, current.Events, current.Enabled, current.UpdatedAt = sub.URL, sub.Events, sub.Enabled, time.Now()
+
+	return current, s.repo.Update(ctx, current)
+}
+
+// Disable disables the subscription id for reason.
+func (s *Store) Disable(ctx context.Context, id, reason string) error {
+	sub, err := s.Get(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	sub.Enabled, sub.DisabledReason, sub.UpdatedAt = false, reason, time.Now()
+
+	return s.repo.Update(ctx, sub)
+}
+
+// Delete deletes the subscription id with its failures and attempts.
+func (s *Store) Delete(ctx context.Context, id string) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+
+	_, err := s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.HDel(ctx, failuresKey, id)
+		pipe.Del(ctx, attemptsKey(id))
+		return nil
+	})
+
+	return err
+}
+
+// RecordFailure counts a failed attempt of the subscription id and returns the
+// consecutive failures.
+func (s *Store) RecordFailure(ctx context.Context, id string) (int64, error) {
+	return s.client.HIncrBy(ctx, failuresKey, id, 1).Result()
+}
+
+// ResetFailures clears the consecutive failures of the subscription id.
+func (s *Store) ResetFailures(ctx context.Context, id string) error {
+	return s.client.HDel(ctx, failuresKey, id).Err()
+}
+
+// Log adds attempt to the log of its subscription, trimmed to Config.LogSize.
+func (s *Store) Log(ctx context.Context, attempt Attempt) error {
+	raw, err := json.Marshal(attempt)
+	if err != nil {
+		return err
+	}
+
+	key := attemptsKey(attempt.SubscriptionID)
+	_, err = s.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		pipe.LPush(ctx, key, raw)
+		pipe.LTrim(ctx, key, 0, int64(s.logSize-1))
+		return nil
+	})
+
+	return err
+}
+
+// Attempts returns the last limit attempts of the subscription id, the last first.
+func (s *Store) Attempts(ctx context.Context, id string, limit int) ([]Attempt, error) {
+	values, err := s.client.LRange(ctx, attemptsKey(id), 0, int64(limit-1)).Result()
+	if err != nil {
+		return nil, err
+	}
+
+	attempts := make([]Attempt, 0, len(values))
+	for _, value := range values {
+		var attempt Attempt
+		if err := json.Unmarshal([]byte(value), &attempt); err != nil {
+			return nil, err
+		}
+
+		attempts = append(attempts, attempt)
+	}
+
+	return attempts, nil
+}
+
+// claimDelivery marks the delivery of the event eventID to the subscription id
+// as enqueued, and reports whether it was not already.
+func (s *Store) claimDelivery(ctx context.Context, eventID, id string) (bool, error) {
+	return s.client.SetNX(ctx, deliveredKey(eventID, id), "1", deliveredTTL).Result()
+}
+
+// releaseDelivery lets the event eventID be enqueued again for the subscription id.
+func (s *Store) releaseDelivery(ctx context.Context, eventID, id string) error {
+	return s.client.Del(ctx, deliveredKey(eventID, id)).Err()
+}
+
+// deliveredTTL is how long an enqueued delivery is remembered. The outbox relay
+// stops publishing a message again long before.
+const deliveredTTL = 7 * 24 * time.Hour
+
+// deliveredKey marks the delivery of the event eventID to the subscription id
+// as enqueued.
+func deliveredKey(eventID, id string) string {
+	return appRedis.REDIS_PREFIX_KEY_WEBHOOKS.Key(id + ":delivered:" + eventID)
+}
+
+// attemptsKey is the Redis list of the attempts of the subscription id.
+func attemptsKey(id string) string {
+	return appRedis.REDIS_PREFIX_KEY_WEBHOOKS.Key(id + ":attempts")
+}
@@ -0,0 +1,95 @@
+// Package webhooks pushes the domain events of the outbox to the URLs of the
+// subscriptions managed through the admin API.
+//
+// Every event is sent as a structured CloudEvents JSON document, signed with
+// the secret of the subscription:
+//
+//	X-Webhook-Timestamp: 1767225600
+//	X-Webhook-Signature: sha256=<hex HMAC-SHA256 of "<timestamp>.<body>">
+//
+// Receivers recompute the signature with Sign, reject old timestamps to stop
+// replays, and deduplicate on the event ID since delivery is at least once.
+// Failed deliveries are retried by the jobs of the webhooks queue, every
+// attempt is logged, and a subscription failing DisableAfter times in a row
+// is disabled until it is enabled again.
+package webhooks
+
+import (
+	"encoding/json"
+	"strconv"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/invopop/validation"
+)
+
+// Headers of the webhook requests.
+const (
+	HeaderDelivery  = "X-Webhook-Delivery"
+	HeaderTimestamp = "X-Webhook-Timestamp"
+	HeaderSignature = "X-Webhook-Signature"
+)
+
+// ContentType is the media type of structured CloudEvents in JSON.
+const ContentType = "application/cloudevents+json"
+
+// DefaultQueue is the jobs queue delivering the webhooks.
+const DefaultQueue = "webhooks"
+
+// Config holds the configuration of the webhooks.
+type Config struct {
+	Enable bool   // Sends the outbox events to the subscriptions.
+	Queue  string // The jobs queue delivering the webhooks. Defaults to webhooks.
+	// Source is the CloudEvents source of the events. Defaults to
+	// /golang-clean-architecture.
+	Source string
+	// DisableAfter is the number of consecutive failed attempts disabling a
+	// subscription. Defaults to 20.
+	DisableAfter int
+	LogSize      int // The number of attempts kept per subscription. Defaults to 100.
+}
+
+func (c Config) Validate() error {
+	return validation.ValidateStruct(&c,
+		validation.Field(&c.DisableAfter, validation.Min(0)),
+		validation.Field(&c.LogSize, validation.Min(0)),
+	)
+}
+
+func (c Config) withDefaults() Config {
+	if c.Queue == "" {
+		c.Queue = DefaultQueue
+	}
+
+	if c.Source == "" {
+		c.Source = "/golang-clean-architecture"
+	}
+
+	if c.DisableAfter <= 0 {
+		c.DisableAfter = 20
+	}
+
+	if c.LogSize <= 0 {
+		c.LogSize = 100
+	}
+
+	return c
+}
+
+// CloudEvent is a CloudEvents 1.0 event in structured JSON mode.
+type CloudEvent struct {
+	SpecVersion     string          `json:"specversion"`
+	ID              string          `json:"id"`     // The ID of the outbox message, unique per source.
+	Source          string          `json:"source"` // Config.Source.
+	Type            string          `json:"type"`   // The event type, e.g. user.signed_up.
+	Subject         string          `json:"subject,omitempty"`
+	Time            time.Time       `json:"time"`
+	DataContentType string          `json:"datacontenttype"`
+	Data            json.RawMessage `json:"data"`
+}
+
+// Sign returns the X-Webhook-Signature of body sent at timestamp, in Unix
+// seconds, to a subscription with secret.
+func Sign(secret string, timestamp int64, body []byte) string {
+	return "sha256=" + encryptions.NewCrypto(secret).EncodeSHA256HMAC(strconv.FormatInt(timestamp, 10), ".", string(body))
+}
@@ -0,0 +1,332 @@
+package webhooks
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/jobs"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/outbox"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/transporter"
+	"github.com/alicebob/miniredis/v2"
+	"github.com/go-redis/redis/v8"
+	"github.com/rs/zerolog"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	signedUp      = "user.signed_up"
+	statusChanged = "user.status_changed"
+)
+
+// receiver is a webhook endpoint failing the first failures requests.
+type receiver struct {
+	*httptest.Server
+	failures atomic.Int32
+
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func newReceiver(t *testing.T) *receiver {
+	r := &receiver{}
+	r.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		body, _ := io.ReadAll(req.Body)
+
+		r.mu.Lock()
+		r.requests, r.bodies = append(r.requests, req), append(r.bodies, body)
+		r.mu.Unlock()
+
+		if r.failures.Add(-1) >= 0 {
+			w.WriteHeader(http.StatusServiceUnavailable)
+			return
+		}
+
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	t.Cleanup(r.Close)
+
+	return r
+}
+
+func (r *receiver) received() ([]*http.Request, [][]byte) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*http.Request(nil), r.requests...), append([][]byte(nil), r.bodies...)
+}
+
+// memoryRepository keeps the subscriptions in memory, in creation order.
+type memoryRepository struct {
+	mu   sync.Mutex
+	subs []Subscription
+}
+
+func (r *memoryRepository) Create(_ context.Context, sub Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.subs = append(r.subs, sub)
+
+	return nil
+}
+
+func (r *memoryRepository) Get(_ context.Context, id string) (Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if i := r.index(id); i >= 0 {
+		return r.subs[i], nil
+	}
+
+	return Subscription{}, ErrSubscriptionNotFound
+}
+
+func (r *memoryRepository) List(context.Context) ([]Subscription, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return slices.Clone(r.subs), nil
+}
+
+func (r *memoryRepository) Update(_ context.Context, sub Subscription) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(sub.ID)
+	if i < 0 {
+		return nil
+	}
+
+	r.subs[i].URL, r.subs[i].Events, r.subs[i].Enabled, r.subs[i].DisabledReason, r.subs[i].UpdatedAt = sub.URL, sub.Events, sub.Enabled, sub.DisabledReason, sub.UpdatedAt
+
+	return nil
+}
+
+func (r *memoryRepository) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	i := r.index(id)
+	if i < 0 {
+		return ErrSubscriptionNotFound
+	}
+
+	r.subs = slices.Delete(r.subs, i, i+1)
+
+	return nil
+}
+
+func (r *memoryRepository) index(id string) int {
+	return slices.IndexFunc(r.subs, func(sub Subscription) bool { return sub.ID == id })
+}
+
+func newTestDispatcher(t *testing.T, cfg Config) (*Dispatcher, *Store) {
+	t.Helper()
+
+	mr := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: mr.Addr()})
+	t.Cleanup(func() { _ = client.Close() })
+
+	backend := jobs.NewMemoryBackend()
+	worker := jobs.NewWorker(jobs.Config{
+		PollInterval: 5 * time.Millisecond,
+		Queues:       []jobs.QueueConfig{{Name: DefaultQueue, MaxAttempts: 3, RetryBackoff: time.Millisecond, MaxRetryBackoff: time.Millisecond}},
+	}, backend, nil)
+
+	nop := zerolog.Nop()
+	store := NewStore(&memoryRepository{}, client, cfg)
+	d := NewDispatcher(cfg, store, []string{signedUp, statusChanged}, jobs.NewClient(backend), transporter.NewClient(&nop), nil)
+	d.Register(worker)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		_ = worker.Run(ctx)
+	}()
+	t.Cleanup(func() {
+		cancel()
+		<-done
+	})
+
+	return d, store
+}
+
+func message(id int64, eventType string) outbox.Message {
+	return outbox.Message{
+		ID:            id,
+		AggregateType: "user",
+		AggregateID:   "42",
+		EventType:     eventType,
+		Payload:       json.RawMessage(`{"user_id":42}`),
+		CreatedAt:     time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC),
+	}
+}
+
+func TestDispatcher_DeliversSignedCloudEvents(t *testing.T) {
+	d, store := newTestDispatcher(t, Config{})
+	r := newReceiver(t)
+	ctx := context.Background()
+
+	sub, err := store.Create(ctx, Subscription{URL: r.URL, Events: []string{signedUp}})
+	require.NoError(t, err)
+	require.Len(t, sub.Secret, 64)
+
+	require.NoError(t, d.Publish(ctx, message(7, signedUp)))
+	require.NoError(t, d.Publish(ctx, message(8, statusChanged)), "events the subscription misses are skipped")
+
+	require.Eventually(t, func() bool { requests, _ := r.received(); return len(requests) == 1 }, 2*time.Second, 5*time.Millisecond)
+
+	requests, bodies := r.received()
+	req, body := requests[0], bodies[0]
+
+	assert.Equal(t, http.MethodPost, req.Method)
+	assert.Equal(t, ContentType, req.Header.Get("Content-Type"))
+	assert.NotEmpty(t, req.Header.Get(HeaderDelivery))
+
+	timestamp, err := strconv.ParseInt(req.Header.Get(HeaderTimestamp), 10, 64)
+	require.NoError(t, err)
+	assert.Equal(t, Sign(sub.Secret, timestamp, body), req.Header.Get(HeaderSignature))
+
+	var event CloudEvent
+	require.NoError(t, json.Unmarshal(body, &event))
+	assert.Equal(t, "1.0", event.SpecVersion)
+	assert.Equal(t, "7", event.ID)
+	assert.Equal(t, "/golang-clean-architecture", event.Source)
+	assert.Equal(t, signedUp, event.Type)
+	assert.Equal(t, "42", event.Subject)
+	assert.JSONEq(t, `{"user_id":42}`, string(event.Data))
+
+	require.Eventually(t, func() bool {
+		attempts, err := store.Attempts(ctx, sub.ID, 10)
+		return err == nil && len(attempts) == 1 && attempts[0].Status == StatusSucceeded
+	}, 2*time.Second, 5*time.Millisecond)
+}
+
+func TestDispatcher_RetriesAndRedelivers(t *testing.T) {
+	d, store := newTestDispatcher(t, Config{})
+	r := newReceiver(t)
+	r.failures.Store(2)
+	ctx := context.Background()
+
+	sub, err := store.Create(ctx, Subscription{URL: r.URL, Events: []string{signedUp}})
+	require.NoError(t, err)
+
+	require.NoError(t, d.Publish(ctx, message(7, signedUp)))
+
+	var attempts []Attempt
+	require.Eventually(t, func() bool {
+		attempts, err = store.Attempts(ctx, sub.ID, 10)
+		return err == nil && len(attempts) == 3
+	}, 2*time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, StatusSucceeded, attempts[0].Status)
+	assert.Equal(t, 3, attempts[0].Attempt)
+	assert.Equal(t, StatusFailed, attempts[2].Status)
+	assert.Equal(t, http.StatusServiceUnavailable, attempts[2].StatusCode)
+	assert.Equal(t, attempts[0].DeliveryID, attempts[2].DeliveryID, "the attempts share the ID of the delivery")
+
+	sub, err = store.Get(ctx, sub.ID)
+	require.NoError(t, err)
+	assert.Zero(t, sub.ConsecutiveFailures, "a success resets the failures")
+
+	deliveryID, err := d.Redeliver(ctx, sub.ID, attempts[0].DeliveryID)
+	require.NoError(t, err)
+	assert.NotEqual(t, attempts[0].DeliveryID, deliveryID)
+
+	require.Eventually(t, func() bool { requests, _ := r.received(); return len(requests) == 4 }, 2*time.Second, 5*time.Millisecond)
+
+	_, bodies := r.received()
+	assert.JSONEq(t, string(bodies[2]), string(bodies[3]), "the same event is delivered again")
+
+	_, err = d.Redeliver(ctx, sub.ID, "missing")
+	assert.ErrorIs(t, err, ErrDeliveryNotFound)
+}
+
+func TestDispatcher_PublishesAgainOnlyToNewSubscriptions(t *testing.T) {
+	d, store := newTestDispatcher(t, Config{})
+	first, second := newReceiver(t), newReceiver(t)
+	ctx := context.Background()
+
+	_, err := store.Create(ctx, Subscription{URL: first.URL, Events: []string{signedUp}})
+	require.NoError(t, err)
+
+	require.NoError(t, d.Publish(ctx, message(7, signedUp)))
+
+	_, err = store.Create(ctx, Subscription{URL: second.URL, Events: []string{signedUp}})
+	require.NoError(t, err)
+
+	// The relay publishes the message again when another publisher failed.
+	require.NoError(t, d.Publish(ctx, message(7, signedUp)))
+
+	require.Eventually(t, func() bool { requests, _ := second.received(); return len(requests) == 1 }, 2*time.Second, 5*time.Millisecond)
+	time.Sleep(50 * time.Millisecond)
+
+	requests, _ := first.received()
+	assert.Len(t, requests, 1, "the event is not enqueued twice for a subscription")
+}
+
+func TestDispatcher_DisablesFailingSubscriptions(t *testing.T) {
+	d, store := newTestDispatcher(t, Config{DisableAfter: 2})
+	r := newReceiver(t)
+	r.failures.Store(100)
+	ctx := context.Background()
+
+	sub, err := store.Create(ctx, Subscription{URL: r.URL, Events: []string{signedUp}})
+	require.NoError(t, err)
+
+	require.NoError(t, d.Publish(ctx, message(7, signedUp)))
+
+	require.Eventually(t, func() bool {
+		sub, err = store.Get(ctx, sub.ID)
+		return err == nil && !sub.Enabled
+	}, 2*time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, "disabled after 2 consecutive failed attempts", sub.DisabledReason)
+
+	require.Never(t, func() bool { requests, _ := r.received(); return len(requests) > 2 }, 50*time.Millisecond, 5*time.Millisecond, "a disabled subscription is not retried")
+
+	require.NoError(t, d.Publish(ctx, message(8, signedUp)))
+
+	attempts, err := store.Attempts(ctx, sub.ID, 10)
+	require.NoError(t, err)
+	_, err = d.Redeliver(ctx, sub.ID, attempts[0].DeliveryID)
+	assert.ErrorIs(t, err, ErrSubscriptionDisabled)
+
+	sub.Enabled = true
+	sub, err = store.Update(ctx, sub)
+	require.NoError(t, err)
+	assert.Empty(t, sub.DisabledReason)
+	assert.Zero(t, sub.ConsecutiveFailures)
+}
+
+func TestSubscription_Validate(t *testing.T) {
+	supported := []string{signedUp, statusChanged}
+
+	assert.NoError(t, Subscription{URL: "https://example.com/hooks", Events: []string{signedUp}}.validate(supported))
+	assert.Error(t, Subscription{URL: "/hooks", Events: []string{signedUp}}.validate(supported), "the URL must be absolute")
+	assert.Error(t, Subscription{URL: "ftp://example.com", Events: []string{signedUp}}.validate(supported))
+	assert.Error(t, Subscription{URL: "https://example.com/hooks"}.validate(supported), "events are required")
+	assert.Error(t, Subscription{URL: "https://example.com/hooks", Events: []string{"user.deleted"}}.validate(supported))
+	assert.Error(t, Subscription{URL: "https://example.com/hooks", Events: []string{signedUp}, Secret: "short"}.validate(supported))
+}
+
+func TestSign(t *testing.T) {
+	signature := Sign("secret", 1767225600, []byte(`{"id":"1"}`))
+
+	assert.Equal(t, signature, Sign("secret", 1767225600, []byte(`{"id":"1"}`)))
+	assert.NotEqual(t, signature, Sign("secret", 1767225601, []byte(`{"id":"1"}`)), "the timestamp is signed")
+	assert.NotEqual(t, signature, Sign("other", 1767225600, []byte(`{"id":"1"}`)))
+	assert.Regexp(t, `^sha256=[0-9a-f]{64}$`, signature)
+}
@@ -20,3 +20,9 @@ func (s *MYSQLRepositoryTestSuite) SetupSuite() {
 }
 
 func (s *MYSQLRepositoryTestSuite) TearDownSuite() { s.TearDown() }
+
+func (s *MYSQLRepositoryTestSuite) SetupTest() { resetUsers(&s.DBSuite) }
+
+func (s *MYSQLRepositoryTestSuite) TestUsersRepository() { testUsersRepository(&s.DBSuite) }
+
+func (s *MYSQLRepositoryTestSuite) TestSeeds() { testSeeds(&s.DBSuite) }
@@ -0,0 +1,30 @@
+package tests
+
+import (
+	"testing"
+
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/testutil"
+	"github.com/stretchr/testify/suite"
+)
+
+type PostgresRepositoryTestSuite struct {
+	testutil.PostgresSuite
+}
+
+func TestPostgresRepositoryTestSuite(t *testing.T) {
+	suite.Run(t, new(PostgresRepositoryTestSuite))
+}
+
+func (s *PostgresRepositoryTestSuite) SetupSuite() {
+	s.DatabaseName = "golang_clean_architecture"
+	s.MigrationsDir = "../database/postgres/migration"
+	s.Setup()
+}
+
+func (s *PostgresRepositoryTestSuite) TearDownSuite() { s.TearDown() }
+
+func (s *PostgresRepositoryTestSuite) SetupTest() { resetUsers(&s.DBSuite) }
+
+func (s *PostgresRepositoryTestSuite) TestUsersRepository() { testUsersRepository(&s.DBSuite) }
+
+func (s *PostgresRepositoryTestSuite) TestSeeds() { testSeeds(&s.DBSuite) }
@@ -0,0 +1,58 @@
+package tests
+
+import (
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	userSeeds "github.com/DoWithLogic/golang-clean-architecture/internal/app/users/seeds"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/server"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/encryptions"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/seed"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/testutil"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+)
+
+const seedsCryptoKey = "DoWithLogic!@#"
+
+// testSeeds runs the seed sets of the test environment against the database of s.
+func testSeeds(s *testutil.DBSuite) {
+	crypto := encryptions.NewCrypto(seedsCryptoKey)
+
+	registry, err := server.NewSeedRegistry(s.DB, crypto)
+	s.Require().NoError(err)
+
+	generated := userSeeds.Generated()
+
+	reports := s.Seed(registry)
+	s.Equal([]seed.Report{{Set: "users", Seeder: "generated users", Result: seed.Result{Created: len(generated)}}}, reports)
+
+	// Seeding again skips the users that exist.
+	reports = s.Seed(registry)
+	s.Equal([]seed.Report{{Set: "users", Seeder: "generated users", Result: seed.Result{Skipped: len(generated)}}}, reports)
+
+	var seeded []entities.User
+	s.Require().NoError(s.DB.Order("id").Find(&seeded).Error)
+	s.Require().Len(seeded, len(generated))
+
+	combinations := make(map[types.USER_STATUS]map[types.CONTACT_TYPE]bool)
+	for _, user := range seeded {
+		if combinations[user.Status] == nil {
+			combinations[user.Status] = make(map[types.CONTACT_TYPE]bool)
+		}
+
+		combinations[user.Status][user.ContactType] = true
+		s.Equal(crypto.EncodeSHA256(userSeeds.DefaultPassword), user.Password, "passwords are hashed like on sign up")
+
+		var histories int64
+		s.Require().NoError(s.DB.Model(&entities.PasswordHistory{}).Where("user_id = ?", user.ID).Count(&histories).Error)
+		s.EqualValues(1, histories)
+	}
+
+	for _, status := range []types.USER_STATUS{types.ACTIVE, types.PENDING, types.REJECT, types.BANNED} {
+		s.Len(combinations[status], 2, "status %s has a user for every contact type", status)
+	}
+
+	_, err = registry.Run(s.Ctx, "production")
+	s.NoError(err, "no set runs in production by default")
+
+	_, err = registry.Run(s.Ctx, "production", "users")
+	s.ErrorIs(err, seed.ErrNotAllowed)
+}
@@ -0,0 +1,143 @@
+package tests
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/entities"
+	"github.com/DoWithLogic/golang-clean-architecture/internal/app/users/repository"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/response/app_error"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/testutil"
+	"github.com/DoWithLogic/golang-clean-architecture/pkg/types"
+	"github.com/samber/lo"
+)
+
+// resetUsers empties the users tables, password histories are removed first
+// because of their foreign key.
+func resetUsers(s *testutil.DBSuite) {
+	s.Require().NoError(s.DB.Exec("DELETE FROM user_password_histories").Error)
+	s.Require().NoError(s.DB.Exec("DELETE FROM users").Error)
+}
+
+func newUser(name, contactValue string, status types.USER_STATUS) *entities.User {
+	return &entities.User{
+		Name:         name,
+		ContactType:  types.CONTACT_TYPE_EMAIL,
+		ContactValue: contactValue,
+		Password:     "hashed",
+		Status:       status,
+	}
+}
+
+// testUsersRepository runs the users repository tests against the database of s.
+// Every driver suite runs them, so the repository behaves the same on all of them.
+func testUsersRepository(s *testutil.DBSuite) {
+	repo := repository.NewRepository(s.DB)
+
+	s.Run("AddUser and UserDetail", func() {
+		startedAt := time.Now().Truncate(time.Second)
+
+		user := newUser("John", "john@example.com", types.PENDING)
+		s.Require().NoError(repo.AddUser(s.Ctx, user))
+		s.Require().NotZero(user.ID)
+
+		got, err := repo.UserDetail(s.Ctx, entities.WithID(user.ID))
+		s.Require().NoError(err)
+		s.Equal("John", got.Name)
+		s.Equal(types.CONTACT_TYPE_EMAIL, got.ContactType)
+		s.Equal(types.PENDING, got.Status)
+		s.Equal(types.LANGUAGE_ID, lo.FromPtr(got.Language), "language defaults to ID")
+		s.WithinRange(got.CreatedAt, startedAt.Add(-time.Second), time.Now().Add(time.Second))
+		s.Zero(got.CreatedAt.Nanosecond(), "timestamps are stored with second precision")
+
+		got, err = repo.UserDetail(s.Ctx, entities.WithContactValue("john@example.com"))
+		s.Require().NoError(err)
+		s.Equal(user.ID, got.ID)
+	})
+
+	s.Run("UserDetail not found", func() {
+		_, err := repo.UserDetail(s.Ctx, entities.WithID(999999))
+		s.ErrorIs(err, app_error.ErrUserNotFound)
+	})
+
+	s.Run("AddUser duplicated contact", func() {
+		s.Require().NoError(repo.AddUser(s.Ctx, newUser("Jane", "jane@example.com", types.PENDING)))
+
+		for _, contactValue := range []string{"jane@example.com", "JANE@example.com"} {
+			err := repo.AddUser(s.Ctx, newUser("Jane", contactValue, types.PENDING))
+			s.Require().Error(err, contactValue)
+
+			var appErr *response.AppError
+			s.Require().ErrorAs(err, &appErr)
+			s.Equal(http.StatusConflict, appErr.Code)
+			s.ErrorIs(err, app_error.ErrUserAlreadyExists)
+		}
+	})
+
+	s.Run("UpdateUser", func() {
+		user := newUser("Bob", "bob@example.com", types.PENDING)
+		s.Require().NoError(repo.AddUser(s.Ctx, user))
+
+		s.Require().NoError(repo.UpdateUser(s.Ctx, &entities.UpdateUser{
+			ID:     user.ID,
+			Name:   lo.ToPtr("Robert"),
+			Status: lo.ToPtr(types.ACTIVE),
+		}))
+
+		got, err := repo.UserDetail(s.Ctx, entities.WithID(user.ID))
+		s.Require().NoError(err)
+		s.Equal("Robert", got.Name)
+		s.Equal(types.ACTIVE, got.Status)
+		s.Require().NotNil(got.UpdatedAt)
+
+		s.Require().NoError(repo.AddUser(s.Ctx, newUser("Alice", "alice@example.com", types.PENDING)))
+		err = repo.UpdateUser(s.Ctx, &entities.UpdateUser{ID: user.ID, ContactValue: lo.ToPtr("alice@example.com")})
+		s.ErrorIs(err, app_error.ErrUserAlreadyExists)
+	})
+
+	s.Run("Users", func() {
+		resetUsers(s)
+
+		for _, user := range []*entities.User{
+			newUser("Alice Smith", "alice@example.com", types.ACTIVE),
+			newUser("Bob", "bob@example.com", types.PENDING),
+			newUser("Carol", "carol.smith@example.com", types.ACTIVE),
+			newUser("Dave", "dave@example.com", types.ACTIVE),
+		} {
+			s.Require().NoError(repo.AddUser(s.Ctx, user))
+		}
+
+		list, total, err := repo.Users(s.Ctx, entities.UserFilter{Status: lo.ToPtr(types.ACTIVE), Limit: 2})
+		s.Require().NoError(err)
+		s.EqualValues(3, total)
+		s.Equal([]string{"Alice Smith", "Carol"}, lo.Map(list, func(u entities.User, _ int) string { return u.Name }))
+
+		list, _, err = repo.Users(s.Ctx, entities.UserFilter{Status: lo.ToPtr(types.ACTIVE), Limit: 2, Offset: 2})
+		s.Require().NoError(err)
+		s.Equal([]string{"Dave"}, lo.Map(list, func(u entities.User, _ int) string { return u.Name }))
+
+		// The search is case insensitive and matches the name or the contact value.
+		list, total, err = repo.Users(s.Ctx, entities.UserFilter{Search: lo.ToPtr("SMITH"), Limit: 10})
+		s.Require().NoError(err)
+		s.EqualValues(2, total)
+		s.Equal([]string{"Alice Smith", "Carol"}, lo.Map(list, func(u entities.User, _ int) string { return u.Name }))
+
+		byIDs, err := repo.UsersByIDs(s.Ctx, []int64{list[0].ID, list[1].ID, 999999})
+		s.Require().NoError(err)
+		s.Len(byIDs, 2)
+	})
+
+	s.Run("PasswordHistories", func() {
+		user := newUser("Eve", "eve@example.com", types.ACTIVE)
+		s.Require().NoError(repo.AddUser(s.Ctx, user))
+
+		for _, password := range []string{"first", "second", "third"} {
+			s.Require().NoError(repo.AddPasswordHistory(s.Ctx, entities.NewPasswordHistory(user.ID, password)))
+		}
+
+		histories, err := repo.PasswordHistories(s.Ctx, user.ID, 2)
+		s.Require().NoError(err)
+		s.Equal([]string{"third", "second"}, lo.Map(histories, func(h entities.PasswordHistory, _ int) string { return h.Password }))
+	})
+}